@@ -1,15 +1,40 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"proyecto/internal/application"
+	"time"
 )
 
 func main() {
 	/* Set environment variables */
 	os.Setenv("TOKEN", "123456") // Token to access data modification operations
 
-	/* Run the application */
-	app := application.NewApplicationDefault("localhost:8080")
-	app.Run()
+	/* v1 products are deprecated in favor of /v2 and stop being served six months later */
+	v1Deprecation := time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+	v1Sunset := time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC)
+
+	/* Run the application. The sweeper starts on dry-run: the sample catalog expired in 2021-2022,
+	   so review the SWEEP lines of the log before letting it unpublish products */
+	app := application.NewApplicationDefault(&application.ConfigApplicationDefault{
+		Address:                 "localhost:8080",
+		ExpirationSweepInterval: time.Hour,      // Look for expired products every hour
+		ExpirationGracePeriod:   24 * time.Hour, // Expired products stay published one more day
+		ExpirationDryRun:        true,
+		PublicationInterval:     time.Minute,      // Publish windows are checked at least once a minute
+		CategoryDeletePolicy:    "block",          // Categories with products can't be deleted
+		CodePrefix:              "200",            // Products created without a code get an in-store EAN-13
+		IdempotencyTTL:          24 * time.Hour,   // Retries with the same Idempotency-Key are replayed for a day
+		EventBufferSize:         1000,             // Product event streams can resume after the last 1000 changes
+		EventHeartbeat:          15 * time.Second, // Idle product event streams get a heartbeat every 15 seconds
+		WebhookMaxAttempts:      6,                // Webhook deliveries are dead-lettered after 6 failed attempts
+		WebhookBackoff:          30 * time.Second, // Failed webhook deliveries are retried after 30s, 1m, 2m, 4m...
+		WebhookTimeout:          10 * time.Second, // Webhook receivers have 10 seconds to answer
+		ProductV1Deprecation:    v1Deprecation,
+		ProductV1Sunset:         v1Sunset,
+	})
+	if err := app.Run(); err != nil {
+		fmt.Println(err)
+	}
 }
@@ -1,78 +1,382 @@
 package application
 
 import (
+	"context"
 	"net/http"
 	"os"
+	"os/signal"
+	"proyecto/internal"
+	"proyecto/internal/clock"
 	"proyecto/internal/handlers"
 	"proyecto/internal/middleware"
+	"proyecto/internal/notifier"
 	"proyecto/internal/repository"
 	"proyecto/internal/service"
 	"proyecto/internal/storage"
+	"proyecto/internal/worker"
+	"proyecto/platform/web/openapi"
+	"syscall"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 )
 
+// ConfigApplicationDefault is the configuration of the default application
+type ConfigApplicationDefault struct {
+	Address                 string        // Server address (host:port)
+	ExpirationSweepInterval time.Duration // Time between two sweeps of expired products
+	ExpirationGracePeriod   time.Duration // Time an expired product stays published
+	ExpirationDryRun        bool          // Only log the products the sweeper would unpublish
+	PublicationInterval     time.Duration // Maximum time between two checks of the publish windows
+	CategoryDeletePolicy    string        // What happens to the products of a deleted category: block, reassign or orphan
+	ReorderCheckInterval    time.Duration // Time between two checks of the reorder points
+	LotPolicy               string        // Order the lots are consumed in: fifo or fefo
+	CartTTL                 time.Duration // Time a cart lives untouched
+	CodePrefix              string        // Prefix of the EAN-13 codes generated for products created without one (empty disables it)
+	IdempotencyTTL          time.Duration // Time the responses to requests sent with an Idempotency-Key are kept
+	EventBufferSize         int           // Product events retained for the streams resuming with Last-Event-ID
+	EventHeartbeat          time.Duration // Time between two heartbeats of an idle product event stream
+	WebhookMaxAttempts      int           // Failed attempts before a webhook delivery is dead-lettered
+	WebhookBackoff          time.Duration // Wait after the first failed webhook attempt, doubled on every new failure
+	WebhookTimeout          time.Duration // Time a webhook receiver has to answer an attempt
+	ProductV1Deprecation    time.Time     // Moment the v1 product resource was deprecated (zero leaves the Deprecation header out)
+	ProductV1Sunset         time.Time     // Moment the v1 product resource stops being served (zero leaves the Sunset header out)
+}
+
 type ApplicationDefault struct {
-	address string // Server address (host:port)
+	address                 string        // Server address (host:port)
+	expirationSweepInterval time.Duration // Time between two sweeps of expired products
+	expirationGracePeriod   time.Duration // Time an expired product stays published
+	expirationDryRun        bool          // Only log the products the sweeper would unpublish
+	publicationInterval     time.Duration // Maximum time between two checks of the publish windows
+	categoryDeletePolicy    string        // What happens to the products of a deleted category
+	reorderCheckInterval    time.Duration // Time between two checks of the reorder points
+	lotPolicy               string        // Order the lots are consumed in
+	cartTTL                 time.Duration // Time a cart lives untouched
+	codePrefix              string        // Prefix of the EAN-13 codes generated for products created without one
+	idempotencyTTL          time.Duration // Time the responses to requests sent with an Idempotency-Key are kept
+	eventBufferSize         int           // Product events retained for the streams resuming with Last-Event-ID
+	eventHeartbeat          time.Duration // Time between two heartbeats of an idle product event stream
+	webhookMaxAttempts      int           // Failed attempts before a webhook delivery is dead-lettered
+	webhookBackoff          time.Duration // Wait after the first failed webhook attempt
+	webhookTimeout          time.Duration // Time a webhook receiver has to answer an attempt
+	productV1Deprecation    time.Time     // Moment the v1 product resource was deprecated
+	productV1Sunset         time.Time     // Moment the v1 product resource stops being served
 }
 
 // NewApplicationDefault creates a new default valued ApplicationDefault
-// NewApplicationDefault(string) -> *ApplicationDefault
+// NewApplicationDefault(*ConfigApplicationDefault) -> *ApplicationDefault
 // Args:
-//		address: Server address (host:port)
+//		cfg: Application configuration. Missing values take their default
 // Return:
 //		*ApplicationDefault: New ApplicationDefault instance
 
-func NewApplicationDefault(address string) *ApplicationDefault {
+func NewApplicationDefault(cfg *ConfigApplicationDefault) *ApplicationDefault {
+	/* Default values */
+	defaultConfig := ConfigApplicationDefault{
+		Address:                 "localhost:8080",
+		ExpirationSweepInterval: time.Hour,
+		PublicationInterval:     time.Minute,
+		CategoryDeletePolicy:    internal.CategoryDeleteBlock,
+		ReorderCheckInterval:    5 * time.Minute,
+		LotPolicy:               internal.LotPolicyFIFO,
+		CartTTL:                 service.DefaultCartTTL,
+		IdempotencyTTL:          24 * time.Hour,
+		EventBufferSize:         service.DefaultProductEventCapacity,
+		EventHeartbeat:          handlers.DefaultEventHeartbeat,
+		WebhookMaxAttempts:      service.DefaultWebhookMaxAttempts,
+		WebhookBackoff:          service.DefaultWebhookBackoff,
+		WebhookTimeout:          10 * time.Second,
+	}
+	if cfg != nil {
+		if cfg.Address != "" {
+			defaultConfig.Address = cfg.Address
+		}
+		if cfg.ExpirationSweepInterval > 0 {
+			defaultConfig.ExpirationSweepInterval = cfg.ExpirationSweepInterval
+		}
+		if cfg.CategoryDeletePolicy != "" {
+			defaultConfig.CategoryDeletePolicy = cfg.CategoryDeletePolicy
+		}
+		if cfg.PublicationInterval > 0 {
+			defaultConfig.PublicationInterval = cfg.PublicationInterval
+		}
+		if cfg.ReorderCheckInterval > 0 {
+			defaultConfig.ReorderCheckInterval = cfg.ReorderCheckInterval
+		}
+		if cfg.CartTTL > 0 {
+			defaultConfig.CartTTL = cfg.CartTTL
+		}
+		if cfg.IdempotencyTTL > 0 {
+			defaultConfig.IdempotencyTTL = cfg.IdempotencyTTL
+		}
+		if cfg.EventBufferSize > 0 {
+			defaultConfig.EventBufferSize = cfg.EventBufferSize
+		}
+		if cfg.EventHeartbeat > 0 {
+			defaultConfig.EventHeartbeat = cfg.EventHeartbeat
+		}
+		if cfg.WebhookMaxAttempts > 0 {
+			defaultConfig.WebhookMaxAttempts = cfg.WebhookMaxAttempts
+		}
+		if cfg.WebhookBackoff > 0 {
+			defaultConfig.WebhookBackoff = cfg.WebhookBackoff
+		}
+		if cfg.WebhookTimeout > 0 {
+			defaultConfig.WebhookTimeout = cfg.WebhookTimeout
+		}
+		if cfg.LotPolicy != "" {
+			defaultConfig.LotPolicy = cfg.LotPolicy
+		}
+		defaultConfig.ExpirationGracePeriod = cfg.ExpirationGracePeriod
+		defaultConfig.ExpirationDryRun = cfg.ExpirationDryRun
+		defaultConfig.CodePrefix = cfg.CodePrefix
+		defaultConfig.ProductV1Deprecation = cfg.ProductV1Deprecation
+		defaultConfig.ProductV1Sunset = cfg.ProductV1Sunset
+	}
+
 	return &ApplicationDefault{
-		address: address,
+		address:                 defaultConfig.Address,
+		expirationSweepInterval: defaultConfig.ExpirationSweepInterval,
+		expirationGracePeriod:   defaultConfig.ExpirationGracePeriod,
+		expirationDryRun:        defaultConfig.ExpirationDryRun,
+		publicationInterval:     defaultConfig.PublicationInterval,
+		categoryDeletePolicy:    defaultConfig.CategoryDeletePolicy,
+		reorderCheckInterval:    defaultConfig.ReorderCheckInterval,
+		lotPolicy:               defaultConfig.LotPolicy,
+		cartTTL:                 defaultConfig.CartTTL,
+		codePrefix:              defaultConfig.CodePrefix,
+		idempotencyTTL:          defaultConfig.IdempotencyTTL,
+		eventBufferSize:         defaultConfig.EventBufferSize,
+		eventHeartbeat:          defaultConfig.EventHeartbeat,
+		webhookMaxAttempts:      defaultConfig.WebhookMaxAttempts,
+		webhookBackoff:          defaultConfig.WebhookBackoff,
+		webhookTimeout:          defaultConfig.WebhookTimeout,
+		productV1Deprecation:    defaultConfig.ProductV1Deprecation,
+		productV1Sunset:         defaultConfig.ProductV1Sunset,
 	}
 }
 
-// Run runs the application
-func (h *ApplicationDefault) Run() {
+// Run runs the application until it receives an interrupt or terminate signal
+func (h *ApplicationDefault) Run() (err error) {
 	/* Intialize dependencies */
 	storagePath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/docs/db/products.json"
+	movementsPath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/docs/db/movements.json"
+	categoriesPath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/docs/db/categories.json"
+	warehousesPath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/docs/db/warehouses.json"
+	suppliersPath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/docs/db/suppliers.json"
+	pricesPath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/docs/db/prices.json"
+	cartsPath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/docs/db/carts.json"
+	ordersPath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/docs/db/orders.json"
+	lotsPath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/docs/db/lots.json"
+	promotionsPath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/docs/db/promotions.json"
+	idempotencyPath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/docs/db/idempotency_keys.json"
+	productEventsPath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/docs/db/product_events.json"
+	webhooksPath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/docs/db/webhooks.json"
+	webhookDeliveriesPath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/docs/db/webhook_deliveries.json"
+	taxRatesPath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/docs/config/tax_rates.json"
 	logpath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/docs/logs/log.txt"
-	storage := storage.NewProductStorageDefault(storagePath)
-	repository := repository.NewProductMap(storage)
-	service := service.NewProductServiceDefault(repository)
-	handler := handlers.NewProductHandler(service)
+	productRepository := repository.NewProductMap(storage.NewProductStorageDefault(storagePath))
+	movementRepository := repository.NewMovementMap(storage.NewMovementStorageDefault(movementsPath))
+	movementService := service.NewMovementServiceDefault(productRepository, movementRepository)
+	warehouseRepository := repository.NewWarehouseMap(storage.NewWarehouseStorageDefault(warehousesPath))
+	movementService.SetWarehouses(warehouseRepository)
+	warehouseService := service.NewWarehouseServiceDefault(warehouseRepository, movementService)
+	systemClock := clock.NewClockSystem()
+	movementService.SetClock(systemClock)
+	movementService.SetLots(repository.NewLotMap(storage.NewLotStorageDefault(lotsPath)), h.lotPolicy)
+	productService := service.NewProductServiceDefault(productRepository)
+	productService.SetLedger(movementService)
+	productService.AddDependent(movementService)
+	productService.SetClock(systemClock)
+	productService.SetCodeGeneration(h.codePrefix)
+	productEvents := service.NewProductEventBusDefault(storage.NewProductEventStorageDefault(productEventsPath), h.eventBufferSize)
+	productEvents.SetClock(systemClock)
+	productService.SetEvents(productEvents)
+	movementService.SetEvents(productEvents, productService)
+	priceRepository := repository.NewPriceMap(storage.NewPriceStorageDefault(pricesPath))
+	promotionRepository := repository.NewPromotionMap(storage.NewPromotionStorageDefault(promotionsPath))
+	priceService := service.NewPriceServiceDefault(productRepository, priceRepository, promotionRepository)
+	priceService.SetClock(systemClock)
+	productService.SetPricing(priceService)
+	productService.AddDependent(priceService)
+	priceService.SetEvents(productEvents, productService)
+	taxService := service.NewTaxServiceDefault(storage.NewTaxRateStorageDefault(taxRatesPath))
+	productService.SetTaxes(taxService)
+	categoryRepository := repository.NewCategoryMap(storage.NewCategoryStorageDefault(categoriesPath))
+	categoryService := service.NewCategoryServiceDefault(categoryRepository, productService, h.categoryDeletePolicy)
+	productService.AddDependent(categoryService)
+	supplierRepository := repository.NewSupplierMap(storage.NewSupplierStorageDefault(suppliersPath))
+	supplierService := service.NewSupplierServiceDefault(supplierRepository, productService)
+	productService.AddDependent(supplierService)
+	orderRepository := repository.NewOrderMap(storage.NewOrderStorageDefault(ordersPath))
+	orderService := service.NewOrderServiceDefault(orderRepository, productService, movementService)
+	orderService.SetClock(systemClock)
+	cartService := service.NewCartServiceDefault(repository.NewCartMap(storage.NewCartStorageDefault(cartsPath)), productService, orderService, h.cartTTL)
+	cartService.SetClock(systemClock)
+	webhookService := service.NewWebhookServiceDefault(
+		repository.NewWebhookMap(storage.NewWebhookStorageDefault(webhooksPath)),
+		repository.NewWebhookDeliveryMap(storage.NewWebhookDeliveryStorageDefault(webhookDeliveriesPath)),
+	)
+	webhookService.SetClock(systemClock)
+	webhookService.SetRetryPolicy(h.webhookMaxAttempts, h.webhookBackoff, service.DefaultWebhookMaxBackoff)
+	handler := handlers.NewProductHandler(productService)
+	movementHandler := handlers.NewMovementHandler(movementService)
+	categoryHandler := handlers.NewCategoryHandler(categoryService)
+	priceHandler := handlers.NewPriceHandler(priceService)
+	warehouseHandler := handlers.NewWarehouseHandler(warehouseService)
+	supplierHandler := handlers.NewSupplierHandler(supplierService)
+	orderHandler := handlers.NewOrderHandler(orderService)
+	cartHandler := handlers.NewCartHandler(cartService)
+	taxHandler := handlers.NewTaxHandler(taxService, productService, cartService, orderService)
+	productEventHandler := handlers.NewProductEventHandler(productEvents, h.eventHeartbeat)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
 	router := chi.NewRouter()
 	/* Open log file */
 	file, err := os.OpenFile(logpath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0777)
 	if err != nil {
-		panic(err)
+		return
 	}
 	defer file.Close()
 
 	/* Middlewares */
+	router.Use(middleware.MiddlewareRequestID)
 	router.Use(middleware.MiddlewareLogger(file))
-	router.Use(middleware.MiddelwareAuthentication)
-
-	/* Public endpoints */
-	router.Route("/products", func(r chi.Router) {
-		/*
-			TODO:
-				No se pueden hacer Route con la misma ruta base por lo que
-			de este modo no se puede aplicar middlewares difenciados por tipo de endpoint.
-				Intente usar group pero esto siempre retorna 405 Method Not Allowed.
-
-				Una solucion posible es aplicar el middleware (de autenticacion) directamente
-			sobre las funciones handler de los endpoins privados, pero esto no es muy elegante.
-		*/
-
-		/* Public Endpoints */
-		r.Get("/", handler.GetAllProducts())
-		r.Get("/{id}", handler.GetProductByID())
-		r.Get("/search", handler.GetProductByPrice())
-
-		/* Private Endpoints */
-		r.Post("/", handler.AddNewProduct())
-		r.Put("/", handler.UpdateProduct())
-		r.Patch("/{id}", handler.UpdateProductPartial())
-		r.Delete("/{id}", handler.DeleteProduct())
+
+	/* API documentation, served without authentication */
+	router.Get("/openapi.json", handlers.ProductSpec.Handler(router))
+	router.Get("/docs", openapi.DocsHandler("/openapi.json"))
+
+	/* Private endpoints. The described requests are checked against handlers.ProductSpec */
+	api := router.With(
+		middleware.MiddelwareAuthentication,
+		handlers.ProductSpec.Validator(router, false).Middleware,
+		middleware.NewIdempotency(storage.NewIdempotencyStorageDefault(idempotencyPath), systemClock, h.idempotencyTTL).Middleware,
+	)
+
+	/* Product API */
+	productAPI{
+		product:     handler,
+		movement:    movementHandler,
+		price:       priceHandler,
+		tax:         taxHandler,
+		supplier:    supplierHandler,
+		category:    categoryHandler,
+		events:      productEventHandler,
+		versions:    middleware.NewVersioning(handlers.ProductVersion1, handlers.ProductVersion1, handlers.ProductVersion2),
+		deprecation: middleware.NewDeprecation(handlers.ProductVersion1, h.productV1Deprecation, h.productV1Sunset),
+	}.mount(api)
+
+	api.Route("/categories", func(r chi.Router) {
+		r.Get("/", categoryHandler.GetAllCategories())
+		r.Get("/{id}", categoryHandler.GetCategoryByID())
+		r.Get("/{id}/products", categoryHandler.GetProductsByCategory())
+		r.Post("/", categoryHandler.AddNewCategory())
+		r.Put("/{id}", categoryHandler.UpdateCategory())
+		r.Delete("/{id}", categoryHandler.DeleteCategory())
+	})
+
+	api.Route("/promotions", func(r chi.Router) {
+		r.Put("/{id}", priceHandler.UpdatePromotion())
+		r.Delete("/{id}", priceHandler.DeletePromotion())
+	})
+
+	api.Route("/suppliers", func(r chi.Router) {
+		r.Get("/", supplierHandler.GetAllSuppliers())
+		r.Get("/{id}", supplierHandler.GetSupplierByID())
+		r.Get("/{id}/products", supplierHandler.GetProductsBySupplier())
+		r.Post("/", supplierHandler.AddNewSupplier())
+		r.Put("/{id}", supplierHandler.UpdateSupplier())
+		r.Delete("/{id}", supplierHandler.DeleteSupplier())
+		r.Put("/{id}/products/{productID}", supplierHandler.LinkProduct())
+		r.Delete("/{id}/products/{productID}", supplierHandler.UnlinkProduct())
+	})
+
+	api.Route("/warehouses", func(r chi.Router) {
+		r.Get("/", warehouseHandler.GetAllWarehouses())
+		r.Get("/{id}", warehouseHandler.GetWarehouseByID())
+		r.Get("/{id}/stock", movementHandler.GetWarehouseStock())
+		r.Post("/", warehouseHandler.AddNewWarehouse())
+		r.Put("/{id}", warehouseHandler.UpdateWarehouse())
+		r.Delete("/{id}", warehouseHandler.DeleteWarehouse())
 	})
 
-	http.ListenAndServe(h.address, router)
+	api.Route("/orders", func(r chi.Router) {
+		r.Get("/", orderHandler.GetAllOrders())
+		r.Get("/{id}", orderHandler.GetOrderByID())
+		r.Post("/", orderHandler.AddNewOrder())
+		r.Put("/{id}", orderHandler.UpdateOrder())
+		r.Post("/{id}/place", orderHandler.PlaceOrder())
+		r.Post("/{id}/confirm", orderHandler.ConfirmOrder())
+		r.Post("/{id}/ship", orderHandler.ShipOrder())
+		r.Post("/{id}/cancel", orderHandler.CancelOrder())
+		r.Get("/{id}/tax", taxHandler.GetOrderTax())
+	})
+
+	api.Route("/carts", func(r chi.Router) {
+		r.Post("/", cartHandler.CreateCart())
+		r.Get("/{id}", cartHandler.GetCartByID())
+		r.Post("/{id}/lines", cartHandler.AddLine())
+		r.Put("/{id}/lines/{productID}", cartHandler.UpdateLine())
+		r.Delete("/{id}/lines/{productID}", cartHandler.RemoveLine())
+		r.Post("/{id}/checkout", cartHandler.Checkout())
+		r.Get("/{id}/tax", taxHandler.GetCartTax())
+	})
+
+	api.Route("/movements", func(r chi.Router) {
+		r.Get("/reconciliation", movementHandler.GetReconciliation())
+	})
+
+	api.Route("/lots", func(r chi.Router) {
+		r.Post("/write-off", movementHandler.WriteOffExpiredLots())
+	})
+
+	api.Route("/webhooks", func(r chi.Router) {
+		r.Get("/", webhookHandler.GetAllWebhooks())
+		r.Get("/dead-letters", webhookHandler.GetDeadLetters())
+		r.Get("/{id}", webhookHandler.GetWebhookByID())
+		r.Get("/{id}/deliveries", webhookHandler.GetDeliveries())
+		r.Post("/", webhookHandler.AddNewWebhook())
+		r.Put("/{id}", webhookHandler.UpdateWebhook())
+		r.Delete("/{id}", webhookHandler.DeleteWebhook())
+		r.Post("/deliveries/{id}/redeliver", webhookHandler.RedeliverDelivery())
+	})
+
+	/* Background workers */
+	sweeper := worker.NewExpirationSweeper(productService, systemClock, h.expirationSweepInterval, h.expirationGracePeriod, h.expirationDryRun, file)
+	sweeper.Start()
+	defer sweeper.Stop()
+	scheduler := worker.NewPublicationScheduler(productService, systemClock, h.publicationInterval, file)
+	scheduler.Start()
+	defer scheduler.Stop()
+	monitor := worker.NewReorderMonitor(productService, notifier.NewNotifierLog(file), systemClock, h.reorderCheckInterval)
+	monitor.Start()
+	defer monitor.Stop()
+	dispatcher := worker.NewWebhookDispatcher(webhookService, productEvents, &http.Client{Timeout: h.webhookTimeout}, systemClock, time.Minute, file)
+	dispatcher.Start()
+	defer dispatcher.Stop()
+
+	/* Serve until a shutdown signal is received */
+	server := &http.Server{Addr: h.address, Handler: router}
+	server.RegisterOnShutdown(productEvents.Close) // The event streams never finish on their own
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	select {
+	case err = <-serverErr:
+		return
+	case <-signals:
+	}
+
+	/* Graceful shutdown */
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = server.Shutdown(ctx)
+	return
 }
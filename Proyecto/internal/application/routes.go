@@ -0,0 +1,124 @@
+package application
+
+import (
+	"proyecto/internal/handlers"
+	"proyecto/internal/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// productAPI holds the handlers serving the product API. Its routes are described by
+// handlers.ProductSpec
+type productAPI struct {
+	product     *handlers.ProductHandler      // Products, variants, tags and barcodes
+	movement    *handlers.MovementHandler     // Stock ledger of a product
+	price       *handlers.PriceHandler        // Prices and promotions of a product
+	tax         *handlers.TaxHandler          // Taxes of a product
+	supplier    *handlers.SupplierHandler     // Suppliers of a product
+	category    *handlers.CategoryHandler     // Categories of a product
+	events      *handlers.ProductEventHandler // Feed of the product changes
+	versions    *middleware.Versioning        // Version the product resource is served with
+	deprecation *middleware.Deprecation       // Deprecation announced on the v1 responses
+}
+
+// mount registers the routes of the product API. The v1 routes are served unprefixed, with the
+// version negotiated by the Accept header, and under /v1. The v2 product resource is served
+// under /v2. The responses served with v1 announce its deprecation
+// mount(router chi.Router)
+// Args:
+//		router: Router the routes are registered on
+
+func (p productAPI) mount(router chi.Router) {
+	router.Group(func(r chi.Router) {
+		r.Use(p.versions.Negotiate, p.deprecation.Announce)
+		p.mountV1(r)
+	})
+	router.Route("/v1", func(r chi.Router) {
+		r.Use(p.versions.Pin(handlers.ProductVersion1), p.deprecation.Announce)
+		p.mountV1(r)
+	})
+	v2 := router.Route("/v2", func(r chi.Router) {
+		r.Use(p.versions.Pin(handlers.ProductVersion2))
+		p.mountV2(r)
+	})
+	p.deprecation.SetSuccessor("/v2", v2)
+}
+
+// mountV1 registers the v1 routes, /products and /tags. The product resource is served in the
+// version picked for the request
+// mountV1(router chi.Router)
+// Args:
+//		router: Router the routes are registered on
+
+func (p productAPI) mountV1(router chi.Router) {
+	router.Route("/products", func(r chi.Router) {
+		/*
+			TODO:
+				No se pueden hacer Route con la misma ruta base por lo que
+			de este modo no se puede aplicar middlewares difenciados por tipo de endpoint.
+				Intente usar group pero esto siempre retorna 405 Method Not Allowed.
+
+				Una solucion posible es aplicar el middleware (de autenticacion) directamente
+			sobre las funciones handler de los endpoins privados, pero esto no es muy elegante.
+		*/
+
+		/* Public Endpoints */
+		r.Get("/", p.product.Versioned(p.product.GetAllProducts(), p.product.GetAllProductsV2()))
+		r.Get("/{id}", p.product.Versioned(p.product.GetProductByID(), p.product.GetProductByIDV2()))
+		r.Get("/search", p.product.SearchProducts())
+		r.Get("/expiring", p.product.GetExpiringProducts())
+		r.Get("/reorder", p.product.GetReorderReport())
+		r.Get("/events", p.events.StreamProductEvents())
+
+		/* Private Endpoints */
+		r.Post("/", p.product.Versioned(p.product.AddNewProduct(), p.product.AddNewProductV2()))
+		r.Put("/{id}", p.product.Versioned(p.product.UpdateProduct(), p.product.UpdateProductV2()))
+		r.Patch("/{id}", p.product.UpdateProductPartial())
+		r.Delete("/{id}", p.product.DeleteProduct())
+
+		/* Variants */
+		r.Get("/{id}/variants", p.product.Versioned(p.product.GetVariants(), p.product.GetVariantsV2()))
+		r.Post("/{id}/variants", p.product.AddNewVariant())
+		r.Put("/{id}/variants/{variantID}", p.product.UpdateVariant())
+		r.Delete("/{id}/variants/{variantID}", p.product.DeleteVariant())
+
+		/* Stock ledger */
+		r.Get("/{id}/movements", p.movement.GetMovementsByProduct())
+		r.Post("/{id}/movements", p.movement.PostMovement())
+		r.Get("/{id}/stock", p.movement.GetStockLevels())
+		r.Post("/{id}/transfers", p.movement.TransferStock())
+		r.Get("/{id}/lots", p.movement.GetLotsByProduct())
+
+		/* Pricing */
+		r.Get("/{id}/prices", p.price.GetPriceTimeline())
+		r.Get("/{id}/promotions", p.price.GetPromotions())
+		r.Get("/{id}/tax", p.tax.GetProductTax())
+		r.Get("/{id}/barcode", p.product.GetProductBarcode())
+		r.Post("/{id}/promotions", p.price.AddNewPromotion())
+
+		/* Suppliers of a product */
+		r.Get("/{id}/suppliers", p.supplier.GetSuppliersByProduct())
+
+		/* Categories of a product */
+		r.Get("/{id}/categories", p.category.GetProductCategories())
+		r.Put("/{id}/categories", p.category.UpdateProductCategories())
+	})
+
+	router.Get("/tags", p.product.GetTags())
+}
+
+// mountV2 registers the v2 product resource, /products
+// mountV2(router chi.Router)
+// Args:
+//		router: Router the routes are registered on
+
+func (p productAPI) mountV2(router chi.Router) {
+	router.Route("/products", func(r chi.Router) {
+		r.Get("/", p.product.GetAllProductsV2())
+		r.Get("/{id}", p.product.GetProductByIDV2())
+		r.Post("/", p.product.AddNewProductV2())
+		r.Put("/{id}", p.product.UpdateProductV2())
+		r.Delete("/{id}", p.product.DeleteProduct())
+		r.Get("/{id}/variants", p.product.GetVariantsV2())
+	})
+}
@@ -0,0 +1,100 @@
+package application
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"proyecto/internal/handlers"
+	"proyecto/internal/middleware"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// newProductRouter mounts the product API on a new router. The handlers have no services, so
+// the routes can be inspected but not served
+// newProductRouter() -> *chi.Mux
+
+func newProductRouter() *chi.Mux {
+	router := chi.NewRouter()
+	productAPI{
+		product:     handlers.NewProductHandler(nil),
+		movement:    handlers.NewMovementHandler(nil),
+		price:       handlers.NewPriceHandler(nil),
+		tax:         handlers.NewTaxHandler(nil, nil, nil, nil),
+		supplier:    handlers.NewSupplierHandler(nil),
+		category:    handlers.NewCategoryHandler(nil),
+		events:      handlers.NewProductEventHandler(nil, 0),
+		versions:    middleware.NewVersioning(handlers.ProductVersion1, handlers.ProductVersion1, handlers.ProductVersion2),
+		deprecation: middleware.NewDeprecation(handlers.ProductVersion1, time.Time{}, time.Time{}),
+	}.mount(router)
+	return router
+}
+
+// TestProductSpec tests handlers.ProductSpec describes the product API
+func TestProductSpec(t *testing.T) {
+	// Test 1: should describe every route of the product API
+	t.Run("should describe every route of the product API", func(t *testing.T) {
+		router := newProductRouter()
+
+		/* Assertions */
+		require.Empty(t, handlers.ProductSpec.Undescribed(router), "routes missing in handlers.ProductSpec")
+		require.Empty(t, handlers.ProductSpec.Unregistered(router), "operations of handlers.ProductSpec without route")
+	})
+
+	// Test 2: should serve the OpenAPI document
+	t.Run("should serve the OpenAPI document", func(t *testing.T) {
+		router := newProductRouter()
+		router.Get("/openapi.json", handlers.ProductSpec.Handler(router))
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("GET", "/openapi.json", nil)
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		/* Assertions */
+		var document struct {
+			OpenAPI string                               `json:"openapi"`
+			Paths   map[string]map[string]map[string]any `json:"paths"`
+		}
+		require.Equal(t, http.StatusOK, res.Code)
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &document))
+		require.Equal(t, "3.0.3", document.OpenAPI)
+		require.Equal(t, "AddNewProduct", document.Paths["/products/"]["post"]["operationId"])
+		require.Equal(t, true, document.Paths["/products/"]["post"]["deprecated"])
+		require.Equal(t, "AddNewProductV1", document.Paths["/v1/products/"]["post"]["operationId"])
+		require.Equal(t, "AddNewProductV2", document.Paths["/v2/products/"]["post"]["operationId"])
+		require.NotContains(t, document.Paths["/v2/products/"]["post"], "deprecated")
+		require.NotContains(t, document.Paths, "/openapi.json")
+		content := func(path string) map[string]any {
+			return document.Paths[path]["get"]["responses"].(map[string]any)["200"].(map[string]any)["content"].(map[string]any)
+		}
+		require.Contains(t, content("/products/{id}"), handlers.MediaTypeProductV2)
+		require.NotContains(t, content("/v1/products/{id}"), handlers.MediaTypeProductV2)
+	})
+	// Test 3: should answer the errors of every product route as problems
+	t.Run("should answer the errors of every product route as problems", func(t *testing.T) {
+		router := newProductRouter()
+
+		/* Request every route holding a product id with an invalid one */
+		walk := func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+			if !strings.Contains(route, "/products/{id}") {
+				return nil
+			}
+			path := strings.NewReplacer("{id}", "x", "{variantID}", "1").Replace(route)
+			req := httptest.NewRequest(method, path, strings.NewReader("{}"))
+			req.Header.Set("Content-Type", "application/json")
+			res := httptest.NewRecorder()
+			router.ServeHTTP(res, req)
+
+			/* Assertions */
+			require.Equal(t, http.StatusBadRequest, res.Code, method+" "+route)
+			require.Equal(t, "application/problem+json", res.Header().Get("Content-Type"), method+" "+route)
+			return nil
+		}
+		require.NoError(t, chi.Walk(router, walk))
+	})
+}
@@ -0,0 +1,33 @@
+package internal
+
+import "time"
+
+/* Cart statuses */
+const (
+	CartOpen       = "open"        // Lines can be added, updated and removed.
+	CartCheckedOut = "checked_out" // Converted into an order. Final.
+)
+
+// TCartLine represents a line of a cart. The flags compare the snapshot against the live product.
+type TCartLine struct {
+	ProductID    int     `json:"product_id"`
+	Quantity     int     `json:"quantity"`
+	UnitPrice    float64 `json:"unit_price"`    // Price of the product when the line was added or last updated.
+	CurrentPrice float64 `json:"current_price"` // Live price of the product.
+	PriceChanged bool    `json:"price_changed"` // The live price differs from the snapshot.
+	Unavailable  bool    `json:"unavailable"`   // The product is gone, unpublished, expired or short of stock.
+}
+
+// TCart represents a server-side shopping cart.
+type TCart struct {
+	ID           int         `json:"id"`
+	Customer     string      `json:"customer"`
+	Status       string      `json:"status"`
+	Lines        []TCartLine `json:"lines"`
+	Total        float64     `json:"total"`         // Sum of the lines at the snapshot prices.
+	CurrentTotal float64     `json:"current_total"` // Sum of the lines at the live prices.
+	OrderID      int         `json:"order_id,omitempty"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+	ExpiresAt    time.Time   `json:"expires_at"` // The cart expires when it goes this long untouched.
+}
@@ -0,0 +1,15 @@
+package internal
+
+import "errors"
+
+/* Errors definition */
+var (
+	ErrCartNotFound = errors.New("cart not found")
+)
+
+/* Cart repository definition */
+type CartRepository interface {
+	GetCartByID(id int) (TCart, error) // Return a cart by its id.
+	InsertNewCart(cart *TCart) error   // Add a new cart into the repository.
+	UpdateCart(cart *TCart) error      // Update a cart from the repository if it exists.
+}
@@ -0,0 +1,23 @@
+package internal
+
+import "errors"
+
+/* Errors definition */
+var (
+	ErrCartNotExists     = errors.New("cart not exists")
+	ErrCartExpired       = errors.New("cart expired")
+	ErrCartClosed        = errors.New("cart closed")
+	ErrCartLineNotExists = errors.New("cart line not exists")
+	ErrCartOutdated      = errors.New("cart outdated")
+	ErrInvalidCartLine   = errors.New("invalid cart line")
+)
+
+/* Cart service definition */
+type CartService interface {
+	CreateCart(cart *TCart) error                          // Create a new empty cart.
+	GetCartByID(id int) (TCart, error)                     // Return a cart with its lines compared against the live products.
+	AddLine(id, productID, quantity int) (TCart, error)    // Add units of a product to a cart.
+	UpdateLine(id, productID, quantity int) (TCart, error) // Set the units of a line taking the live price again.
+	RemoveLine(id, productID int) (TCart, error)           // Remove a line from a cart.
+	Checkout(id int) (TOrder, error)                       // Convert a cart into a placed order.
+}
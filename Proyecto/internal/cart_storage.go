@@ -0,0 +1,7 @@
+package internal
+
+/* Cart storage definition */
+type CartStorage interface {
+	GetAll() (map[int]TCart, error) // Get all carts from storage
+	WriteAll(map[int]TCart) error   // Write all carts to storage
+}
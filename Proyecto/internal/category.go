@@ -0,0 +1,16 @@
+package internal
+
+/* Category delete policies */
+const (
+	CategoryDeleteBlock    = "block"    // A category with products can't be deleted.
+	CategoryDeleteReassign = "reassign" // The products move to the parent category.
+	CategoryDeleteOrphan   = "orphan"   // The products just lose the category.
+)
+
+// TCategory represents a category of the product taxonomy.
+type TCategory struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	ParentID   *int   `json:"parent_id"`   // Parent category. Nil for the root categories.
+	ProductIDs []int  `json:"product_ids"` // Products assigned to the category.
+}
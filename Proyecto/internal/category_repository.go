@@ -0,0 +1,17 @@
+package internal
+
+import "errors"
+
+/* Errors definition */
+var (
+	ErrCategoryNotFound = errors.New("category not found")
+)
+
+/* Category repository definition */
+type CategoryRepository interface {
+	GetAllCategories() ([]TCategory, error)        // Return all the categories in the repository.
+	GetCategoryByID(id int) (TCategory, error)     // Return a category by its id.
+	InsertNewCategory(category *TCategory) error   // Add a new category into the repository.
+	UpdateCategories(categories []TCategory) error // Update a group of categories from the repository if they exist.
+	DeleteCategory(id int) error                   // Delete a category from the repository.
+}
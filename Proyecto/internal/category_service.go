@@ -0,0 +1,24 @@
+package internal
+
+import "errors"
+
+/* Errors definition */
+var (
+	ErrCategoryNotExists     = errors.New("category not exists")
+	ErrCategoryAlreadyExists = errors.New("category already exists")
+	ErrCategoryCycle         = errors.New("category hierarchy cycle")
+	ErrInvalidCategoryParent = errors.New("invalid category parent")
+	ErrCategoryHasProducts   = errors.New("category has products")
+)
+
+/* Category service definition */
+type CategoryService interface {
+	GetAllCategories() ([]TCategory, error)                                    // Return all the categories.
+	GetCategoryByID(id int) (TCategory, error)                                 // Return a category by its id.
+	InsertNewCategory(category *TCategory) error                               // Add a new category.
+	UpdateCategory(category *TCategory) error                                  // Update a category if it exists.
+	DeleteCategory(id int) error                                               // Delete a category following the delete policy.
+	GetProductsByCategory(id int, includeDescendants bool) ([]TProduct, error) // Return the products of a category.
+	GetCategoriesByProduct(productID int) ([]TCategory, error)                 // Return the categories of a product.
+	AssignProductCategories(productID int, categoryIDs []int) error            // Replace the categories of a product.
+}
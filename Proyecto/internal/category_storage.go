@@ -0,0 +1,7 @@
+package internal
+
+/* Category storage definition */
+type CategoryStorage interface {
+	GetAll() (map[int]TCategory, error) // Get all categories from storage
+	WriteAll(map[int]TCategory) error   // Write all categories to storage
+}
@@ -0,0 +1,8 @@
+package internal
+
+import "time"
+
+/* Clock definition */
+type Clock interface {
+	Now() time.Time // Return the current time.
+}
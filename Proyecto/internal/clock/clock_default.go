@@ -0,0 +1,61 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// ClockSystem is the clock backed by the system time
+type ClockSystem struct{}
+
+// NewClockSystem creates a new ClockSystem
+// NewClockSystem() -> *ClockSystem
+// Return:
+//		*ClockSystem: New ClockSystem
+
+func NewClockSystem() *ClockSystem {
+	return &ClockSystem{}
+}
+
+// Now returns the current system time
+func (c *ClockSystem) Now() time.Time {
+	return time.Now()
+}
+
+// ClockFixed is a clock which only moves when told to. Meant for tests
+type ClockFixed struct {
+	now time.Time  // Current time of the clock
+	mu  sync.Mutex // Guards now
+}
+
+// NewClockFixed creates a new ClockFixed
+// NewClockFixed(now time.Time) -> *ClockFixed
+// Args:
+//		now: Initial time of the clock
+// Return:
+//		*ClockFixed: New ClockFixed
+
+func NewClockFixed(now time.Time) *ClockFixed {
+	return &ClockFixed{now: now}
+}
+
+// Now returns the current time of the clock
+func (c *ClockFixed) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set moves the clock to the given time
+func (c *ClockFixed) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// Advance moves the clock forward by the given duration
+func (c *ClockFixed) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
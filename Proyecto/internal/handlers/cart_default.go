@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"proyecto/internal"
+	"proyecto/platform/web/request"
+	"proyecto/platform/web/response"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+/* Cart handler definition */
+type CartHandler struct {
+	CartService internal.CartService // Cart service instance
+}
+
+// NewCartHandler creates a new default valued CartHandler
+// NewCartHandler(cs internal.CartService) -> *CartHandler
+// Args:
+//		cs: Cart service instance
+// Return:
+//		*CartHandler: New CartHandler instance
+
+func NewCartHandler(cs internal.CartService) *CartHandler {
+	return &CartHandler{
+		CartService: cs,
+	}
+}
+
+// BodyRequestCartJSON is the body request for a new cart in JSON format
+type BodyRequestCartJSON struct {
+	Customer string `json:"customer"` // Customer of the cart. (Optional)
+}
+
+// BodyRequestCartLineJSON is the body request for a line of a cart in JSON format
+type BodyRequestCartLineJSON struct {
+	ProductID int `json:"product_id"` // Product of the line. Ignored when the line is in the url.
+	Quantity  int `json:"quantity"`   // Units to add or units of the line.
+}
+
+// cartError writes the response for an error raised by the cart service
+// cartError(w http.ResponseWriter, err error)
+// Args:
+//		w:   HTTP response writer
+//		err: Error raised by the service
+
+func cartError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, internal.ErrCartNotExists):
+		response.Text(w, http.StatusNotFound, "Cart not found.")
+	case errors.Is(err, internal.ErrCartLineNotExists):
+		response.Text(w, http.StatusNotFound, "Cart line not found.")
+	case errors.Is(err, internal.ErrProductNotExists):
+		response.Text(w, http.StatusNotFound, "Product not found.")
+	case errors.Is(err, internal.ErrCartExpired):
+		response.Text(w, http.StatusGone, "Cart expired.")
+	case errors.Is(err, internal.ErrEmptyField), errors.Is(err, internal.ErrInvalidCartLine):
+		response.Text(w, http.StatusBadRequest, "Invalid body. "+err.Error())
+	case errors.Is(err, internal.ErrCartClosed):
+		response.Text(w, http.StatusConflict, "Cart closed. "+err.Error())
+	case errors.Is(err, internal.ErrCartOutdated):
+		response.Text(w, http.StatusConflict, "Cart outdated. "+err.Error())
+	case errors.Is(err, internal.ErrProductNotAvailable):
+		response.Text(w, http.StatusConflict, "Product not available. "+err.Error())
+	case errors.Is(err, internal.ErrInsufficientStock):
+		response.Text(w, http.StatusConflict, "Insufficient stock. "+err.Error())
+	default:
+		response.Text(w, http.StatusInternalServerError, "Internal server error.")
+	}
+}
+
+// cartLineParams retrieves the cart id and the product id of a line from the url
+// cartLineParams(w http.ResponseWriter, r *http.Request) -> (int, int, bool)
+// Args:
+//		w: HTTP response writer the error is written to
+//		r: HTTP request
+// Return:
+//		int:  Cart id
+//		int:  Product id
+//		bool: False if the params are invalid (the response is already written)
+
+func cartLineParams(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Text(w, http.StatusBadRequest, "Invalid ID.")
+		return 0, 0, false
+	}
+	productID, err := strconv.Atoi(chi.URLParam(r, "productID"))
+	if err != nil {
+		response.Text(w, http.StatusBadRequest, "Invalid product ID.")
+		return 0, 0, false
+	}
+	return id, productID, true
+}
+
+/* Endpoint function handlers */
+
+// CreateCart creates a new empty cart
+// URL params : none
+// Body params: BodyRequestCartJSON (Optional)
+func (c *CartHandler) CreateCart() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the body from the request */
+		var body BodyRequestCartJSON
+		if r.ContentLength != 0 {
+			if err := request.JSON(r, &body); err != nil {
+				response.Text(w, http.StatusBadRequest, "Invalid body.")
+				return
+			}
+		}
+
+		/* Create the cart */
+		cart := internal.TCart{Customer: body.Customer}
+		if err := c.CartService.CreateCart(&cart); err != nil {
+			cartError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusCreated, map[string]any{
+			"data":    cart,
+			"message": "Cart created successfully.",
+		})
+	}
+}
+
+// GetCartByID returns a cart with its totals and the lines whose price or availability changed
+// URL params:
+//
+//	id (Numeric): ID of the cart.
+func (c *CartHandler) GetCartByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+
+		/* Search the cart */
+		cart, err := c.CartService.GetCartByID(id)
+		if err != nil {
+			cartError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data": cart,
+		})
+	}
+}
+
+// AddLine adds units of a product to a cart
+// URL params : id
+// Body params: BodyRequestCartLineJSON
+func (c *CartHandler) AddLine() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+
+		/* Retrieve the body from the request */
+		var body BodyRequestCartLineJSON
+		if err := request.JSON(r, &body); err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid body.")
+			return
+		}
+
+		/* Add the line */
+		cart, err := c.CartService.AddLine(id, body.ProductID, body.Quantity)
+		if err != nil {
+			cartError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data":    cart,
+			"message": "Line added successfully.",
+		})
+	}
+}
+
+// UpdateLine sets the units of a line taking the live price of the product again
+// URL params : id, productID
+// Body params: BodyRequestCartLineJSON
+func (c *CartHandler) UpdateLine() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the ids from the url */
+		id, productID, ok := cartLineParams(w, r)
+		if !ok {
+			return
+		}
+
+		/* Retrieve the body from the request */
+		var body BodyRequestCartLineJSON
+		if err := request.JSON(r, &body); err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid body.")
+			return
+		}
+
+		/* Update the line */
+		cart, err := c.CartService.UpdateLine(id, productID, body.Quantity)
+		if err != nil {
+			cartError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data":    cart,
+			"message": "Line updated successfully.",
+		})
+	}
+}
+
+// RemoveLine removes a line from a cart
+// URL params : id, productID
+func (c *CartHandler) RemoveLine() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the ids from the url */
+		id, productID, ok := cartLineParams(w, r)
+		if !ok {
+			return
+		}
+
+		/* Remove the line */
+		cart, err := c.CartService.RemoveLine(id, productID)
+		if err != nil {
+			cartError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data":    cart,
+			"message": "Line removed successfully.",
+		})
+	}
+}
+
+// Checkout converts a cart into a placed order
+// URL params : id
+func (c *CartHandler) Checkout() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+
+		/* Check the cart out */
+		order, err := c.CartService.Checkout(id)
+		if err != nil {
+			cartError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusCreated, map[string]any{
+			"data":    order,
+			"message": "Order placed successfully.",
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"proyecto/internal"
+	"proyecto/internal/clock"
+	"proyecto/internal/handlers"
+	"proyecto/internal/repository"
+	"proyecto/internal/service"
+	"proyecto/internal/storage"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// initCartStorage initializes the cart storage
+// initCartStorage(map[int]internal.TCart) -> *storage.CartStorageDefault
+// Args:
+// 	initialCarts: Initial carts
+// Returns:
+// 	*CartStorageDefault: Initialized storage
+
+func initCartStorage(initialCarts map[int]internal.TCart) *storage.CartStorageDefault {
+	/* Storage creation */
+	filepath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/internal/handlers/carts_test.json"
+	storage := storage.NewCartStorageDefault(filepath)
+
+	/* Initial data of the storage */
+	err := storage.WriteAll(initialCarts)
+	if err != nil {
+		panic(err)
+	}
+	return storage
+}
+
+// cartTestHandler returns a cart handler over the given products and carts along with the product repository and the clock
+func cartTestHandler(products map[int]internal.TProduct, carts map[int]internal.TCart) (*handlers.CartHandler, *repository.ProductMap, *clock.ClockFixed) {
+	fixedClock := clock.NewClockFixed(time.Date(2024, 1, 10, 9, 0, 0, 0, time.Local))
+	productStorage := initStorage(products)
+	productRepository := repository.NewProductMap(&productStorage)
+	productService := service.NewProductServiceDefault(productRepository)
+	productService.SetClock(fixedClock)
+	movementService := service.NewMovementServiceDefault(productRepository, repository.NewMovementMap(initMovementStorage(map[int]internal.TMovement{})))
+	orderService := service.NewOrderServiceDefault(repository.NewOrderMap(initOrderStorage(map[int]internal.TOrder{})), productService, movementService)
+	orderService.SetClock(fixedClock)
+	cartService := service.NewCartServiceDefault(repository.NewCartMap(initCartStorage(carts)), productService, orderService, time.Hour)
+	cartService.SetClock(fixedClock)
+	return handlers.NewCartHandler(cartService), productRepository, fixedClock
+}
+
+// cartMapFailingClose is a cart repository whose first updates closing a cart fail as if the storage was down
+type cartMapFailingClose struct {
+	*repository.CartMap
+	failures *int
+}
+
+// UpdateCart fails without saving a cart being closed while failures are left
+func (c cartMapFailingClose) UpdateCart(cart *internal.TCart) error {
+	if cart.Status == internal.CartCheckedOut && *c.failures > 0 {
+		*c.failures--
+		return internal.ErrStorageError
+	}
+	return c.CartMap.UpdateCart(cart)
+}
+
+// TestCartCheckout tests the cart handlers from the first line to the checkout
+func TestCartCheckout(t *testing.T) {
+	// Test 1: should flag a changed price and only check out once it is accepted
+	t.Run("should flag a changed price and only check out once it is accepted", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialProducts := map[int]internal.TProduct{
+			1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: true, Expiration: "11/11/2024", Price: 10.5},
+		}
+		initialCarts := map[int]internal.TCart{
+			1: {ID: 1, Status: internal.CartOpen, Lines: []internal.TCartLine{}, ExpiresAt: time.Date(2024, 1, 10, 10, 0, 0, 0, time.Local)},
+		}
+		handler, productRepository, _ := cartTestHandler(initialProducts, initialCarts)
+
+		/* Add a line snapshotting the price */
+		req := httptest.NewRequest("POST", "/carts/1/lines", strings.NewReader(`{"product_id": 1, "quantity": 2}`))
+		req.Header.Set("Content-Type", "application/json")
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+		handler.AddLine()(res, req)
+		require.Equal(t, http.StatusOK, res.Code)
+
+		/* The price changes */
+		product, err := productRepository.GetProductByID(1)
+		require.NoError(t, err)
+		product.Price = 12
+		require.NoError(t, productRepository.UpdateProduct(&product))
+
+		/* The line is flagged */
+		req = httptest.NewRequest("GET", "/carts/1", nil)
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res = httptest.NewRecorder()
+		handler.GetCartByID()(res, req)
+		var body struct {
+			Data internal.TCart `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Equal(t, []internal.TCartLine{{ProductID: 1, Quantity: 2, UnitPrice: 10.5, CurrentPrice: 12, PriceChanged: true}}, body.Data.Lines)
+		require.Equal(t, 21.0, body.Data.Total)
+		require.Equal(t, 24.0, body.Data.CurrentTotal)
+
+		/* The checkout is refused */
+		req = httptest.NewRequest("POST", "/carts/1/checkout", nil)
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res = httptest.NewRecorder()
+		handler.Checkout()(res, req)
+		require.Equal(t, http.StatusConflict, res.Code)
+		require.Equal(t, "Cart outdated. cart outdated: product 1 changed its price", res.Body.String())
+
+		/* Updating the line accepts the new price */
+		req = httptest.NewRequest("PUT", "/carts/1/lines/1", strings.NewReader(`{"quantity": 3}`))
+		req.Header.Set("Content-Type", "application/json")
+		req = addURLParams(req, map[string]string{"id": "1", "productID": "1"})
+		res = httptest.NewRecorder()
+		handler.UpdateLine()(res, req)
+		require.Equal(t, http.StatusOK, res.Code)
+
+		/* The checkout places the order */
+		req = httptest.NewRequest("POST", "/carts/1/checkout", nil)
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res = httptest.NewRecorder()
+		handler.Checkout()(res, req)
+		var order struct {
+			Data internal.TOrder `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &order))
+		require.Equal(t, http.StatusCreated, res.Code)
+		require.Equal(t, internal.OrderPlaced, order.Data.Status)
+		require.Equal(t, 36.0, order.Data.Total)
+
+		/* The cart is closed */
+		req = httptest.NewRequest("DELETE", "/carts/1/lines/1", nil)
+		req = addURLParams(req, map[string]string{"id": "1", "productID": "1"})
+		res = httptest.NewRecorder()
+		handler.RemoveLine()(res, req)
+		require.Equal(t, http.StatusConflict, res.Code)
+	})
+
+	// Test 2: should refuse a cart left untouched too long
+	t.Run("should refuse a cart left untouched too long", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialCarts := map[int]internal.TCart{
+			1: {ID: 1, Status: internal.CartOpen, Lines: []internal.TCartLine{}, ExpiresAt: time.Date(2024, 1, 10, 10, 0, 0, 0, time.Local)},
+		}
+		handler, _, fixedClock := cartTestHandler(map[int]internal.TProduct{}, initialCarts)
+		fixedClock.Advance(2 * time.Hour)
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("GET", "/carts/1", nil)
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+		handler.GetCartByID()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusGone, res.Code)
+		require.Equal(t, "Cart expired.", res.Body.String())
+	})
+
+	// Test 3: should resume the order placed by a checkout which could not close the cart
+	t.Run("should resume the order placed by a checkout which could not close the cart", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialProducts := map[int]internal.TProduct{
+			1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: true, Expiration: "11/11/2024", Price: 10.5},
+		}
+		initialCarts := map[int]internal.TCart{
+			1: {ID: 1, Customer: "Jane", Status: internal.CartOpen, Lines: []internal.TCartLine{{ProductID: 1, Quantity: 2, UnitPrice: 10.5}}, ExpiresAt: time.Date(2024, 1, 10, 10, 0, 0, 0, time.Local)},
+		}
+		fixedClock := clock.NewClockFixed(time.Date(2024, 1, 10, 9, 0, 0, 0, time.Local))
+		productStorage := initStorage(initialProducts)
+		productRepository := repository.NewProductMap(&productStorage)
+		productService := service.NewProductServiceDefault(productRepository)
+		productService.SetClock(fixedClock)
+		movementService := service.NewMovementServiceDefault(productRepository, repository.NewMovementMap(initMovementStorage(map[int]internal.TMovement{})))
+		orderService := service.NewOrderServiceDefault(repository.NewOrderMap(initOrderStorage(map[int]internal.TOrder{})), productService, movementService)
+		orderService.SetClock(fixedClock)
+		failures := 1
+		cartRepository := cartMapFailingClose{repository.NewCartMap(initCartStorage(initialCarts)), &failures}
+		cartService := service.NewCartServiceDefault(cartRepository, productService, orderService, time.Hour)
+		cartService.SetClock(fixedClock)
+		handler := handlers.NewCartHandler(cartService)
+
+		/* The order is placed but the cart can't be closed */
+		req := httptest.NewRequest("POST", "/carts/1/checkout", nil)
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+		handler.Checkout()(res, req)
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+
+		/* The retry closes the cart with the same order */
+		req = httptest.NewRequest("POST", "/carts/1/checkout", nil)
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res = httptest.NewRecorder()
+		handler.Checkout()(res, req)
+		var order struct {
+			Data internal.TOrder `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &order))
+
+		/* Assertions */
+		require.Equal(t, http.StatusCreated, res.Code)
+		require.Equal(t, 1, order.Data.ID)
+		require.Equal(t, internal.OrderPlaced, order.Data.Status)
+		orders, err := orderService.GetAllOrders()
+		require.NoError(t, err)
+		require.Len(t, orders, 1)
+		cart, err := cartService.GetCartByID(1)
+		require.NoError(t, err)
+		require.Equal(t, internal.CartCheckedOut, cart.Status)
+		require.Equal(t, 1, cart.OrderID)
+	})
+}
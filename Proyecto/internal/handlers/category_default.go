@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"proyecto/internal"
+	"proyecto/platform/web/request"
+	"proyecto/platform/web/response"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+/* Category handler definition */
+type CategoryHandler struct {
+	CategoryService internal.CategoryService // Category service instance
+}
+
+// NewCategoryHandler creates a new default valued CategoryHandler
+// NewCategoryHandler(cs internal.CategoryService) -> *CategoryHandler
+// Args:
+//		cs: Category service instance
+// Return:
+//		*CategoryHandler: New CategoryHandler instance
+
+func NewCategoryHandler(cs internal.CategoryService) *CategoryHandler {
+	return &CategoryHandler{
+		CategoryService: cs,
+	}
+}
+
+// BodyRequestCategoryJSON is the body request for a category in JSON format
+type BodyRequestCategoryJSON struct {
+	Name     string `json:"name"`      // Category name.
+	ParentID *int   `json:"parent_id"` // Parent category id. (Optional)
+}
+
+// BodyRequestProductCategoriesJSON is the body request for the categories of a product in JSON format
+type BodyRequestProductCategoriesJSON struct {
+	CategoryIDs []int `json:"category_ids"` // Categories of the product.
+}
+
+// categoryError writes the response for an error raised by the category service
+// categoryError(w http.ResponseWriter, err error)
+// Args:
+//		w:   HTTP response writer
+//		err: Error raised by the service
+
+func categoryError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, internal.ErrCategoryNotExists):
+		response.Text(w, http.StatusNotFound, "Category not found.")
+	case errors.Is(err, internal.ErrProductNotExists):
+		response.Text(w, http.StatusNotFound, "Product not found.")
+	case errors.Is(err, internal.ErrEmptyField), errors.Is(err, internal.ErrInvalidCategoryParent):
+		response.Text(w, http.StatusBadRequest, "Invalid body. "+err.Error())
+	case errors.Is(err, internal.ErrCategoryAlreadyExists):
+		response.Text(w, http.StatusConflict, "Category already exists.")
+	case errors.Is(err, internal.ErrCategoryCycle):
+		response.Text(w, http.StatusConflict, "Category hierarchy cycle.")
+	case errors.Is(err, internal.ErrCategoryHasProducts):
+		response.Text(w, http.StatusConflict, "Category has products.")
+	default:
+		response.Text(w, http.StatusInternalServerError, "Internal server error.")
+	}
+}
+
+/* Endpoint function handlers */
+
+// GetAllCategories returns all the categories of the taxonomy
+// URL params: none
+func (c *CategoryHandler) GetAllCategories() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		categories, err := c.CategoryService.GetAllCategories()
+		if err != nil {
+			categoryError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data": categories,
+		})
+	}
+}
+
+// GetCategoryByID search a category by ID and return if there is a match.
+// URL params:
+//
+//	id (Numeric): ID of the category.
+func (c *CategoryHandler) GetCategoryByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+
+		/* Search the category by id */
+		category, err := c.CategoryService.GetCategoryByID(id)
+		if err != nil {
+			categoryError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data": category,
+		})
+	}
+}
+
+// AddNewCategory creates a new category
+// URL params : none
+// Body params: BodyRequestCategoryJSON
+func (c *CategoryHandler) AddNewCategory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the body from the request */
+		var body BodyRequestCategoryJSON
+		if err := request.JSON(r, &body); err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid body.")
+			return
+		}
+
+		/* Insert the new category */
+		category := internal.TCategory{Name: body.Name, ParentID: body.ParentID}
+		if err := c.CategoryService.InsertNewCategory(&category); err != nil {
+			categoryError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusCreated, map[string]any{
+			"data":    category,
+			"message": "Category created successfully.",
+		})
+	}
+}
+
+// UpdateCategory updates the name and the parent of a category
+// URL params : id
+// Body params: BodyRequestCategoryJSON
+func (c *CategoryHandler) UpdateCategory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+
+		/* Retrieve the body from the request */
+		var body BodyRequestCategoryJSON
+		if err := request.JSON(r, &body); err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid body.")
+			return
+		}
+
+		/* Update the category */
+		category := internal.TCategory{ID: id, Name: body.Name, ParentID: body.ParentID}
+		if err := c.CategoryService.UpdateCategory(&category); err != nil {
+			categoryError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data":    category,
+			"message": "Category updated successfully.",
+		})
+	}
+}
+
+// DeleteCategory deletes a category following the configured delete policy
+// URL params : id
+func (c *CategoryHandler) DeleteCategory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+
+		/* Delete the category */
+		if err := c.CategoryService.DeleteCategory(id); err != nil {
+			categoryError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// GetProductsByCategory returns the products of a category
+// URL params:
+//
+//	id (Numeric): ID of the category.
+//	include_descendants (Boolean): Also return the products of the subcategories. (Optional)
+func (c *CategoryHandler) GetProductsByCategory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the params from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+		includeDescendants := false
+		if value := r.URL.Query().Get("include_descendants"); value != "" {
+			includeDescendants, err = strconv.ParseBool(value)
+			if err != nil {
+				response.Text(w, http.StatusBadRequest, "Invalid include_descendants.")
+				return
+			}
+		}
+
+		/* Search the products */
+		products, err := c.CategoryService.GetProductsByCategory(id, includeDescendants)
+		if err != nil {
+			categoryError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data": products,
+		})
+	}
+}
+
+// GetProductCategories returns the categories of a product
+// URL params:
+//
+//	id (Numeric): ID of the product.
+func (c *CategoryHandler) GetProductCategories() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := urlID(r, "id")
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Search the categories */
+		categories, err := c.CategoryService.GetCategoriesByProduct(id)
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data": categories,
+		})
+	}
+}
+
+// UpdateProductCategories replaces the categories of a product
+// URL params : id
+// Body params: BodyRequestProductCategoriesJSON
+func (c *CategoryHandler) UpdateProductCategories() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := urlID(r, "id")
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Retrieve the body from the request */
+		var body BodyRequestProductCategoriesJSON
+		if err := request.JSON(r, &body); err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Assign the categories */
+		if err := c.CategoryService.AssignProductCategories(id, body.CategoryIDs); err != nil {
+			productError(w, r, err)
+			return
+		}
+		categories, err := c.CategoryService.GetCategoriesByProduct(id)
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data":    categories,
+			"message": "Product categories updated successfully.",
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"proyecto/internal"
+	"proyecto/internal/handlers"
+	"proyecto/internal/repository"
+	"proyecto/internal/service"
+	"proyecto/internal/storage"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// initCategoryStorage initializes the category storage
+// initCategoryStorage(map[int]internal.TCategory) -> *storage.CategoryStorageDefault
+// Args:
+// 	initialCategories: Initial categories
+// Returns:
+// 	*CategoryStorageDefault: Initialized storage
+
+func initCategoryStorage(initialCategories map[int]internal.TCategory) *storage.CategoryStorageDefault {
+	/* Storage creation */
+	filepath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/internal/handlers/categories_test.json"
+	storage := storage.NewCategoryStorageDefault(filepath)
+
+	/* Initial data of the storage */
+	err := storage.WriteAll(initialCategories)
+	if err != nil {
+		panic(err)
+	}
+	return storage
+}
+
+// categoryTestData returns a small taxonomy: Food > Dairy > Cheese, and Drinks
+func categoryTestData() (map[int]internal.TProduct, map[int]internal.TCategory) {
+	food, dairy := 1, 2
+	products := map[int]internal.TProduct{
+		1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: false, Expiration: "11/11/2001", Price: 10.5},
+		2: {ID: 2, Name: "Product 2", Quantity: 20, CodeValue: "AX02", IsPublished: true, Expiration: "11/11/2002", Price: 20.5},
+		3: {ID: 3, Name: "Product 3", Quantity: 30, CodeValue: "AX03", IsPublished: true, Expiration: "11/11/2003", Price: 30.5},
+	}
+	categories := map[int]internal.TCategory{
+		1: {ID: 1, Name: "Food", ProductIDs: []int{1}},
+		2: {ID: 2, Name: "Dairy", ParentID: &food, ProductIDs: []int{2}},
+		3: {ID: 3, Name: "Cheese", ParentID: &dairy, ProductIDs: []int{3}},
+		4: {ID: 4, Name: "Drinks", ProductIDs: []int{}},
+	}
+	return products, categories
+}
+
+// TestGetProductsByCategory tests the GetProductsByCategory handler
+func TestGetProductsByCategory(t *testing.T) {
+	// Test 1: should include the products of the descendant categories
+	t.Run("should include the products of the descendant categories", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialProducts, initialCategories := categoryTestData()
+		productStorage := initStorage(initialProducts)
+		productService := service.NewProductServiceDefault(repository.NewProductMap(&productStorage))
+		categoryRepository := repository.NewCategoryMap(initCategoryStorage(initialCategories))
+		handler := handlers.NewCategoryHandler(service.NewCategoryServiceDefault(categoryRepository, productService, internal.CategoryDeleteBlock))
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("GET", "/categories/2/products?include_descendants=true", nil)
+		req = addURLParams(req, map[string]string{"id": "2"})
+		res := httptest.NewRecorder()
+
+		handler.GetProductsByCategory()(res, req)
+
+		/* Expected values definition */
+		expectedCode := http.StatusOK
+		expectedBody := `{"data": [
+			{"id": 2, "name": "Product 2", "quantity": 20, "code_value": "AX02", "is_published": true, "expiration": "11/11/2002", "price": 20.5},
+			{"id": 3, "name": "Product 3", "quantity": 30, "code_value": "AX03", "is_published": true, "expiration": "11/11/2003", "price": 30.5}
+		]}`
+
+		/* Assertions */
+		require.Equal(t, expectedCode, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+	})
+}
+
+// TestUpdateCategory tests the UpdateCategory handler
+func TestUpdateCategory(t *testing.T) {
+	// Test 1: should reject a parent which creates a cycle
+	t.Run("should reject a parent which creates a cycle", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialProducts, initialCategories := categoryTestData()
+		productStorage := initStorage(initialProducts)
+		productService := service.NewProductServiceDefault(repository.NewProductMap(&productStorage))
+		categoryRepository := repository.NewCategoryMap(initCategoryStorage(initialCategories))
+		handler := handlers.NewCategoryHandler(service.NewCategoryServiceDefault(categoryRepository, productService, internal.CategoryDeleteBlock))
+
+		/* Prepare the request and the response */
+		reqBody := `{"name": "Food", "parent_id": 3}`
+		req := httptest.NewRequest("PUT", "/categories/1", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+
+		handler.UpdateCategory()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusConflict, res.Code)
+		require.Equal(t, "Category hierarchy cycle.", res.Body.String())
+	})
+}
+
+// TestDeleteCategory tests the DeleteCategory handler
+func TestDeleteCategory(t *testing.T) {
+	// Test 1: should block the delete of a category with products
+	t.Run("should block the delete of a category with products", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialProducts, initialCategories := categoryTestData()
+		productStorage := initStorage(initialProducts)
+		productService := service.NewProductServiceDefault(repository.NewProductMap(&productStorage))
+		categoryRepository := repository.NewCategoryMap(initCategoryStorage(initialCategories))
+		handler := handlers.NewCategoryHandler(service.NewCategoryServiceDefault(categoryRepository, productService, internal.CategoryDeleteBlock))
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("DELETE", "/categories/2", nil)
+		req = addURLParams(req, map[string]string{"id": "2"})
+		res := httptest.NewRecorder()
+
+		handler.DeleteCategory()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusConflict, res.Code)
+		require.Equal(t, "Category has products.", res.Body.String())
+	})
+
+	// Test 2: should reassign the products and the children to the parent
+	t.Run("should reassign the products and the children to the parent", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialProducts, initialCategories := categoryTestData()
+		productStorage := initStorage(initialProducts)
+		productService := service.NewProductServiceDefault(repository.NewProductMap(&productStorage))
+		categoryRepository := repository.NewCategoryMap(initCategoryStorage(initialCategories))
+		handler := handlers.NewCategoryHandler(service.NewCategoryServiceDefault(categoryRepository, productService, internal.CategoryDeleteReassign))
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("DELETE", "/categories/2", nil)
+		req = addURLParams(req, map[string]string{"id": "2"})
+		res := httptest.NewRecorder()
+
+		handler.DeleteCategory()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusNoContent, res.Code)
+		food, err := categoryRepository.GetCategoryByID(1)
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 2}, food.ProductIDs)
+		cheese, err := categoryRepository.GetCategoryByID(3)
+		require.NoError(t, err)
+		require.Equal(t, 1, *cheese.ParentID)
+	})
+	// Test 3: should delete a category left empty by the delete of its product
+	t.Run("should delete a category left empty by the delete of its product", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialProducts, initialCategories := categoryTestData()
+		productStorage := initStorage(initialProducts)
+		productService := service.NewProductServiceDefault(repository.NewProductMap(&productStorage))
+		categoryRepository := repository.NewCategoryMap(initCategoryStorage(initialCategories))
+		categoryService := service.NewCategoryServiceDefault(categoryRepository, productService, internal.CategoryDeleteBlock)
+		productService.AddDependent(categoryService)
+		productHandler := handlers.NewProductHandler(productService)
+		handler := handlers.NewCategoryHandler(categoryService)
+
+		/* Delete the only product of Cheese */
+		req := httptest.NewRequest("DELETE", "/products/3", nil)
+		req = addURLParams(req, map[string]string{"id": "3"})
+		res := httptest.NewRecorder()
+		productHandler.DeleteProduct()(res, req)
+		require.Equal(t, http.StatusNoContent, res.Code)
+
+		/* Prepare the request and the response */
+		req = httptest.NewRequest("DELETE", "/categories/3", nil)
+		req = addURLParams(req, map[string]string{"id": "3"})
+		res = httptest.NewRecorder()
+
+		handler.DeleteCategory()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusNoContent, res.Code)
+		_, err := categoryRepository.GetCategoryByID(3)
+		require.ErrorIs(t, err, internal.ErrCategoryNotFound)
+		dairy, err := categoryRepository.GetCategoryByID(2)
+		require.NoError(t, err)
+		require.Equal(t, []int{2}, dairy.ProductIDs)
+	})
+}
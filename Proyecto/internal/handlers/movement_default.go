@@ -0,0 +1,333 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"proyecto/internal"
+	"proyecto/platform/web/request"
+	"proyecto/platform/web/response"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+/* Movement handler definition */
+type MovementHandler struct {
+	MovementService internal.MovementService // Movement service instance
+}
+
+// NewMovementHandler creates a new default valued MovementHandler
+// NewMovementHandler(ms internal.MovementService) -> *MovementHandler
+// Args:
+//		ms: Movement service instance
+// Return:
+//		*MovementHandler: New MovementHandler instance
+
+func NewMovementHandler(ms internal.MovementService) *MovementHandler {
+	return &MovementHandler{
+		MovementService: ms,
+	}
+}
+
+// BodyRequestMovementJSON is the body request for a stock movement in JSON format
+type BodyRequestMovementJSON struct {
+	Type        string `json:"type"`         // Movement type: receipt, sale, write_off or correction.
+	Quantity    int    `json:"quantity"`     // Movement quantity. Signed only for corrections.
+	Reason      string `json:"reason"`       // Reason of the movement.
+	Reference   string `json:"reference"`    // External reference (invoice, order, count...). (Optional)
+	WarehouseID int    `json:"warehouse_id"` // Warehouse whose stock is affected. (Optional, default warehouse)
+	LotID       int    `json:"lot_id"`       // Lot an outbound movement draws from. (Optional, chosen by the lot policy)
+	Expiration  string `json:"expiration"`   // Expiration of the lot a receipt creates with the format dd/mm/yyyy.
+}
+
+// BodyRequestTransferJSON is the body request for a stock transfer in JSON format
+type BodyRequestTransferJSON struct {
+	FromWarehouseID int    `json:"from_warehouse_id"` // Warehouse the stock leaves.
+	ToWarehouseID   int    `json:"to_warehouse_id"`   // Warehouse the stock arrives at.
+	Quantity        int    `json:"quantity"`          // Quantity to move.
+	Reason          string `json:"reason"`            // Reason of the transfer.
+	Reference       string `json:"reference"`         // External reference (delivery note...). (Optional)
+}
+
+// movementError writes the response for an error raised by the movement service
+// movementError(w http.ResponseWriter, err error)
+// Args:
+//		w:   HTTP response writer
+//		err: Error raised by the service
+
+func movementError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, internal.ErrProductNotExists):
+		response.Text(w, http.StatusNotFound, "Product not found.")
+	case errors.Is(err, internal.ErrWarehouseNotExists):
+		response.Text(w, http.StatusNotFound, "Warehouse not found.")
+	case errors.Is(err, internal.ErrLotNotExists):
+		response.Text(w, http.StatusNotFound, "Lot not found.")
+	case errors.Is(err, internal.ErrInvalidMovementType), errors.Is(err, internal.ErrInvalidMovementQuantity),
+		errors.Is(err, internal.ErrEmptyField), errors.Is(err, internal.ErrInvalidTransfer),
+		errors.Is(err, internal.ErrInvalidDate):
+		response.Text(w, http.StatusBadRequest, "Invalid body. "+err.Error())
+	case errors.Is(err, internal.ErrInsufficientStock):
+		response.Text(w, http.StatusConflict, "Insufficient stock.")
+	default:
+		response.Text(w, http.StatusInternalServerError, "Internal server error.")
+	}
+}
+
+// parseDateParam parses an optional date query param with the format dd/mm/yyyy
+// parseDateParam(r *http.Request, name string) -> (time.Time, error)
+// Args:
+//		r: 	  HTTP request
+//		name: Query param name
+// Return:
+//		time.Time: Parsed date (zero value if the param is missing)
+//		error: 	   Error raised during the execution (if exists)
+
+func parseDateParam(r *http.Request, name string) (time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation("02/01/2006", value, time.Local)
+}
+
+/* Endpoint function handlers */
+
+// PostMovement records a stock movement of a product
+// URL params : id
+// Body params: BodyRequestMovementJSON
+func (m *MovementHandler) PostMovement() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := urlID(r, "id")
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Retrieve the body from the request */
+		var body BodyRequestMovementJSON
+		if err := request.JSON(r, &body); err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Record the movement */
+		movement := internal.TMovement{
+			ProductID:   id,
+			WarehouseID: body.WarehouseID,
+			Type:        body.Type,
+			Quantity:    body.Quantity,
+			Reason:      body.Reason,
+			Reference:   body.Reference,
+			LotID:       body.LotID,
+			Expiration:  body.Expiration,
+		}
+		if err := m.MovementService.PostMovement(&movement); err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Send the movement as response */
+		response.JSON(w, http.StatusCreated, map[string]any{
+			"data":    movement,
+			"message": "Movement recorded successfully.",
+		})
+	}
+}
+
+// GetMovementsByProduct returns the movements of a product
+// URL params:
+//
+//	id (Numeric): ID of the product.
+//	from (dd/mm/yyyy): First day of the range. (Optional)
+//	to (dd/mm/yyyy): Last day of the range. (Optional)
+func (m *MovementHandler) GetMovementsByProduct() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the params from the url */
+		id, err := urlID(r, "id")
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+		from, err := parseDateParam(r, "from")
+		if err != nil {
+			productError(w, r, fmt.Errorf("%w: from", ErrInvalidQuery))
+			return
+		}
+		to, err := parseDateParam(r, "to")
+		if err != nil {
+			productError(w, r, fmt.Errorf("%w: to", ErrInvalidQuery))
+			return
+		}
+		if !to.IsZero() {
+			to = to.AddDate(0, 0, 1) // The last day is included
+		}
+
+		/* Search the movements */
+		movements, err := m.MovementService.GetMovementsByProduct(id, from, to)
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Send the movements as response */
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data": movements,
+		})
+	}
+}
+
+// GetReconciliation returns the products whose stored quantity drifted from the ledger
+// URL params: none
+func (m *MovementHandler) GetReconciliation() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		drifts, err := m.MovementService.Reconcile()
+		if err != nil {
+			response.Text(w, http.StatusInternalServerError, "Internal server error.")
+			return
+		}
+
+		/* Send the drifts as response */
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data": drifts,
+		})
+	}
+}
+
+// TransferStock moves stock of a product between two warehouses
+// URL params : id
+// Body params: BodyRequestTransferJSON
+func (m *MovementHandler) TransferStock() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := urlID(r, "id")
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Retrieve the body from the request */
+		var body BodyRequestTransferJSON
+		if err := request.JSON(r, &body); err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Record the transfer */
+		movements, err := m.MovementService.TransferStock(internal.TTransfer{
+			ProductID:       id,
+			FromWarehouseID: body.FromWarehouseID,
+			ToWarehouseID:   body.ToWarehouseID,
+			Quantity:        body.Quantity,
+			Reason:          body.Reason,
+			Reference:       body.Reference,
+		})
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusCreated, map[string]any{
+			"data":    movements,
+			"message": "Transfer recorded successfully.",
+		})
+	}
+}
+
+// GetStockLevels returns the stock of a product by warehouse along with the total
+// URL params:
+//
+//	id (Numeric): ID of the product.
+func (m *MovementHandler) GetStockLevels() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := urlID(r, "id")
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Search the stock levels */
+		levels, err := m.MovementService.GetStockLevels(id)
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+		var total int
+		for _, level := range levels {
+			total += level.Quantity
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data":  levels,
+			"total": total,
+		})
+	}
+}
+
+// GetWarehouseStock returns the stock of every product in a warehouse
+// URL params:
+//
+//	id (Numeric): ID of the warehouse.
+func (m *MovementHandler) GetWarehouseStock() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+
+		/* Search the stock levels */
+		levels, err := m.MovementService.GetWarehouseStock(id)
+		if err != nil {
+			movementError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data": levels,
+		})
+	}
+}
+
+// GetLotsByProduct returns the lots of a product still in stock in the order they are consumed
+// URL params:
+//
+//	id (Numeric): ID of the product.
+func (m *MovementHandler) GetLotsByProduct() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := urlID(r, "id")
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Search the lots */
+		lots, err := m.MovementService.GetLotsByProduct(id)
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data": lots,
+		})
+	}
+}
+
+// WriteOffExpiredLots writes off the stock left in the expired lots
+// URL params: none
+func (m *MovementHandler) WriteOffExpiredLots() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		movements, err := m.MovementService.WriteOffExpiredLots()
+		if err != nil {
+			movementError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data":    movements,
+			"message": "Expired lots written off successfully.",
+		})
+	}
+}
@@ -0,0 +1,349 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"proyecto/internal"
+	"proyecto/internal/clock"
+	"proyecto/internal/handlers"
+	"proyecto/internal/repository"
+	"proyecto/internal/service"
+	"proyecto/internal/storage"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// initMovementStorage initializes the movement storage
+// initMovementStorage(map[int]internal.TMovement) -> *storage.MovementStorageDefault
+// Args:
+// 	initialMovements: Initial movements
+// Returns:
+// 	*MovementStorageDefault: Initialized storage
+
+func initMovementStorage(initialMovements map[int]internal.TMovement) *storage.MovementStorageDefault {
+	/* Storage creation */
+	filepath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/internal/handlers/movements_test.json"
+	storage := storage.NewMovementStorageDefault(filepath)
+
+	/* Initial data of the storage */
+	err := storage.WriteAll(initialMovements)
+	if err != nil {
+		panic(err)
+	}
+	return storage
+}
+
+// TestPostMovement test the PostMovement handler
+func TestPostMovement(t *testing.T) {
+	// Test 1: should record a movement and update the product quantity
+	t.Run("should record a movement and update the product quantity", func(t *testing.T) {
+		/* Prepare the test data */
+		initialProducts := map[int]internal.TProduct{
+			1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: false, Expiration: "11/11/2001", Price: 10.5},
+		}
+
+		/* Initialize dependencies */
+		productStorage := initStorage(initialProducts)
+		productRepository := repository.NewProductMap(&productStorage)
+		movementRepository := repository.NewMovementMap(initMovementStorage(map[int]internal.TMovement{}))
+		service := service.NewMovementServiceDefault(productRepository, movementRepository)
+		handler := handlers.NewMovementHandler(service)
+
+		/* Prepare the request and the response */
+		reqBody := `{"type": "sale", "quantity": 4, "reason": "counter sale", "reference": "ticket-77"}`
+		req := httptest.NewRequest("POST", "/products/1/movements", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+
+		handler.PostMovement()(res, req)
+
+		/* Expected values definition */
+		expectedCode := http.StatusCreated
+		var body struct {
+			Data internal.TMovement `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+
+		/* Assertions */
+		require.Equal(t, expectedCode, res.Code)
+		require.Equal(t, 2, body.Data.ID) // The opening balance takes the first id
+		require.Equal(t, -4, body.Data.Quantity)
+		require.Equal(t, "ticket-77", body.Data.Reference)
+		product, err := productRepository.GetProductByID(1)
+		require.NoError(t, err)
+		require.Equal(t, 6, product.Quantity)
+		movements, err := movementRepository.GetMovementsByProduct(1, time.Time{}, time.Time{})
+		require.NoError(t, err)
+		require.Len(t, movements, 2)
+		require.Equal(t, "opening balance", movements[0].Reason)
+	})
+
+	// Test 2: should return a conflict error when the stock is not enough
+	t.Run("should return a conflict error when the stock is not enough", func(t *testing.T) {
+		/* Prepare the test data */
+		initialProducts := map[int]internal.TProduct{
+			1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: false, Expiration: "11/11/2001", Price: 10.5},
+		}
+
+		/* Initialize dependencies */
+		productStorage := initStorage(initialProducts)
+		productRepository := repository.NewProductMap(&productStorage)
+		movementRepository := repository.NewMovementMap(initMovementStorage(map[int]internal.TMovement{}))
+		service := service.NewMovementServiceDefault(productRepository, movementRepository)
+		handler := handlers.NewMovementHandler(service)
+
+		/* Prepare the request and the response */
+		reqBody := `{"type": "write_off", "quantity": 11, "reason": "broken"}`
+		req := httptest.NewRequest("POST", "/products/1/movements", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+
+		handler.PostMovement()(res, req)
+
+		/* Expected values definition */
+		expectedCode := http.StatusConflict
+		expectedBody := `{"type":"/problems/insufficient_stock","code":"insufficient_stock","title":"Insufficient stock","status":409,"detail":"insufficient stock","instance":"/products/1/movements"}`
+
+		/* Assertions */
+		require.Equal(t, expectedCode, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+		require.Equal(t, "application/problem+json", res.Header().Get("Content-Type"))
+	})
+
+	// Test 3: should return a bad request error for an unknown type
+	t.Run("should return a bad request error for an unknown type", func(t *testing.T) {
+		/* Prepare the test data */
+		initialProducts := map[int]internal.TProduct{
+			1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: false, Expiration: "11/11/2001", Price: 10.5},
+		}
+
+		/* Initialize dependencies */
+		productStorage := initStorage(initialProducts)
+		productRepository := repository.NewProductMap(&productStorage)
+		movementRepository := repository.NewMovementMap(initMovementStorage(map[int]internal.TMovement{}))
+		service := service.NewMovementServiceDefault(productRepository, movementRepository)
+		handler := handlers.NewMovementHandler(service)
+
+		/* Prepare the request and the response */
+		reqBody := `{"type": "gift", "quantity": 1, "reason": "promo"}`
+		req := httptest.NewRequest("POST", "/products/1/movements", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+
+		handler.PostMovement()(res, req)
+
+		/* Expected values definition */
+		expectedCode := http.StatusBadRequest
+		expectedBody := `{"type":"/problems/invalid_movement_type","code":"invalid_movement_type","title":"Invalid movement type","status":400,"detail":"invalid movement type","instance":"/products/1/movements"}`
+
+		/* Assertions */
+		require.Equal(t, expectedCode, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+	})
+}
+
+// TestGetReconciliation test the GetReconciliation handler
+func TestGetReconciliation(t *testing.T) {
+	// Test 1: should flag the products that drifted from the ledger
+	t.Run("should flag the products that drifted from the ledger", func(t *testing.T) {
+		/* Prepare the test data */
+		date := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+		initialProducts := map[int]internal.TProduct{
+			1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: false, Expiration: "11/11/2001", Price: 10.5},
+			2: {ID: 2, Name: "Product 2", Quantity: 25, CodeValue: "AX02", IsPublished: true, Expiration: "11/11/2002", Price: 20.5},
+		}
+		initialMovements := map[int]internal.TMovement{
+			1: {ID: 1, ProductID: 1, Type: "receipt", Quantity: 10, Reason: "initial stock", Date: date},
+			2: {ID: 2, ProductID: 2, Type: "receipt", Quantity: 20, Reason: "initial stock", Date: date},
+		}
+
+		/* Initialize dependencies */
+		productStorage := initStorage(initialProducts)
+		productRepository := repository.NewProductMap(&productStorage)
+		movementRepository := repository.NewMovementMap(initMovementStorage(initialMovements))
+		service := service.NewMovementServiceDefault(productRepository, movementRepository)
+		handler := handlers.NewMovementHandler(service)
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("GET", "/movements/reconciliation", nil)
+		res := httptest.NewRecorder()
+
+		handler.GetReconciliation()(res, req)
+
+		/* Expected values definition */
+		expectedCode := http.StatusOK
+		expectedBody := `{"data": [
+			{"product_id": 2, "stored_quantity": 25, "ledger_quantity": 20, "drift": 5}
+		]}`
+		expectedHeader := http.Header{"Content-Type": []string{"application/json"}}
+
+		/* Assertions */
+		require.Equal(t, expectedCode, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+		require.Equal(t, expectedHeader, res.Header())
+	})
+}
+
+// initLotStorage initializes the lot storage
+// initLotStorage(map[int]internal.TLot) -> *storage.LotStorageDefault
+// Args:
+// 	initialLots: Initial lots
+// Returns:
+// 	*LotStorageDefault: Initialized storage
+
+func initLotStorage(initialLots map[int]internal.TLot) *storage.LotStorageDefault {
+	/* Storage creation */
+	filepath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/internal/handlers/lots_test.json"
+	storage := storage.NewLotStorageDefault(filepath)
+
+	/* Initial data of the storage */
+	err := storage.WriteAll(initialLots)
+	if err != nil {
+		panic(err)
+	}
+	return storage
+}
+
+// TestGetLotsByProduct tests the lot consumption through the GetLotsByProduct handler
+func TestGetLotsByProduct(t *testing.T) {
+	// Test 1: should consume the lots expiring first and derive the product expiration
+	t.Run("should consume the lots expiring first and derive the product expiration", func(t *testing.T) {
+		/* Prepare the test data */
+		initialProducts := map[int]internal.TProduct{
+			1: {ID: 1, Name: "Product 1", Quantity: 0, CodeValue: "AX01", IsPublished: true, Expiration: "11/11/2024", Price: 10.5},
+		}
+
+		/* Initialize dependencies */
+		productStorage := initStorage(initialProducts)
+		productRepository := repository.NewProductMap(&productStorage)
+		movementRepository := repository.NewMovementMap(initMovementStorage(map[int]internal.TMovement{}))
+		service := service.NewMovementServiceDefault(productRepository, movementRepository)
+		service.SetClock(clock.NewClockFixed(time.Date(2025, 1, 10, 9, 0, 0, 0, time.Local)))
+		service.SetLots(repository.NewLotMap(initLotStorage(map[int]internal.TLot{})), internal.LotPolicyFEFO)
+		handler := handlers.NewMovementHandler(service)
+
+		/* Two receipts, the second one expiring first */
+		for _, expiration := range []string{"30/06/2025", "31/03/2025"} {
+			movement := internal.TMovement{ProductID: 1, Type: internal.MovementReceipt, Quantity: 10, Reason: "purchase", Expiration: expiration}
+			require.NoError(t, service.PostMovement(&movement))
+		}
+		sale := internal.TMovement{ProductID: 1, Type: internal.MovementSale, Quantity: 12, Reason: "counter sale"}
+		require.NoError(t, service.PostMovement(&sale))
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("GET", "/products/1/lots", nil)
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+
+		handler.GetLotsByProduct()(res, req)
+
+		/* Expected values definition */
+		expectedCode := http.StatusOK
+		expectedBody := `{"data": [
+			{"id": 1, "product_id": 1, "warehouse_id": 1, "quantity": 8, "received_quantity": 10, "expiration": "30/06/2025",
+			 "received_at": "` + time.Date(2025, 1, 10, 9, 0, 0, 0, time.Local).Format(time.RFC3339Nano) + `", "movement_id": 1}
+		]}`
+
+		/* Assertions */
+		require.Equal(t, expectedCode, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+		product, err := productRepository.GetProductByID(1)
+		require.NoError(t, err)
+		require.Equal(t, 8, product.Quantity)
+		require.Equal(t, "30/06/2025", product.Expiration)
+	})
+
+	// Test 2: should reject a receipt without expiration
+	t.Run("should reject a receipt without expiration", func(t *testing.T) {
+		/* Prepare the test data */
+		initialProducts := map[int]internal.TProduct{
+			1: {ID: 1, Name: "Product 1", Quantity: 0, CodeValue: "AX01", IsPublished: true, Expiration: "11/11/2024", Price: 10.5},
+		}
+
+		/* Initialize dependencies */
+		productStorage := initStorage(initialProducts)
+		productRepository := repository.NewProductMap(&productStorage)
+		movementRepository := repository.NewMovementMap(initMovementStorage(map[int]internal.TMovement{}))
+		service := service.NewMovementServiceDefault(productRepository, movementRepository)
+		service.SetLots(repository.NewLotMap(initLotStorage(map[int]internal.TLot{})), internal.LotPolicyFIFO)
+		handler := handlers.NewMovementHandler(service)
+
+		/* Prepare the request and the response */
+		reqBody := `{"type": "receipt", "quantity": 10, "reason": "purchase"}`
+		req := httptest.NewRequest("POST", "/products/1/movements", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+
+		handler.PostMovement()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		require.Contains(t, res.Body.String(), `"code":"empty_field"`)
+	})
+}
+
+// TestWriteOffExpiredLots tests the WriteOffExpiredLots handler
+func TestWriteOffExpiredLots(t *testing.T) {
+	// Test 1: should write off only the stock left in the expired lots
+	t.Run("should write off only the stock left in the expired lots", func(t *testing.T) {
+		/* Prepare the test data */
+		receivedAt := time.Date(2025, 1, 10, 9, 0, 0, 0, time.Local)
+		initialProducts := map[int]internal.TProduct{
+			1: {ID: 1, Name: "Product 1", Quantity: 15, CodeValue: "AX01", IsPublished: true, Expiration: "31/01/2025", Price: 10.5},
+		}
+		initialMovements := map[int]internal.TMovement{
+			1: {ID: 1, ProductID: 1, WarehouseID: 1, Type: internal.MovementReceipt, Quantity: 5, Reason: "purchase", Date: receivedAt},
+			2: {ID: 2, ProductID: 1, WarehouseID: 1, Type: internal.MovementReceipt, Quantity: 10, Reason: "purchase", Date: receivedAt},
+		}
+		initialLots := map[int]internal.TLot{
+			1: {ID: 1, ProductID: 1, WarehouseID: 1, Quantity: 5, ReceivedQuantity: 5, Expiration: "31/01/2025", ReceivedAt: receivedAt, MovementID: 1},
+			2: {ID: 2, ProductID: 1, WarehouseID: 1, Quantity: 10, ReceivedQuantity: 10, Expiration: "28/02/2025", ReceivedAt: receivedAt, MovementID: 2},
+		}
+
+		/* Initialize dependencies */
+		productStorage := initStorage(initialProducts)
+		productRepository := repository.NewProductMap(&productStorage)
+		movementRepository := repository.NewMovementMap(initMovementStorage(initialMovements))
+		lotRepository := repository.NewLotMap(initLotStorage(initialLots))
+		service := service.NewMovementServiceDefault(productRepository, movementRepository)
+		service.SetClock(clock.NewClockFixed(time.Date(2025, 2, 1, 0, 0, 0, 0, time.Local)))
+		service.SetLots(lotRepository, internal.LotPolicyFIFO)
+		handler := handlers.NewMovementHandler(service)
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("POST", "/lots/write-off", nil)
+		res := httptest.NewRecorder()
+
+		handler.WriteOffExpiredLots()(res, req)
+
+		/* Expected values definition */
+		expectedCode := http.StatusOK
+		var body struct {
+			Data []internal.TMovement `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+
+		/* Assertions */
+		require.Equal(t, expectedCode, res.Code)
+		require.Len(t, body.Data, 1)
+		require.Equal(t, internal.MovementWriteOff, body.Data[0].Type)
+		require.Equal(t, -5, body.Data[0].Quantity)
+		require.Equal(t, 1, body.Data[0].LotID)
+		lots, err := lotRepository.GetLotsByProduct(1)
+		require.NoError(t, err)
+		require.Equal(t, 0, lots[0].Quantity)
+		require.Equal(t, 10, lots[1].Quantity)
+		product, err := productRepository.GetProductByID(1)
+		require.NoError(t, err)
+		require.Equal(t, 10, product.Quantity)
+		require.Equal(t, "28/02/2025", product.Expiration)
+	})
+}
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"proyecto/internal"
+	"proyecto/platform/web/request"
+	"proyecto/platform/web/response"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+/* Order handler definition */
+type OrderHandler struct {
+	OrderService internal.OrderService // Order service instance
+}
+
+// NewOrderHandler creates a new default valued OrderHandler
+// NewOrderHandler(os internal.OrderService) -> *OrderHandler
+// Args:
+//		os: Order service instance
+// Return:
+//		*OrderHandler: New OrderHandler instance
+
+func NewOrderHandler(os internal.OrderService) *OrderHandler {
+	return &OrderHandler{
+		OrderService: os,
+	}
+}
+
+// BodyRequestOrderItemJSON is the body request for a line of an order in JSON format
+type BodyRequestOrderItemJSON struct {
+	ProductID int `json:"product_id"` // Ordered product.
+	Quantity  int `json:"quantity"`   // Ordered units.
+}
+
+// BodyRequestOrderJSON is the body request for an order in JSON format
+type BodyRequestOrderJSON struct {
+	Customer string                     `json:"customer"` // Customer of the order. (Optional)
+	Items    []BodyRequestOrderItemJSON `json:"items"`    // Lines of the order.
+}
+
+// toOrder converts the body request into an order
+// toOrder(id int) -> internal.TOrder
+
+func (b BodyRequestOrderJSON) toOrder(id int) internal.TOrder {
+	items := make([]internal.TOrderItem, 0, len(b.Items))
+	for _, item := range b.Items {
+		items = append(items, internal.TOrderItem{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+	return internal.TOrder{ID: id, Customer: b.Customer, Items: items}
+}
+
+// orderError writes the response for an error raised by the order service
+// orderError(w http.ResponseWriter, err error)
+// Args:
+//		w:   HTTP response writer
+//		err: Error raised by the service
+
+func orderError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, internal.ErrOrderNotExists):
+		response.Text(w, http.StatusNotFound, "Order not found.")
+	case errors.Is(err, internal.ErrEmptyField), errors.Is(err, internal.ErrInvalidOrderItems):
+		response.Text(w, http.StatusBadRequest, "Invalid body. "+err.Error())
+	case errors.Is(err, internal.ErrInvalidOrderTransition):
+		response.Text(w, http.StatusConflict, "Invalid order status. "+err.Error())
+	case errors.Is(err, internal.ErrProductNotAvailable), errors.Is(err, internal.ErrProductNotExists):
+		response.Text(w, http.StatusConflict, "Product not available. "+err.Error())
+	case errors.Is(err, internal.ErrInsufficientStock):
+		response.Text(w, http.StatusConflict, "Insufficient stock. "+err.Error())
+	default:
+		response.Text(w, http.StatusInternalServerError, "Internal server error.")
+	}
+}
+
+/* Endpoint function handlers */
+
+// GetAllOrders returns all the orders
+// URL params: none
+func (o *OrderHandler) GetAllOrders() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		orders, err := o.OrderService.GetAllOrders()
+		if err != nil {
+			orderError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data": orders,
+		})
+	}
+}
+
+// GetOrderByID search an order by ID and return if there is a match.
+// URL params:
+//
+//	id (Numeric): ID of the order.
+func (o *OrderHandler) GetOrderByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+
+		/* Search the order by id */
+		order, err := o.OrderService.GetOrderByID(id)
+		if err != nil {
+			orderError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data": order,
+		})
+	}
+}
+
+// AddNewOrder creates a new draft order
+// URL params : none
+// Body params: BodyRequestOrderJSON
+func (o *OrderHandler) AddNewOrder() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the body from the request */
+		var body BodyRequestOrderJSON
+		if err := request.JSON(r, &body); err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid body.")
+			return
+		}
+
+		/* Insert the new order */
+		order := body.toOrder(0)
+		if err := o.OrderService.InsertNewOrder(&order); err != nil {
+			orderError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusCreated, map[string]any{
+			"data":    order,
+			"message": "Order created successfully.",
+		})
+	}
+}
+
+// UpdateOrder replaces the customer and the items of a draft order
+// URL params : id
+// Body params: BodyRequestOrderJSON
+func (o *OrderHandler) UpdateOrder() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+
+		/* Retrieve the body from the request */
+		var body BodyRequestOrderJSON
+		if err := request.JSON(r, &body); err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid body.")
+			return
+		}
+
+		/* Update the order */
+		order := body.toOrder(id)
+		if err := o.OrderService.UpdateOrder(&order); err != nil {
+			orderError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data":    order,
+			"message": "Order updated successfully.",
+		})
+	}
+}
+
+// changeStatus returns a handler moving an order to a new status
+// changeStatus(change func(id int) (internal.TOrder, error), message string) -> http.HandlerFunc
+// Args:
+//		change:  Service method performing the transition
+//		message: Message sent on success
+
+func changeStatus(change func(id int) (internal.TOrder, error), message string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+
+		/* Move the order */
+		order, err := change(id)
+		if err != nil {
+			orderError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data":    order,
+			"message": message,
+		})
+	}
+}
+
+// PlaceOrder checks a draft order against the catalog and places it
+// URL params : id
+func (o *OrderHandler) PlaceOrder() http.HandlerFunc {
+	return changeStatus(o.OrderService.PlaceOrder, "Order placed successfully.")
+}
+
+// ConfirmOrder takes the stock of a placed order
+// URL params : id
+func (o *OrderHandler) ConfirmOrder() http.HandlerFunc {
+	return changeStatus(o.OrderService.ConfirmOrder, "Order confirmed successfully.")
+}
+
+// ShipOrder marks a confirmed order as shipped
+// URL params : id
+func (o *OrderHandler) ShipOrder() http.HandlerFunc {
+	return changeStatus(o.OrderService.ShipOrder, "Order shipped successfully.")
+}
+
+// CancelOrder cancels an order restoring the stock it took
+// URL params : id
+func (o *OrderHandler) CancelOrder() http.HandlerFunc {
+	return changeStatus(o.OrderService.CancelOrder, "Order cancelled successfully.")
+}
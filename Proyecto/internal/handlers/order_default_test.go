@@ -0,0 +1,296 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"proyecto/internal"
+	"proyecto/internal/clock"
+	"proyecto/internal/handlers"
+	"proyecto/internal/repository"
+	"proyecto/internal/service"
+	"proyecto/internal/storage"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// initOrderStorage initializes the order storage
+// initOrderStorage(map[int]internal.TOrder) -> *storage.OrderStorageDefault
+// Args:
+// 	initialOrders: Initial orders
+// Returns:
+// 	*OrderStorageDefault: Initialized storage
+
+func initOrderStorage(initialOrders map[int]internal.TOrder) *storage.OrderStorageDefault {
+	/* Storage creation */
+	filepath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/internal/handlers/orders_test.json"
+	storage := storage.NewOrderStorageDefault(filepath)
+
+	/* Initial data of the storage */
+	err := storage.WriteAll(initialOrders)
+	if err != nil {
+		panic(err)
+	}
+	return storage
+}
+
+// orderTestHandler returns an order handler over the given products and orders along with the product repository
+func orderTestHandler(products map[int]internal.TProduct, orders map[int]internal.TOrder) (*handlers.OrderHandler, *repository.ProductMap) {
+	fixedClock := clock.NewClockFixed(time.Date(2024, 1, 10, 9, 0, 0, 0, time.Local))
+	productStorage := initStorage(products)
+	productRepository := repository.NewProductMap(&productStorage)
+	productService := service.NewProductServiceDefault(productRepository)
+	productService.SetClock(fixedClock)
+	movementService := service.NewMovementServiceDefault(productRepository, repository.NewMovementMap(initMovementStorage(map[int]internal.TMovement{})))
+	movementService.SetClock(fixedClock)
+	orderService := service.NewOrderServiceDefault(repository.NewOrderMap(initOrderStorage(orders)), productService, movementService)
+	orderService.SetClock(fixedClock)
+	return handlers.NewOrderHandler(orderService), productRepository
+}
+
+// orderMapFailingUpdate is an order repository whose updates fail as if the storage was down
+type orderMapFailingUpdate struct {
+	*repository.OrderMap
+}
+
+// UpdateOrder fails without saving the order
+func (o orderMapFailingUpdate) UpdateOrder(order *internal.TOrder) error {
+	return internal.ErrStorageError
+}
+
+// orderStatusRequest runs a status change handler over an order
+func orderStatusRequest(handler http.HandlerFunc, id string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/orders/"+id, nil)
+	req = addURLParams(req, map[string]string{"id": id})
+	res := httptest.NewRecorder()
+	handler(res, req)
+	return res
+}
+
+// TestOrderLifecycle tests the order handlers through the whole life of an order
+func TestOrderLifecycle(t *testing.T) {
+	// Test 1: should take the stock on confirmation and restore it on cancellation
+	t.Run("should take the stock on confirmation and restore it on cancellation", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialProducts := map[int]internal.TProduct{
+			1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: true, Expiration: "11/11/2024", Price: 10.5},
+			2: {ID: 2, Name: "Product 2", Quantity: 5, CodeValue: "AX02", IsPublished: true, Expiration: "11/11/2024", Price: 2.25},
+		}
+		handler, productRepository := orderTestHandler(initialProducts, map[int]internal.TOrder{})
+
+		/* Create the draft */
+		reqBody := `{"customer": "Jane", "items": [{"product_id": 1, "quantity": 3}, {"product_id": 2, "quantity": 4}]}`
+		req := httptest.NewRequest("POST", "/orders", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		res := httptest.NewRecorder()
+		handler.AddNewOrder()(res, req)
+		require.Equal(t, http.StatusCreated, res.Code)
+
+		/* Place it taking the prices */
+		res = orderStatusRequest(handler.PlaceOrder(), "1")
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Contains(t, res.Body.String(), `"status":"placed"`)
+		require.Contains(t, res.Body.String(), `"total":40.5`)
+
+		/* Confirm it taking the stock */
+		res = orderStatusRequest(handler.ConfirmOrder(), "1")
+		require.Equal(t, http.StatusOK, res.Code)
+		product, err := productRepository.GetProductByID(1)
+		require.NoError(t, err)
+		require.Equal(t, 7, product.Quantity)
+		product, err = productRepository.GetProductByID(2)
+		require.NoError(t, err)
+		require.Equal(t, 1, product.Quantity)
+
+		/* Cancel it restoring the stock */
+		res = orderStatusRequest(handler.CancelOrder(), "1")
+		require.Equal(t, http.StatusOK, res.Code)
+		product, err = productRepository.GetProductByID(1)
+		require.NoError(t, err)
+		require.Equal(t, 10, product.Quantity)
+		product, err = productRepository.GetProductByID(2)
+		require.NoError(t, err)
+		require.Equal(t, 5, product.Quantity)
+
+		/* A cancelled order can't move anymore */
+		res = orderStatusRequest(handler.ShipOrder(), "1")
+		require.Equal(t, http.StatusConflict, res.Code)
+		require.Equal(t, "Invalid order status. invalid order status transition: cancelled -> shipped", res.Body.String())
+	})
+
+	// Test 2: should take no stock when a line can't be served
+	t.Run("should take no stock when a line can't be served", func(t *testing.T) {
+		/* Initialize dependencies: the stock dropped after the order was placed */
+		initialProducts := map[int]internal.TProduct{
+			1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: true, Expiration: "11/11/2024", Price: 10.5},
+			2: {ID: 2, Name: "Product 2", Quantity: 1, CodeValue: "AX02", IsPublished: true, Expiration: "11/11/2024", Price: 2.25},
+		}
+		initialOrders := map[int]internal.TOrder{
+			1: {ID: 1, Status: internal.OrderPlaced, Items: []internal.TOrderItem{{ProductID: 1, Quantity: 3, UnitPrice: 10.5}, {ProductID: 2, Quantity: 4, UnitPrice: 2.25}}, Total: 40.5},
+		}
+		handler, productRepository := orderTestHandler(initialProducts, initialOrders)
+
+		/* Confirm the order */
+		res := orderStatusRequest(handler.ConfirmOrder(), "1")
+
+		/* Assertions */
+		require.Equal(t, http.StatusConflict, res.Code)
+		require.Equal(t, "Insufficient stock. insufficient stock: product 2", res.Body.String())
+		product, err := productRepository.GetProductByID(1)
+		require.NoError(t, err)
+		require.Equal(t, 10, product.Quantity)
+	})
+
+	// Test 3: should give the stock back when the confirmed order can't be saved
+	t.Run("should give the stock back when the confirmed order can't be saved", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialProducts := map[int]internal.TProduct{
+			1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: true, Expiration: "11/11/2024", Price: 10.5},
+		}
+		initialOrders := map[int]internal.TOrder{
+			1: {ID: 1, Status: internal.OrderPlaced, Items: []internal.TOrderItem{{ProductID: 1, Quantity: 3, UnitPrice: 10.5}}, Total: 31.5},
+		}
+		fixedClock := clock.NewClockFixed(time.Date(2024, 1, 10, 9, 0, 0, 0, time.Local))
+		productStorage := initStorage(initialProducts)
+		productRepository := repository.NewProductMap(&productStorage)
+		productService := service.NewProductServiceDefault(productRepository)
+		productService.SetClock(fixedClock)
+		movementService := service.NewMovementServiceDefault(productRepository, repository.NewMovementMap(initMovementStorage(map[int]internal.TMovement{})))
+		movementService.SetClock(fixedClock)
+		orderRepository := orderMapFailingUpdate{repository.NewOrderMap(initOrderStorage(initialOrders))}
+		orderService := service.NewOrderServiceDefault(orderRepository, productService, movementService)
+		orderService.SetClock(fixedClock)
+		handler := handlers.NewOrderHandler(orderService)
+
+		/* Confirm the order */
+		res := orderStatusRequest(handler.ConfirmOrder(), "1")
+
+		/* Assertions */
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		product, err := productRepository.GetProductByID(1)
+		require.NoError(t, err)
+		require.Equal(t, 10, product.Quantity)
+		movements, err := movementService.GetMovementsByProduct(1, time.Time{}, time.Time{})
+		require.NoError(t, err)
+		require.Len(t, movements, 3) // Opening balance, sale and its reversal
+		require.Equal(t, internal.MovementCorrection, movements[2].Type)
+		require.Equal(t, 3, movements[2].Quantity)
+		order, err := orderService.GetOrderByID(1)
+		require.NoError(t, err)
+		require.Equal(t, internal.OrderPlaced, order.Status)
+	})
+
+	// Test 4: should draw the stock from every warehouse holding it and give it back to them
+	t.Run("should draw the stock from every warehouse holding it and give it back to them", func(t *testing.T) {
+		/* Initialize dependencies: 10 units in the main warehouse and 5 in the north one */
+		initialProducts, initialWarehouses, initialMovements := warehouseTestData()
+		initialOrders := map[int]internal.TOrder{
+			1: {ID: 1, Status: internal.OrderPlaced, Items: []internal.TOrderItem{{ProductID: 1, Quantity: 12, UnitPrice: 10.5}}, Total: 126},
+		}
+		fixedClock := clock.NewClockFixed(time.Date(2024, 1, 10, 9, 0, 0, 0, time.Local))
+		productStorage := initStorage(initialProducts)
+		productRepository := repository.NewProductMap(&productStorage)
+		productService := service.NewProductServiceDefault(productRepository)
+		productService.SetClock(fixedClock)
+		movementService := service.NewMovementServiceDefault(productRepository, repository.NewMovementMap(initMovementStorage(initialMovements)))
+		movementService.SetClock(fixedClock)
+		movementService.SetWarehouses(repository.NewWarehouseMap(initWarehouseStorage(initialWarehouses)))
+		orderService := service.NewOrderServiceDefault(repository.NewOrderMap(initOrderStorage(initialOrders)), productService, movementService)
+		orderService.SetClock(fixedClock)
+		handler := handlers.NewOrderHandler(orderService)
+
+		/* Confirm the order */
+		res := orderStatusRequest(handler.ConfirmOrder(), "1")
+		require.Equal(t, http.StatusOK, res.Code)
+		levels, err := movementService.GetStockLevels(1)
+		require.NoError(t, err)
+		require.Equal(t, []internal.TStockLevel{{ProductID: 1, WarehouseID: 2, Quantity: 3}}, levels)
+
+		/* Cancel it */
+		res = orderStatusRequest(handler.CancelOrder(), "1")
+		require.Equal(t, http.StatusOK, res.Code)
+		levels, err = movementService.GetStockLevels(1)
+		require.NoError(t, err)
+		require.Equal(t, []internal.TStockLevel{{ProductID: 1, WarehouseID: 1, Quantity: 10}, {ProductID: 1, WarehouseID: 2, Quantity: 5}}, levels)
+		product, err := productRepository.GetProductByID(1)
+		require.NoError(t, err)
+		require.Equal(t, 15, product.Quantity)
+	})
+
+	// Test 5: should not confirm a product withdrawn after the order was placed
+	t.Run("should not confirm a product withdrawn after the order was placed", func(t *testing.T) {
+		cases := []struct {
+			product      internal.TProduct
+			expectedBody string
+		}{
+			{
+				product:      internal.TProduct{ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: false, Expiration: "11/11/2024", Price: 10.5},
+				expectedBody: "Product not available. product not available: product 1 is not published",
+			},
+			{
+				product:      internal.TProduct{ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: true, Expiration: "09/01/2024", Price: 10.5},
+				expectedBody: "Product not available. product not available: product 1 is expired",
+			},
+		}
+		for _, c := range cases {
+			/* Initialize dependencies */
+			initialOrders := map[int]internal.TOrder{
+				1: {ID: 1, Status: internal.OrderPlaced, Items: []internal.TOrderItem{{ProductID: 1, Quantity: 3, UnitPrice: 10.5}}, Total: 31.5},
+			}
+			handler, productRepository := orderTestHandler(map[int]internal.TProduct{1: c.product}, initialOrders)
+
+			/* Confirm the order */
+			res := orderStatusRequest(handler.ConfirmOrder(), "1")
+
+			/* Assertions */
+			require.Equal(t, http.StatusConflict, res.Code)
+			require.Equal(t, c.expectedBody, res.Body.String())
+			product, err := productRepository.GetProductByID(1)
+			require.NoError(t, err)
+			require.Equal(t, 10, product.Quantity)
+		}
+	})
+}
+
+// TestPlaceOrder tests the PlaceOrder handler
+func TestPlaceOrder(t *testing.T) {
+	cases := []struct {
+		name         string
+		product      internal.TProduct
+		expectedBody string
+	}{
+		{
+			name:         "should reject an unpublished product",
+			product:      internal.TProduct{ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: false, Expiration: "11/11/2024", Price: 10.5},
+			expectedBody: "Product not available. product not available: product 1 is not published",
+		},
+		{
+			name:         "should reject an expired product",
+			product:      internal.TProduct{ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: true, Expiration: "09/01/2024", Price: 10.5},
+			expectedBody: "Product not available. product not available: product 1 is expired",
+		},
+		{
+			name:         "should reject a product without enough stock",
+			product:      internal.TProduct{ID: 1, Name: "Product 1", Quantity: 2, CodeValue: "AX01", IsPublished: true, Expiration: "11/11/2024", Price: 10.5},
+			expectedBody: "Insufficient stock. insufficient stock: product 1",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			/* Initialize dependencies */
+			initialOrders := map[int]internal.TOrder{
+				1: {ID: 1, Status: internal.OrderDraft, Items: []internal.TOrderItem{{ProductID: 1, Quantity: 3}}},
+			}
+			handler, _ := orderTestHandler(map[int]internal.TProduct{1: c.product}, initialOrders)
+
+			/* Place the order */
+			res := orderStatusRequest(handler.PlaceOrder(), "1")
+
+			/* Assertions */
+			require.Equal(t, http.StatusConflict, res.Code)
+			require.Equal(t, c.expectedBody, res.Body.String())
+		})
+	}
+}
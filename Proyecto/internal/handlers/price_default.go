@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"proyecto/internal"
+	"proyecto/platform/web/request"
+	"proyecto/platform/web/response"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+/* Price handler definition */
+type PriceHandler struct {
+	PriceService internal.PriceService // Price service instance
+}
+
+// NewPriceHandler creates a new default valued PriceHandler
+// NewPriceHandler(ps internal.PriceService) -> *PriceHandler
+// Args:
+//		ps: Price service instance
+// Return:
+//		*PriceHandler: New PriceHandler instance
+
+func NewPriceHandler(ps internal.PriceService) *PriceHandler {
+	return &PriceHandler{
+		PriceService: ps,
+	}
+}
+
+// BodyRequestPromotionJSON is the body request for a promotion in JSON format
+type BodyRequestPromotionJSON struct {
+	Type        string    `json:"type"`        // Promotion type: percentage or fixed.
+	Value       float64   `json:"value"`       // Percentage or amount of the discount.
+	Description string    `json:"description"` // Promotion description. (Optional)
+	StartAt     time.Time `json:"start_at"`    // Moment the promotion starts. RFC 3339
+	EndAt       time.Time `json:"end_at"`      // Moment the promotion ends. RFC 3339
+}
+
+// priceError writes the response for an error raised by the price service
+// priceError(w http.ResponseWriter, err error)
+// Args:
+//		w:   HTTP response writer
+//		err: Error raised by the service
+
+func priceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, internal.ErrProductNotExists):
+		response.Text(w, http.StatusNotFound, "Product not found.")
+	case errors.Is(err, internal.ErrPromotionNotExists):
+		response.Text(w, http.StatusNotFound, "Promotion not found.")
+	case errors.Is(err, internal.ErrInvalidPromotion):
+		response.Text(w, http.StatusBadRequest, "Invalid body. "+err.Error())
+	default:
+		response.Text(w, http.StatusInternalServerError, "Internal server error.")
+	}
+}
+
+/* Endpoint function handlers */
+
+// GetPriceTimeline returns the list and effective prices of a product over time
+// URL params:
+//
+//	id (Numeric): ID of the product.
+func (p *PriceHandler) GetPriceTimeline() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := urlID(r, "id")
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Build the timeline */
+		timeline, err := p.PriceService.GetPriceTimeline(id)
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data": timeline,
+		})
+	}
+}
+
+// GetPromotions returns the promotions of a product
+// URL params:
+//
+//	id (Numeric): ID of the product.
+func (p *PriceHandler) GetPromotions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := urlID(r, "id")
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Search the promotions */
+		promotions, err := p.PriceService.GetPromotionsByProduct(id)
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data": promotions,
+		})
+	}
+}
+
+// AddNewPromotion creates a new promotion for a product
+// URL params : id
+// Body params: BodyRequestPromotionJSON
+func (p *PriceHandler) AddNewPromotion() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := urlID(r, "id")
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Retrieve the body from the request */
+		var body BodyRequestPromotionJSON
+		if err := request.JSON(r, &body); err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Insert the promotion */
+		promotion := internal.TPromotion{
+			ProductID:   id,
+			Type:        body.Type,
+			Value:       body.Value,
+			Description: body.Description,
+			StartAt:     body.StartAt,
+			EndAt:       body.EndAt,
+		}
+		if err := p.PriceService.InsertNewPromotion(&promotion); err != nil {
+			productError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusCreated, map[string]any{
+			"data":    promotion,
+			"message": "Promotion created successfully.",
+		})
+	}
+}
+
+// UpdatePromotion updates a promotion
+// URL params : id
+// Body params: BodyRequestPromotionJSON
+func (p *PriceHandler) UpdatePromotion() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+
+		/* Retrieve the body from the request */
+		var body BodyRequestPromotionJSON
+		if err := request.JSON(r, &body); err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid body.")
+			return
+		}
+
+		/* Update the promotion */
+		promotion := internal.TPromotion{
+			ID:          id,
+			Type:        body.Type,
+			Value:       body.Value,
+			Description: body.Description,
+			StartAt:     body.StartAt,
+			EndAt:       body.EndAt,
+		}
+		if err := p.PriceService.UpdatePromotion(&promotion); err != nil {
+			priceError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data":    promotion,
+			"message": "Promotion updated successfully.",
+		})
+	}
+}
+
+// DeletePromotion deletes a promotion
+// URL params : id
+func (p *PriceHandler) DeletePromotion() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+
+		/* Delete the promotion */
+		if err := p.PriceService.DeletePromotion(id); err != nil {
+			priceError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,278 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"proyecto/internal"
+	"proyecto/internal/clock"
+	"proyecto/internal/handlers"
+	"proyecto/internal/repository"
+	"proyecto/internal/service"
+	"proyecto/internal/storage"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// initPriceStorage initializes the price storage
+// initPriceStorage(map[int]internal.TPriceChange) -> *storage.PriceStorageDefault
+// Args:
+// 	initialChanges: Initial price changes
+// Returns:
+// 	*PriceStorageDefault: Initialized storage
+
+func initPriceStorage(initialChanges map[int]internal.TPriceChange) *storage.PriceStorageDefault {
+	/* Storage creation */
+	filepath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/internal/handlers/prices_test.json"
+	storage := storage.NewPriceStorageDefault(filepath)
+
+	/* Initial data of the storage */
+	err := storage.WriteAll(initialChanges)
+	if err != nil {
+		panic(err)
+	}
+	return storage
+}
+
+// initPromotionStorage initializes the promotion storage
+// initPromotionStorage(map[int]internal.TPromotion) -> *storage.PromotionStorageDefault
+// Args:
+// 	initialPromotions: Initial promotions
+// Returns:
+// 	*PromotionStorageDefault: Initialized storage
+
+func initPromotionStorage(initialPromotions map[int]internal.TPromotion) *storage.PromotionStorageDefault {
+	/* Storage creation */
+	filepath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/internal/handlers/promotions_test.json"
+	storage := storage.NewPromotionStorageDefault(filepath)
+
+	/* Initial data of the storage */
+	err := storage.WriteAll(initialPromotions)
+	if err != nil {
+		panic(err)
+	}
+	return storage
+}
+
+// priceTestData returns a product whose price rose on March with a promotion on February
+func priceTestData() (map[int]internal.TProduct, map[int]internal.TPriceChange, map[int]internal.TPromotion) {
+	products := map[int]internal.TProduct{
+		1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: true, Expiration: "11/11/2024", Price: 120},
+	}
+	changes := map[int]internal.TPriceChange{
+		1: {ID: 1, ProductID: 1, Price: 100, Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		2: {ID: 2, ProductID: 1, Price: 120, Date: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	promotions := map[int]internal.TPromotion{
+		1: {ID: 1, ProductID: 1, Type: internal.PromotionPercentage, Value: 10, Description: "summer sale",
+			StartAt: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), EndAt: time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC)},
+	}
+	return products, changes, promotions
+}
+
+// TestGetPriceTimeline tests the GetPriceTimeline handler
+func TestGetPriceTimeline(t *testing.T) {
+	// Test 1: should split the timeline on every price change and promotion boundary
+	t.Run("should split the timeline on every price change and promotion boundary", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialProducts, initialChanges, initialPromotions := priceTestData()
+		productStorage := initStorage(initialProducts)
+		priceService := service.NewPriceServiceDefault(
+			repository.NewProductMap(&productStorage),
+			repository.NewPriceMap(initPriceStorage(initialChanges)),
+			repository.NewPromotionMap(initPromotionStorage(initialPromotions)),
+		)
+		handler := handlers.NewPriceHandler(priceService)
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("GET", "/products/1/prices", nil)
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+
+		handler.GetPriceTimeline()(res, req)
+
+		/* Expected values definition */
+		expectedCode := http.StatusOK
+		expectedBody := `{"data": [
+			{"from": "2024-01-01T00:00:00Z", "to": "2024-02-01T00:00:00Z", "list_price": 100, "effective_price": 100},
+			{"from": "2024-02-01T00:00:00Z", "to": "2024-02-15T00:00:00Z", "list_price": 100, "effective_price": 90, "promotion_id": 1},
+			{"from": "2024-02-15T00:00:00Z", "to": "2024-03-01T00:00:00Z", "list_price": 100, "effective_price": 100},
+			{"from": "2024-03-01T00:00:00Z", "list_price": 120, "effective_price": 120}
+		]}`
+
+		/* Assertions */
+		require.Equal(t, expectedCode, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+	})
+
+	// Test 2: should not inherit the history of a deleted product reusing its id
+	t.Run("should not inherit the history of a deleted product reusing its id", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialProducts, initialChanges, initialPromotions := priceTestData()
+		productStorage := initStorage(initialProducts)
+		productRepository := repository.NewProductMap(&productStorage)
+		priceService := service.NewPriceServiceDefault(
+			productRepository,
+			repository.NewPriceMap(initPriceStorage(initialChanges)),
+			repository.NewPromotionMap(initPromotionStorage(initialPromotions)),
+		)
+		priceService.SetClock(clock.NewClockFixed(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)))
+		productService := service.NewProductServiceDefault(productRepository)
+		productService.SetPricing(priceService)
+		productService.AddDependent(priceService)
+		handler := handlers.NewPriceHandler(priceService)
+		productHandler := handlers.NewProductHandler(productService)
+
+		/* Delete the product and create a new one taking its id */
+		req := httptest.NewRequest("DELETE", "/products/1", nil)
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+		productHandler.DeleteProduct()(res, req)
+		require.Equal(t, http.StatusNoContent, res.Code)
+
+		reqBody := `{"name": "new product", "quantity": 5, "code_value": "AX02", "expiration": "01/01/2030", "price": 50}`
+		req = httptest.NewRequest("POST", "/products/", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		res = httptest.NewRecorder()
+		productHandler.AddNewProduct()(res, req)
+		require.Equal(t, http.StatusCreated, res.Code)
+		require.Contains(t, res.Body.String(), `"id":1`)
+
+		/* Prepare the request and the response */
+		req = httptest.NewRequest("GET", "/products/1/prices", nil)
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res = httptest.NewRecorder()
+
+		handler.GetPriceTimeline()(res, req)
+
+		/* Expected values definition */
+		expectedCode := http.StatusOK
+		expectedBody := `{"data": [
+			{"from": "2024-05-01T00:00:00Z", "list_price": 50, "effective_price": 50}
+		]}`
+
+		/* Assertions */
+		require.Equal(t, expectedCode, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+		promotions, err := priceService.GetPromotionsByProduct(1)
+		require.NoError(t, err)
+		require.Empty(t, promotions)
+	})
+}
+
+// TestAddNewPromotion tests the AddNewPromotion handler
+func TestAddNewPromotion(t *testing.T) {
+	// Test 1: should reject a percentage over 100
+	t.Run("should reject a percentage over 100", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialProducts, initialChanges, initialPromotions := priceTestData()
+		productStorage := initStorage(initialProducts)
+		priceService := service.NewPriceServiceDefault(
+			repository.NewProductMap(&productStorage),
+			repository.NewPriceMap(initPriceStorage(initialChanges)),
+			repository.NewPromotionMap(initPromotionStorage(initialPromotions)),
+		)
+		handler := handlers.NewPriceHandler(priceService)
+
+		/* Prepare the request and the response */
+		reqBody := `{"type": "percentage", "value": 150, "start_at": "2024-05-01T00:00:00Z", "end_at": "2024-05-02T00:00:00Z"}`
+		req := httptest.NewRequest("POST", "/products/1/promotions", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+
+		handler.AddNewPromotion()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		require.Contains(t, res.Body.String(), `"detail":"invalid promotion: percentage must be in (0, 100]"`)
+	})
+
+	// Test 2: should show the effective price while the promotion is active
+	t.Run("should show the effective price while the promotion is active", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialProducts, initialChanges, initialPromotions := priceTestData()
+		productStorage := initStorage(initialProducts)
+		productRepository := repository.NewProductMap(&productStorage)
+		priceService := service.NewPriceServiceDefault(
+			productRepository,
+			repository.NewPriceMap(initPriceStorage(initialChanges)),
+			repository.NewPromotionMap(initPromotionStorage(initialPromotions)),
+		)
+		productService := service.NewProductServiceDefault(productRepository)
+		productService.SetPricing(priceService)
+		productService.SetClock(clock.NewClockFixed(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)))
+		handler := handlers.NewPriceHandler(priceService)
+		productHandler := handlers.NewProductHandler(productService)
+
+		/* Create a fixed discount over May the 1st */
+		reqBody := `{"type": "fixed", "value": 20, "start_at": "2024-05-01T00:00:00Z", "end_at": "2024-05-02T00:00:00Z"}`
+		req := httptest.NewRequest("POST", "/products/1/promotions", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+		handler.AddNewPromotion()(res, req)
+		require.Equal(t, http.StatusCreated, res.Code)
+
+		/* Read the product */
+		req = httptest.NewRequest("GET", "/products/1", nil)
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res = httptest.NewRecorder()
+		productHandler.GetProductByID()(res, req)
+
+		/* Expected values definition */
+		expectedCode := http.StatusOK
+		expectedBody := `{"data":
+			{"id": 1, "name": "Product 1", "quantity": 10, "code_value": "AX01", "is_published": true, "expiration": "11/11/2024", "price": 120, "effective_price": 100}
+		}`
+
+		/* Assertions */
+		require.Equal(t, expectedCode, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+	})
+
+	// Test 3: should publish the price change of the product and its variants
+	t.Run("should publish the price change of the product and its variants", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialProducts, initialChanges, initialPromotions := priceTestData()
+		parentID := 1
+		initialProducts[2] = internal.TProduct{ID: 2, Name: "Product 2", Quantity: 5, CodeValue: "AX02", IsPublished: true, Expiration: "11/11/2024", Price: 60, ParentID: &parentID}
+		productStorage := initStorage(initialProducts)
+		productRepository := repository.NewProductMap(&productStorage)
+		priceService := service.NewPriceServiceDefault(
+			productRepository,
+			repository.NewPriceMap(initPriceStorage(initialChanges)),
+			repository.NewPromotionMap(initPromotionStorage(initialPromotions)),
+		)
+		productService := service.NewProductServiceDefault(productRepository)
+		productService.SetPricing(priceService)
+		productService.SetClock(clock.NewClockFixed(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)))
+		bus := initProductEventBus(10)
+		t.Cleanup(bus.Close)
+		priceService.SetEvents(bus, productService)
+		handler := handlers.NewPriceHandler(priceService)
+
+		/* Prepare the request and the response */
+		reqBody := `{"type": "percentage", "value": 50, "start_at": "2024-05-01T00:00:00Z", "end_at": "2024-05-02T00:00:00Z"}`
+		req := httptest.NewRequest("POST", "/products/1/promotions", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+
+		handler.AddNewPromotion()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusCreated, res.Code)
+		events, err := bus.Since(0)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		require.Equal(t, 1, events[0].ProductID)
+		require.Equal(t, []string{internal.ProductChangePrice}, events[0].Changes)
+		require.Equal(t, 60.0, *events[0].Product.EffectivePrice)
+		require.Equal(t, 2, events[1].ProductID)
+		require.Equal(t, 30.0, *events[1].Product.EffectivePrice)
+		require.Equal(t, []string{internal.WebhookEventPriceChanged, internal.ProductEventUpdated}, internal.WebhookEventTypesOf(events[1]))
+	})
+}
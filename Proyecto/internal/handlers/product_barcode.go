@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"proyecto/platform/barcode"
+	"strings"
+)
+
+/* Barcode rendering sizes in pixels */
+const (
+	barcodeModuleWidth = 2
+	barcodeHeight      = 80
+)
+
+// GetProductBarcode renders the code of a product as a barcode image
+// URL params:
+//
+//	id (Numeric): ID of the product.
+//	symbology (String): code128 or ean13. (Optional, default code128)
+//	format (String): svg or png. (Optional, default svg)
+func (p *ProductHandler) GetProductBarcode() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := urlID(r, "id")
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Retrieve the rendering options */
+		symbology := strings.ToLower(r.URL.Query().Get("symbology"))
+		if symbology == "" {
+			symbology = barcode.Code128
+		}
+		format := strings.ToLower(r.URL.Query().Get("format"))
+		if format == "" {
+			format = "svg"
+		}
+		if format != "svg" && format != "png" {
+			productError(w, r, fmt.Errorf("%w: format", ErrInvalidQuery))
+			return
+		}
+
+		/* Search the product */
+		product, err := p.ProductService.GetProductByID(id)
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Encode the code */
+		modules, err := barcode.Encode(symbology, product.CodeValue)
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Render the image */
+		image, contentType := barcode.SVG(modules, barcodeModuleWidth, barcodeHeight), "image/svg+xml"
+		if format == "png" {
+			if image, err = barcode.PNG(modules, barcodeModuleWidth, barcodeHeight); err != nil {
+				productError(w, r, err)
+				return
+			}
+			contentType = "image/png"
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(http.StatusOK)
+		w.Write(image)
+	}
+}
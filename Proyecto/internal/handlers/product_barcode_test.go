@@ -0,0 +1,123 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"proyecto/internal"
+	"proyecto/internal/handlers"
+	"proyecto/internal/repository"
+	"proyecto/internal/service"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetProductBarcode tests the GetProductBarcode handler
+func TestGetProductBarcode(t *testing.T) {
+	initialProducts := map[int]internal.TProduct{
+		1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: true, Expiration: "11/11/2024", Price: 10.5},
+		2: {ID: 2, Name: "Product 2", Quantity: 20, CodeValue: "4006381333931", IsPublished: true, Expiration: "11/11/2024", Price: 20.5},
+	}
+	cases := []struct {
+		name            string
+		id              string
+		query           string
+		expectedCode    int
+		expectedType    string
+		expectedProblem string
+	}{
+		{
+			name:         "should render a code128 svg by default",
+			id:           "1",
+			expectedCode: http.StatusOK,
+			expectedType: "image/svg+xml",
+		},
+		{
+			name:         "should render an ean13 png",
+			id:           "2",
+			query:        "?symbology=ean13&format=png",
+			expectedCode: http.StatusOK,
+			expectedType: "image/png",
+		},
+		{
+			name:            "should reject a code which is not an ean13",
+			id:              "1",
+			query:           "?symbology=ean13",
+			expectedCode:    http.StatusUnprocessableEntity,
+			expectedType:    "application/problem+json",
+			expectedProblem: "not_encodable",
+		},
+		{
+			name:            "should reject an unknown symbology",
+			id:              "1",
+			query:           "?symbology=qr",
+			expectedCode:    http.StatusBadRequest,
+			expectedType:    "application/problem+json",
+			expectedProblem: "unknown_symbology",
+		},
+		{
+			name:            "should reject an unknown format",
+			id:              "1",
+			query:           "?format=gif",
+			expectedCode:    http.StatusBadRequest,
+			expectedType:    "application/problem+json",
+			expectedProblem: "invalid_query",
+		},
+		{
+			name:            "should return a not found error",
+			id:              "3",
+			expectedCode:    http.StatusNotFound,
+			expectedType:    "application/problem+json",
+			expectedProblem: "product_not_found",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			/* Initialize dependencies */
+			storage := initStorage(initialProducts)
+			repository := repository.NewProductMap(&storage)
+			service := service.NewProductServiceDefault(repository)
+			handler := handlers.NewProductHandler(service)
+
+			/* Prepare the request and the response */
+			req := httptest.NewRequest("GET", "/products/"+c.id+"/barcode"+c.query, nil)
+			req = addURLParams(req, map[string]string{"id": c.id})
+			res := httptest.NewRecorder()
+			handler.GetProductBarcode()(res, req)
+
+			/* Assertions */
+			require.Equal(t, c.expectedCode, res.Code)
+			require.Equal(t, c.expectedType, res.Header().Get("Content-Type"))
+			if c.expectedProblem != "" {
+				require.Contains(t, res.Body.String(), `"code":"`+c.expectedProblem+`"`)
+			}
+		})
+	}
+}
+
+// TestAddNewProductGeneratedCode tests the EAN-13 codes generated for the products created without one
+func TestAddNewProductGeneratedCode(t *testing.T) {
+	/* Prepare the test data */
+	initialProducts := map[int]internal.TProduct{
+		1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "2000000000015", IsPublished: true, Expiration: "11/11/2024", Price: 10.5},
+	}
+
+	/* Initialize dependencies */
+	storage := initStorage(initialProducts)
+	repository := repository.NewProductMap(&storage)
+	service := service.NewProductServiceDefault(repository)
+	service.SetCodeGeneration("200")
+	handler := handlers.NewProductHandler(service)
+
+	/* Prepare the request and the response */
+	reqBody := `{"name": "new product", "quantity": 5, "is_published": true, "expiration": "01/01/2024", "price": 20}`
+	req := httptest.NewRequest("POST", "/products/", strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	handler.AddNewProduct()(res, req)
+
+	/* Assertions: the sequence continues after the stored code */
+	require.Equal(t, http.StatusCreated, res.Code)
+	require.Contains(t, res.Body.String(), `"code_value":"2000000000022"`)
+}
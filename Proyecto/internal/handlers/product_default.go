@@ -1,14 +1,14 @@
 package handlers
 
 import (
-	"errors"
+	"fmt"
 	"net/http"
 	"proyecto/internal"
 	"proyecto/platform/web/request"
 	"proyecto/platform/web/response"
 	"strconv"
-
-	"github.com/go-chi/chi/v5"
+	"strings"
+	"time"
 )
 
 /* Product handler definition */
@@ -16,9 +16,6 @@ type ProductHandler struct {
 	ProductService internal.ProductService // Product service instance
 }
 
-/* product field names from JSON format */
-var productFields = []string{"id", "name", "quantity", "code_value", "is_published", "expiration", "price"}
-
 // NewProductHandler creates a new default valued productHandler
 // NewProductHandler(ps internal.ProductService) -> *ProductHandler
 // Args:
@@ -34,23 +31,132 @@ func NewProductHandler(ps internal.ProductService) *ProductHandler {
 
 // ProductJSON is the JSON representation of a product
 type ProductJSON struct {
-	ID          int     `json:"id"`
-	Name        string  `json:"name"`
-	Quantity    int     `json:"quantity"`
-	CodeValue   string  `json:"code_value"`
-	IsPublished bool    `json:"is_published"`
-	Expiration  string  `json:"expiration"`
-	Price       float64 `json:"price"`
+	ID              int               `json:"id"`
+	Name            string            `json:"name"`
+	Quantity        int               `json:"quantity"`
+	CodeValue       string            `json:"code_value"`
+	IsPublished     bool              `json:"is_published"`
+	Expiration      string            `json:"expiration"`
+	Price           float64           `json:"price"`
+	PublishAt       *time.Time        `json:"publish_at,omitempty"`
+	UnpublishAt     *time.Time        `json:"unpublish_at,omitempty"`
+	Tags            []string          `json:"tags,omitempty"`
+	ParentID        *int              `json:"parent_id,omitempty"`
+	Attributes      map[string]string `json:"attributes,omitempty"`
+	ReorderPoint    *int              `json:"reorder_point,omitempty"`
+	ReorderQuantity int               `json:"reorder_quantity,omitempty"`
+	TaxClass        string            `json:"tax_class,omitempty"`
+}
+
+// newProductJSON serializes a product to ProductJSON
+// newProductJSON(product internal.TProduct) -> ProductJSON
+// Args:
+//		product: Product to serialize
+// Return:
+//		ProductJSON: JSON representation of the product
+
+func newProductJSON(product internal.TProduct) ProductJSON {
+	return ProductJSON{
+		ID:              product.ID,
+		Name:            product.Name,
+		Quantity:        product.Quantity,
+		CodeValue:       product.CodeValue,
+		IsPublished:     product.IsPublished,
+		Expiration:      product.Expiration,
+		Price:           product.Price,
+		PublishAt:       product.PublishAt,
+		UnpublishAt:     product.UnpublishAt,
+		Tags:            product.Tags,
+		ParentID:        product.ParentID,
+		Attributes:      product.Attributes,
+		ReorderPoint:    product.ReorderPoint,
+		ReorderQuantity: product.ReorderQuantity,
+		TaxClass:        product.TaxClass,
+	}
 }
 
 // BodyRequestProductJSON is the body request for a product in JSON format
 type BodyRequestProductJSON struct {
-	Name        string  `json:"name"`         // Product name.
-	Quantity    int     `json:"quantity"`     // Product quantity.
-	CodeValue   string  `json:"code_value"`   // Product code value.
-	IsPublished bool    `json:"is_published"` // Product is published (Optional)
-	Expiration  string  `json:"expiration"`   // Product expiration date. Format DD/MM/YYYY
-	Price       float64 `json:"price"`        // Product price.
+	Name            string            `json:"name"`                       // Product name.
+	Quantity        int               `json:"quantity"`                   // Product quantity.
+	CodeValue       string            `json:"code_value"`                 // Product code value.
+	IsPublished     bool              `json:"is_published"`               // Product is published (Optional)
+	Expiration      string            `json:"expiration"`                 // Product expiration date. Format DD/MM/YYYY
+	Price           float64           `json:"price"`                      // Product price.
+	PublishAt       *time.Time        `json:"publish_at,omitempty"`       // Moment the product goes live. RFC 3339 (Optional)
+	UnpublishAt     *time.Time        `json:"unpublish_at,omitempty"`     // Moment the product is withdrawn. RFC 3339 (Optional)
+	Tags            []string          `json:"tags,omitempty"`             // Free-form labels. (Optional)
+	Attributes      map[string]string `json:"attributes,omitempty"`       // Size, flavor, pack... (Optional)
+	ReorderPoint    *int              `json:"reorder_point,omitempty"`    // Quantity at or below which the product must be restocked. (Optional)
+	ReorderQuantity int               `json:"reorder_quantity,omitempty"` // Quantity usually purchased when restocking. (Optional)
+	TaxClass        string            `json:"tax_class,omitempty"`        // Tax class the rates are looked up by. (Optional, standard)
+}
+
+// BodyRequestProductPutJSON is the body request replacing a product in JSON format. Every
+// required field must be present, even if it holds its empty value
+type BodyRequestProductPutJSON struct {
+	ID              *int              `json:"id"`               // Product id. (Optional, must match the url)
+	Name            *string           `json:"name"`             // Product name.
+	Quantity        *int              `json:"quantity"`         // Product quantity.
+	CodeValue       *string           `json:"code_value"`       // Product code value.
+	IsPublished     *bool             `json:"is_published"`     // Product is published.
+	Expiration      *string           `json:"expiration"`       // Product expiration date. Format DD/MM/YYYY
+	Price           *float64          `json:"price"`            // Product price.
+	PublishAt       *time.Time        `json:"publish_at"`       // Moment the product goes live. RFC 3339 (Optional)
+	UnpublishAt     *time.Time        `json:"unpublish_at"`     // Moment the product is withdrawn. RFC 3339 (Optional)
+	Tags            []string          `json:"tags"`             // Free-form labels. (Optional)
+	Attributes      map[string]string `json:"attributes"`       // Size, flavor, pack... (Optional)
+	ReorderPoint    *int              `json:"reorder_point"`    // Quantity at or below which the product must be restocked. (Optional)
+	ReorderQuantity int               `json:"reorder_quantity"` // Quantity usually purchased when restocking. (Optional)
+	TaxClass        string            `json:"tax_class"`        // Tax class the rates are looked up by. (Optional, standard)
+}
+
+// toProduct serializes the body to internal.TProduct checking the required fields and the id
+// toProduct(id int) -> (internal.TProduct, error)
+// Args:
+//		id: Product id taken from the url
+// Return:
+//		internal.TProduct: Product to update
+//		error: 			   request.FieldErrors with the missing or inconsistent fields (if any)
+
+func (b BodyRequestProductPutJSON) toProduct(id int) (internal.TProduct, error) {
+	invalid := request.FieldErrors{}
+	if b.ID != nil && *b.ID != id {
+		invalid["/id"] = fmt.Sprintf("does not match the url id %d", id)
+	}
+	required := map[string]bool{
+		"/name":         b.Name == nil,
+		"/quantity":     b.Quantity == nil,
+		"/code_value":   b.CodeValue == nil,
+		"/is_published": b.IsPublished == nil,
+		"/expiration":   b.Expiration == nil,
+		"/price":        b.Price == nil,
+	}
+	for field, missing := range required {
+		if missing {
+			invalid[field] = "required field"
+		}
+	}
+	if len(invalid) > 0 {
+		return internal.TProduct{}, invalid
+	}
+
+	return internal.TProduct{
+		ID:              id,
+		Name:            *b.Name,
+		Quantity:        *b.Quantity,
+		CodeValue:       *b.CodeValue,
+		IsPublished:     *b.IsPublished,
+		Expiration:      *b.Expiration,
+		Price:           *b.Price,
+		PublishAt:       b.PublishAt,
+		UnpublishAt:     b.UnpublishAt,
+		Tags:            b.Tags,
+		Attributes:      b.Attributes,
+		ReorderPoint:    b.ReorderPoint,
+		ReorderQuantity: b.ReorderQuantity,
+		TaxClass:        b.TaxClass,
+	}, nil
 }
 
 /* Endpoint function handlers */
@@ -61,7 +167,7 @@ func (p *ProductHandler) GetAllProducts() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		productsJSON := p.ProductService.GetAllProducts()
 		/* Send to the client all the products */
-		response.JSON(w, http.StatusOK, map[string]any{
+		response.Render(w, r, http.StatusOK, map[string]any{
 			"data": productsJSON,
 		})
 	}
@@ -74,27 +180,21 @@ func (p *ProductHandler) GetAllProducts() http.HandlerFunc {
 func (p *ProductHandler) GetProductByID() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		/* Retrieve the id from the url */
-		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		id, err := urlID(r, "id")
 		if err != nil {
-			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			productError(w, r, err)
 			return
 		}
 
 		/* Search the product by id */
 		product, err := p.ProductService.GetProductByID(id)
 		if err != nil {
-			switch {
-			case errors.Is(err, internal.ErrProductNotExists):
-				response.Text(w, http.StatusNotFound, "Product not found.")
-				return
-			default:
-				response.Text(w, http.StatusInternalServerError, "Internal server error.")
-				return
-			}
+			productError(w, r, err)
+			return
 		}
 
 		/* Send the product as response */
-		response.JSON(w, http.StatusOK, map[string]any{
+		response.Render(w, r, http.StatusOK, map[string]any{
 			"data": product,
 		})
 	}
@@ -109,215 +209,199 @@ func (p *ProductHandler) GetProductByPrice() http.HandlerFunc {
 		/* Retrieve the priceGt from the url */
 		priceGt, err := strconv.ParseFloat(r.URL.Query().Get("priceGt"), 64)
 		if err != nil {
-			response.Text(w, http.StatusBadRequest, "Invalid priceGt.")
+			productError(w, r, fmt.Errorf("%w: priceGt", ErrInvalidQuery))
 			return
 		}
 
 		filteredProducts := p.ProductService.GetProductByPriceGt(priceGt)
-		response.JSON(w, http.StatusOK, filteredProducts)
+		response.Render(w, r, http.StatusOK, filteredProducts)
 	}
 }
 
-// AddNewProduct creates a new product on the website
-// URL params : none
-// Body params: BodyRequestProductJSON
-func (p *ProductHandler) AddNewProduct() http.HandlerFunc {
+// SearchProducts searches products by tags and/or price. Without tags it behaves as GetProductByPrice
+// URL params:
+//
+//	tags (Comma separated): Tags to search. (Optional)
+//	match (all|any): Products must have all or any of the tags. (Optional, default any)
+//	priceGt (Numeric): Price to filter by. (Optional when tags are given)
+func (p *ProductHandler) SearchProducts() http.HandlerFunc {
+	byPrice := p.GetProductByPrice()
 	return func(w http.ResponseWriter, r *http.Request) {
-		/* Retrieve the body from the request */
-		var body BodyRequestProductJSON
-		err := request.JSON(r, &body)
-		if err != nil {
-			response.Text(w, http.StatusBadRequest, "Invalid body.")
+		query := r.URL.Query()
+		if query.Get("tags") == "" {
+			byPrice(w, r)
 			return
 		}
 
-		/* Serialize to internal.TProduct */
-		product := internal.TProduct{
-			Name:        body.Name,
-			Quantity:    body.Quantity,
-			CodeValue:   body.CodeValue,
-			IsPublished: body.IsPublished,
-			Expiration:  body.Expiration,
-			Price:       body.Price,
+		/* Retrieve the search params from the url */
+		var matchAll bool
+		switch query.Get("match") {
+		case "", "any":
+		case "all":
+			matchAll = true
+		default:
+			productError(w, r, fmt.Errorf("%w: match", ErrInvalidQuery))
+			return
+		}
+		priceGt := -1.0
+		if value := query.Get("priceGt"); value != "" {
+			var err error
+			priceGt, err = strconv.ParseFloat(value, 64)
+			if err != nil {
+				productError(w, r, fmt.Errorf("%w: priceGt", ErrInvalidQuery))
+				return
+			}
 		}
 
-		/* Intert the new product into repository */
-		err = p.ProductService.InsertNewProduct(&product)
+		/* Search the products */
+		products, err := p.ProductService.SearchProductsByTags(strings.Split(query.Get("tags"), ","), matchAll)
 		if err != nil {
-			switch {
-			case errors.Is(err, internal.ErrProductAlreadyExists):
-				response.Text(w, http.StatusBadRequest, "Product already exists.")
-				return
-			case errors.Is(err, internal.ErrEmptyField), errors.Is(err, internal.ErrInvalidDate):
-				response.Text(w, http.StatusBadRequest, "Invalid body."+err.Error())
-				return
-			default:
-				response.Text(w, http.StatusInternalServerError, "Internal server error.")
-				return
+			productError(w, r, err)
+			return
+		}
+		filteredProducts := make([]internal.TProduct, 0, len(products))
+		for _, product := range products {
+			if product.Price > priceGt {
+				filteredProducts = append(filteredProducts, product)
 			}
 		}
+		response.Render(w, r, http.StatusOK, map[string]any{
+			"data": filteredProducts,
+		})
+	}
+}
 
-		/* Serialize to ProductJSON */
-		productJSON := ProductJSON{
-			ID:          product.ID,
-			Name:        product.Name,
-			Quantity:    product.Quantity,
-			CodeValue:   product.CodeValue,
-			IsPublished: product.IsPublished,
-			Expiration:  product.Expiration,
-			Price:       product.Price,
+// GetTags returns the tags in use with the amount of products using them
+// URL params: none
+func (p *ProductHandler) GetTags() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		counts, err := p.ProductService.GetTagCounts()
+		if err != nil {
+			productError(w, r, err)
+			return
 		}
+		response.Render(w, r, http.StatusOK, map[string]any{
+			"data": counts,
+		})
+	}
+}
 
-		/* Send the new product as response */
-		response.JSON(w, http.StatusCreated, map[string]any{
-			"data":    productJSON,
-			"message": "Product created successfully.",
+// GetReorderReport returns the products at or below their reorder point along with the
+// suggested purchase quantities
+// URL params: none
+func (p *ProductHandler) GetReorderReport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response.Render(w, r, http.StatusOK, map[string]any{
+			"data": p.ProductService.GetReorderReport(),
 		})
 	}
 }
 
-// isProductBodyComplete checks if the body request is complete
-// Args:
-//
-//	fields: map[string]any
-//
-// Return:
+// GetExpiringProducts returns the products which expire within the next days
+// URL params:
 //
-//	bool: true if the body is complete, false otherwise
-func isProductBodyComplete(fields map[string]any) bool {
-	for _, value := range productFields {
-		if _, ok := fields[value]; !ok {
-			return false
+//	days (Numeric): Amount of days to look ahead. (Optional, default 7)
+func (p *ProductHandler) GetExpiringProducts() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the days from the url */
+		days := 7
+		if value := r.URL.Query().Get("days"); value != "" {
+			var err error
+			days, err = strconv.Atoi(value)
+			if err != nil || days < 0 {
+				productError(w, r, fmt.Errorf("%w: days", ErrInvalidQuery))
+				return
+			}
 		}
-	}
-	return true
 
+		/* Send the expiring products as response */
+		response.Render(w, r, http.StatusOK, map[string]any{
+			"data": p.ProductService.GetExpiringProducts(days),
+		})
+	}
 }
 
-// UpdateProduct update a product on the website
+// AddNewProduct creates a new product on the website
 // URL params : none
-// Body params: ProductJSON
-func (p *ProductHandler) UpdateProduct() http.HandlerFunc {
+// Body params: BodyRequestProductJSON
+func (p *ProductHandler) AddNewProduct() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		/* Check if the request body is complete */
-		var fields map[string]any
-		err := request.JSON(r, &fields)
+		/* Retrieve the body from the request */
+		var body BodyRequestProductJSON
+		err := request.Body(r, &body)
 		if err != nil {
-			response.Text(w, http.StatusBadRequest, "Invalid body.")
-			return
-		}
-		if !isProductBodyComplete(fields) {
-			response.Text(w, http.StatusBadRequest, "Invalid body.")
+			productError(w, r, err)
 			return
 		}
 
 		/* Serialize to internal.TProduct */
 		product := internal.TProduct{
-			ID:          int(fields["id"].(float64)),
-			Name:        fields["name"].(string),
-			Quantity:    int(fields["quantity"].(float64)),
-			CodeValue:   fields["code_value"].(string),
-			IsPublished: fields["is_published"].(bool),
-			Expiration:  fields["expiration"].(string),
-			Price:       fields["price"].(float64),
+			Name:            body.Name,
+			Quantity:        body.Quantity,
+			CodeValue:       body.CodeValue,
+			IsPublished:     body.IsPublished,
+			Expiration:      body.Expiration,
+			Price:           body.Price,
+			PublishAt:       body.PublishAt,
+			UnpublishAt:     body.UnpublishAt,
+			Tags:            body.Tags,
+			Attributes:      body.Attributes,
+			ReorderPoint:    body.ReorderPoint,
+			ReorderQuantity: body.ReorderQuantity,
+			TaxClass:        body.TaxClass,
 		}
 
-		/* Update the product into repository */
-		err = p.ProductService.UpdateProduct(&product)
+		/* Intert the new product into repository */
+		err = p.ProductService.InsertNewProduct(&product)
 		if err != nil {
-			switch {
-			case errors.Is(err, internal.ErrProductAlreadyExists):
-				response.Text(w, http.StatusBadRequest, "Product code already exists.")
-				return
-			case errors.Is(err, internal.ErrProductNotExists):
-				response.Text(w, http.StatusNotFound, "Product not found.")
-				return
-			default:
-				response.Text(w, http.StatusInternalServerError, "Internal server error.")
-				return
-			}
+			productError(w, r, err)
+			return
 		}
 
-		/* Send the response to the client */
-		response.JSON(w, http.StatusOK, map[string]any{
-			"data":    product,
-			"message": "Product updated successfully.",
+		/* Serialize to ProductJSON */
+		productJSON := newProductJSON(product)
+
+		/* Send the new product as response */
+		response.JSON(w, http.StatusCreated, map[string]any{
+			"data":    productJSON,
+			"message": "Product created successfully.",
 		})
 	}
 }
 
-// UpdateProduct partially updates a product on the website
+// UpdateProduct replaces a product on the website
 // URL params : id
-// Body params: BodyRequestProductJSON
-func (p *ProductHandler) UpdateProductPartial() http.HandlerFunc {
+// Body params: BodyRequestProductPutJSON
+func (p *ProductHandler) UpdateProduct() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		/* Retrieve the id from the url */
-		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		id, err := urlID(r, "id")
 		if err != nil {
-			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			productError(w, r, err)
 			return
 		}
 
-		/* Retrieve the fields from the request body */
-		var fields map[string]any
-		err = request.JSON(r, &fields)
-		if err != nil {
-			response.Text(w, http.StatusBadRequest, "Invalid body.")
+		/* Retrieve the body from the request */
+		var body BodyRequestProductPutJSON
+		if err := request.BodyStrict(r, &body); err != nil {
+			productError(w, r, err)
 			return
 		}
-
-		/* Retrieve the Product by ID */
-		product, err := p.ProductService.GetProductByID(id)
+		product, err := body.toProduct(id)
 		if err != nil {
-			switch {
-			case errors.Is(err, internal.ErrProductNotExists):
-				response.Text(w, http.StatusNotFound, "Product not found.")
-				return
-			default:
-				response.Text(w, http.StatusInternalServerError, "Internal server error.")
-				return
-			}
-		}
-
-		/* Update the product fields */
-		for key, value := range fields {
-			switch key {
-			case "name":
-				product.Name = value.(string)
-			case "quantity":
-				product.Quantity = int(value.(float64))
-			case "code_value":
-				product.CodeValue = value.(string)
-			case "is_published":
-				product.IsPublished = value.(bool)
-			case "expiration":
-				product.Expiration = value.(string)
-			case "price":
-				product.Price = value.(float64)
-			default:
-				response.Text(w, http.StatusBadRequest, "Invalid body unpespected field: "+key)
-				return
-			}
+			productError(w, r, err)
+			return
 		}
 
-		/* Update the product */
-		err = p.ProductService.UpdateProduct(&product)
-		if err != nil {
-			switch {
-			case errors.Is(err, internal.ErrProductNotExists):
-				response.Text(w, http.StatusNotFound, "Product not found.")
-				return
-			case errors.Is(err, internal.ErrProductAlreadyExists):
-				response.Text(w, http.StatusBadRequest, "Product code already exists.")
-				return
-			default:
-				response.Text(w, http.StatusInternalServerError, "Internal server error.")
-				return
-			}
+		/* Update the product into repository */
+		if err := p.ProductService.UpdateProduct(&product); err != nil {
+			productError(w, r, err)
+			return
 		}
 
 		/* Send the response to the client */
 		response.JSON(w, http.StatusOK, map[string]any{
+			"data":    newProductJSON(product),
 			"message": "Product updated successfully.",
 		})
 	}
@@ -329,22 +413,16 @@ func (p *ProductHandler) UpdateProductPartial() http.HandlerFunc {
 func (p *ProductHandler) DeleteProduct() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		/* Retrieve the id from the url */
-		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		id, err := urlID(r, "id")
 		if err != nil {
-			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			productError(w, r, err)
 			return
 		}
 		/* Delete the product by id */
 		err = p.ProductService.DeleteProduct(id)
 		if err != nil {
-			switch {
-			case errors.Is(err, internal.ErrProductNotExists):
-				response.Text(w, http.StatusNotFound, "Product not found.")
-				return
-			default:
-				response.Text(w, http.StatusInternalServerError, "Internal server error.")
-				return
-			}
+			productError(w, r, err)
+			return
 		}
 		/* Send the response to the client */
 		w.WriteHeader(http.StatusNoContent)
@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"proyecto/internal"
+	"proyecto/internal/clock"
 	"proyecto/internal/handlers"
 	"proyecto/internal/middleware"
 	"proyecto/internal/repository"
@@ -13,6 +14,7 @@ import (
 	"proyecto/internal/storage"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/require"
@@ -85,7 +87,7 @@ func TestGetAllProducts(t *testing.T) {
 			{"id": 3, "name": "Product 3", "quantity": 30, "code_value": "AX03", "is_published": false, "expiration": "11/11/2003", "price": 30.5},
 			{"id": 4, "name": "Product 4", "quantity": 40, "code_value": "AX04", "is_published": true, "expiration": "11/11/2004", "price": 40.5}
 		]}`
-		expectedHeader := http.Header{"Content-Type": []string{"application/json"}}
+		expectedHeader := http.Header{"Content-Type": []string{"application/json"}, "Vary": []string{"Accept"}}
 
 		/* Assertions */
 		require.Equal(t, expectedCode, res.Code)
@@ -93,6 +95,58 @@ func TestGetAllProducts(t *testing.T) {
 		require.Equal(t, expectedHeader, res.Header())
 
 	})
+
+	// Test 2: should render the products in the accepted media type
+	t.Run("should render the products in the accepted media type", func(t *testing.T) {
+		/* Prepare the test data */
+		initialProducts := map[int]internal.TProduct{
+			1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: false, Expiration: "11/11/2001", Price: 10.5, Tags: []string{"dairy", "fresh"}},
+			2: {ID: 2, Name: "Product <2>", Quantity: 20, CodeValue: "AX02", IsPublished: true, Expiration: "11/11/2002", Price: 20.5},
+		}
+
+		/* Initialize dependencies */
+		storage := initStorage(initialProducts)
+		repository := repository.NewProductMap(&storage)
+		service := service.NewProductServiceDefault(repository)
+		handler := handlers.NewProductHandler(service)
+
+		/* XML */
+		req := httptest.NewRequest("GET", "/products", nil)
+		req.Header.Set("Accept", "application/json;q=0.5, application/xml")
+		res := httptest.NewRecorder()
+		handler.GetAllProducts()(res, req)
+		expectedXML := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<response><data>` +
+			`<item><id>1</id><name>Product 1</name><quantity>10</quantity><code_value>AX01</code_value><is_published>false</is_published>` +
+			`<expiration>11/11/2001</expiration><price>10.5</price><tags><item>dairy</item><item>fresh</item></tags></item>` +
+			`<item><id>2</id><name>Product &lt;2&gt;</name><quantity>20</quantity><code_value>AX02</code_value><is_published>true</is_published>` +
+			`<expiration>11/11/2002</expiration><price>20.5</price></item>` +
+			`</data></response>`
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Equal(t, expectedXML, res.Body.String())
+		require.Equal(t, http.Header{"Content-Type": []string{"application/xml"}, "Vary": []string{"Accept"}}, res.Header())
+
+		/* CSV */
+		req = httptest.NewRequest("GET", "/products", nil)
+		req.Header.Set("Accept", "text/*")
+		res = httptest.NewRecorder()
+		handler.GetAllProducts()(res, req)
+		expectedCSV := "id,name,quantity,code_value,is_published,expiration,price,tags\n" +
+			"1,Product 1,10,AX01,false,11/11/2001,10.5,dairy;fresh\n" +
+			"2,Product <2>,20,AX02,true,11/11/2002,20.5,\n"
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Equal(t, expectedCSV, res.Body.String())
+		require.Equal(t, http.Header{"Content-Type": []string{"text/csv; charset=utf-8"}, "Vary": []string{"Accept"}}, res.Header())
+
+		/* Nothing acceptable */
+		req = httptest.NewRequest("GET", "/products", nil)
+		req.Header.Set("Accept", "application/yaml, application/json;q=0")
+		res = httptest.NewRecorder()
+		handler.GetAllProducts()(res, req)
+		expectedBody := `{"type": "/problems/not_acceptable", "code": "not_acceptable", "title": "Not acceptable", "status": 406,
+			"detail": "none of the available media types is acceptable. available: application/json, application/xml, text/csv", "instance": "/products"}`
+		require.Equal(t, http.StatusNotAcceptable, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+	})
 }
 
 // TestGetProductById test the GetProductById handler
@@ -123,7 +177,7 @@ func TestGetProductById(t *testing.T) {
 		expectedBody := `{"data":
 			{"id":2, "name": "Product 2", "quantity": 20, "code_value": "AX02", "is_published": true, "expiration": "11/11/2002", "price": 20.5}
 		}`
-		expectedHeader := http.Header{"Content-Type": []string{"application/json"}}
+		expectedHeader := http.Header{"Content-Type": []string{"application/json"}, "Vary": []string{"Accept"}}
 
 		/* Assertions */
 		require.Equal(t, expectedCode, res.Code)
@@ -132,7 +186,41 @@ func TestGetProductById(t *testing.T) {
 
 	})
 
-	// Test 2: should return a bad request error
+	// Test 2: should evaluate the publish window against the clock
+	t.Run("should evaluate the publish window against the clock", func(t *testing.T) {
+		/* Prepare the test data */
+		publishAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+		initialProducts := map[int]internal.TProduct{
+			1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: false, Expiration: "11/11/2030", Price: 10.5, PublishAt: &publishAt},
+		}
+
+		/* Initialize dependencies */
+		storage := initStorage(initialProducts)
+		repository := repository.NewProductMap(&storage)
+		service := service.NewProductServiceDefault(repository)
+		service.SetClock(clock.NewClockFixed(publishAt.Add(time.Second)))
+		handler := handlers.NewProductHandler(service)
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("GET", "/products/1", nil)
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+
+		handler.GetProductByID()(res, req)
+
+		/* Expected values definition */
+		expectedCode := http.StatusOK
+		expectedBody := `{"data":
+			{"id":1, "name": "Product 1", "quantity": 10, "code_value": "AX01", "is_published": true, "expiration": "11/11/2030", "price": 10.5,
+			"publish_at": "2024-03-01T10:00:00Z"}
+		}`
+
+		/* Assertions */
+		require.Equal(t, expectedCode, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+	})
+
+	// Test 3: should return a bad request error
 	t.Run("should return a bad request error", func(t *testing.T) {
 		/* Prepare the test data */
 		initialProducts := map[int]internal.TProduct{
@@ -155,17 +243,18 @@ func TestGetProductById(t *testing.T) {
 
 		/* Expected values definition */
 		expectedCode := http.StatusBadRequest
-		expectedBody := "Invalid ID."
-		expectedHeader := http.Header{"Content-Type": []string{"text/plain; charset=utf-8"}}
+		expectedBody := `{"type": "/problems/invalid_id", "code": "invalid_id", "title": "Invalid ID", "status": 400,
+			"detail": "invalid id: id=\"A2\"", "instance": "/products/A2"}`
+		expectedHeader := http.Header{"Content-Type": []string{"application/problem+json"}}
 
 		/* Assertions */
 		require.Equal(t, expectedCode, res.Code)
-		require.Equal(t, expectedBody, res.Body.String())
+		require.JSONEq(t, expectedBody, res.Body.String())
 		require.Equal(t, expectedHeader, res.Header())
 
 	})
 
-	// Test 3: should return a not found error
+	// Test 4: should return a not found error
 	t.Run("should return a not found error", func(t *testing.T) {
 		/* Prepare the test data */
 		initialProducts := map[int]internal.TProduct{
@@ -188,12 +277,13 @@ func TestGetProductById(t *testing.T) {
 
 		/* Expected values definition */
 		expectedCode := http.StatusNotFound
-		expectedBody := "Product not found."
-		expectedHeader := http.Header{"Content-Type": []string{"text/plain; charset=utf-8"}}
+		expectedBody := `{"type": "/problems/product_not_found", "code": "product_not_found", "title": "Product not found", "status": 404,
+			"detail": "product not exists", "instance": "/products/3"}`
+		expectedHeader := http.Header{"Content-Type": []string{"application/problem+json"}}
 
 		/* Assertions */
 		require.Equal(t, expectedCode, res.Code)
-		require.Equal(t, expectedBody, res.Body.String())
+		require.JSONEq(t, expectedBody, res.Body.String())
 		require.Equal(t, expectedHeader, res.Header())
 	})
 }
@@ -289,6 +379,106 @@ func TestAddNewProduct(t *testing.T) {
 		require.Equal(t, expectedHeader, res.Header())
 
 	})
+
+	// Test 3: should add a new product sent as XML
+	t.Run("should add a new product sent as XML", func(t *testing.T) {
+		/* Prepare the test data */
+		initialProducts := map[int]internal.TProduct{
+			1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: false, Expiration: "11/11/2001", Price: 10.5},
+		}
+
+		/* Initialize dependencies */
+		storage := initStorage(initialProducts)
+		repository := repository.NewProductMap(&storage)
+		service := service.NewProductServiceDefault(repository)
+		handler := handlers.NewProductHandler(service)
+
+		/* Prepare the request and the response */
+		reqBody := `<product>
+			<name>new product</name>
+			<quantity>1000</quantity>
+			<is_published>true</is_published>
+			<code_value>0004</code_value>
+			<expiration>01/01/2000</expiration>
+			<price>20</price>
+			<tags><item>Fresh</item></tags>
+		</product>`
+		req := httptest.NewRequest("POST", "/products/", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/xml")
+		res := httptest.NewRecorder()
+
+		handler.AddNewProduct()(res, req)
+
+		/* Expected values definition */
+		expectedCode := http.StatusCreated
+		expectedBody := `{"data": {"id": 2, "name": "new product", "quantity": 1000, "code_value": "0004", "is_published": true,
+			"expiration": "01/01/2000", "price": 20, "tags": ["fresh"]}, "message": "Product created successfully."}`
+
+		/* Assertions */
+		require.Equal(t, expectedCode, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+	})
+	// Test 4: should reject a negative stock without storing the product
+	t.Run("should reject a negative stock without storing the product", func(t *testing.T) {
+		/* Prepare the test data */
+		initialProducts := map[int]internal.TProduct{
+			1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: false, Expiration: "11/11/2001", Price: 10.5},
+		}
+
+		/* Initialize dependencies */
+		productStorage := initStorage(initialProducts)
+		productRepository := repository.NewProductMap(&productStorage)
+		movementRepository := repository.NewMovementMap(initMovementStorage(map[int]internal.TMovement{}))
+		productService := service.NewProductServiceDefault(productRepository)
+		productService.SetLedger(service.NewMovementServiceDefault(productRepository, movementRepository))
+		handler := handlers.NewProductHandler(productService)
+
+		/* Prepare the request and the response */
+		reqBody := `{"name": "new product", "quantity": -5, "is_published": true, "code_value": "AX04", "expiration": "01/01/2000", "price": 20}`
+		req := httptest.NewRequest("POST", "/products/", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		res := httptest.NewRecorder()
+
+		handler.AddNewProduct()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		require.Contains(t, res.Body.String(), `"code":"invalid_quantity"`)
+		require.Len(t, productRepository.GetAllProducts(), 1)
+	})
+
+	// Test 5: should record the initial stock as a lot expiring with the product
+	t.Run("should record the initial stock as a lot expiring with the product", func(t *testing.T) {
+		/* Initialize dependencies */
+		productStorage := initStorage(map[int]internal.TProduct{})
+		productRepository := repository.NewProductMap(&productStorage)
+		movementRepository := repository.NewMovementMap(initMovementStorage(map[int]internal.TMovement{}))
+		lotRepository := repository.NewLotMap(initLotStorage(map[int]internal.TLot{}))
+		movementService := service.NewMovementServiceDefault(productRepository, movementRepository)
+		movementService.SetLots(lotRepository, internal.LotPolicyFIFO)
+		productService := service.NewProductServiceDefault(productRepository)
+		productService.SetLedger(movementService)
+		handler := handlers.NewProductHandler(productService)
+
+		/* Prepare the request and the response */
+		reqBody := `{"name": "new product", "quantity": 5, "is_published": true, "code_value": "AX01", "expiration": "01/01/2030", "price": 20}`
+		req := httptest.NewRequest("POST", "/products/", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		res := httptest.NewRecorder()
+
+		handler.AddNewProduct()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusCreated, res.Code)
+		stored, err := productRepository.GetProductByID(1)
+		require.NoError(t, err)
+		require.Equal(t, 5, stored.Quantity)
+		lots, err := lotRepository.GetLotsByProduct(1)
+		require.NoError(t, err)
+		require.Len(t, lots, 1)
+		require.Equal(t, 5, lots[0].Quantity)
+		require.Equal(t, "01/01/2030", lots[0].Expiration)
+	})
 }
 
 // TestDeleteProduct test the DeleteProduct handler
@@ -346,12 +536,13 @@ func TestDeleteProduct(t *testing.T) {
 
 		/* Expected values definition */
 		expectedCode := http.StatusBadRequest
-		expectedBody := "Invalid ID."
-		expectedHeader := http.Header{"Content-Type": []string{"text/plain; charset=utf-8"}}
+		expectedBody := `{"type": "/problems/invalid_id", "code": "invalid_id", "title": "Invalid ID", "status": 400,
+			"detail": "invalid id: id=\"A2\"", "instance": "/products/A2"}`
+		expectedHeader := http.Header{"Content-Type": []string{"application/problem+json"}}
 
 		/* Assertions */
 		require.Equal(t, expectedCode, res.Code)
-		require.Equal(t, expectedBody, res.Body.String())
+		require.JSONEq(t, expectedBody, res.Body.String())
 		require.Equal(t, expectedHeader, res.Header())
 
 	})
@@ -379,12 +570,13 @@ func TestDeleteProduct(t *testing.T) {
 
 		/* Expected values definition */
 		expectedCode := http.StatusNotFound
-		expectedBody := "Product not found."
-		expectedHeader := http.Header{"Content-Type": []string{"text/plain; charset=utf-8"}}
+		expectedBody := `{"type": "/problems/product_not_found", "code": "product_not_found", "title": "Product not found", "status": 404,
+			"detail": "product not exists", "instance": "/products/3"}`
+		expectedHeader := http.Header{"Content-Type": []string{"application/problem+json"}}
 
 		/* Assertions */
 		require.Equal(t, expectedCode, res.Code)
-		require.Equal(t, expectedBody, res.Body.String())
+		require.JSONEq(t, expectedBody, res.Body.String())
 		require.Equal(t, expectedHeader, res.Header())
 	})
 
@@ -423,6 +615,51 @@ func TestDeleteProduct(t *testing.T) {
 		require.Equal(t, expectedHeader, res.Header())
 
 	})
+
+	// Test 5: should start a product reusing the id of a deleted one with an empty ledger
+	t.Run("should start a product reusing the id of a deleted one with an empty ledger", func(t *testing.T) {
+		/* Initialize dependencies */
+		productStorage := initStorage(map[int]internal.TProduct{})
+		productRepository := repository.NewProductMap(&productStorage)
+		movementRepository := repository.NewMovementMap(initMovementStorage(map[int]internal.TMovement{}))
+		lotRepository := repository.NewLotMap(initLotStorage(map[int]internal.TLot{}))
+		movementService := service.NewMovementServiceDefault(productRepository, movementRepository)
+		movementService.SetLots(lotRepository, internal.LotPolicyFIFO)
+		productService := service.NewProductServiceDefault(productRepository)
+		productService.SetLedger(movementService)
+		productService.AddDependent(movementService)
+		handler := handlers.NewProductHandler(productService)
+
+		/* Prepare the request and the response */
+		insert := func(quantity string) *httptest.ResponseRecorder {
+			reqBody := `{"name": "new product", "quantity": ` + quantity + `, "is_published": true, "code_value": "AX01", "expiration": "01/01/2030", "price": 20}`
+			req := httptest.NewRequest("POST", "/products/", strings.NewReader(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+			res := httptest.NewRecorder()
+			handler.AddNewProduct()(res, req)
+			return res
+		}
+		require.Equal(t, http.StatusCreated, insert("5").Code)
+		req := httptest.NewRequest("DELETE", "/products/1", nil)
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+		handler.DeleteProduct()(res, req)
+		require.Equal(t, http.StatusNoContent, res.Code)
+		res = insert("3")
+
+		/* Assertions */
+		require.Equal(t, http.StatusCreated, res.Code)
+		stored, err := productRepository.GetProductByID(1)
+		require.NoError(t, err)
+		require.Equal(t, 3, stored.Quantity)
+		movements, err := movementRepository.GetMovementsByProduct(1, time.Time{}, time.Time{})
+		require.NoError(t, err)
+		require.Len(t, movements, 1)
+		lots, err := lotRepository.GetLotsByProduct(1)
+		require.NoError(t, err)
+		require.Len(t, lots, 1)
+		require.Equal(t, 3, lots[0].Quantity)
+	})
 }
 
 // TestUpdateProduct tests the UpdateProduct handler
@@ -451,8 +688,9 @@ func TestUpdateProduct(t *testing.T) {
 			"expiration": "01/01/2000",
 			"price": 20
 		}`
-		req := httptest.NewRequest("PUT", "/products/", strings.NewReader(reqbody))
+		req := httptest.NewRequest("PUT", "/products/1", strings.NewReader(reqbody))
 		req.Header.Set("Content-Type", "application/json")
+		req = addURLParams(req, map[string]string{"id": "1"})
 		res := httptest.NewRecorder()
 		handler.UpdateProduct()(res, req)
 
@@ -501,18 +739,22 @@ func TestUpdateProduct(t *testing.T) {
 			"expiration": "01/01/2000",
 			"price": 20
 		}`
-		req := httptest.NewRequest("PUT", "/products/", strings.NewReader(reqbody))
+		req := httptest.NewRequest("PUT", "/products/1", strings.NewReader(reqbody))
+		req.Header.Set("Content-Type", "application/json")
+		req = addURLParams(req, map[string]string{"id": "1"})
 		res := httptest.NewRecorder()
 		handler.UpdateProduct()(res, req)
 
 		/* Expected values definition */
-		expectedCode := http.StatusBadRequest
-		expectedBody := "Invalid body."
-		expectedHeader := http.Header{"Content-Type": []string{"text/plain; charset=utf-8"}}
+		expectedCode := http.StatusUnprocessableEntity
+		expectedBody := `{"type": "/problems/invalid_fields", "code": "invalid_fields", "title": "Invalid fields", "status": 422,
+			"detail": "request fields invalid. /name: required field; /quantity: required field", "instance": "/products/1",
+			"errors": {"/name": "required field", "/quantity": "required field"}}`
+		expectedHeader := http.Header{"Content-Type": []string{"application/problem+json"}}
 
 		/* Assertions */
 		require.Equal(t, expectedCode, res.Code)
-		require.Equal(t, expectedBody, res.Body.String())
+		require.JSONEq(t, expectedBody, res.Body.String())
 		require.Equal(t, expectedHeader, res.Header())
 	})
 
@@ -540,21 +782,22 @@ func TestUpdateProduct(t *testing.T) {
 			"expiration": "01/01/2000",
 			"price": 20
 		}`
-		req := httptest.NewRequest("GET", "/products/1", strings.NewReader(reqbody))
+		req := httptest.NewRequest("PUT", "/products/3", strings.NewReader(reqbody))
 		req.Header.Set("Content-Type", "application/json")
-		req = addURLParams(req, map[string]string{"id": "1"})
+		req = addURLParams(req, map[string]string{"id": "3"})
 		res := httptest.NewRecorder()
 
 		handler.UpdateProduct()(res, req)
 
 		/* Expected values definition */
 		expectedCode := http.StatusNotFound
-		expectedBody := "Product not found."
-		expectedHeader := http.Header{"Content-Type": []string{"text/plain; charset=utf-8"}}
+		expectedBody := `{"type": "/problems/product_not_found", "code": "product_not_found", "title": "Product not found", "status": 404,
+			"detail": "product not exists", "instance": "/products/3"}`
+		expectedHeader := http.Header{"Content-Type": []string{"application/problem+json"}}
 
 		/* Assertions */
 		require.Equal(t, expectedCode, res.Code)
-		require.Equal(t, expectedBody, res.Body.String())
+		require.JSONEq(t, expectedBody, res.Body.String())
 		require.Equal(t, expectedHeader, res.Header())
 	})
 
@@ -604,4 +847,325 @@ func TestUpdateProduct(t *testing.T) {
 		require.Equal(t, expectedHeader, res.Header())
 
 	})
+
+	// Test 5: should reject bodies which don't match the request model
+	t.Run("should reject bodies which don't match the request model", func(t *testing.T) {
+		/* Prepare the test data */
+		initialProducts := map[int]internal.TProduct{
+			1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: false, Expiration: "11/11/2001", Price: 10.5},
+		}
+		fields := `"name": "new product", "quantity": 1000, "is_published": true, "code_value": "AX04", "expiration": "01/01/2000"`
+		cases := []struct {
+			name         string
+			reqbody      string
+			expectedCode int
+			expectedBody string
+		}{
+			{
+				name:         "unknown field",
+				reqbody:      `{"id": 1, ` + fields + `, "price": 20, "color": "red"}`,
+				expectedCode: http.StatusUnprocessableEntity,
+				expectedBody: `{"type": "/problems/invalid_fields", "code": "invalid_fields", "title": "Invalid fields", "status": 422,
+					"detail": "request fields invalid. /color: unexpected field", "instance": "/products/1",
+					"errors": {"/color": "unexpected field"}}`,
+			},
+			{
+				name:         "mistyped field",
+				reqbody:      `{"id": 1, ` + fields + `, "price": "20"}`,
+				expectedCode: http.StatusUnprocessableEntity,
+				expectedBody: `{"type": "/problems/invalid_fields", "code": "invalid_fields", "title": "Invalid fields", "status": 422,
+					"detail": "request fields invalid. /price: expected a number", "instance": "/products/1",
+					"errors": {"/price": "expected a number"}}`,
+			},
+			{
+				name:         "id mismatch",
+				reqbody:      `{"id": 2, ` + fields + `, "price": 20}`,
+				expectedCode: http.StatusUnprocessableEntity,
+				expectedBody: `{"type": "/problems/invalid_fields", "code": "invalid_fields", "title": "Invalid fields", "status": 422,
+					"detail": "request fields invalid. /id: does not match the url id 1", "instance": "/products/1",
+					"errors": {"/id": "does not match the url id 1"}}`,
+			},
+		}
+
+		for _, c := range cases {
+			/* Initialize dependencies */
+			storage := initStorage(initialProducts)
+			repository := repository.NewProductMap(&storage)
+			service := service.NewProductServiceDefault(repository)
+			handler := handlers.NewProductHandler(service)
+
+			/* Prepare the request and the response */
+			req := httptest.NewRequest("PUT", "/products/1", strings.NewReader(c.reqbody))
+			req.Header.Set("Content-Type", "application/json")
+			req = addURLParams(req, map[string]string{"id": "1"})
+			res := httptest.NewRecorder()
+
+			handler.UpdateProduct()(res, req)
+
+			/* Assertions */
+			require.Equal(t, c.expectedCode, res.Code, c.name)
+			require.JSONEq(t, c.expectedBody, res.Body.String(), c.name)
+			require.Equal(t, http.Header{"Content-Type": []string{"application/problem+json"}}, res.Header(), c.name)
+		}
+	})
+
+	// Test 6: should reject a quantity the ledger can't cover without updating the product
+	t.Run("should reject a quantity the ledger can't cover without updating the product", func(t *testing.T) {
+		/* Prepare the test data */
+		initialProducts := map[int]internal.TProduct{
+			1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: false, Expiration: "11/11/2001", Price: 10.5},
+		}
+		initialMovements := map[int]internal.TMovement{
+			1: {ID: 1, ProductID: 1, WarehouseID: 1, Type: internal.MovementReceipt, Quantity: 4, Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+			2: {ID: 2, ProductID: 1, WarehouseID: 2, Type: internal.MovementReceipt, Quantity: 6, Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		}
+		cases := []struct {
+			name         string
+			quantity     string
+			expectedCode int
+			expectedType string
+		}{
+			{name: "negative quantity", quantity: "-5", expectedCode: http.StatusBadRequest, expectedType: "invalid_quantity"},
+			{name: "stock held by another warehouse", quantity: "3", expectedCode: http.StatusConflict, expectedType: "insufficient_stock"},
+		}
+
+		for _, c := range cases {
+			/* Initialize dependencies */
+			productStorage := initStorage(initialProducts)
+			productRepository := repository.NewProductMap(&productStorage)
+			movementRepository := repository.NewMovementMap(initMovementStorage(initialMovements))
+			productService := service.NewProductServiceDefault(productRepository)
+			productService.SetLedger(service.NewMovementServiceDefault(productRepository, movementRepository))
+			handler := handlers.NewProductHandler(productService)
+
+			/* Prepare the request and the response */
+			reqBody := `{"id": 1, "name": "new product", "quantity": ` + c.quantity + `, "is_published": true, "code_value": "AX01", "expiration": "01/01/2000", "price": 20}`
+			req := httptest.NewRequest("PUT", "/products/1", strings.NewReader(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+			req = addURLParams(req, map[string]string{"id": "1"})
+			res := httptest.NewRecorder()
+
+			handler.UpdateProduct()(res, req)
+
+			/* Assertions */
+			require.Equal(t, c.expectedCode, res.Code, c.name)
+			require.Contains(t, res.Body.String(), `"code":"`+c.expectedType+`"`, c.name)
+			stored, err := productRepository.GetProductByID(1)
+			require.NoError(t, err)
+			require.Equal(t, initialProducts[1], stored, c.name)
+		}
+	})
+}
+
+// TestGetExpiringProducts tests the GetExpiringProducts handler
+func TestGetExpiringProducts(t *testing.T) {
+	// Test 1: should return the products expiring within the days
+	t.Run("should return the products expiring within the days", func(t *testing.T) {
+		/* Prepare the test data */
+		soon := time.Now().AddDate(0, 0, 3).Format("02/01/2006")
+		later := time.Now().AddDate(0, 0, 30).Format("02/01/2006")
+		initialProducts := map[int]internal.TProduct{
+			1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: true, Expiration: "11/11/2001", Price: 10.5},
+			2: {ID: 2, Name: "Product 2", Quantity: 20, CodeValue: "AX02", IsPublished: true, Expiration: soon, Price: 20.5},
+			3: {ID: 3, Name: "Product 3", Quantity: 30, CodeValue: "AX03", IsPublished: true, Expiration: later, Price: 30.5},
+		}
+
+		/* Initialize dependencies */
+		storage := initStorage(initialProducts)
+		repository := repository.NewProductMap(&storage)
+		service := service.NewProductServiceDefault(repository)
+		handler := handlers.NewProductHandler(service)
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("GET", "/products/expiring?days=7", nil)
+		res := httptest.NewRecorder()
+		handler.GetExpiringProducts()(res, req)
+
+		/* Expected values definition */
+		expectedCode := http.StatusOK
+		expectedBody := `{"data": [
+			{"id": 2, "name": "Product 2", "quantity": 20, "code_value": "AX02", "is_published": true, "expiration": "` + soon + `", "price": 20.5}
+		]}`
+
+		/* Assertions */
+		require.Equal(t, expectedCode, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+	})
+
+	// Test 2: should return a bad request error
+	t.Run("should return a bad request error", func(t *testing.T) {
+		/* Initialize dependencies */
+		storage := initStorage(map[int]internal.TProduct{})
+		repository := repository.NewProductMap(&storage)
+		service := service.NewProductServiceDefault(repository)
+		handler := handlers.NewProductHandler(service)
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("GET", "/products/expiring?days=week", nil)
+		res := httptest.NewRecorder()
+		handler.GetExpiringProducts()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		require.Equal(t, "application/problem+json", res.Header().Get("Content-Type"))
+		require.JSONEq(t, `{"type": "/problems/invalid_query", "code": "invalid_query", "title": "Invalid query param", "status": 400,
+			"detail": "invalid query param: days", "instance": "/products/expiring"}`, res.Body.String())
+	})
+}
+
+// TestSearchProducts tests the SearchProducts handler
+func TestSearchProducts(t *testing.T) {
+	/* Prepare the test data */
+	initialProducts := map[int]internal.TProduct{
+		1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: true, Expiration: "11/11/2001", Price: 10.5, Tags: []string{"organic", "promo"}},
+		2: {ID: 2, Name: "Product 2", Quantity: 20, CodeValue: "AX02", IsPublished: true, Expiration: "11/11/2002", Price: 20.5, Tags: []string{"organic"}},
+		3: {ID: 3, Name: "Product 3", Quantity: 30, CodeValue: "AX03", IsPublished: true, Expiration: "11/11/2003", Price: 30.5, Tags: []string{"gluten-free"}},
+	}
+
+	// Test 1: should return the products with all the tags
+	t.Run("should return the products with all the tags", func(t *testing.T) {
+		/* Initialize dependencies */
+		storage := initStorage(initialProducts)
+		repository := repository.NewProductMap(&storage)
+		service := service.NewProductServiceDefault(repository)
+		handler := handlers.NewProductHandler(service)
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("GET", "/products/search?tags=Organic,promo&match=all", nil)
+		res := httptest.NewRecorder()
+		handler.SearchProducts()(res, req)
+
+		/* Expected values definition */
+		expectedCode := http.StatusOK
+		expectedBody := `{"data": [
+			{"id": 1, "name": "Product 1", "quantity": 10, "code_value": "AX01", "is_published": true, "expiration": "11/11/2001", "price": 10.5, "tags": ["organic", "promo"]}
+		]}`
+
+		/* Assertions */
+		require.Equal(t, expectedCode, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+	})
+
+	// Test 2: should return the products with any of the tags
+	t.Run("should return the products with any of the tags", func(t *testing.T) {
+		/* Initialize dependencies */
+		storage := initStorage(initialProducts)
+		repository := repository.NewProductMap(&storage)
+		service := service.NewProductServiceDefault(repository)
+		handler := handlers.NewProductHandler(service)
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("GET", "/products/search?tags=promo,gluten-free&match=any", nil)
+		res := httptest.NewRecorder()
+		handler.SearchProducts()(res, req)
+
+		/* Expected values definition */
+		expectedCode := http.StatusOK
+		expectedBody := `{"data": [
+			{"id": 1, "name": "Product 1", "quantity": 10, "code_value": "AX01", "is_published": true, "expiration": "11/11/2001", "price": 10.5, "tags": ["organic", "promo"]},
+			{"id": 3, "name": "Product 3", "quantity": 30, "code_value": "AX03", "is_published": true, "expiration": "11/11/2003", "price": 30.5, "tags": ["gluten-free"]}
+		]}`
+
+		/* Assertions */
+		require.Equal(t, expectedCode, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+	})
+}
+
+// TestGetTags tests the GetTags handler
+func TestGetTags(t *testing.T) {
+	// Test 1: should return the tag usage counts after an insert
+	t.Run("should return the tag usage counts after an insert", func(t *testing.T) {
+		/* Prepare the test data */
+		initialProducts := map[int]internal.TProduct{
+			1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: true, Expiration: "11/11/2001", Price: 10.5, Tags: []string{"organic", "promo"}},
+		}
+
+		/* Initialize dependencies */
+		storage := initStorage(initialProducts)
+		repository := repository.NewProductMap(&storage)
+		service := service.NewProductServiceDefault(repository)
+		handler := handlers.NewProductHandler(service)
+
+		/* Insert a product whose tags need normalization */
+		reqBody := `{"name": "new product", "quantity": 5, "code_value": "AX02", "expiration": "01/01/2000", "price": 2,
+			"tags": [" Organic", "organic", "Gluten-Free"]}`
+		req := httptest.NewRequest("POST", "/products/", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		res := httptest.NewRecorder()
+		handler.AddNewProduct()(res, req)
+		require.Equal(t, http.StatusCreated, res.Code)
+
+		/* Prepare the request and the response */
+		req = httptest.NewRequest("GET", "/tags", nil)
+		res = httptest.NewRecorder()
+		handler.GetTags()(res, req)
+
+		/* Expected values definition */
+		expectedCode := http.StatusOK
+		expectedBody := `{"data": [
+			{"tag": "organic", "count": 2},
+			{"tag": "gluten-free", "count": 1},
+			{"tag": "promo", "count": 1}
+		]}`
+
+		/* Assertions */
+		require.Equal(t, expectedCode, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+	})
+
+	// Test 2: should fail instead of panicking if the storage can't be read
+	t.Run("should fail instead of panicking if the storage can't be read", func(t *testing.T) {
+		/* Initialize dependencies */
+		storage := storage.NewProductStorageDefault("/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/internal/handlers/missing/products_test.json")
+		repository := repository.NewProductMap(storage)
+		service := service.NewProductServiceDefault(repository)
+		handler := handlers.NewProductHandler(service)
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("GET", "/tags", nil)
+		res := httptest.NewRecorder()
+		handler.GetTags()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.Equal(t, "application/problem+json", res.Header().Get("Content-Type"))
+	})
+}
+
+// TestGetReorderReport tests the GetReorderReport handler
+func TestGetReorderReport(t *testing.T) {
+	// Test 1: should suggest enough units to get above the reorder point
+	t.Run("should suggest enough units to get above the reorder point", func(t *testing.T) {
+		/* Prepare the test data */
+		low, exact, high := 20, 10, 5
+		initialProducts := map[int]internal.TProduct{
+			1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: true, Expiration: "11/11/2001", Price: 10.5, ReorderPoint: &low, ReorderQuantity: 5},
+			2: {ID: 2, Name: "Product 2", Quantity: 10, CodeValue: "AX02", IsPublished: true, Expiration: "11/11/2002", Price: 20.5, ReorderPoint: &exact, ReorderQuantity: 50},
+			3: {ID: 3, Name: "Product 3", Quantity: 10, CodeValue: "AX03", IsPublished: true, Expiration: "11/11/2003", Price: 30.5, ReorderPoint: &high},
+			4: {ID: 4, Name: "Product 4", Quantity: 0, CodeValue: "AX04", IsPublished: true, Expiration: "11/11/2004", Price: 40.5},
+		}
+
+		/* Initialize dependencies */
+		storage := initStorage(initialProducts)
+		repository := repository.NewProductMap(&storage)
+		service := service.NewProductServiceDefault(repository)
+		handler := handlers.NewProductHandler(service)
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("GET", "/products/reorder", nil)
+		res := httptest.NewRecorder()
+		handler.GetReorderReport()(res, req)
+
+		/* Expected values definition */
+		expectedCode := http.StatusOK
+		expectedBody := `{"data": [
+			{"product_id": 1, "name": "Product 1", "code_value": "AX01", "quantity": 10, "reorder_point": 20, "suggested_quantity": 11},
+			{"product_id": 2, "name": "Product 2", "code_value": "AX02", "quantity": 10, "reorder_point": 10, "suggested_quantity": 50}
+		]}`
+
+		/* Assertions */
+		require.Equal(t, expectedCode, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+	})
 }
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"proyecto/internal"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/* Server-Sent Events */
+const (
+	MediaTypeEventStream  = "text/event-stream"
+	LastEventIDHeader     = "Last-Event-ID"
+	ProductEventsReset    = "reset"          // Sent first when the stream can't be resumed: the client must reload the catalog
+	DefaultEventHeartbeat = 15 * time.Second // Time between two heartbeats of an idle stream
+)
+
+/* Product event handler definition */
+type ProductEventHandler struct {
+	bus       internal.ProductEventBus // Bus the product events are read from
+	heartbeat time.Duration            // Time between two heartbeats of an idle stream
+}
+
+// NewProductEventHandler creates a new ProductEventHandler
+// NewProductEventHandler(bus internal.ProductEventBus, heartbeat time.Duration) -> *ProductEventHandler
+// Args:
+//		bus: 	   Product event bus
+//		heartbeat: Time between two heartbeats of an idle stream. DefaultEventHeartbeat when not positive
+// Return:
+//		*ProductEventHandler: New ProductEventHandler instance
+
+func NewProductEventHandler(bus internal.ProductEventBus, heartbeat time.Duration) *ProductEventHandler {
+	if heartbeat <= 0 {
+		heartbeat = DefaultEventHeartbeat
+	}
+	return &ProductEventHandler{bus: bus, heartbeat: heartbeat}
+}
+
+// productEventFilter selects the events a stream is subscribed to
+type productEventFilter struct {
+	products map[int]bool    // Products of the events. Empty for every product
+	types    map[string]bool // Types of the events. Empty for every type
+}
+
+// match checks if an event passes the filter
+// match(event internal.TProductEvent) -> bool
+
+func (f productEventFilter) match(event internal.TProductEvent) bool {
+	return (len(f.products) == 0 || f.products[event.ProductID]) && (len(f.types) == 0 || f.types[event.Type])
+}
+
+// parseProductEventFilter reads the filter of a stream from the productId and type query params
+// parseProductEventFilter(r *http.Request) -> (productEventFilter, error)
+
+func parseProductEventFilter(r *http.Request) (productEventFilter, error) {
+	filter := productEventFilter{products: make(map[int]bool), types: make(map[string]bool)}
+	for _, value := range strings.Split(r.URL.Query().Get("productId"), ",") {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			return filter, fmt.Errorf("%w: productId=%q", ErrInvalidQuery, value)
+		}
+		filter.products[id] = true
+	}
+	for _, value := range strings.Split(r.URL.Query().Get("type"), ",") {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		known := false
+		for _, eventType := range internal.ProductEventTypes {
+			known = known || eventType == value
+		}
+		if !known {
+			return filter, fmt.Errorf("%w: type=%q, expected one of %s", ErrInvalidQuery, value, strings.Join(internal.ProductEventTypes, ", "))
+		}
+		filter.types[value] = true
+	}
+	return filter, nil
+}
+
+// writeEvent writes an event of the stream and flushes it to the client
+// writeEvent(w http.ResponseWriter, flusher http.Flusher, id int64, eventType string, data any) -> error
+
+func writeEvent(w http.ResponseWriter, flusher http.Flusher, id int64, eventType string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if id > 0 {
+		fmt.Fprintf(w, "id: %d\n", id)
+	}
+	if _, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", eventType, payload); err != nil {
+		return err
+	}
+	flusher.Flush()
+	return nil
+}
+
+// StreamProductEvents streams the product events as Server-Sent Events until the client leaves.
+// A client reconnecting with the Last-Event-ID header (or lastEventId query param) first gets the
+// retained events it missed. When some were no longer retained a reset event is sent first
+// URL params : productId (optional, comma separated ids), type (optional, comma separated types),
+//
+//	lastEventId (optional, used without the Last-Event-ID header)
+//
+// Body params: none
+func (h *ProductEventHandler) StreamProductEvents() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the filter and the resume point from the request */
+		filter, err := parseProductEventFilter(r)
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+		lastEventID := r.Header.Get(LastEventIDHeader)
+		if lastEventID == "" {
+			lastEventID = r.URL.Query().Get("lastEventId")
+		}
+		var lastID int64
+		if lastEventID != "" {
+			if lastID, err = strconv.ParseInt(lastEventID, 10, 64); err != nil || lastID < 0 {
+				productError(w, r, fmt.Errorf("%w: %s=%q", ErrInvalidQuery, LastEventIDHeader, lastEventID))
+				return
+			}
+		}
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+
+		/* Subscribe before reading the backlog, so no event falls in between */
+		events, cancel := h.bus.Subscribe()
+		defer cancel()
+		backlog, err := h.bus.Since(lastID)
+		if err != nil && !errors.Is(err, internal.ErrProductEventsEvicted) {
+			productError(w, r, err)
+			return
+		}
+
+		/* Open the stream */
+		w.Header().Set("Content-Type", MediaTypeEventStream)
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		/* Replay the missed events */
+		if errors.Is(err, internal.ErrProductEventsEvicted) {
+			if writeEvent(w, flusher, 0, ProductEventsReset, map[string]any{"last_event_id": lastID}) != nil {
+				return
+			}
+			lastID = 0
+		}
+		for _, event := range backlog {
+			if event.ID > lastID {
+				lastID = event.ID
+			}
+			if filter.match(event) && writeEvent(w, flusher, event.ID, event.Type, event) != nil {
+				return
+			}
+		}
+
+		/* Follow the new events, sending a heartbeat while idle */
+		heartbeat := time.NewTicker(h.heartbeat)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+					return
+				}
+				flusher.Flush()
+			case event, open := <-events:
+				if !open {
+					return
+				}
+				if event.ID <= lastID || !filter.match(event) {
+					continue
+				}
+				lastID = event.ID
+				if writeEvent(w, flusher, event.ID, event.Type, event) != nil {
+					return
+				}
+				heartbeat.Reset(h.heartbeat)
+			}
+		}
+	}
+}
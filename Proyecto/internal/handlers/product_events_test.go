@@ -0,0 +1,254 @@
+package handlers_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"proyecto/internal"
+	"proyecto/internal/handlers"
+	"proyecto/internal/repository"
+	"proyecto/internal/service"
+	"proyecto/internal/storage"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// productEventsPath is the storage of the product events of the tests
+const productEventsPath = "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/internal/handlers/product_events_test.json"
+
+// initProductEventBus initializes a product event bus over an empty storage
+// initProductEventBus(capacity int) -> *service.ProductEventBusDefault
+// Args:
+// 	capacity: Events retained
+// Returns:
+// 	*service.ProductEventBusDefault: Initialized bus
+
+func initProductEventBus(capacity int) *service.ProductEventBusDefault {
+	/* Storage creation */
+	eventStorage := storage.NewProductEventStorageDefault(productEventsPath)
+	if err := eventStorage.WriteAll(nil); err != nil {
+		panic(err)
+	}
+	return service.NewProductEventBusDefault(eventStorage, capacity)
+}
+
+// sseEvent is an event, or a comment, read from a stream
+type sseEvent struct {
+	id      string
+	event   string
+	data    string
+	comment string
+}
+
+// readSSEEvent reads the next event of a stream
+// readSSEEvent(t *testing.T, reader *bufio.Reader) -> sseEvent
+
+func readSSEEvent(t *testing.T, reader *bufio.Reader) sseEvent {
+	var event sseEvent
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return event
+		}
+		field, value, _ := strings.Cut(line, ": ")
+		switch field {
+		case "id":
+			event.id = value
+		case "event":
+			event.event = value
+		case "data":
+			event.data = value
+		case "":
+			event.comment = value
+		}
+	}
+}
+
+// openProductEventStream opens a stream of product events on a test server
+// openProductEventStream(t *testing.T, url string, lastEventID string) -> *bufio.Reader
+
+func openProductEventStream(t *testing.T, url string, lastEventID string) *bufio.Reader {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		req.Header.Set(handlers.LastEventIDHeader, lastEventID)
+	}
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { res.Body.Close() })
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, handlers.MediaTypeEventStream, res.Header.Get("Content-Type"))
+	require.Equal(t, "no-cache", res.Header.Get("Cache-Control"))
+	return bufio.NewReader(res.Body)
+}
+
+// TestStreamProductEvents tests the StreamProductEvents handler
+func TestStreamProductEvents(t *testing.T) {
+	/* Prepare the test data */
+	initialProducts := map[int]internal.TProduct{
+		1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: false, Expiration: "11/11/2001", Price: 10.5},
+		2: {ID: 2, Name: "Product 2", Quantity: 20, CodeValue: "AX02", IsPublished: true, Expiration: "11/11/2002", Price: 20.5},
+	}
+
+	/* newServer serves the stream of a bus fed by a product service */
+	newServer := func(bus *service.ProductEventBusDefault, heartbeat time.Duration) (*httptest.Server, *service.ProductServiceDefault) {
+		productStorage := initStorage(initialProducts)
+		productService := service.NewProductServiceDefault(repository.NewProductMap(&productStorage))
+		productService.SetEvents(bus)
+		router := chi.NewRouter()
+		router.Get("/products/events", handlers.NewProductEventHandler(bus, heartbeat).StreamProductEvents())
+		server := httptest.NewServer(router)
+		t.Cleanup(server.Close)
+		t.Cleanup(bus.Close)
+		return server, productService
+	}
+
+	// Test 1: should stream the changes made through the service
+	t.Run("should stream the changes made through the service", func(t *testing.T) {
+		/* Initialize dependencies */
+		bus := initProductEventBus(10)
+		server, productService := newServer(bus, time.Minute)
+		reader := openProductEventStream(t, server.URL+"/products/events", "")
+
+		/* Change the catalog */
+		product := internal.TProduct{Name: "Product 3", Quantity: 5, CodeValue: "AX03", Expiration: "01/01/2000", Price: 3}
+		require.NoError(t, productService.InsertNewProduct(&product))
+		published := initialProducts[1]
+		published.IsPublished = true
+		require.NoError(t, productService.UpdateProduct(&published))
+		renamed := initialProducts[2]
+		renamed.Name = "Product 2 renamed"
+		require.NoError(t, productService.UpdateProduct(&renamed))
+		require.NoError(t, productService.DeleteProduct(3))
+		repriced := renamed
+		repriced.Price = 22
+		require.NoError(t, productService.UpdateProduct(&repriced))
+
+		/* Assertions */
+		event := readSSEEvent(t, reader)
+		require.Equal(t, sseEvent{id: "1", event: internal.ProductEventInserted}, sseEvent{id: event.id, event: event.event})
+		var data internal.TProductEvent
+		require.NoError(t, json.Unmarshal([]byte(event.data), &data))
+		require.Equal(t, int64(1), data.ID)
+		require.Equal(t, 3, data.ProductID)
+		require.Equal(t, "Product 3", data.Product.Name)
+
+		event = readSSEEvent(t, reader)
+		require.Equal(t, sseEvent{id: "2", event: internal.ProductEventPublished}, sseEvent{id: event.id, event: event.event})
+		event = readSSEEvent(t, reader)
+		require.Equal(t, sseEvent{id: "3", event: internal.ProductEventUpdated}, sseEvent{id: event.id, event: event.event})
+		event = readSSEEvent(t, reader)
+		require.Equal(t, sseEvent{id: "4", event: internal.ProductEventDeleted}, sseEvent{id: event.id, event: event.event})
+		data = internal.TProductEvent{}
+		require.NoError(t, json.Unmarshal([]byte(event.data), &data))
+		require.Equal(t, 3, data.ProductID)
+		require.Nil(t, data.Product)
+		event = readSSEEvent(t, reader)
+		data = internal.TProductEvent{}
+		require.NoError(t, json.Unmarshal([]byte(event.data), &data))
+		require.Equal(t, internal.ProductEventUpdated, data.Type)
+		require.Equal(t, []string{internal.ProductChangePrice}, data.Changes)
+	})
+
+	// Test 2: should resume after the Last-Event-ID with the filters applied
+	t.Run("should resume after the Last-Event-ID with the filters applied", func(t *testing.T) {
+		/* Initialize dependencies */
+		bus := initProductEventBus(10)
+		server, _ := newServer(bus, time.Minute)
+		for _, event := range []internal.TProductEvent{
+			{Type: internal.ProductEventUpdated, ProductID: 1},
+			{Type: internal.ProductEventUpdated, ProductID: 2},
+			{Type: internal.ProductEventDeleted, ProductID: 1},
+			{Type: internal.ProductEventUpdated, ProductID: 1},
+		} {
+			require.NoError(t, bus.Publish(&event))
+		}
+
+		/* Resume after the first event, following only the updates of product 1 */
+		reader := openProductEventStream(t, server.URL+"/products/events?productId=1&type=product.updated", "1")
+		require.NoError(t, bus.Publish(&internal.TProductEvent{Type: internal.ProductEventUpdated, ProductID: 2}))
+		require.NoError(t, bus.Publish(&internal.TProductEvent{Type: internal.ProductEventUpdated, ProductID: 1}))
+
+		/* Assertions */
+		require.Equal(t, "4", readSSEEvent(t, reader).id)
+		require.Equal(t, "6", readSSEEvent(t, reader).id)
+	})
+
+	// Test 3: should send a reset when the missed events are no longer retained
+	t.Run("should send a reset when the missed events are no longer retained", func(t *testing.T) {
+		/* Initialize dependencies */
+		bus := initProductEventBus(2)
+		server, _ := newServer(bus, time.Minute)
+		for i := 1; i <= 4; i++ {
+			require.NoError(t, bus.Publish(&internal.TProductEvent{Type: internal.ProductEventUpdated, ProductID: i}))
+		}
+
+		/* Resume after an evicted event */
+		reader := openProductEventStream(t, server.URL+"/products/events", "1")
+
+		/* Assertions */
+		event := readSSEEvent(t, reader)
+		require.Equal(t, sseEvent{event: handlers.ProductEventsReset, data: `{"last_event_id":1}`}, event)
+		require.Equal(t, "3", readSSEEvent(t, reader).id)
+		require.Equal(t, "4", readSSEEvent(t, reader).id)
+	})
+
+	// Test 4: should resume from the retained events after a restart
+	t.Run("should resume from the retained events after a restart", func(t *testing.T) {
+		/* Initialize dependencies */
+		bus := initProductEventBus(2)
+		for i := 1; i <= 3; i++ {
+			require.NoError(t, bus.Publish(&internal.TProductEvent{Type: internal.ProductEventUpdated, ProductID: i}))
+		}
+		restarted := service.NewProductEventBusDefault(storage.NewProductEventStorageDefault(productEventsPath), 2)
+		server, _ := newServer(restarted, time.Minute)
+
+		/* Resume after a retained event */
+		reader := openProductEventStream(t, server.URL+"/products/events", "2")
+		require.NoError(t, restarted.Publish(&internal.TProductEvent{Type: internal.ProductEventDeleted, ProductID: 1}))
+
+		/* Assertions */
+		require.Equal(t, "3", readSSEEvent(t, reader).id)
+		event := readSSEEvent(t, reader)
+		require.Equal(t, sseEvent{id: "4", event: internal.ProductEventDeleted}, sseEvent{id: event.id, event: event.event})
+	})
+
+	// Test 5: should send heartbeats while idle
+	t.Run("should send heartbeats while idle", func(t *testing.T) {
+		/* Initialize dependencies */
+		bus := initProductEventBus(10)
+		server, _ := newServer(bus, 10*time.Millisecond)
+		reader := openProductEventStream(t, server.URL+"/products/events", "")
+
+		/* Assertions */
+		require.Equal(t, sseEvent{comment: "heartbeat"}, readSSEEvent(t, reader))
+	})
+
+	// Test 6: should reject invalid filters
+	t.Run("should reject invalid filters", func(t *testing.T) {
+		/* Initialize dependencies */
+		bus := initProductEventBus(10)
+		handler := handlers.NewProductEventHandler(bus, time.Minute)
+
+		for _, target := range []string{"/products/events?productId=x", "/products/events?type=product.renamed", "/products/events?lastEventId=-1"} {
+			/* Prepare the request and the response */
+			req := httptest.NewRequest("GET", target, nil)
+			res := httptest.NewRecorder()
+			handler.StreamProductEvents()(res, req)
+
+			/* Assertions */
+			require.Equal(t, http.StatusBadRequest, res.Code, target)
+			require.Equal(t, "application/problem+json", res.Header().Get("Content-Type"), target)
+		}
+	})
+}
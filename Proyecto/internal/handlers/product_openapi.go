@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"net/http"
+	"proyecto/internal"
+	"proyecto/platform/web/openapi"
+	"proyecto/platform/web/patch"
+	"proyecto/platform/web/response"
+)
+
+/* Media types of the product endpoints */
+var (
+	productReadTypes  = []string{response.MediaTypeJSON, response.MediaTypeXML, response.MediaTypeCSV}
+	productWriteTypes = []string{"application/json", "application/xml", "text/xml"}
+)
+
+// ProductSpec describes the routes of the product API, /products and /tags. The v1 routes are
+// served unprefixed and under /v1, and the v2 product resource under /v2. The unprefixed product
+// resource serves v2 too, with the MediaTypeProductV2 bodies of the requests asking for it
+var ProductSpec = openapi.NewSpec("Product API", "2.0.0").
+	SetProblem(response.Problem{}).
+
+	/* Products */
+	Describe("GET", "/products/", openapi.Operation{
+		ID: "GetAllProducts", Tag: "Products", Summary: "List the products", Deprecated: true,
+		Response: []internal.TProduct{}, ResponseTypes: productReadTypes,
+		Responses: map[string]any{MediaTypeProductV2: []ProductV2JSON{}},
+		Problems:  []int{http.StatusNotAcceptable},
+	}).
+	Describe("GET", "/products/{id}", openapi.Operation{
+		ID: "GetProductByID", Tag: "Products", Summary: "Get a product by id", Deprecated: true,
+		Response: internal.TProduct{}, ResponseTypes: productReadTypes,
+		Responses: map[string]any{MediaTypeProductV2: ProductV2JSON{}},
+		Problems:  []int{http.StatusBadRequest, http.StatusNotFound, http.StatusNotAcceptable},
+	}).
+	Describe("GET", "/products/search", openapi.Operation{
+		ID: "SearchProducts", Tag: "Products", Summary: "Search products by tags and price. Without tags the products are answered as a bare array",
+		Query: []openapi.Parameter{
+			{Name: "tags", Type: "string", Description: "Comma separated tags to search"},
+			{Name: "match", Type: "string", Description: "Products must have all or any (default) of the tags"},
+			{Name: "priceGt", Type: "number", Description: "Minimum price, exclusive. Required without tags"},
+		},
+		Response: []internal.TProduct{}, ResponseTypes: productReadTypes,
+		Problems: []int{http.StatusBadRequest, http.StatusNotAcceptable},
+	}).
+	Describe("GET", "/products/expiring", openapi.Operation{
+		ID: "GetExpiringProducts", Tag: "Products", Summary: "List the products expiring within the next days",
+		Query:    []openapi.Parameter{{Name: "days", Type: "integer", Description: "Days to look ahead (default 7)"}},
+		Response: []internal.TProduct{}, ResponseTypes: productReadTypes,
+		Problems: []int{http.StatusBadRequest, http.StatusNotAcceptable},
+	}).
+	Describe("GET", "/products/reorder", openapi.Operation{
+		ID: "GetReorderReport", Tag: "Products", Summary: "List the products at or below their reorder point",
+		Response: []internal.TReorderSuggestion{}, ResponseTypes: productReadTypes,
+		Problems: []int{http.StatusNotAcceptable},
+	}).
+	Describe("GET", "/products/events", openapi.Operation{
+		ID: "StreamProductEvents", Tag: "Products", Summary: "Stream the product changes as Server-Sent Events. Resumes after the Last-Event-ID header",
+		Query: []openapi.Parameter{
+			{Name: "productId", Type: "string", Description: "Comma separated ids of the products to follow (default all)"},
+			{Name: "type", Type: "string", Description: "Comma separated event types to follow: product.inserted, product.updated, product.deleted, product.published, product.unpublished (default all)"},
+			{Name: "lastEventId", Type: "integer", Description: "Id of the last event received, for clients unable to send the Last-Event-ID header"},
+		},
+		ResponseTypes: []string{MediaTypeEventStream},
+		Problems:      []int{http.StatusBadRequest},
+	}).
+	Describe("POST", "/products/", openapi.Operation{
+		ID: "AddNewProduct", Tag: "Products", Summary: "Create a product", Deprecated: true,
+		Request: BodyRequestProductJSON{}, RequestTypes: productWriteTypes,
+		Requests: map[string]any{MediaTypeProductV2: BodyRequestProductV2JSON{}},
+		Status:   http.StatusCreated, Response: ProductJSON{}, Members: map[string]any{"message": ""},
+		Responses: map[string]any{MediaTypeProductV2: ProductV2JSON{}},
+		Problems:  []int{http.StatusBadRequest, http.StatusConflict, http.StatusNotAcceptable, http.StatusUnsupportedMediaType, http.StatusUnprocessableEntity},
+	}).
+	Describe("PUT", "/products/{id}", openapi.Operation{
+		ID: "UpdateProduct", Tag: "Products", Summary: "Replace a product", Deprecated: true,
+		Request: BodyRequestProductPutJSON{}, RequestTypes: productWriteTypes,
+		Requests: map[string]any{MediaTypeProductV2: BodyRequestProductV2JSON{}},
+		Response: ProductJSON{}, Members: map[string]any{"message": ""},
+		Responses: map[string]any{MediaTypeProductV2: ProductV2JSON{}},
+		Problems:  []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusNotAcceptable, http.StatusUnsupportedMediaType, http.StatusUnprocessableEntity},
+	}).
+	Describe("PATCH", "/products/{id}", openapi.Operation{
+		ID: "UpdateProductPartial", Tag: "Products", Summary: "Update some fields of a product",
+		Requests: map[string]any{
+			patch.MediaTypeMergePatch: ProductMergePatchJSON{},
+			"application/json":        ProductMergePatchJSON{},
+			patch.MediaTypeJSONPatch:  []patch.Operation{},
+		},
+		Response: internal.TProduct{}, Members: map[string]any{"message": ""},
+		Problems: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnsupportedMediaType, http.StatusUnprocessableEntity},
+	}).
+	Describe("DELETE", "/products/{id}", openapi.Operation{
+		ID: "DeleteProduct", Tag: "Products", Summary: "Delete a product",
+		Status:   http.StatusNoContent,
+		Problems: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+	}).
+	Describe("GET", "/products/{id}/barcode", openapi.Operation{
+		ID: "GetProductBarcode", Tag: "Products", Summary: "Render the code of a product as a barcode",
+		Query: []openapi.Parameter{
+			{Name: "symbology", Type: "string", Description: "code128 (default) or ean13"},
+			{Name: "format", Type: "string", Description: "svg (default) or png"},
+		},
+		ResponseTypes: []string{"image/svg+xml", "image/png"},
+		Problems:      []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity},
+	}).
+	Describe("GET", "/tags", openapi.Operation{
+		ID: "GetTags", Tag: "Products", Summary: "List the tags in use with their product count",
+		Response: []internal.TTagCount{}, ResponseTypes: productReadTypes,
+		Problems: []int{http.StatusNotAcceptable},
+	}).
+
+	/* Variants */
+	Describe("GET", "/products/{id}/variants", openapi.Operation{
+		ID: "GetVariants", Tag: "Variants", Summary: "List the variants of a product", Deprecated: true,
+		Response: []internal.TProduct{}, ResponseTypes: productReadTypes,
+		Responses: map[string]any{MediaTypeProductV2: []ProductV2JSON{}},
+		Problems:  []int{http.StatusBadRequest, http.StatusNotFound, http.StatusNotAcceptable},
+	}).
+	Describe("POST", "/products/{id}/variants", openapi.Operation{
+		ID: "AddNewVariant", Tag: "Variants", Summary: "Create a variant of a product",
+		Request: BodyRequestVariantJSON{}, RequestTypes: productWriteTypes,
+		Status: http.StatusCreated, Response: internal.TProduct{}, Members: map[string]any{"message": ""},
+		Problems: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnsupportedMediaType},
+	}).
+	Describe("PUT", "/products/{id}/variants/{variantID}", openapi.Operation{
+		ID: "UpdateVariant", Tag: "Variants", Summary: "Replace a variant of a product",
+		Request: BodyRequestVariantJSON{}, RequestTypes: productWriteTypes,
+		Response: internal.TProduct{}, Members: map[string]any{"message": ""},
+		Problems: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnsupportedMediaType},
+	}).
+	Describe("DELETE", "/products/{id}/variants/{variantID}", openapi.Operation{
+		ID: "DeleteVariant", Tag: "Variants", Summary: "Delete a variant of a product",
+		Status:   http.StatusNoContent,
+		Problems: []int{http.StatusBadRequest, http.StatusNotFound},
+	}).
+
+	/* Stock ledger */
+	Describe("GET", "/products/{id}/movements", openapi.Operation{
+		ID: "GetMovementsByProduct", Tag: "Stock", Summary: "List the stock movements of a product",
+		Query: []openapi.Parameter{
+			{Name: "from", Type: "string", Description: "First day, dd/mm/yyyy"},
+			{Name: "to", Type: "string", Description: "Last day, dd/mm/yyyy"},
+		},
+		Response: []internal.TMovement{},
+		Problems: []int{http.StatusBadRequest, http.StatusNotFound},
+	}).
+	Describe("POST", "/products/{id}/movements", openapi.Operation{
+		ID: "PostMovement", Tag: "Stock", Summary: "Record a stock movement of a product",
+		Request: BodyRequestMovementJSON{},
+		Status:  http.StatusCreated, Response: internal.TMovement{}, Members: map[string]any{"message": ""},
+		Problems: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnsupportedMediaType},
+	}).
+	Describe("GET", "/products/{id}/stock", openapi.Operation{
+		ID: "GetStockLevels", Tag: "Stock", Summary: "Get the stock of a product by warehouse",
+		Response: []internal.TStockLevel{}, Members: map[string]any{"total": 0},
+		Problems: []int{http.StatusBadRequest, http.StatusNotFound},
+	}).
+	Describe("POST", "/products/{id}/transfers", openapi.Operation{
+		ID: "TransferStock", Tag: "Stock", Summary: "Move stock of a product between warehouses",
+		Request: BodyRequestTransferJSON{},
+		Status:  http.StatusCreated, Response: []internal.TMovement{}, Members: map[string]any{"message": ""},
+		Problems: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnsupportedMediaType},
+	}).
+	Describe("GET", "/products/{id}/lots", openapi.Operation{
+		ID: "GetLotsByProduct", Tag: "Stock", Summary: "List the lots of a product still in stock",
+		Response: []internal.TLot{},
+		Problems: []int{http.StatusBadRequest, http.StatusNotFound},
+	}).
+
+	/* Pricing */
+	Describe("GET", "/products/{id}/prices", openapi.Operation{
+		ID: "GetPriceTimeline", Tag: "Pricing", Summary: "Get the list and effective prices of a product over time",
+		Response: []internal.TPricePoint{},
+		Problems: []int{http.StatusBadRequest, http.StatusNotFound},
+	}).
+	Describe("GET", "/products/{id}/promotions", openapi.Operation{
+		ID: "GetPromotions", Tag: "Pricing", Summary: "List the promotions of a product",
+		Response: []internal.TPromotion{},
+		Problems: []int{http.StatusBadRequest, http.StatusNotFound},
+	}).
+	Describe("POST", "/products/{id}/promotions", openapi.Operation{
+		ID: "AddNewPromotion", Tag: "Pricing", Summary: "Create a promotion of a product",
+		Request: BodyRequestPromotionJSON{},
+		Status:  http.StatusCreated, Response: internal.TPromotion{}, Members: map[string]any{"message": ""},
+		Problems: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnsupportedMediaType},
+	}).
+	Describe("GET", "/products/{id}/tax", openapi.Operation{
+		ID: "GetProductTax", Tag: "Pricing", Summary: "Get the net, tax and gross price of a product",
+		Query:    []openapi.Parameter{{Name: "jurisdiction", Type: "string", Description: "Jurisdiction whose rates apply (default jurisdiction)"}},
+		Response: internal.TTaxBreakdown{},
+		Problems: []int{http.StatusBadRequest, http.StatusNotFound},
+	}).
+
+	/* Suppliers and categories */
+	Describe("GET", "/products/{id}/suppliers", openapi.Operation{
+		ID: "GetSuppliersByProduct", Tag: "Suppliers", Summary: "List the suppliers of a product",
+		Response: []internal.TProductSupplier{},
+		Problems: []int{http.StatusBadRequest, http.StatusNotFound},
+	}).
+	Describe("GET", "/products/{id}/categories", openapi.Operation{
+		ID: "GetProductCategories", Tag: "Categories", Summary: "List the categories of a product",
+		Response: []internal.TCategory{},
+		Problems: []int{http.StatusBadRequest, http.StatusNotFound},
+	}).
+	Describe("PUT", "/products/{id}/categories", openapi.Operation{
+		ID: "UpdateProductCategories", Tag: "Categories", Summary: "Replace the categories of a product",
+		Request:  BodyRequestProductCategoriesJSON{},
+		Response: []internal.TCategory{}, Members: map[string]any{"message": ""},
+		Problems: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnsupportedMediaType},
+	}).
+
+	/* v1 under its version prefix */
+	Alias("/v1", "V1").
+
+	/* v2 product resource */
+	Describe("GET", "/v2/products/", openapi.Operation{
+		ID: "GetAllProductsV2", Tag: "Products v2", Summary: "List the products",
+		Response: []ProductV2JSON{},
+		Problems: []int{http.StatusNotAcceptable},
+	}).
+	Describe("GET", "/v2/products/{id}", openapi.Operation{
+		ID: "GetProductByIDV2", Tag: "Products v2", Summary: "Get a product by id",
+		Response: ProductV2JSON{},
+		Problems: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusNotAcceptable},
+	}).
+	Describe("POST", "/v2/products/", openapi.Operation{
+		ID: "AddNewProductV2", Tag: "Products v2", Summary: "Create a product",
+		Request: BodyRequestProductV2JSON{},
+		Status:  http.StatusCreated, Response: ProductV2JSON{}, Members: map[string]any{"message": ""},
+		Problems: []int{http.StatusBadRequest, http.StatusConflict, http.StatusNotAcceptable, http.StatusUnsupportedMediaType, http.StatusUnprocessableEntity},
+	}).
+	Describe("PUT", "/v2/products/{id}", openapi.Operation{
+		ID: "UpdateProductV2", Tag: "Products v2", Summary: "Replace a product",
+		Request:  BodyRequestProductV2JSON{},
+		Response: ProductV2JSON{}, Members: map[string]any{"message": ""},
+		Problems: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusNotAcceptable, http.StatusUnsupportedMediaType, http.StatusUnprocessableEntity},
+	}).
+	Describe("DELETE", "/v2/products/{id}", openapi.Operation{
+		ID: "DeleteProductV2", Tag: "Products v2", Summary: "Delete a product",
+		Status:   http.StatusNoContent,
+		Problems: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+	}).
+	Describe("GET", "/v2/products/{id}/variants", openapi.Operation{
+		ID: "GetVariantsV2", Tag: "Products v2", Summary: "List the variants of a product",
+		Response: []ProductV2JSON{},
+		Problems: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusNotAcceptable},
+	})
@@ -0,0 +1,171 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"proyecto/internal"
+	"proyecto/internal/handlers"
+	"proyecto/internal/middleware"
+	"proyecto/internal/repository"
+	"proyecto/internal/service"
+	"proyecto/platform/web/response"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// newValidatedRouter serves some product routes behind the validator of handlers.ProductSpec,
+// checking the responses too
+// newValidatedRouter(handler *handlers.ProductHandler) -> *chi.Mux
+
+func newValidatedRouter(handler *handlers.ProductHandler) *chi.Mux {
+	router := chi.NewRouter()
+	router.Use(handlers.ProductSpec.Validator(router, true).Middleware)
+	router.Route("/products", func(r chi.Router) {
+		r.Get("/", handler.GetAllProducts())
+		r.Get("/{id}", handler.GetProductByID())
+		r.Get("/search", handler.SearchProducts())
+		r.Post("/", handler.AddNewProduct())
+		r.Put("/{id}", handler.UpdateProduct())
+		r.Patch("/{id}", handler.UpdateProductPartial())
+		r.Delete("/{id}", handler.DeleteProduct())
+	})
+	return router
+}
+
+// TestProductSpecValidator tests the product routes against handlers.ProductSpec
+func TestProductSpecValidator(t *testing.T) {
+	/* Prepare the test data */
+	initialProducts := map[int]internal.TProduct{
+		1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: false, Expiration: "11/11/2001", Price: 10.5, Tags: []string{"dairy"}},
+		2: {ID: 2, Name: "Product 2", Quantity: 20, CodeValue: "AX02", IsPublished: true, Expiration: "11/11/2002", Price: 20.5},
+	}
+
+	// Test 1: should answer valid requests as described
+	t.Run("should answer valid requests as described", func(t *testing.T) {
+		/* Initialize dependencies */
+		storage := initStorage(initialProducts)
+		repository := repository.NewProductMap(&storage)
+		service := service.NewProductServiceDefault(repository)
+		router := newValidatedRouter(handlers.NewProductHandler(service))
+
+		/* Requests and expected statuses */
+		cases := []struct {
+			method, target, contentType, body string
+			code                              int
+		}{
+			{"GET", "/products/", "", "", http.StatusOK},
+			{"GET", "/products/1", "", "", http.StatusOK},
+			{"GET", "/products/9", "", "", http.StatusNotFound},
+			{"GET", "/products/search?tags=dairy", "", "", http.StatusOK},
+			{"POST", "/products/", "application/json", `{"name": "Product 3", "quantity": 5, "code_value": "AX03", "is_published": true, "expiration": "01/01/2000", "price": 3}`, http.StatusCreated},
+			{"POST", "/products/", "text/xml", `<product><name>Product 4</name><quantity>5</quantity><code_value>AX04</code_value><expiration>01/01/2000</expiration><price>4</price></product>`, http.StatusCreated},
+			{"PUT", "/products/1", "application/json", `{"id": 1, "name": "Product 1", "quantity": 10, "code_value": "AX01", "is_published": false, "expiration": "11/11/2001", "price": 11}`, http.StatusOK},
+			{"PATCH", "/products/1", "application/merge-patch+json", `{"tax_class": null, "reorder_point": null, "attributes": {"size": null}}`, http.StatusOK},
+			{"DELETE", "/products/2", "", "", http.StatusNoContent},
+		}
+		for _, c := range cases {
+			req := httptest.NewRequest(c.method, c.target, strings.NewReader(c.body))
+			if c.contentType != "" {
+				req.Header.Set("Content-Type", c.contentType)
+			}
+			res := httptest.NewRecorder()
+			router.ServeHTTP(res, req)
+
+			/* Assertions */
+			require.Equal(t, c.code, res.Code, "%s %s: %s", c.method, c.target, res.Body.String())
+		}
+	})
+
+	// Test 2: should reject invalid requests before the handler
+	t.Run("should reject invalid requests before the handler", func(t *testing.T) {
+		/* Initialize dependencies */
+		storage := initStorage(initialProducts)
+		repository := repository.NewProductMap(&storage)
+		service := service.NewProductServiceDefault(repository)
+		router := newValidatedRouter(handlers.NewProductHandler(service))
+
+		/* Requests and expected problems */
+		cases := []struct {
+			method, target, contentType, body string
+			code                              int
+			expectedErrors                    map[string]string
+		}{
+			{"GET", "/products/abc", "", "", http.StatusUnprocessableEntity,
+				map[string]string{"/path/id": "expected an integer"}},
+			{"GET", "/products/search?priceGt=x", "", "", http.StatusUnprocessableEntity,
+				map[string]string{"/query/priceGt": "expected a number"}},
+			{"POST", "/products/", "application/json", `{"name": "Product 3", "price": "10", "quantity": 1.5}`, http.StatusUnprocessableEntity,
+				map[string]string{"/body/price": "expected a number", "/body/quantity": "expected an integer"}},
+			{"POST", "/products/", "text/plain", `name`, http.StatusUnsupportedMediaType, nil},
+			{"POST", "/products/", "application/json", `{"name":`, http.StatusBadRequest, nil},
+		}
+		for _, c := range cases {
+			req := httptest.NewRequest(c.method, c.target, strings.NewReader(c.body))
+			if c.contentType != "" {
+				req.Header.Set("Content-Type", c.contentType)
+			}
+			res := httptest.NewRecorder()
+			router.ServeHTTP(res, req)
+
+			/* Assertions */
+			require.Equal(t, c.code, res.Code, "%s %s: %s", c.method, c.target, res.Body.String())
+			var problem response.Problem
+			require.Equal(t, "application/problem+json", res.Header().Get("Content-Type"))
+			require.NoError(t, json.Unmarshal(res.Body.Bytes(), &problem))
+			require.Equal(t, c.expectedErrors, problem.Errors)
+		}
+
+		/* The rejected requests didn't reach the storage */
+		products, err := storage.GetAll()
+		require.NoError(t, err)
+		require.Len(t, products, 2)
+	})
+
+	// Test 3: should describe the v2 representation negotiated on the unprefixed routes
+	t.Run("should describe the v2 representation negotiated on the unprefixed routes", func(t *testing.T) {
+		/* Initialize dependencies */
+		storage := initStorage(initialProducts)
+		repository := repository.NewProductMap(&storage)
+		service := service.NewProductServiceDefault(repository)
+		handler := handlers.NewProductHandler(service)
+		router := chi.NewRouter()
+		router.Use(handlers.ProductSpec.Validator(router, true).Middleware)
+		router.Route("/products", func(r chi.Router) {
+			r.Use(middleware.NewVersioning(handlers.ProductVersion1, handlers.ProductVersion1, handlers.ProductVersion2).Negotiate)
+			r.Get("/{id}", handler.Versioned(handler.GetProductByID(), handler.GetProductByIDV2()))
+			r.Post("/", handler.Versioned(handler.AddNewProduct(), handler.AddNewProductV2()))
+		})
+
+		/* Requests and expected statuses */
+		v2Body := `{"name": "Product 3", "code": "AX03", "quantity": 5, "published": true, "expires_on": "2030-01-31", "pricing": {"base": 3}}`
+		cases := []struct {
+			method, target, accept, contentType, body string
+			code                                      int
+		}{
+			{"GET", "/products/1", handlers.MediaTypeProductV2, "", "", http.StatusOK},
+			{"POST", "/products/", "", handlers.MediaTypeProductV2, v2Body, http.StatusCreated},
+			{"POST", "/products/", "", handlers.MediaTypeProductV2, `{"name": "Product 4", "code_value": "AX04"}`, http.StatusUnprocessableEntity},
+		}
+		for _, c := range cases {
+			req := httptest.NewRequest(c.method, c.target, strings.NewReader(c.body))
+			if c.accept != "" {
+				req.Header.Set("Accept", c.accept)
+			}
+			if c.contentType != "" {
+				req.Header.Set("Content-Type", c.contentType)
+			}
+			res := httptest.NewRecorder()
+			router.ServeHTTP(res, req)
+
+			/* Assertions */
+			require.Equal(t, c.code, res.Code, "%s %s: %s", c.method, c.target, res.Body.String())
+			if c.code < http.StatusBadRequest {
+				require.Equal(t, handlers.MediaTypeProductV2, res.Header().Get("Content-Type"))
+			}
+		}
+	})
+}
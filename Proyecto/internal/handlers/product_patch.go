@@ -0,0 +1,346 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"proyecto/internal"
+	"proyecto/platform/web/patch"
+	"proyecto/platform/web/request"
+	"proyecto/platform/web/response"
+	"time"
+)
+
+// ErrUnsupportedPatch is raised when a PATCH body is neither a merge patch nor a JSON patch
+var ErrUnsupportedPatch = errors.New("unsupported patch media type")
+
+// ProductMergePatchJSON describes a JSON Merge Patch of a product. Every field is optional
+// and a null removes it, as RFC 7396 allows, so they are all nullable
+type ProductMergePatchJSON struct {
+	Name            *string            `json:"name"`
+	Quantity        *int               `json:"quantity"`
+	CodeValue       *string            `json:"code_value"`
+	IsPublished     *bool              `json:"is_published"`
+	Expiration      *string            `json:"expiration"`
+	Price           *float64           `json:"price"`
+	PublishAt       *time.Time         `json:"publish_at"`
+	UnpublishAt     *time.Time         `json:"unpublish_at"`
+	Tags            []string           `json:"tags"`
+	Attributes      map[string]*string `json:"attributes"`
+	ReorderPoint    *int               `json:"reorder_point"`
+	ReorderQuantity *int               `json:"reorder_quantity"`
+	TaxClass        *string            `json:"tax_class"`
+}
+
+// productDocument returns the patchable fields of a product as a decoded JSON document.
+// Unset optional fields are present as null (or empty) so they can be tested and replaced
+// productDocument(product internal.TProduct) -> (map[string]any, error)
+// Args:
+//		product: Product to convert
+// Return:
+//		map[string]any: Patchable document
+//		error: 			Error raised during the execution (if exists)
+
+func productDocument(product internal.TProduct) (map[string]any, error) {
+	tags := product.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	attributes := product.Attributes
+	if attributes == nil {
+		attributes = map[string]string{}
+	}
+	encoded, err := json.Marshal(map[string]any{
+		"name":             product.Name,
+		"quantity":         product.Quantity,
+		"code_value":       product.CodeValue,
+		"is_published":     product.IsPublished,
+		"expiration":       product.Expiration,
+		"price":            product.Price,
+		"publish_at":       product.PublishAt,
+		"unpublish_at":     product.UnpublishAt,
+		"tags":             tags,
+		"attributes":       attributes,
+		"reorder_point":    product.ReorderPoint,
+		"reorder_quantity": product.ReorderQuantity,
+		"tax_class":        product.TaxClass,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	err = json.Unmarshal(encoded, &doc)
+	return doc, err
+}
+
+// applyProductDocument sets the patchable fields of a product from a patched document.
+// Removed fields take their empty value. Every field with a wrong type is reported
+// applyProductDocument(product *internal.TProduct, doc any) -> error
+// Args:
+//		product: Product to update in place
+//		doc: 	 Patched document
+// Return:
+//		error: request.FieldErrors with one message per invalid field (if any)
+
+func applyProductDocument(product *internal.TProduct, doc any) error {
+	fields, ok := doc.(map[string]any)
+	if !ok {
+		return request.FieldErrors{"": "expected an object"}
+	}
+
+	/* Start from the empty values */
+	patched := *product
+	patched.Name, patched.Quantity, patched.CodeValue, patched.IsPublished = "", 0, "", false
+	patched.Expiration, patched.Price, patched.PublishAt, patched.UnpublishAt = "", 0, nil, nil
+	patched.Tags, patched.Attributes, patched.ReorderPoint, patched.ReorderQuantity = nil, nil, nil, 0
+	patched.TaxClass = ""
+
+	invalid := request.FieldErrors{}
+	for key, value := range fields {
+		var expected string
+		ok := true
+		switch key {
+		case "name":
+			patched.Name, ok = value.(string)
+			expected = "a string"
+		case "code_value":
+			patched.CodeValue, ok = value.(string)
+			expected = "a string"
+		case "expiration":
+			patched.Expiration, ok = value.(string)
+			expected = "a date with format DD/MM/YYYY"
+		case "is_published":
+			patched.IsPublished, ok = value.(bool)
+			expected = "a boolean"
+		case "price":
+			patched.Price, ok = value.(float64)
+			expected = "a number"
+		case "quantity", "reorder_quantity":
+			var level *int
+			if level, ok = parseOptionalInt(value); ok && level != nil {
+				if key == "quantity" {
+					patched.Quantity = *level
+				} else {
+					patched.ReorderQuantity = *level
+				}
+			}
+			ok = ok && value != nil
+			expected = "an integer"
+		case "reorder_point":
+			patched.ReorderPoint, ok = parseOptionalInt(value)
+			expected = "an integer or null"
+		case "publish_at":
+			patched.PublishAt, ok = parseOptionalTime(value)
+			expected = "an RFC 3339 moment or null"
+		case "unpublish_at":
+			patched.UnpublishAt, ok = parseOptionalTime(value)
+			expected = "an RFC 3339 moment or null"
+		case "tags":
+			patched.Tags, ok = parseTags(value)
+			expected = "an array of strings or null"
+		case "attributes":
+			patched.Attributes, ok = parseAttributes(value)
+			expected = "an object of strings or null"
+		case "tax_class":
+			if value != nil {
+				patched.TaxClass, ok = value.(string)
+			}
+			expected = "a string or null"
+		default:
+			invalid["/"+key] = "unexpected field"
+			continue
+		}
+		if !ok {
+			invalid["/"+key] = "expected " + expected
+		}
+	}
+	if len(invalid) > 0 {
+		return invalid
+	}
+	*product = patched
+	return nil
+}
+
+// patchDocument applies the patch in the request body to a document. The patch format is
+// selected by the Content-Type: application/merge-patch+json (or application/json) for a
+// JSON Merge Patch, application/json-patch+json for a JSON Patch
+// patchDocument(r *http.Request, doc any) -> (any, error)
+// Args:
+//		r: 	 HTTP request holding the patch
+//		doc: Decoded JSON document to patch
+// Return:
+//		any:   Patched document
+//		error: Error raised during the execution (if exists)
+
+func patchDocument(r *http.Request, doc any) (any, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case patch.MediaTypeMergePatch, "application/json":
+		var merge any
+		if err := json.NewDecoder(r.Body).Decode(&merge); err != nil {
+			return nil, fmt.Errorf("%w. %v", request.ErrRequestJSONInvalid, err)
+		}
+		return patch.MergePatch(doc, merge), nil
+	case patch.MediaTypeJSONPatch:
+		var operations []patch.Operation
+		if err := json.NewDecoder(r.Body).Decode(&operations); err != nil {
+			return nil, fmt.Errorf("%w. %v", request.ErrRequestJSONInvalid, err)
+		}
+		return patch.JSONPatch(doc, operations)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedPatch, mediaType)
+	}
+}
+
+// parseOptionalTime parses an optional RFC 3339 moment from a decoded JSON value
+// Args:
+//
+//	value: any (string or nil)
+//
+// Return:
+//
+//	*time.Time: parsed moment, nil if the value is null
+//	bool: true if the value is valid, false otherwise
+func parseOptionalTime(value any) (*time.Time, bool) {
+	if value == nil {
+		return nil, true
+	}
+	text, ok := value.(string)
+	if !ok {
+		return nil, false
+	}
+	moment, err := time.Parse(time.RFC3339, text)
+	if err != nil {
+		return nil, false
+	}
+	return &moment, true
+}
+
+// parseTags parses an optional list of tags from a decoded JSON value
+// Args:
+//
+//	value: any ([]any of strings or nil)
+//
+// Return:
+//
+//	[]string: parsed tags, nil if the value is null
+//	bool: true if the value is valid, false otherwise
+func parseTags(value any) ([]string, bool) {
+	if value == nil {
+		return nil, true
+	}
+	items, ok := value.([]any)
+	if !ok {
+		return nil, false
+	}
+	tags := make([]string, 0, len(items))
+	for _, item := range items {
+		tag, ok := item.(string)
+		if !ok {
+			return nil, false
+		}
+		tags = append(tags, tag)
+	}
+	return tags, true
+}
+
+// parseAttributes parses an optional set of attributes from a decoded JSON value
+// Args:
+//
+//	value: any (map[string]any of strings or nil)
+//
+// Return:
+//
+//	map[string]string: parsed attributes, nil if the value is null
+//	bool: true if the value is valid, false otherwise
+func parseAttributes(value any) (map[string]string, bool) {
+	if value == nil {
+		return nil, true
+	}
+	items, ok := value.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	attributes := make(map[string]string, len(items))
+	for key, item := range items {
+		text, ok := item.(string)
+		if !ok {
+			return nil, false
+		}
+		attributes[key] = text
+	}
+	return attributes, true
+}
+
+// parseOptionalInt parses an optional integer from a decoded JSON value
+// Args:
+//
+//	value: any (float64 or nil)
+//
+// Return:
+//
+//	*int: parsed integer, nil if the value is null
+//	bool: true if the value is valid, false otherwise
+func parseOptionalInt(value any) (*int, bool) {
+	if value == nil {
+		return nil, true
+	}
+	number, ok := value.(float64)
+	if !ok || number != float64(int(number)) {
+		return nil, false
+	}
+	integer := int(number)
+	return &integer, true
+}
+
+// UpdateProductPartial partially updates a product with a JSON Merge Patch (RFC 7396) or a
+// JSON Patch (RFC 6902). The patched product is type checked and validated before it is saved
+// URL params : id
+// Body params: merge patch or JSON patch operations over the fields of ProductJSON
+func (p *ProductHandler) UpdateProductPartial() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := urlID(r, "id")
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Retrieve the Product by ID as stored, so the resolved fields are not saved back */
+		product, err := p.ProductService.GetStoredProductByID(id)
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Patch the product as a JSON document */
+		doc, err := productDocument(product)
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+		patched, err := patchDocument(r, doc)
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+		if err := applyProductDocument(&product, patched); err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Update the product */
+		if err := p.ProductService.UpdateProduct(&product); err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Send the response to the client */
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data":    product,
+			"message": "Product updated successfully.",
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"proyecto/internal"
+	"proyecto/internal/clock"
+	"proyecto/internal/handlers"
+	"proyecto/internal/repository"
+	"proyecto/internal/service"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestUpdateProductPartial tests the UpdateProductPartial handler
+func TestUpdateProductPartial(t *testing.T) {
+	initialProducts := map[int]internal.TProduct{
+		1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: true, Expiration: "11/11/2024", Price: 10.5,
+			Tags: []string{"a", "b"}, Attributes: map[string]string{"brand": "X", "pack": "1kg"}},
+		2: {ID: 2, Name: "Product 2", Quantity: 20, CodeValue: "AX02", IsPublished: true, Expiration: "11/11/2024", Price: 20.5},
+	}
+	cases := []struct {
+		name         string
+		contentType  string
+		body         string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "should apply a merge patch",
+			contentType:  "application/merge-patch+json",
+			body:         `{"price": 12, "tags": ["c"], "attributes": {"pack": null, "flavor": "mint"}}`,
+			expectedCode: http.StatusOK,
+			expectedBody: `{"data": {"id": 1, "name": "Product 1", "quantity": 10, "code_value": "AX01", "is_published": true, "expiration": "11/11/2024",
+				"price": 12, "tags": ["c"], "attributes": {"brand": "X", "flavor": "mint"}}, "message": "Product updated successfully."}`,
+		},
+		{
+			name:        "should apply a json patch",
+			contentType: "application/json-patch+json",
+			body: `[{"op": "test", "path": "/price", "value": 10.5}, {"op": "replace", "path": "/price", "value": 11},
+				{"op": "remove", "path": "/tags/0"}, {"op": "remove", "path": "/attributes"}]`,
+			expectedCode: http.StatusOK,
+			expectedBody: `{"data": {"id": 1, "name": "Product 1", "quantity": 10, "code_value": "AX01", "is_published": true, "expiration": "11/11/2024",
+				"price": 11, "tags": ["b"]}, "message": "Product updated successfully."}`,
+		},
+		{
+			name:         "should fail a json patch whose test does not hold",
+			contentType:  "application/json-patch+json",
+			body:         `[{"op": "test", "path": "/price", "value": 99}, {"op": "replace", "path": "/price", "value": 11}]`,
+			expectedCode: http.StatusConflict,
+			expectedBody: `{"type": "/problems/patch_test_failed", "code": "patch_test_failed", "title": "Patch test failed", "status": 409,
+				"detail": "patch test failed: /price is 99", "instance": "/products/1"}`,
+		},
+		{
+			name:         "should report every field with a wrong type",
+			contentType:  "application/merge-patch+json",
+			body:         `{"price": "10", "quantity": 1.5, "color": "red"}`,
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: `{"type": "/problems/invalid_fields", "code": "invalid_fields", "title": "Invalid fields", "status": 422,
+				"detail": "request fields invalid. /color: unexpected field; /price: expected a number; /quantity: expected an integer",
+				"instance": "/products/1",
+				"errors": {"/price": "expected a number", "/quantity": "expected an integer", "/color": "unexpected field"}}`,
+		},
+		{
+			name:         "should validate the patched product",
+			contentType:  "application/json-patch+json",
+			body:         `[{"op": "replace", "path": "/code_value", "value": "AX02"}]`,
+			expectedCode: http.StatusConflict,
+			expectedBody: `{"type": "/problems/product_already_exists", "code": "product_already_exists", "title": "Product code already exists",
+				"status": 409, "detail": "product already exists", "instance": "/products/1"}`,
+		},
+		{
+			name:         "should reject an unsupported media type",
+			contentType:  "text/plain",
+			body:         `price=12`,
+			expectedCode: http.StatusUnsupportedMediaType,
+			expectedBody: `{"type": "/problems/unsupported_media_type", "code": "unsupported_media_type", "title": "Unsupported media type",
+				"status": 415, "detail": "unsupported patch media type: \"text/plain\"", "instance": "/products/1"}`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			/* Initialize dependencies */
+			storage := initStorage(initialProducts)
+			repository := repository.NewProductMap(&storage)
+			service := service.NewProductServiceDefault(repository)
+			handler := handlers.NewProductHandler(service)
+
+			/* Prepare the request and the response */
+			req := httptest.NewRequest("PATCH", "/products/1", strings.NewReader(c.body))
+			req.Header.Set("Content-Type", c.contentType)
+			req = addURLParams(req, map[string]string{"id": "1"})
+			res := httptest.NewRecorder()
+			handler.UpdateProductPartial()(res, req)
+
+			/* Assertions */
+			require.Equal(t, c.expectedCode, res.Code)
+			require.JSONEq(t, c.expectedBody, res.Body.String())
+		})
+	}
+}
+
+// TestUpdateProductPartialStored tests the UpdateProductPartial handler patches the product as stored
+func TestUpdateProductPartialStored(t *testing.T) {
+	// Test 1: should keep the publish state the window computes out of the stored product
+	t.Run("should keep the publish state the window computes out of the stored product", func(t *testing.T) {
+		/* Initialize dependencies: the publish window is open but the flag is unset */
+		publishAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		initialProducts := map[int]internal.TProduct{
+			1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: false, Expiration: "11/11/2024", Price: 10.5, PublishAt: &publishAt},
+		}
+		storage := initStorage(initialProducts)
+		productRepository := repository.NewProductMap(&storage)
+		productService := service.NewProductServiceDefault(productRepository)
+		productService.SetClock(clock.NewClockFixed(time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC)))
+		handler := handlers.NewProductHandler(productService)
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("PATCH", "/products/1", strings.NewReader(`{"price": 12}`))
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+		handler.UpdateProductPartial()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusOK, res.Code)
+		product, err := productRepository.GetProductByID(1)
+		require.NoError(t, err)
+		require.False(t, product.IsPublished)
+		require.Equal(t, 12.0, product.Price)
+		product, err = productService.GetProductByID(1)
+		require.NoError(t, err)
+		require.True(t, product.IsPublished)
+	})
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"proyecto/internal"
+	"proyecto/platform/barcode"
+	"proyecto/platform/web/patch"
+	"proyecto/platform/web/request"
+	"proyecto/platform/web/response"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+/* Errors raised by the handlers while reading a request */
+var (
+	ErrInvalidID    = errors.New("invalid id")
+	ErrInvalidQuery = errors.New("invalid query param")
+	ErrInvalidBody  = errors.New("invalid body")
+)
+
+// productProblems maps the errors of the product endpoints to their problem types
+var productProblems = response.NewProblems().
+	Register(internal.ErrProductNotExists, http.StatusNotFound, "product_not_found", "Product not found").
+	Register(internal.ErrProductAlreadyExists, http.StatusConflict, "product_already_exists", "Product code already exists").
+	Register(internal.ErrProductHasVariants, http.StatusConflict, "product_has_variants", "Product has variants").
+	Register(internal.ErrInvalidVariantParent, http.StatusConflict, "invalid_variant_parent", "A variant can't have variants").
+	Register(internal.ErrEmptyField, http.StatusBadRequest, "empty_field", "Required field is empty").
+	Register(internal.ErrInvalidDate, http.StatusBadRequest, "invalid_date", "Invalid date").
+	Register(internal.ErrInvalidPublishWindow, http.StatusBadRequest, "invalid_publish_window", "Invalid publish window").
+	Register(internal.ErrInvalidReorderLevels, http.StatusBadRequest, "invalid_reorder_levels", "Invalid reorder levels").
+	Register(internal.ErrInvalidMovementQuantity, http.StatusBadRequest, "invalid_quantity", "Invalid quantity").
+	Register(internal.ErrInvalidMovementType, http.StatusBadRequest, "invalid_movement_type", "Invalid movement type").
+	Register(internal.ErrInvalidTransfer, http.StatusBadRequest, "invalid_transfer", "Invalid transfer").
+	Register(internal.ErrInvalidPromotion, http.StatusBadRequest, "invalid_promotion", "Invalid promotion").
+	Register(internal.ErrJurisdictionNotExists, http.StatusBadRequest, "unknown_jurisdiction", "Unknown jurisdiction").
+	Register(internal.ErrCategoryNotExists, http.StatusNotFound, "category_not_found", "Category not found").
+	Register(internal.ErrInsufficientStock, http.StatusConflict, "insufficient_stock", "Insufficient stock").
+	Register(internal.ErrWarehouseNotExists, http.StatusNotFound, "warehouse_not_found", "Warehouse not found").
+	Register(internal.ErrLotNotExists, http.StatusNotFound, "lot_not_found", "Lot not found").
+	Register(internal.ErrUnknownTaxClass, http.StatusBadRequest, "unknown_tax_class", "Unknown tax class").
+	Register(request.ErrRequestContentTypeNotJSON, http.StatusUnsupportedMediaType, "unsupported_media_type", "Unsupported media type").
+	Register(request.ErrRequestContentTypeUnsupported, http.StatusUnsupportedMediaType, "unsupported_media_type", "Unsupported media type").
+	Register(response.ErrNotAcceptable, http.StatusNotAcceptable, "not_acceptable", "Not acceptable").
+	Register(request.ErrRequestXMLInvalid, http.StatusBadRequest, "malformed_body", "Malformed XML body").
+	Register(request.ErrRequestJSONInvalid, http.StatusBadRequest, "malformed_body", "Malformed JSON body").
+	Register(request.ErrRequestFieldsInvalid, http.StatusUnprocessableEntity, "invalid_fields", "Invalid fields").
+	Register(ErrUnsupportedPatch, http.StatusUnsupportedMediaType, "unsupported_media_type", "Unsupported media type").
+	Register(patch.ErrInvalidPatch, http.StatusBadRequest, "invalid_patch", "Invalid patch").
+	Register(patch.ErrPathNotFound, http.StatusUnprocessableEntity, "patch_path_not_found", "Patch path not found").
+	Register(patch.ErrTestFailed, http.StatusConflict, "patch_test_failed", "Patch test failed").
+	Register(ErrInvalidBody, http.StatusBadRequest, "invalid_body", "Invalid body").
+	Register(ErrInvalidID, http.StatusBadRequest, "invalid_id", "Invalid ID").
+	Register(ErrInvalidQuery, http.StatusBadRequest, "invalid_query", "Invalid query param").
+	Register(barcode.ErrUnknownSymbology, http.StatusBadRequest, "unknown_symbology", "Unknown barcode symbology").
+	Register(barcode.ErrNotEncodable, http.StatusUnprocessableEntity, "not_encodable", "Code can't be encoded")
+
+// productError writes the problem an error raised by a product endpoint maps to
+// productError(w http.ResponseWriter, r *http.Request, err error)
+// Args:
+//		w:   HTTP response writer
+//		r:   HTTP request being served
+//		err: Error raised
+
+func productError(w http.ResponseWriter, r *http.Request, err error) {
+	productProblems.Write(w, r, err)
+}
+
+// urlID retrieves a numeric id from the url
+// urlID(r *http.Request, param string) -> (int, error)
+// Args:
+//		r: 	   HTTP request
+//		param: Name of the url param
+// Return:
+//		int:   Id found in the url
+//		error: ErrInvalidID if the param is not numeric
+
+func urlID(r *http.Request, param string) (int, error) {
+	id, err := strconv.Atoi(chi.URLParam(r, param))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s=%q", ErrInvalidID, param, chi.URLParam(r, param))
+	}
+	return id, nil
+}
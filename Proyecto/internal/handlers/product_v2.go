@@ -0,0 +1,358 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"proyecto/internal"
+	"proyecto/platform/web/request"
+	"proyecto/platform/web/response"
+	"strconv"
+	"time"
+)
+
+/* Versions of the product resource */
+const (
+	ProductVersion1 = "1" // Original representation. Deprecated
+	ProductVersion2 = "2" // ISO dates, nested pricing and string ids
+)
+
+// MediaTypeProductV2 is the media type of the v2 product representation
+const MediaTypeProductV2 = "application/json; version=2"
+
+/* Date layouts of the product expiration */
+const (
+	expirationLayoutV1 = "02/01/2006" // Stored and v1 layout
+	expirationLayoutV2 = "2006-01-02" // ISO 8601 date, v2 layout
+)
+
+// ProductPricingV2JSON is the pricing of a product in the v2 representation
+type ProductPricingV2JSON struct {
+	Base      float64 `json:"base"`      // List price.
+	Effective float64 `json:"effective"` // Price after the active promotions. The list price without them.
+	TaxClass  string  `json:"tax_class"` // Tax class the rates are looked up by.
+}
+
+// ProductV2JSON is the v2 JSON representation of a product
+type ProductV2JSON struct {
+	ID              string               `json:"id"`
+	Name            string               `json:"name"`
+	Code            string               `json:"code"`
+	Quantity        int                  `json:"quantity"`
+	Published       bool                 `json:"published"`
+	ExpiresOn       string               `json:"expires_on"` // ISO 8601 date, YYYY-MM-DD
+	Pricing         ProductPricingV2JSON `json:"pricing"`
+	PublishAt       *time.Time           `json:"publish_at,omitempty"`
+	UnpublishAt     *time.Time           `json:"unpublish_at,omitempty"`
+	Tags            []string             `json:"tags,omitempty"`
+	ParentID        *string              `json:"parent_id,omitempty"`
+	Attributes      map[string]string    `json:"attributes,omitempty"`
+	ReorderPoint    *int                 `json:"reorder_point,omitempty"`
+	ReorderQuantity int                  `json:"reorder_quantity,omitempty"`
+}
+
+// productV2 serializes a product to its v2 representation. An expiration not in the stored
+// layout is passed as is
+// productV2(product internal.TProduct) -> ProductV2JSON
+// Args:
+//		product: Product to serialize
+// Return:
+//		ProductV2JSON: v2 representation of the product
+
+func productV2(product internal.TProduct) ProductV2JSON {
+	result := ProductV2JSON{
+		ID:              strconv.Itoa(product.ID),
+		Name:            product.Name,
+		Code:            product.CodeValue,
+		Quantity:        product.Quantity,
+		Published:       product.IsPublished,
+		ExpiresOn:       product.Expiration,
+		Pricing:         ProductPricingV2JSON{Base: product.Price, Effective: product.Price, TaxClass: product.TaxClass},
+		PublishAt:       product.PublishAt,
+		UnpublishAt:     product.UnpublishAt,
+		Tags:            product.Tags,
+		Attributes:      product.Attributes,
+		ReorderPoint:    product.ReorderPoint,
+		ReorderQuantity: product.ReorderQuantity,
+	}
+	if expiration, err := time.Parse(expirationLayoutV1, product.Expiration); err == nil {
+		result.ExpiresOn = expiration.Format(expirationLayoutV2)
+	}
+	if product.EffectivePrice != nil {
+		result.Pricing.Effective = *product.EffectivePrice
+	}
+	if result.Pricing.TaxClass == "" {
+		result.Pricing.TaxClass = internal.TaxClassStandard
+	}
+	if product.ParentID != nil {
+		parentID := strconv.Itoa(*product.ParentID)
+		result.ParentID = &parentID
+	}
+	return result
+}
+
+// productsV2 serializes products to their v2 representation
+// productsV2(products []internal.TProduct) -> []ProductV2JSON
+
+func productsV2(products []internal.TProduct) []ProductV2JSON {
+	result := make([]ProductV2JSON, 0, len(products))
+	for _, product := range products {
+		result = append(result, productV2(product))
+	}
+	return result
+}
+
+// BodyRequestPricingV2JSON is the pricing of a product in the v2 body requests
+type BodyRequestPricingV2JSON struct {
+	Base     *float64 `json:"base"`      // List price.
+	TaxClass string   `json:"tax_class"` // Tax class the rates are looked up by. (Optional, standard)
+}
+
+// BodyRequestProductV2JSON is the body request creating or replacing a product in the v2 JSON
+// representation. Every required field must be present, even if it holds its empty value
+type BodyRequestProductV2JSON struct {
+	ID              *string                   `json:"id"`               // Product id. (Optional, must match the url)
+	Name            *string                   `json:"name"`             // Product name.
+	Code            *string                   `json:"code"`             // Product code.
+	Quantity        *int                      `json:"quantity"`         // Product quantity.
+	Published       *bool                     `json:"published"`        // Product is published. (Optional on creation, false)
+	ExpiresOn       *string                   `json:"expires_on"`       // Product expiration date. ISO 8601 date, YYYY-MM-DD
+	Pricing         *BodyRequestPricingV2JSON `json:"pricing"`          // Product pricing.
+	PublishAt       *time.Time                `json:"publish_at"`       // Moment the product goes live. RFC 3339 (Optional)
+	UnpublishAt     *time.Time                `json:"unpublish_at"`     // Moment the product is withdrawn. RFC 3339 (Optional)
+	Tags            []string                  `json:"tags"`             // Free-form labels. (Optional)
+	Attributes      map[string]string         `json:"attributes"`       // Size, flavor, pack... (Optional)
+	ReorderPoint    *int                      `json:"reorder_point"`    // Quantity at or below which the product must be restocked. (Optional)
+	ReorderQuantity int                       `json:"reorder_quantity"` // Quantity usually purchased when restocking. (Optional)
+}
+
+// toProduct serializes the body to internal.TProduct checking the required fields, the id and
+// the expiration date
+// toProduct(id int, replace bool) -> (internal.TProduct, error)
+// Args:
+//		id:      Product id taken from the url. Zero on creation
+//		replace: The body replaces a product, so the published flag is required too
+// Return:
+//		internal.TProduct: Product to insert or update
+//		error: 			   request.FieldErrors with the missing or invalid fields (if any)
+
+func (b BodyRequestProductV2JSON) toProduct(id int, replace bool) (internal.TProduct, error) {
+	invalid := request.FieldErrors{}
+	if b.ID != nil && replace && *b.ID != strconv.Itoa(id) {
+		invalid["/id"] = fmt.Sprintf("does not match the url id %d", id)
+	}
+	required := map[string]bool{
+		"/name":         b.Name == nil,
+		"/code":         b.Code == nil,
+		"/quantity":     b.Quantity == nil,
+		"/published":    b.Published == nil && replace,
+		"/expires_on":   b.ExpiresOn == nil,
+		"/pricing":      b.Pricing == nil,
+		"/pricing/base": b.Pricing != nil && b.Pricing.Base == nil,
+	}
+	for field, missing := range required {
+		if missing {
+			invalid[field] = "required field"
+		}
+	}
+	var expiration time.Time
+	if b.ExpiresOn != nil {
+		var err error
+		if expiration, err = time.Parse(expirationLayoutV2, *b.ExpiresOn); err != nil {
+			invalid["/expires_on"] = "must be a date, YYYY-MM-DD"
+		}
+	}
+	if len(invalid) > 0 {
+		return internal.TProduct{}, invalid
+	}
+
+	product := internal.TProduct{
+		ID:              id,
+		Name:            *b.Name,
+		Quantity:        *b.Quantity,
+		CodeValue:       *b.Code,
+		Expiration:      expiration.Format(expirationLayoutV1),
+		Price:           *b.Pricing.Base,
+		PublishAt:       b.PublishAt,
+		UnpublishAt:     b.UnpublishAt,
+		Tags:            b.Tags,
+		Attributes:      b.Attributes,
+		ReorderPoint:    b.ReorderPoint,
+		ReorderQuantity: b.ReorderQuantity,
+		TaxClass:        b.Pricing.TaxClass,
+	}
+	if b.Published != nil {
+		product.IsPublished = *b.Published
+	}
+	return product, nil
+}
+
+// writeV2 writes a v2 JSON response. Any other media type the client asks for is answered
+// with a 406 problem, the v2 representation being JSON only
+// writeV2(w http.ResponseWriter, r *http.Request, code int, body any)
+// Args:
+//		w:    HTTP response writer
+//		r:    HTTP request being served
+//		code: HTTP status code
+//		body: Response body
+
+func writeV2(w http.ResponseWriter, r *http.Request, code int, body any) {
+	w.Header().Add("Vary", "Accept")
+	if _, err := response.Negotiate(r, response.MediaTypeJSON); err != nil {
+		productError(w, r, err)
+		return
+	}
+	bytes, err := json.Marshal(body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", MediaTypeProductV2)
+	w.WriteHeader(code)
+	w.Write(bytes)
+}
+
+// Versioned serves a request with the handler of the version picked for it (see
+// request.Version)
+// Versioned(v1 http.HandlerFunc, v2 http.HandlerFunc) -> http.HandlerFunc
+// Args:
+//		v1: Handler of the v1 representation
+//		v2: Handler of the v2 representation
+// Return:
+//		http.HandlerFunc: Handler of the resource
+
+func (p *ProductHandler) Versioned(v1 http.HandlerFunc, v2 http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if request.Version(r) == ProductVersion2 {
+			v2(w, r)
+			return
+		}
+		v1(w, r)
+	}
+}
+
+/* Endpoint function handlers */
+
+// GetAllProductsV2 returns all the products in the v2 representation
+// Url params: none
+func (p *ProductHandler) GetAllProductsV2() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		products := p.ProductService.GetAllProducts()
+		writeV2(w, r, http.StatusOK, map[string]any{
+			"data": productsV2(products),
+		})
+	}
+}
+
+// GetProductByIDV2 search a product by ID and return it in the v2 representation
+// URL params:
+//
+//	id (Numeric): ID of the desirable product.
+func (p *ProductHandler) GetProductByIDV2() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := urlID(r, "id")
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Search the product by id */
+		product, err := p.ProductService.GetProductByID(id)
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+		writeV2(w, r, http.StatusOK, map[string]any{
+			"data": productV2(product),
+		})
+	}
+}
+
+// AddNewProductV2 creates a new product from its v2 representation
+// URL params : none
+// Body params: BodyRequestProductV2JSON
+func (p *ProductHandler) AddNewProductV2() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the body from the request */
+		var body BodyRequestProductV2JSON
+		if err := request.JSONStrict(r, &body); err != nil {
+			productError(w, r, err)
+			return
+		}
+		product, err := body.toProduct(0, false)
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Insert the new product into repository */
+		if err := p.ProductService.InsertNewProduct(&product); err != nil {
+			productError(w, r, err)
+			return
+		}
+		writeV2(w, r, http.StatusCreated, map[string]any{
+			"data":    productV2(product),
+			"message": "Product created successfully.",
+		})
+	}
+}
+
+// UpdateProductV2 replaces a product with its v2 representation
+// URL params : id
+// Body params: BodyRequestProductV2JSON
+func (p *ProductHandler) UpdateProductV2() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := urlID(r, "id")
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Retrieve the body from the request */
+		var body BodyRequestProductV2JSON
+		if err := request.JSONStrict(r, &body); err != nil {
+			productError(w, r, err)
+			return
+		}
+		product, err := body.toProduct(id, true)
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Update the product into repository */
+		if err := p.ProductService.UpdateProduct(&product); err != nil {
+			productError(w, r, err)
+			return
+		}
+		writeV2(w, r, http.StatusOK, map[string]any{
+			"data":    productV2(product),
+			"message": "Product updated successfully.",
+		})
+	}
+}
+
+// GetVariantsV2 returns the variants of a product in the v2 representation
+// URL params : id
+func (p *ProductHandler) GetVariantsV2() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := urlID(r, "id")
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Search the variants */
+		variants, err := p.ProductService.GetVariants(id)
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+		writeV2(w, r, http.StatusOK, map[string]any{
+			"data": productsV2(variants),
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"proyecto/internal"
+	"proyecto/internal/handlers"
+	"proyecto/internal/repository"
+	"proyecto/internal/service"
+	"proyecto/platform/web/request"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetProductByIDV2 tests the GetProductByIDV2 handler
+func TestGetProductByIDV2(t *testing.T) {
+	// Test 1: should return the product with ISO dates, nested pricing and string ids
+	t.Run("should return the product with ISO dates, nested pricing and string ids", func(t *testing.T) {
+		/* Initialize dependencies */
+		storage := initStorage(variantTestData())
+		repository := repository.NewProductMap(&storage)
+		service := service.NewProductServiceDefault(repository)
+		handler := handlers.NewProductHandler(service)
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("GET", "/v2/products/2", nil)
+		req = addURLParams(req, map[string]string{"id": "2"})
+		res := httptest.NewRecorder()
+		handler.GetProductByIDV2()(res, req)
+
+		/* Expected values definition */
+		expectedCode := http.StatusOK
+		expectedBody := `{"data":
+			{"id": "2", "name": "Yerba", "code": "YB500", "quantity": 20, "published": true, "expires_on": "2024-11-11",
+			 "pricing": {"base": 5.5, "effective": 5.5, "tax_class": "standard"},
+			 "tags": ["infusion"], "parent_id": "1", "attributes": {"brand": "Taragui", "pack": "500g"}}
+		}`
+		expectedHeader := http.Header{"Content-Type": []string{handlers.MediaTypeProductV2}, "Vary": []string{"Accept"}}
+
+		/* Assertions */
+		require.Equal(t, expectedCode, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+		require.Equal(t, expectedHeader, res.Header())
+	})
+
+	// Test 2: should fail if the client does not accept JSON
+	t.Run("should fail if the client does not accept JSON", func(t *testing.T) {
+		/* Initialize dependencies */
+		storage := initStorage(variantTestData())
+		repository := repository.NewProductMap(&storage)
+		service := service.NewProductServiceDefault(repository)
+		handler := handlers.NewProductHandler(service)
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("GET", "/v2/products/1", nil)
+		req.Header.Set("Accept", "application/xml")
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+		handler.GetProductByIDV2()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusNotAcceptable, res.Code)
+		require.Equal(t, "application/problem+json", res.Header().Get("Content-Type"))
+	})
+}
+
+// TestAddNewProductV2 tests the AddNewProductV2 handler
+func TestAddNewProductV2(t *testing.T) {
+	// Test 1: should create a product from its v2 representation
+	t.Run("should create a product from its v2 representation", func(t *testing.T) {
+		/* Initialize dependencies */
+		storage := initStorage(map[int]internal.TProduct{})
+		repository := repository.NewProductMap(&storage)
+		service := service.NewProductServiceDefault(repository)
+		handler := handlers.NewProductHandler(service)
+
+		/* Prepare the request and the response */
+		reqBody := `{"name": "Mate", "code": "MT01", "quantity": 5, "published": true, "expires_on": "2030-01-31", "pricing": {"base": 12.5}}`
+		req := httptest.NewRequest("POST", "/v2/products/", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		res := httptest.NewRecorder()
+		handler.AddNewProductV2()(res, req)
+
+		/* Expected values definition */
+		expectedCode := http.StatusCreated
+		expectedBody := `{"message": "Product created successfully.", "data":
+			{"id": "1", "name": "Mate", "code": "MT01", "quantity": 5, "published": true, "expires_on": "2030-01-31",
+			 "pricing": {"base": 12.5, "effective": 12.5, "tax_class": "standard"}}
+		}`
+
+		/* Assertions */
+		require.Equal(t, expectedCode, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+		stored, err := repository.GetProductByID(1)
+		require.NoError(t, err)
+		require.Equal(t, "31/01/2030", stored.Expiration)
+		require.Equal(t, 12.5, stored.Price)
+	})
+
+	// Test 2: should fail if a required field is missing or the date is not ISO 8601
+	t.Run("should fail if a required field is missing or the date is not ISO 8601", func(t *testing.T) {
+		/* Initialize dependencies */
+		storage := initStorage(map[int]internal.TProduct{})
+		repository := repository.NewProductMap(&storage)
+		service := service.NewProductServiceDefault(repository)
+		handler := handlers.NewProductHandler(service)
+
+		/* Prepare the request and the response */
+		reqBody := `{"name": "Mate", "code": "MT01", "quantity": 5, "expires_on": "31/01/2030", "pricing": {}}`
+		req := httptest.NewRequest("POST", "/v2/products/", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		res := httptest.NewRecorder()
+		handler.AddNewProductV2()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusUnprocessableEntity, res.Code)
+		require.Contains(t, res.Body.String(), `"/expires_on":"must be a date, YYYY-MM-DD"`)
+		require.Contains(t, res.Body.String(), `"/pricing/base":"required field"`)
+		require.Empty(t, repository.GetAllProducts())
+	})
+}
+
+// TestUpdateProductV2 tests the UpdateProductV2 handler
+func TestUpdateProductV2(t *testing.T) {
+	// Test 1: should replace a product from its v2 representation
+	t.Run("should replace a product from its v2 representation", func(t *testing.T) {
+		/* Initialize dependencies */
+		storage := initStorage(variantTestData())
+		repository := repository.NewProductMap(&storage)
+		service := service.NewProductServiceDefault(repository)
+		handler := handlers.NewProductHandler(service)
+
+		/* Prepare the request and the response */
+		reqBody := `{"id": "3", "name": "Product 3", "code": "AX03", "quantity": 31, "published": false, "expires_on": "2024-12-01", "pricing": {"base": 32, "tax_class": "reduced"}}`
+		req := httptest.NewRequest("PUT", "/v2/products/3", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		req = addURLParams(req, map[string]string{"id": "3"})
+		res := httptest.NewRecorder()
+		handler.UpdateProductV2()(res, req)
+
+		/* Expected values definition */
+		expectedCode := http.StatusOK
+		expectedBody := `{"message": "Product updated successfully.", "data":
+			{"id": "3", "name": "Product 3", "code": "AX03", "quantity": 31, "published": false, "expires_on": "2024-12-01",
+			 "pricing": {"base": 32, "effective": 32, "tax_class": "reduced"}}
+		}`
+
+		/* Assertions */
+		require.Equal(t, expectedCode, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+	})
+
+	// Test 2: should fail if the published flag is missing or the id does not match the url
+	t.Run("should fail if the published flag is missing or the id does not match the url", func(t *testing.T) {
+		/* Initialize dependencies */
+		storage := initStorage(variantTestData())
+		repository := repository.NewProductMap(&storage)
+		service := service.NewProductServiceDefault(repository)
+		handler := handlers.NewProductHandler(service)
+
+		/* Prepare the request and the response */
+		reqBody := `{"id": "1", "name": "Product 3", "code": "AX03", "quantity": 31, "expires_on": "2024-12-01", "pricing": {"base": 32}}`
+		req := httptest.NewRequest("PUT", "/v2/products/3", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		req = addURLParams(req, map[string]string{"id": "3"})
+		res := httptest.NewRecorder()
+		handler.UpdateProductV2()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusUnprocessableEntity, res.Code)
+		require.Contains(t, res.Body.String(), `"/id":"does not match the url id 3"`)
+		require.Contains(t, res.Body.String(), `"/published":"required field"`)
+	})
+}
+
+// TestVersioned tests the Versioned handler
+func TestVersioned(t *testing.T) {
+	// Test 1: should serve v1 with the v1 handler
+	t.Run("should serve v1 with the v1 handler", func(t *testing.T) {
+		/* Initialize dependencies */
+		storage := initStorage(variantTestData())
+		repository := repository.NewProductMap(&storage)
+		service := service.NewProductServiceDefault(repository)
+		handler := handlers.NewProductHandler(service)
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("GET", "/v1/products/3", nil)
+		req = addURLParams(request.WithVersion(req, handlers.ProductVersion1), map[string]string{"id": "3"})
+		res := httptest.NewRecorder()
+		handler.Versioned(handler.GetProductByID(), handler.GetProductByIDV2())(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Contains(t, res.Body.String(), `"code_value":"AX03"`)
+	})
+
+	// Test 2: should serve v2 with the v2 handler
+	t.Run("should serve v2 with the v2 handler", func(t *testing.T) {
+		/* Initialize dependencies */
+		storage := initStorage(variantTestData())
+		repository := repository.NewProductMap(&storage)
+		service := service.NewProductServiceDefault(repository)
+		handler := handlers.NewProductHandler(service)
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("GET", "/products/3", nil)
+		req = addURLParams(request.WithVersion(req, handlers.ProductVersion2), map[string]string{"id": "3"})
+		res := httptest.NewRecorder()
+		handler.Versioned(handler.GetProductByID(), handler.GetProductByIDV2())(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Contains(t, res.Body.String(), `"code":"AX03"`)
+	})
+
+	// Test 3: should serve the requests without a version with the v1 handler
+	t.Run("should serve the requests without a version with the v1 handler", func(t *testing.T) {
+		/* Initialize dependencies */
+		storage := initStorage(variantTestData())
+		repository := repository.NewProductMap(&storage)
+		service := service.NewProductServiceDefault(repository)
+		handler := handlers.NewProductHandler(service)
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("GET", "/products/3", nil)
+		req = addURLParams(req, map[string]string{"id": "3"})
+		res := httptest.NewRecorder()
+		handler.Versioned(handler.GetProductByID(), handler.GetProductByIDV2())(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Contains(t, res.Body.String(), `"code_value":"AX03"`)
+	})
+}
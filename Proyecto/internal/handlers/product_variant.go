@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"net/http"
+	"proyecto/internal"
+	"proyecto/platform/web/request"
+	"proyecto/platform/web/response"
+)
+
+// BodyRequestVariantJSON is the body request for a product variant in JSON format
+type BodyRequestVariantJSON struct {
+	Name        string            `json:"name"`         // Variant name. (Optional, inherited from the parent)
+	Quantity    int               `json:"quantity"`     // Variant quantity.
+	CodeValue   string            `json:"code_value"`   // Variant code value. Unique across the catalog.
+	IsPublished *bool             `json:"is_published"` // Variant is published while its parent is. (Optional, default true)
+	Expiration  string            `json:"expiration"`   // Variant expiration date. Format DD/MM/YYYY
+	Price       float64           `json:"price"`        // Variant price.
+	Tags        []string          `json:"tags"`         // Free-form labels. (Optional, inherited from the parent)
+	Attributes  map[string]string `json:"attributes"`   // Size, flavor, pack... (Optional, merged over the parent ones)
+	TaxClass    string            `json:"tax_class"`    // Tax class. (Optional, inherited from the parent)
+}
+
+// toProduct serializes the variant body to internal.TProduct
+func (b BodyRequestVariantJSON) toProduct() internal.TProduct {
+	isPublished := true
+	if b.IsPublished != nil {
+		isPublished = *b.IsPublished
+	}
+	return internal.TProduct{
+		Name:        b.Name,
+		Quantity:    b.Quantity,
+		CodeValue:   b.CodeValue,
+		IsPublished: isPublished,
+		Expiration:  b.Expiration,
+		Price:       b.Price,
+		Tags:        b.Tags,
+		Attributes:  b.Attributes,
+		TaxClass:    b.TaxClass,
+	}
+}
+
+// GetVariants returns the variants of a product
+// URL params:
+//
+//	id (Numeric): ID of the parent product.
+func (p *ProductHandler) GetVariants() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := urlID(r, "id")
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Search the variants */
+		variants, err := p.ProductService.GetVariants(id)
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+		response.Render(w, r, http.StatusOK, map[string]any{
+			"data": variants,
+		})
+	}
+}
+
+// AddNewVariant creates a new variant under a product
+// URL params : id
+// Body params: BodyRequestVariantJSON
+func (p *ProductHandler) AddNewVariant() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := urlID(r, "id")
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Retrieve the body from the request */
+		var body BodyRequestVariantJSON
+		if err := request.Body(r, &body); err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Insert the variant */
+		variant := body.toProduct()
+		if err := p.ProductService.InsertNewVariant(id, &variant); err != nil {
+			productError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusCreated, map[string]any{
+			"data":    variant,
+			"message": "Variant created successfully.",
+		})
+	}
+}
+
+// UpdateVariant updates a variant of a product
+// URL params : id, variantID
+// Body params: BodyRequestVariantJSON
+func (p *ProductHandler) UpdateVariant() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the ids from the url */
+		id, err := urlID(r, "id")
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+		variantID, err := urlID(r, "variantID")
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Retrieve the body from the request */
+		var body BodyRequestVariantJSON
+		if err := request.Body(r, &body); err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Update the variant */
+		variant := body.toProduct()
+		variant.ID = variantID
+		if err := p.ProductService.UpdateVariant(id, &variant); err != nil {
+			productError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data":    variant,
+			"message": "Variant updated successfully.",
+		})
+	}
+}
+
+// DeleteVariant deletes a variant of a product
+// URL params : id, variantID
+func (p *ProductHandler) DeleteVariant() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the ids from the url */
+		id, err := urlID(r, "id")
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+		variantID, err := urlID(r, "variantID")
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Delete the variant */
+		if err := p.ProductService.DeleteVariant(id, variantID); err != nil {
+			productError(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,172 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"proyecto/internal"
+	"proyecto/internal/handlers"
+	"proyecto/internal/repository"
+	"proyecto/internal/service"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// variantTestData returns a parent product with one variant and an unrelated product
+func variantTestData() map[int]internal.TProduct {
+	parent := 1
+	return map[int]internal.TProduct{
+		1: {ID: 1, Name: "Yerba", Quantity: 10, CodeValue: "YB00", IsPublished: true, Expiration: "11/11/2024", Price: 10.5, Tags: []string{"infusion"}, Attributes: map[string]string{"brand": "Taragui"}},
+		2: {ID: 2, Quantity: 20, CodeValue: "YB500", IsPublished: true, Expiration: "11/11/2024", Price: 5.5, ParentID: &parent, Attributes: map[string]string{"pack": "500g"}},
+		3: {ID: 3, Name: "Product 3", Quantity: 30, CodeValue: "AX03", IsPublished: true, Expiration: "11/11/2024", Price: 30.5},
+	}
+}
+
+// TestGetVariants tests the GetVariants handler
+func TestGetVariants(t *testing.T) {
+	// Test 1: should return the variants with the parent attributes inherited
+	t.Run("should return the variants with the parent attributes inherited", func(t *testing.T) {
+		/* Initialize dependencies */
+		storage := initStorage(variantTestData())
+		repository := repository.NewProductMap(&storage)
+		service := service.NewProductServiceDefault(repository)
+		handler := handlers.NewProductHandler(service)
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("GET", "/products/1/variants", nil)
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+		handler.GetVariants()(res, req)
+
+		/* Expected values definition */
+		expectedCode := http.StatusOK
+		expectedBody := `{"data": [
+			{"id": 2, "name": "Yerba", "quantity": 20, "code_value": "YB500", "is_published": true, "expiration": "11/11/2024", "price": 5.5,
+			 "tags": ["infusion"], "parent_id": 1, "attributes": {"brand": "Taragui", "pack": "500g"}}
+		]}`
+
+		/* Assertions */
+		require.Equal(t, expectedCode, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+	})
+
+	// Test 2: should fail if the parent does not exist
+	t.Run("should fail if the parent does not exist", func(t *testing.T) {
+		/* Initialize dependencies */
+		storage := initStorage(variantTestData())
+		repository := repository.NewProductMap(&storage)
+		service := service.NewProductServiceDefault(repository)
+		handler := handlers.NewProductHandler(service)
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("GET", "/products/9/variants", nil)
+		req = addURLParams(req, map[string]string{"id": "9"})
+		res := httptest.NewRecorder()
+		handler.GetVariants()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusNotFound, res.Code)
+		require.JSONEq(t, `{"type": "/problems/product_not_found", "code": "product_not_found", "title": "Product not found", "status": 404,
+			"detail": "product not exists", "instance": "/products/9/variants"}`, res.Body.String())
+	})
+}
+
+// TestAddNewVariant tests the AddNewVariant handler
+func TestAddNewVariant(t *testing.T) {
+	// Test 1: should create a variant under the parent
+	t.Run("should create a variant under the parent", func(t *testing.T) {
+		/* Initialize dependencies */
+		storage := initStorage(variantTestData())
+		repository := repository.NewProductMap(&storage)
+		service := service.NewProductServiceDefault(repository)
+		handler := handlers.NewProductHandler(service)
+
+		/* Prepare the request and the response */
+		reqBody := `{"quantity": 5, "code_value": "YB1000", "expiration": "11/11/2024", "price": 9.5, "attributes": {"pack": "1kg"}}`
+		req := httptest.NewRequest("POST", "/products/1/variants", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+		handler.AddNewVariant()(res, req)
+
+		/* Expected values definition */
+		expectedCode := http.StatusCreated
+		expectedBody := `{"data":
+			{"id": 4, "name": "Yerba", "quantity": 5, "code_value": "YB1000", "is_published": true, "expiration": "11/11/2024", "price": 9.5,
+			 "tags": ["infusion"], "parent_id": 1, "attributes": {"brand": "Taragui", "pack": "1kg"}},
+			"message": "Variant created successfully."
+		}`
+
+		/* Assertions */
+		require.Equal(t, expectedCode, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+	})
+
+	// Test 2: should reject a code already used by a top-level product
+	t.Run("should reject a code already used by a top-level product", func(t *testing.T) {
+		/* Initialize dependencies */
+		storage := initStorage(variantTestData())
+		repository := repository.NewProductMap(&storage)
+		service := service.NewProductServiceDefault(repository)
+		handler := handlers.NewProductHandler(service)
+
+		/* Prepare the request and the response */
+		reqBody := `{"quantity": 5, "code_value": "AX03", "expiration": "11/11/2024", "price": 9.5}`
+		req := httptest.NewRequest("POST", "/products/1/variants", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+		handler.AddNewVariant()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusConflict, res.Code)
+		require.JSONEq(t, `{"type": "/problems/product_already_exists", "code": "product_already_exists", "title": "Product code already exists", "status": 409,
+			"detail": "product already exists", "instance": "/products/1/variants"}`, res.Body.String())
+	})
+
+	// Test 3: should reject a variant of a variant
+	t.Run("should reject a variant of a variant", func(t *testing.T) {
+		/* Initialize dependencies */
+		storage := initStorage(variantTestData())
+		repository := repository.NewProductMap(&storage)
+		service := service.NewProductServiceDefault(repository)
+		handler := handlers.NewProductHandler(service)
+
+		/* Prepare the request and the response */
+		reqBody := `{"quantity": 5, "code_value": "YB250", "expiration": "11/11/2024", "price": 3.5}`
+		req := httptest.NewRequest("POST", "/products/2/variants", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		req = addURLParams(req, map[string]string{"id": "2"})
+		res := httptest.NewRecorder()
+		handler.AddNewVariant()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusConflict, res.Code)
+		require.JSONEq(t, `{"type": "/problems/invalid_variant_parent", "code": "invalid_variant_parent", "title": "A variant can't have variants", "status": 409,
+			"detail": "invalid variant parent", "instance": "/products/2/variants"}`, res.Body.String())
+	})
+}
+
+// TestDeleteProductWithVariants tests the DeleteProduct handler on a parent product
+func TestDeleteProductWithVariants(t *testing.T) {
+	// Test 1: should block the delete of a product with variants
+	t.Run("should block the delete of a product with variants", func(t *testing.T) {
+		/* Initialize dependencies */
+		storage := initStorage(variantTestData())
+		repository := repository.NewProductMap(&storage)
+		service := service.NewProductServiceDefault(repository)
+		handler := handlers.NewProductHandler(service)
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("DELETE", "/products/1", nil)
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+		handler.DeleteProduct()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusConflict, res.Code)
+		require.JSONEq(t, `{"type": "/problems/product_has_variants", "code": "product_has_variants", "title": "Product has variants", "status": 409,
+			"detail": "product has variants", "instance": "/products/1"}`, res.Body.String())
+	})
+}
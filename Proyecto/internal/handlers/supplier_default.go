@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"proyecto/internal"
+	"proyecto/platform/web/request"
+	"proyecto/platform/web/response"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+/* Supplier handler definition */
+type SupplierHandler struct {
+	SupplierService internal.SupplierService // Supplier service instance
+}
+
+// NewSupplierHandler creates a new default valued SupplierHandler
+// NewSupplierHandler(ss internal.SupplierService) -> *SupplierHandler
+// Args:
+//		ss: Supplier service instance
+// Return:
+//		*SupplierHandler: New SupplierHandler instance
+
+func NewSupplierHandler(ss internal.SupplierService) *SupplierHandler {
+	return &SupplierHandler{
+		SupplierService: ss,
+	}
+}
+
+// BodyRequestSupplierJSON is the body request for a supplier in JSON format
+type BodyRequestSupplierJSON struct {
+	Name  string `json:"name"`  // Supplier name.
+	Email string `json:"email"` // Contact email. (Optional)
+	Phone string `json:"phone"` // Contact phone. (Optional)
+}
+
+// BodyRequestSupplyTermsJSON is the body request for the terms of a supplied product in JSON format
+type BodyRequestSupplyTermsJSON struct {
+	Cost         float64 `json:"cost"`           // Unit cost paid to the supplier.
+	LeadTimeDays int     `json:"lead_time_days"` // Days between the order and the delivery.
+}
+
+// supplierError writes the response for an error raised by the supplier service
+// supplierError(w http.ResponseWriter, err error)
+// Args:
+//		w:   HTTP response writer
+//		err: Error raised by the service
+
+func supplierError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, internal.ErrSupplierNotExists):
+		response.Text(w, http.StatusNotFound, "Supplier not found.")
+	case errors.Is(err, internal.ErrProductNotExists):
+		response.Text(w, http.StatusNotFound, "Product not found.")
+	case errors.Is(err, internal.ErrEmptyField), errors.Is(err, internal.ErrInvalidSupplyTerms):
+		response.Text(w, http.StatusBadRequest, "Invalid body. "+err.Error())
+	case errors.Is(err, internal.ErrSupplierAlreadyExists):
+		response.Text(w, http.StatusConflict, "Supplier already exists.")
+	case errors.Is(err, internal.ErrSupplierIsOnlySource):
+		response.Text(w, http.StatusConflict, "Supplier is the only source of a published product.")
+	default:
+		response.Text(w, http.StatusInternalServerError, "Internal server error.")
+	}
+}
+
+/* Endpoint function handlers */
+
+// GetAllSuppliers returns all the suppliers
+// URL params: none
+func (s *SupplierHandler) GetAllSuppliers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		suppliers, err := s.SupplierService.GetAllSuppliers()
+		if err != nil {
+			supplierError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data": suppliers,
+		})
+	}
+}
+
+// GetSupplierByID search a supplier by ID and return if there is a match.
+// URL params:
+//
+//	id (Numeric): ID of the supplier.
+func (s *SupplierHandler) GetSupplierByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+
+		/* Search the supplier by id */
+		supplier, err := s.SupplierService.GetSupplierByID(id)
+		if err != nil {
+			supplierError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data": supplier,
+		})
+	}
+}
+
+// AddNewSupplier creates a new supplier
+// URL params : none
+// Body params: BodyRequestSupplierJSON
+func (s *SupplierHandler) AddNewSupplier() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the body from the request */
+		var body BodyRequestSupplierJSON
+		if err := request.JSON(r, &body); err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid body.")
+			return
+		}
+
+		/* Insert the new supplier */
+		supplier := internal.TSupplier{Name: body.Name, Email: body.Email, Phone: body.Phone}
+		if err := s.SupplierService.InsertNewSupplier(&supplier); err != nil {
+			supplierError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusCreated, map[string]any{
+			"data":    supplier,
+			"message": "Supplier created successfully.",
+		})
+	}
+}
+
+// UpdateSupplier updates the contact data of a supplier
+// URL params : id
+// Body params: BodyRequestSupplierJSON
+func (s *SupplierHandler) UpdateSupplier() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+
+		/* Retrieve the body from the request */
+		var body BodyRequestSupplierJSON
+		if err := request.JSON(r, &body); err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid body.")
+			return
+		}
+
+		/* Update the supplier */
+		supplier := internal.TSupplier{ID: id, Name: body.Name, Email: body.Email, Phone: body.Phone}
+		if err := s.SupplierService.UpdateSupplier(&supplier); err != nil {
+			supplierError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data":    supplier,
+			"message": "Supplier updated successfully.",
+		})
+	}
+}
+
+// DeleteSupplier deletes a supplier
+// URL params : id
+func (s *SupplierHandler) DeleteSupplier() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+
+		/* Delete the supplier */
+		if err := s.SupplierService.DeleteSupplier(id); err != nil {
+			supplierError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// GetProductsBySupplier returns the products of a supplier along with their terms
+// URL params:
+//
+//	id (Numeric): ID of the supplier.
+func (s *SupplierHandler) GetProductsBySupplier() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+
+		/* Search the products */
+		products, err := s.SupplierService.GetProductsBySupplier(id)
+		if err != nil {
+			supplierError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data": products,
+		})
+	}
+}
+
+// GetSuppliersByProduct returns the suppliers of a product along with their terms
+// URL params:
+//
+//	id (Numeric): ID of the product.
+func (s *SupplierHandler) GetSuppliersByProduct() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := urlID(r, "id")
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Search the suppliers */
+		suppliers, err := s.SupplierService.GetSuppliersByProduct(id)
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data": suppliers,
+		})
+	}
+}
+
+// LinkProduct adds a product to a supplier or updates its terms
+// URL params : id, productID
+// Body params: BodyRequestSupplyTermsJSON
+func (s *SupplierHandler) LinkProduct() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the ids from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+		productID, err := strconv.Atoi(chi.URLParam(r, "productID"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid product ID.")
+			return
+		}
+
+		/* Retrieve the body from the request */
+		var body BodyRequestSupplyTermsJSON
+		if err := request.JSON(r, &body); err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid body.")
+			return
+		}
+
+		/* Link the product */
+		terms := internal.TSupplyTerms{ProductID: productID, Cost: body.Cost, LeadTimeDays: body.LeadTimeDays}
+		if err := s.SupplierService.LinkProduct(id, terms); err != nil {
+			supplierError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data":    terms,
+			"message": "Product linked successfully.",
+		})
+	}
+}
+
+// UnlinkProduct removes a product from a supplier
+// URL params : id, productID
+func (s *SupplierHandler) UnlinkProduct() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the ids from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+		productID, err := strconv.Atoi(chi.URLParam(r, "productID"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid product ID.")
+			return
+		}
+
+		/* Unlink the product */
+		if err := s.SupplierService.UnlinkProduct(id, productID); err != nil {
+			supplierError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
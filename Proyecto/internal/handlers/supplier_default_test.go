@@ -0,0 +1,167 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"proyecto/internal"
+	"proyecto/internal/handlers"
+	"proyecto/internal/repository"
+	"proyecto/internal/service"
+	"proyecto/internal/storage"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// initSupplierStorage initializes the supplier storage
+// initSupplierStorage(map[int]internal.TSupplier) -> *storage.SupplierStorageDefault
+// Args:
+// 	initialSuppliers: Initial suppliers
+// Returns:
+// 	*SupplierStorageDefault: Initialized storage
+
+func initSupplierStorage(initialSuppliers map[int]internal.TSupplier) *storage.SupplierStorageDefault {
+	/* Storage creation */
+	filepath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/internal/handlers/suppliers_test.json"
+	storage := storage.NewSupplierStorageDefault(filepath)
+
+	/* Initial data of the storage */
+	err := storage.WriteAll(initialSuppliers)
+	if err != nil {
+		panic(err)
+	}
+	return storage
+}
+
+// supplierTestData returns a published product sourced by two suppliers and one sourced by a single supplier
+func supplierTestData() (map[int]internal.TProduct, map[int]internal.TSupplier) {
+	products := map[int]internal.TProduct{
+		1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: true, Expiration: "11/11/2024", Price: 10.5},
+		2: {ID: 2, Name: "Product 2", Quantity: 20, CodeValue: "AX02", IsPublished: true, Expiration: "11/11/2024", Price: 20.5},
+	}
+	suppliers := map[int]internal.TSupplier{
+		1: {ID: 1, Name: "Acme", Products: []internal.TSupplyTerms{{ProductID: 1, Cost: 7, LeadTimeDays: 5}, {ProductID: 2, Cost: 15, LeadTimeDays: 10}}},
+		2: {ID: 2, Name: "Globex", Products: []internal.TSupplyTerms{{ProductID: 1, Cost: 6.5, LeadTimeDays: 12}}},
+	}
+	return products, suppliers
+}
+
+// TestGetSuppliersByProduct tests the GetSuppliersByProduct handler
+func TestGetSuppliersByProduct(t *testing.T) {
+	// Test 1: should return the suppliers of a product ordered by cost
+	t.Run("should return the suppliers of a product ordered by cost", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialProducts, initialSuppliers := supplierTestData()
+		productStorage := initStorage(initialProducts)
+		productService := service.NewProductServiceDefault(repository.NewProductMap(&productStorage))
+		supplierRepository := repository.NewSupplierMap(initSupplierStorage(initialSuppliers))
+		handler := handlers.NewSupplierHandler(service.NewSupplierServiceDefault(supplierRepository, productService))
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("GET", "/products/1/suppliers", nil)
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+
+		handler.GetSuppliersByProduct()(res, req)
+
+		/* Expected values definition */
+		expectedCode := http.StatusOK
+		expectedBody := `{"data": [
+			{"supplier_id": 2, "name": "Globex", "cost": 6.5, "lead_time_days": 12},
+			{"supplier_id": 1, "name": "Acme", "cost": 7, "lead_time_days": 5}
+		]}`
+
+		/* Assertions */
+		require.Equal(t, expectedCode, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+	})
+
+	// Test 2: should not inherit the suppliers of a deleted product reusing its id
+	t.Run("should not inherit the suppliers of a deleted product reusing its id", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialProducts, initialSuppliers := supplierTestData()
+		productStorage := initStorage(initialProducts)
+		productService := service.NewProductServiceDefault(repository.NewProductMap(&productStorage))
+		supplierRepository := repository.NewSupplierMap(initSupplierStorage(initialSuppliers))
+		supplierService := service.NewSupplierServiceDefault(supplierRepository, productService)
+		productService.AddDependent(supplierService)
+		productHandler := handlers.NewProductHandler(productService)
+		handler := handlers.NewSupplierHandler(supplierService)
+
+		/* Delete the product and create a new one taking its id */
+		req := httptest.NewRequest("DELETE", "/products/2", nil)
+		req = addURLParams(req, map[string]string{"id": "2"})
+		res := httptest.NewRecorder()
+		productHandler.DeleteProduct()(res, req)
+		require.Equal(t, http.StatusNoContent, res.Code)
+
+		reqBody := `{"name": "new product", "quantity": 5, "code_value": "AX03", "expiration": "01/01/2030", "price": 3}`
+		req = httptest.NewRequest("POST", "/products/", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		res = httptest.NewRecorder()
+		productHandler.AddNewProduct()(res, req)
+		require.Equal(t, http.StatusCreated, res.Code)
+		require.Contains(t, res.Body.String(), `"id":2`)
+
+		/* Prepare the request and the response */
+		req = httptest.NewRequest("GET", "/products/2/suppliers", nil)
+		req = addURLParams(req, map[string]string{"id": "2"})
+		res = httptest.NewRecorder()
+
+		handler.GetSuppliersByProduct()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusOK, res.Code)
+		require.JSONEq(t, `{"data": []}`, res.Body.String())
+		acme, err := supplierRepository.GetSupplierByID(1)
+		require.NoError(t, err)
+		require.Equal(t, []internal.TSupplyTerms{{ProductID: 1, Cost: 7, LeadTimeDays: 5}}, acme.Products)
+	})
+}
+
+// TestDeleteSupplier tests the DeleteSupplier handler
+func TestDeleteSupplier(t *testing.T) {
+	// Test 1: should block the delete of the only source of a published product
+	t.Run("should block the delete of the only source of a published product", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialProducts, initialSuppliers := supplierTestData()
+		productStorage := initStorage(initialProducts)
+		productService := service.NewProductServiceDefault(repository.NewProductMap(&productStorage))
+		supplierRepository := repository.NewSupplierMap(initSupplierStorage(initialSuppliers))
+		handler := handlers.NewSupplierHandler(service.NewSupplierServiceDefault(supplierRepository, productService))
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("DELETE", "/suppliers/1", nil)
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+
+		handler.DeleteSupplier()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusConflict, res.Code)
+		require.Equal(t, "Supplier is the only source of a published product.", res.Body.String())
+	})
+
+	// Test 2: should delete a supplier whose products have other sources
+	t.Run("should delete a supplier whose products have other sources", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialProducts, initialSuppliers := supplierTestData()
+		productStorage := initStorage(initialProducts)
+		productService := service.NewProductServiceDefault(repository.NewProductMap(&productStorage))
+		supplierRepository := repository.NewSupplierMap(initSupplierStorage(initialSuppliers))
+		handler := handlers.NewSupplierHandler(service.NewSupplierServiceDefault(supplierRepository, productService))
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("DELETE", "/suppliers/2", nil)
+		req = addURLParams(req, map[string]string{"id": "2"})
+		res := httptest.NewRecorder()
+
+		handler.DeleteSupplier()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusNoContent, res.Code)
+		_, err := supplierRepository.GetSupplierByID(2)
+		require.ErrorIs(t, err, internal.ErrSupplierNotFound)
+	})
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"proyecto/internal"
+	"proyecto/platform/web/response"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+/* Tax handler definition */
+type TaxHandler struct {
+	TaxService     internal.TaxService     // Tax service instance
+	ProductService internal.ProductService // Product service the tax classes are taken from
+	CartService    internal.CartService    // Cart service whose lines are taxed
+	OrderService   internal.OrderService   // Order service whose lines are taxed
+}
+
+// NewTaxHandler creates a new default valued TaxHandler
+// NewTaxHandler(ts internal.TaxService, ps internal.ProductService, cs internal.CartService, os internal.OrderService) -> *TaxHandler
+// Args:
+//		ts: Tax service instance
+//		ps: Product service instance
+//		cs: Cart service instance
+//		os: Order service instance
+// Return:
+//		*TaxHandler: New TaxHandler instance
+
+func NewTaxHandler(ts internal.TaxService, ps internal.ProductService, cs internal.CartService, os internal.OrderService) *TaxHandler {
+	return &TaxHandler{
+		TaxService:     ts,
+		ProductService: ps,
+		CartService:    cs,
+		OrderService:   os,
+	}
+}
+
+// taxError writes the response for an error raised while computing taxes
+// taxError(w http.ResponseWriter, err error)
+// Args:
+//		w:   HTTP response writer
+//		err: Error raised by the services
+
+func taxError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, internal.ErrProductNotExists):
+		response.Text(w, http.StatusNotFound, "Product not found.")
+	case errors.Is(err, internal.ErrCartNotExists):
+		response.Text(w, http.StatusNotFound, "Cart not found.")
+	case errors.Is(err, internal.ErrOrderNotExists):
+		response.Text(w, http.StatusNotFound, "Order not found.")
+	case errors.Is(err, internal.ErrCartExpired):
+		response.Text(w, http.StatusGone, "Cart expired.")
+	case errors.Is(err, internal.ErrJurisdictionNotExists):
+		response.Text(w, http.StatusBadRequest, "Invalid jurisdiction. "+err.Error())
+	case errors.Is(err, internal.ErrUnknownTaxClass):
+		response.Text(w, http.StatusConflict, "Tax class without rate. "+err.Error())
+	default:
+		response.Text(w, http.StatusInternalServerError, "Internal server error.")
+	}
+}
+
+// taxClassOf returns the tax class of a product. Products removed since the line was created
+// are taxed with the standard class
+// taxClassOf(productID int) -> (string, error)
+
+func (t *TaxHandler) taxClassOf(productID int) (string, error) {
+	product, err := t.ProductService.GetProductByID(productID)
+	if errors.Is(err, internal.ErrProductNotExists) {
+		return internal.TaxClassStandard, nil
+	} else if err != nil {
+		return "", err
+	}
+	return product.TaxClass, nil
+}
+
+// breakdown computes the taxes of lines
+// breakdown(r *http.Request, lines []internal.TTaxLine) -> (internal.TTaxBreakdown, error)
+// Args:
+//		r: 	   HTTP request holding the jurisdiction
+//		lines: Lines with their net amount
+// Return:
+//		internal.TTaxBreakdown: Net, tax and gross amounts of the lines
+//		error: 					Error raised during the execution (if exists)
+
+func (t *TaxHandler) breakdown(r *http.Request, lines []internal.TTaxLine) (internal.TTaxBreakdown, error) {
+	for i := range lines {
+		taxClass, err := t.taxClassOf(lines[i].ProductID)
+		if err != nil {
+			return internal.TTaxBreakdown{}, err
+		}
+		lines[i].TaxClass = taxClass
+	}
+	return t.TaxService.ComputeTaxes(lines, r.URL.Query().Get("jurisdiction"))
+}
+
+// writeBreakdown computes the taxes of lines and writes them as response
+// writeBreakdown(w http.ResponseWriter, r *http.Request, lines []internal.TTaxLine)
+// Args:
+//		w: 	   HTTP response writer
+//		r: 	   HTTP request holding the jurisdiction
+//		lines: Lines with their net amount
+
+func (t *TaxHandler) writeBreakdown(w http.ResponseWriter, r *http.Request, lines []internal.TTaxLine) {
+	breakdown, err := t.breakdown(r, lines)
+	if err != nil {
+		taxError(w, err)
+		return
+	}
+	response.JSON(w, http.StatusOK, map[string]any{
+		"data": breakdown,
+	})
+}
+
+/* Endpoint function handlers */
+
+// GetProductTax returns the net, tax and gross price of a product
+// URL params:
+//
+//	id (Numeric): ID of the product.
+//	jurisdiction (String): Jurisdiction whose rates apply. (Optional, default jurisdiction)
+func (t *TaxHandler) GetProductTax() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := urlID(r, "id")
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+
+		/* Search the product */
+		product, err := t.ProductService.GetProductByID(id)
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+		net := product.Price
+		if product.EffectivePrice != nil {
+			net = *product.EffectivePrice
+		}
+		breakdown, err := t.breakdown(r, []internal.TTaxLine{{ProductID: product.ID, Quantity: 1, Net: net}})
+		if err != nil {
+			productError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data": breakdown,
+		})
+	}
+}
+
+// GetCartTax returns the net, tax and gross amounts of the lines of a cart at their snapshot prices
+// URL params:
+//
+//	id (Numeric): ID of the cart.
+//	jurisdiction (String): Jurisdiction whose rates apply. (Optional, default jurisdiction)
+func (t *TaxHandler) GetCartTax() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+
+		/* Search the cart */
+		cart, err := t.CartService.GetCartByID(id)
+		if err != nil {
+			taxError(w, err)
+			return
+		}
+		lines := make([]internal.TTaxLine, 0, len(cart.Lines))
+		for _, line := range cart.Lines {
+			lines = append(lines, internal.TTaxLine{ProductID: line.ProductID, Quantity: line.Quantity, Net: line.UnitPrice * float64(line.Quantity)})
+		}
+		t.writeBreakdown(w, r, lines)
+	}
+}
+
+// GetOrderTax returns the net, tax and gross amounts of the lines of an order
+// URL params:
+//
+//	id (Numeric): ID of the order.
+//	jurisdiction (String): Jurisdiction whose rates apply. (Optional, default jurisdiction)
+func (t *TaxHandler) GetOrderTax() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+
+		/* Search the order */
+		order, err := t.OrderService.GetOrderByID(id)
+		if err != nil {
+			taxError(w, err)
+			return
+		}
+		lines := make([]internal.TTaxLine, 0, len(order.Items))
+		for _, item := range order.Items {
+			lines = append(lines, internal.TTaxLine{ProductID: item.ProductID, Quantity: item.Quantity, Net: item.UnitPrice * float64(item.Quantity)})
+		}
+		t.writeBreakdown(w, r, lines)
+	}
+}
@@ -0,0 +1,148 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"proyecto/internal"
+	"proyecto/internal/handlers"
+	"proyecto/internal/repository"
+	"proyecto/internal/service"
+	"proyecto/internal/storage"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// initTaxRateStorage writes a tax rates config and returns its storage
+// initTaxRateStorage(rounding string) -> *storage.TaxRateStorageDefault
+// Args:
+// 	rounding: Rounding policy of the config
+// Returns:
+// 	*TaxRateStorageDefault: Initialized storage
+
+func initTaxRateStorage(rounding string) *storage.TaxRateStorageDefault {
+	/* Config creation */
+	filepath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/internal/handlers/tax_rates_test.json"
+	config := `{"default_jurisdiction": "AR", "rounding": "` + rounding + `", "jurisdictions": {
+		"AR": {"standard": 0.21, "reduced": 0.105},
+		"UY": {"standard": 0.22, "reduced": 0.10}
+	}}`
+	if err := os.WriteFile(filepath, []byte(config), 0644); err != nil {
+		panic(err)
+	}
+	return storage.NewTaxRateStorageDefault(filepath)
+}
+
+// taxTestHandler returns a tax handler over the given products and orders
+func taxTestHandler(rounding string, products map[int]internal.TProduct, orders map[int]internal.TOrder) *handlers.TaxHandler {
+	productStorage := initStorage(products)
+	productRepository := repository.NewProductMap(&productStorage)
+	productService := service.NewProductServiceDefault(productRepository)
+	movementService := service.NewMovementServiceDefault(productRepository, repository.NewMovementMap(initMovementStorage(map[int]internal.TMovement{})))
+	orderService := service.NewOrderServiceDefault(repository.NewOrderMap(initOrderStorage(orders)), productService, movementService)
+	cartService := service.NewCartServiceDefault(repository.NewCartMap(initCartStorage(map[int]internal.TCart{})), productService, orderService, 0)
+	taxService := service.NewTaxServiceDefault(initTaxRateStorage(rounding))
+	return handlers.NewTaxHandler(taxService, productService, cartService, orderService)
+}
+
+// TestGetProductTax tests the GetProductTax handler
+func TestGetProductTax(t *testing.T) {
+	initialProducts := map[int]internal.TProduct{
+		1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: true, Expiration: "11/11/2024", Price: 100, TaxClass: "reduced"},
+	}
+	cases := []struct {
+		name         string
+		query        string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "should tax the product in the default jurisdiction",
+			query:        "",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"data": {"jurisdiction": "AR", "rounding": "line", "net": 100, "tax": 10.5, "gross": 110.5, "lines": [
+				{"product_id": 1, "quantity": 1, "tax_class": "reduced", "rate": 0.105, "net": 100, "tax": 10.5, "gross": 110.5}
+			]}}`,
+		},
+		{
+			name:         "should tax the product in the requested jurisdiction",
+			query:        "?jurisdiction=uy",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"data": {"jurisdiction": "UY", "rounding": "line", "net": 100, "tax": 10, "gross": 110, "lines": [
+				{"product_id": 1, "quantity": 1, "tax_class": "reduced", "rate": 0.1, "net": 100, "tax": 10, "gross": 110}
+			]}}`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			/* Initialize dependencies */
+			handler := taxTestHandler(internal.TaxRoundPerLine, initialProducts, map[int]internal.TOrder{})
+
+			/* Prepare the request and the response */
+			req := httptest.NewRequest("GET", "/products/1/tax"+c.query, nil)
+			req = addURLParams(req, map[string]string{"id": "1"})
+			res := httptest.NewRecorder()
+			handler.GetProductTax()(res, req)
+
+			/* Assertions */
+			require.Equal(t, c.expectedCode, res.Code)
+			require.JSONEq(t, c.expectedBody, res.Body.String())
+		})
+	}
+
+	// Unknown jurisdiction
+	t.Run("should reject an unknown jurisdiction", func(t *testing.T) {
+		/* Initialize dependencies */
+		handler := taxTestHandler(internal.TaxRoundPerLine, initialProducts, map[int]internal.TOrder{})
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("GET", "/products/1/tax?jurisdiction=BR", nil)
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+		handler.GetProductTax()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		require.Contains(t, res.Body.String(), `"code":"unknown_jurisdiction"`)
+	})
+}
+
+// TestGetOrderTax tests the rounding policies through the GetOrderTax handler
+func TestGetOrderTax(t *testing.T) {
+	/* Three lines whose taxes round down one by one but not summed */
+	initialProducts := map[int]internal.TProduct{
+		1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: true, Expiration: "11/11/2024", Price: 0.14, TaxClass: "reduced"},
+		2: {ID: 2, Name: "Product 2", Quantity: 10, CodeValue: "AX02", IsPublished: true, Expiration: "11/11/2024", Price: 0.14, TaxClass: "reduced"},
+		3: {ID: 3, Name: "Product 3", Quantity: 10, CodeValue: "AX03", IsPublished: true, Expiration: "11/11/2024", Price: 0.14, TaxClass: "reduced"},
+	}
+	initialOrders := map[int]internal.TOrder{
+		1: {ID: 1, Status: internal.OrderPlaced, Total: 0.42, Items: []internal.TOrderItem{
+			{ProductID: 1, Quantity: 1, UnitPrice: 0.14}, {ProductID: 2, Quantity: 1, UnitPrice: 0.14}, {ProductID: 3, Quantity: 1, UnitPrice: 0.14},
+		}},
+	}
+	cases := []struct {
+		rounding    string
+		expectedTax float64
+	}{
+		{rounding: internal.TaxRoundPerLine, expectedTax: 0.03},
+		{rounding: internal.TaxRoundPerTotal, expectedTax: 0.04},
+	}
+	for _, c := range cases {
+		t.Run("should round the tax per "+c.rounding, func(t *testing.T) {
+			/* Initialize dependencies */
+			handler := taxTestHandler(c.rounding, initialProducts, initialOrders)
+
+			/* Prepare the request and the response */
+			req := httptest.NewRequest("GET", "/orders/1/tax", nil)
+			req = addURLParams(req, map[string]string{"id": "1"})
+			res := httptest.NewRecorder()
+			handler.GetOrderTax()(res, req)
+
+			/* Assertions */
+			require.Equal(t, http.StatusOK, res.Code)
+			require.Contains(t, res.Body.String(), `"net":0.42,"tax":`+strconv.FormatFloat(c.expectedTax, 'f', -1, 64)+`,`)
+		})
+	}
+}
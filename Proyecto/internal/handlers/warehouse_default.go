@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"proyecto/internal"
+	"proyecto/platform/web/request"
+	"proyecto/platform/web/response"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+/* Warehouse handler definition */
+type WarehouseHandler struct {
+	WarehouseService internal.WarehouseService // Warehouse service instance
+}
+
+// NewWarehouseHandler creates a new default valued WarehouseHandler
+// NewWarehouseHandler(ws internal.WarehouseService) -> *WarehouseHandler
+// Args:
+//		ws: Warehouse service instance
+// Return:
+//		*WarehouseHandler: New WarehouseHandler instance
+
+func NewWarehouseHandler(ws internal.WarehouseService) *WarehouseHandler {
+	return &WarehouseHandler{
+		WarehouseService: ws,
+	}
+}
+
+// BodyRequestWarehouseJSON is the body request for a warehouse in JSON format
+type BodyRequestWarehouseJSON struct {
+	Code    string `json:"code"`    // Unique short code of the warehouse.
+	Name    string `json:"name"`    // Warehouse name.
+	Address string `json:"address"` // Warehouse address. (Optional)
+}
+
+// warehouseError writes the response for an error raised by the warehouse service
+// warehouseError(w http.ResponseWriter, err error)
+// Args:
+//		w:   HTTP response writer
+//		err: Error raised by the service
+
+func warehouseError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, internal.ErrWarehouseNotExists):
+		response.Text(w, http.StatusNotFound, "Warehouse not found.")
+	case errors.Is(err, internal.ErrEmptyField):
+		response.Text(w, http.StatusBadRequest, "Invalid body. "+err.Error())
+	case errors.Is(err, internal.ErrWarehouseAlreadyExists):
+		response.Text(w, http.StatusConflict, "Warehouse code already exists.")
+	case errors.Is(err, internal.ErrWarehouseHasStock):
+		response.Text(w, http.StatusConflict, "Warehouse has stock.")
+	case errors.Is(err, internal.ErrWarehouseIsDefault):
+		response.Text(w, http.StatusConflict, "Default warehouse can't be deleted.")
+	default:
+		response.Text(w, http.StatusInternalServerError, "Internal server error.")
+	}
+}
+
+/* Endpoint function handlers */
+
+// GetAllWarehouses returns all the warehouses
+// URL params: none
+func (h *WarehouseHandler) GetAllWarehouses() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		warehouses, err := h.WarehouseService.GetAllWarehouses()
+		if err != nil {
+			warehouseError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data": warehouses,
+		})
+	}
+}
+
+// GetWarehouseByID search a warehouse by ID and return if there is a match.
+// URL params:
+//
+//	id (Numeric): ID of the warehouse.
+func (h *WarehouseHandler) GetWarehouseByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+
+		/* Search the warehouse by id */
+		warehouse, err := h.WarehouseService.GetWarehouseByID(id)
+		if err != nil {
+			warehouseError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data": warehouse,
+		})
+	}
+}
+
+// AddNewWarehouse creates a new warehouse
+// URL params : none
+// Body params: BodyRequestWarehouseJSON
+func (h *WarehouseHandler) AddNewWarehouse() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the body from the request */
+		var body BodyRequestWarehouseJSON
+		if err := request.JSON(r, &body); err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid body.")
+			return
+		}
+
+		/* Insert the new warehouse */
+		warehouse := internal.TWarehouse{Code: body.Code, Name: body.Name, Address: body.Address}
+		if err := h.WarehouseService.InsertNewWarehouse(&warehouse); err != nil {
+			warehouseError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusCreated, map[string]any{
+			"data":    warehouse,
+			"message": "Warehouse created successfully.",
+		})
+	}
+}
+
+// UpdateWarehouse updates a warehouse
+// URL params : id
+// Body params: BodyRequestWarehouseJSON
+func (h *WarehouseHandler) UpdateWarehouse() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+
+		/* Retrieve the body from the request */
+		var body BodyRequestWarehouseJSON
+		if err := request.JSON(r, &body); err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid body.")
+			return
+		}
+
+		/* Update the warehouse */
+		warehouse := internal.TWarehouse{ID: id, Code: body.Code, Name: body.Name, Address: body.Address}
+		if err := h.WarehouseService.UpdateWarehouse(&warehouse); err != nil {
+			warehouseError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data":    warehouse,
+			"message": "Warehouse updated successfully.",
+		})
+	}
+}
+
+// DeleteWarehouse deletes an empty warehouse
+// URL params : id
+func (h *WarehouseHandler) DeleteWarehouse() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+
+		/* Delete the warehouse */
+		if err := h.WarehouseService.DeleteWarehouse(id); err != nil {
+			warehouseError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
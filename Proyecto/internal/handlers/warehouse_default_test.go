@@ -0,0 +1,166 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"proyecto/internal"
+	"proyecto/internal/handlers"
+	"proyecto/internal/repository"
+	"proyecto/internal/service"
+	"proyecto/internal/storage"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// initWarehouseStorage initializes the warehouse storage
+// initWarehouseStorage(map[int]internal.TWarehouse) -> *storage.WarehouseStorageDefault
+// Args:
+// 	initialWarehouses: Initial warehouses
+// Returns:
+// 	*WarehouseStorageDefault: Initialized storage
+
+func initWarehouseStorage(initialWarehouses map[int]internal.TWarehouse) *storage.WarehouseStorageDefault {
+	/* Storage creation */
+	filepath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/internal/handlers/warehouses_test.json"
+	storage := storage.NewWarehouseStorageDefault(filepath)
+
+	/* Initial data of the storage */
+	err := storage.WriteAll(initialWarehouses)
+	if err != nil {
+		panic(err)
+	}
+	return storage
+}
+
+// warehouseTestData returns two warehouses and a product stocked on both of them
+func warehouseTestData() (map[int]internal.TProduct, map[int]internal.TWarehouse, map[int]internal.TMovement) {
+	date := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	products := map[int]internal.TProduct{
+		1: {ID: 1, Name: "Product 1", Quantity: 15, CodeValue: "AX01", IsPublished: true, Expiration: "11/11/2024", Price: 10.5},
+	}
+	warehouses := map[int]internal.TWarehouse{
+		1: {ID: 1, Code: "MAIN", Name: "Main warehouse"},
+		2: {ID: 2, Code: "NORTH", Name: "North warehouse"},
+		3: {ID: 3, Code: "SOUTH", Name: "South warehouse"},
+	}
+	movements := map[int]internal.TMovement{
+		1: {ID: 1, ProductID: 1, WarehouseID: 1, Type: "receipt", Quantity: 10, Reason: "initial stock", Date: date},
+		2: {ID: 2, ProductID: 1, WarehouseID: 2, Type: "receipt", Quantity: 5, Reason: "initial stock", Date: date},
+	}
+	return products, warehouses, movements
+}
+
+// TestTransferStock tests the TransferStock handler
+func TestTransferStock(t *testing.T) {
+	// Test 1: should move the stock keeping the aggregate quantity
+	t.Run("should move the stock keeping the aggregate quantity", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialProducts, initialWarehouses, initialMovements := warehouseTestData()
+		productStorage := initStorage(initialProducts)
+		productRepository := repository.NewProductMap(&productStorage)
+		movementService := service.NewMovementServiceDefault(productRepository, repository.NewMovementMap(initMovementStorage(initialMovements)))
+		movementService.SetWarehouses(repository.NewWarehouseMap(initWarehouseStorage(initialWarehouses)))
+		handler := handlers.NewMovementHandler(movementService)
+
+		/* Prepare the request and the response */
+		reqBody := `{"from_warehouse_id": 1, "to_warehouse_id": 3, "quantity": 4, "reason": "rebalance"}`
+		req := httptest.NewRequest("POST", "/products/1/transfers", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+		handler.TransferStock()(res, req)
+		require.Equal(t, http.StatusCreated, res.Code)
+
+		/* Read the stock levels */
+		req = httptest.NewRequest("GET", "/products/1/stock", nil)
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res = httptest.NewRecorder()
+		handler.GetStockLevels()(res, req)
+
+		/* Expected values definition */
+		expectedCode := http.StatusOK
+		expectedBody := `{"data": [
+			{"product_id": 1, "warehouse_id": 1, "quantity": 6},
+			{"product_id": 1, "warehouse_id": 2, "quantity": 5},
+			{"product_id": 1, "warehouse_id": 3, "quantity": 4}
+		], "total": 15}`
+
+		/* Assertions */
+		require.Equal(t, expectedCode, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+		product, err := productRepository.GetProductByID(1)
+		require.NoError(t, err)
+		require.Equal(t, 15, product.Quantity)
+	})
+
+	// Test 2: should return a conflict error when the origin has not enough stock
+	t.Run("should return a conflict error when the origin has not enough stock", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialProducts, initialWarehouses, initialMovements := warehouseTestData()
+		productStorage := initStorage(initialProducts)
+		movementService := service.NewMovementServiceDefault(repository.NewProductMap(&productStorage), repository.NewMovementMap(initMovementStorage(initialMovements)))
+		movementService.SetWarehouses(repository.NewWarehouseMap(initWarehouseStorage(initialWarehouses)))
+		handler := handlers.NewMovementHandler(movementService)
+
+		/* Prepare the request and the response */
+		reqBody := `{"from_warehouse_id": 2, "to_warehouse_id": 1, "quantity": 6, "reason": "rebalance"}`
+		req := httptest.NewRequest("POST", "/products/1/transfers", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+		handler.TransferStock()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusConflict, res.Code)
+		require.Contains(t, res.Body.String(), `"code":"insufficient_stock"`)
+	})
+}
+
+// TestDeleteWarehouse tests the DeleteWarehouse handler
+func TestDeleteWarehouse(t *testing.T) {
+	// Test 1: should block the delete of a warehouse with stock
+	t.Run("should block the delete of a warehouse with stock", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialProducts, initialWarehouses, initialMovements := warehouseTestData()
+		productStorage := initStorage(initialProducts)
+		warehouseRepository := repository.NewWarehouseMap(initWarehouseStorage(initialWarehouses))
+		movementService := service.NewMovementServiceDefault(repository.NewProductMap(&productStorage), repository.NewMovementMap(initMovementStorage(initialMovements)))
+		movementService.SetWarehouses(warehouseRepository)
+		handler := handlers.NewWarehouseHandler(service.NewWarehouseServiceDefault(warehouseRepository, movementService))
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("DELETE", "/warehouses/2", nil)
+		req = addURLParams(req, map[string]string{"id": "2"})
+		res := httptest.NewRecorder()
+		handler.DeleteWarehouse()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusConflict, res.Code)
+		require.Equal(t, "Warehouse has stock.", res.Body.String())
+	})
+
+	// Test 2: should delete an empty warehouse
+	t.Run("should delete an empty warehouse", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialProducts, initialWarehouses, initialMovements := warehouseTestData()
+		productStorage := initStorage(initialProducts)
+		warehouseRepository := repository.NewWarehouseMap(initWarehouseStorage(initialWarehouses))
+		movementService := service.NewMovementServiceDefault(repository.NewProductMap(&productStorage), repository.NewMovementMap(initMovementStorage(initialMovements)))
+		movementService.SetWarehouses(warehouseRepository)
+		handler := handlers.NewWarehouseHandler(service.NewWarehouseServiceDefault(warehouseRepository, movementService))
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("DELETE", "/warehouses/3", nil)
+		req = addURLParams(req, map[string]string{"id": "3"})
+		res := httptest.NewRecorder()
+		handler.DeleteWarehouse()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusNoContent, res.Code)
+		_, err := warehouseRepository.GetWarehouseByID(3)
+		require.ErrorIs(t, err, internal.ErrWarehouseNotFound)
+	})
+}
@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"proyecto/internal"
+	"proyecto/platform/web/request"
+	"proyecto/platform/web/response"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+/* Webhook handler definition */
+type WebhookHandler struct {
+	WebhookService internal.WebhookService // Webhook service instance
+}
+
+// NewWebhookHandler creates a new default valued WebhookHandler
+// NewWebhookHandler(ws internal.WebhookService) -> *WebhookHandler
+// Args:
+//		ws: Webhook service instance
+// Return:
+//		*WebhookHandler: New WebhookHandler instance
+
+func NewWebhookHandler(ws internal.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		WebhookService: ws,
+	}
+}
+
+// BodyRequestWebhookJSON is the body request for a webhook in JSON format
+type BodyRequestWebhookJSON struct {
+	URL    string   `json:"url"`    // Absolute http or https URL the events are posted to.
+	Events []string `json:"events"` // Event types subscribed to.
+	Secret string   `json:"secret"` // Key the payloads are signed with. (Optional on updates, the current one is kept)
+	Active *bool    `json:"active"` // Whether new deliveries are queued. (Optional, true by default)
+}
+
+// WebhookJSON is a webhook as returned by the API. The secret is never returned
+type WebhookJSON struct {
+	ID        int       `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// webhookJSON converts a webhook into its API representation
+// webhookJSON(webhook internal.TWebhook) -> WebhookJSON
+
+func webhookJSON(webhook internal.TWebhook) WebhookJSON {
+	return WebhookJSON{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Events:    webhook.Events,
+		Active:    webhook.Active,
+		CreatedAt: webhook.CreatedAt,
+	}
+}
+
+// webhookError writes the response for an error raised by the webhook service
+// webhookError(w http.ResponseWriter, err error)
+// Args:
+//		w:   HTTP response writer
+//		err: Error raised by the service
+
+func webhookError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, internal.ErrWebhookNotExists):
+		response.Text(w, http.StatusNotFound, "Webhook not found.")
+	case errors.Is(err, internal.ErrWebhookDeliveryNotExists):
+		response.Text(w, http.StatusNotFound, "Webhook delivery not found.")
+	case errors.Is(err, internal.ErrEmptyField), errors.Is(err, internal.ErrInvalidWebhookURL), errors.Is(err, internal.ErrInvalidWebhookEvent):
+		response.Text(w, http.StatusBadRequest, "Invalid body. "+err.Error())
+	case errors.Is(err, internal.ErrWebhookDeliveryNotDead):
+		response.Text(w, http.StatusConflict, "Only dead deliveries can be redelivered.")
+	default:
+		response.Text(w, http.StatusInternalServerError, "Internal server error.")
+	}
+}
+
+// webhookFromBody builds the webhook described by a request body
+// webhookFromBody(id int, body BodyRequestWebhookJSON) -> internal.TWebhook
+
+func webhookFromBody(id int, body BodyRequestWebhookJSON) internal.TWebhook {
+	webhook := internal.TWebhook{ID: id, URL: body.URL, Events: body.Events, Secret: body.Secret, Active: true}
+	if body.Active != nil {
+		webhook.Active = *body.Active
+	}
+	return webhook
+}
+
+/* Endpoint function handlers */
+
+// GetAllWebhooks returns all the webhooks
+// URL params: none
+func (h *WebhookHandler) GetAllWebhooks() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		webhooks, err := h.WebhookService.GetAllWebhooks()
+		if err != nil {
+			webhookError(w, err)
+			return
+		}
+		data := make([]WebhookJSON, 0, len(webhooks))
+		for _, webhook := range webhooks {
+			data = append(data, webhookJSON(webhook))
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data": data,
+		})
+	}
+}
+
+// GetWebhookByID search a webhook by ID and return if there is a match.
+// URL params:
+//
+//	id (Numeric): ID of the webhook.
+func (h *WebhookHandler) GetWebhookByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+
+		/* Search the webhook by id */
+		webhook, err := h.WebhookService.GetWebhookByID(id)
+		if err != nil {
+			webhookError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data": webhookJSON(webhook),
+		})
+	}
+}
+
+// AddNewWebhook subscribes a URL to some event types
+// URL params : none
+// Body params: BodyRequestWebhookJSON
+func (h *WebhookHandler) AddNewWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the body from the request */
+		var body BodyRequestWebhookJSON
+		if err := request.JSON(r, &body); err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid body.")
+			return
+		}
+
+		/* Insert the new webhook */
+		webhook := webhookFromBody(0, body)
+		if err := h.WebhookService.InsertNewWebhook(&webhook); err != nil {
+			webhookError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusCreated, map[string]any{
+			"data":    webhookJSON(webhook),
+			"message": "Webhook created successfully.",
+		})
+	}
+}
+
+// UpdateWebhook updates a webhook
+// URL params : id
+// Body params: BodyRequestWebhookJSON
+func (h *WebhookHandler) UpdateWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+
+		/* Retrieve the body from the request */
+		var body BodyRequestWebhookJSON
+		if err := request.JSON(r, &body); err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid body.")
+			return
+		}
+
+		/* Update the webhook */
+		webhook := webhookFromBody(id, body)
+		if err := h.WebhookService.UpdateWebhook(&webhook); err != nil {
+			webhookError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data":    webhookJSON(webhook),
+			"message": "Webhook updated successfully.",
+		})
+	}
+}
+
+// DeleteWebhook deletes a webhook. Its pending deliveries are dead-lettered
+// URL params : id
+func (h *WebhookHandler) DeleteWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+
+		/* Delete the webhook */
+		if err := h.WebhookService.DeleteWebhook(id); err != nil {
+			webhookError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// GetDeliveries returns the delivery history of a webhook, newest first, with every attempt
+// URL params : id
+func (h *WebhookHandler) GetDeliveries() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+
+		/* Search the deliveries of the webhook */
+		deliveries, err := h.WebhookService.GetDeliveries(id)
+		if err != nil {
+			webhookError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data": deliveries,
+		})
+	}
+}
+
+// GetDeadLetters returns the deliveries whose attempts all failed, newest first
+// URL params: none
+func (h *WebhookHandler) GetDeadLetters() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		deliveries, err := h.WebhookService.GetDeadLetters()
+		if err != nil {
+			webhookError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"data": deliveries,
+		})
+	}
+}
+
+// RedeliverDelivery queues a dead delivery for a new round of attempts
+// URL params : id (of the delivery)
+func (h *WebhookHandler) RedeliverDelivery() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		/* Retrieve the id from the url */
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID.")
+			return
+		}
+
+		/* Queue the delivery again */
+		delivery, err := h.WebhookService.Redeliver(id)
+		if err != nil {
+			webhookError(w, err)
+			return
+		}
+		response.JSON(w, http.StatusAccepted, map[string]any{
+			"data":    delivery,
+			"message": "Delivery queued.",
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"proyecto/internal"
+	"proyecto/internal/clock"
+	"proyecto/internal/handlers"
+	"proyecto/internal/repository"
+	"proyecto/internal/service"
+	"proyecto/internal/storage"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// initWebhookService initializes a webhook service over the given data
+// initWebhookService(map[int]internal.TWebhook, map[int]internal.TWebhookDelivery) -> *service.WebhookServiceDefault
+// Args:
+// 	initialWebhooks:   Initial webhooks
+// 	initialDeliveries: Initial deliveries
+// Returns:
+// 	*service.WebhookServiceDefault: Initialized service
+
+func initWebhookService(initialWebhooks map[int]internal.TWebhook, initialDeliveries map[int]internal.TWebhookDelivery) *service.WebhookServiceDefault {
+	/* Storage creation */
+	webhookStorage := storage.NewWebhookStorageDefault("/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/internal/handlers/webhooks_test.json")
+	deliveryStorage := storage.NewWebhookDeliveryStorageDefault("/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/internal/handlers/webhook_deliveries_test.json")
+
+	/* Initial data of the storage */
+	if err := webhookStorage.WriteAll(initialWebhooks); err != nil {
+		panic(err)
+	}
+	if err := deliveryStorage.WriteAll(initialDeliveries); err != nil {
+		panic(err)
+	}
+	return service.NewWebhookServiceDefault(repository.NewWebhookMap(webhookStorage), repository.NewWebhookDeliveryMap(deliveryStorage))
+}
+
+// webhookTestData returns a webhook with a delivered and a dead delivery
+func webhookTestData() (map[int]internal.TWebhook, map[int]internal.TWebhookDelivery) {
+	date := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	webhooks := map[int]internal.TWebhook{
+		1: {ID: 1, URL: "https://partner.example/hooks", Events: []string{internal.WebhookEventPriceChanged}, Secret: "partner-secret", Active: true, CreatedAt: date},
+	}
+	deliveries := map[int]internal.TWebhookDelivery{
+		1: {ID: 1, WebhookID: 1, EventID: 3, EventType: internal.WebhookEventPriceChanged, Payload: []byte(`{"type":"product.price_changed"}`),
+			Status: internal.WebhookDeliveryDelivered, Attempts: []internal.TWebhookAttempt{{Date: date, StatusCode: 200, DurationMs: 12}}, CreatedAt: date},
+		2: {ID: 2, WebhookID: 1, EventID: 4, EventType: internal.WebhookEventPriceChanged, Payload: []byte(`{"type":"product.price_changed"}`),
+			Status: internal.WebhookDeliveryDead, Attempts: []internal.TWebhookAttempt{{Date: date, StatusCode: 500, Error: "unexpected status 500", DurationMs: 7}},
+			Failures: 1, CreatedAt: date},
+	}
+	return webhooks, deliveries
+}
+
+// TestAddNewWebhook tests the AddNewWebhook handler
+func TestAddNewWebhook(t *testing.T) {
+	// Test 1: should subscribe the url without returning the secret
+	t.Run("should subscribe the url without returning the secret", func(t *testing.T) {
+		/* Initialize dependencies */
+		webhookService := initWebhookService(map[int]internal.TWebhook{}, map[int]internal.TWebhookDelivery{})
+		webhookService.SetClock(clock.NewClockFixed(time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)))
+		handler := handlers.NewWebhookHandler(webhookService)
+
+		/* Prepare the request and the response */
+		reqBody := `{"url": "https://partner.example/hooks", "events": ["product.price_changed", "product.stock_changed", "product.price_changed"], "secret": "partner-secret"}`
+		req := httptest.NewRequest("POST", "/webhooks", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		res := httptest.NewRecorder()
+		handler.AddNewWebhook()(res, req)
+
+		/* Expected values definition */
+		expectedCode := http.StatusCreated
+		expectedBody := `{"data": {"id": 1, "url": "https://partner.example/hooks", "events": ["product.price_changed", "product.stock_changed"],
+			"active": true, "created_at": "2024-03-01T09:00:00Z"}, "message": "Webhook created successfully."}`
+
+		/* Assertions */
+		require.Equal(t, expectedCode, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+		webhook, err := webhookService.GetWebhookByID(1)
+		require.NoError(t, err)
+		require.Equal(t, "partner-secret", webhook.Secret)
+	})
+
+	// Test 2: should reject invalid subscriptions
+	t.Run("should reject invalid subscriptions", func(t *testing.T) {
+		/* Initialize dependencies */
+		handler := handlers.NewWebhookHandler(initWebhookService(map[int]internal.TWebhook{}, map[int]internal.TWebhookDelivery{}))
+
+		for reqBody, expectedBody := range map[string]string{
+			`{"url": "ftp://partner.example", "events": ["product.deleted"], "secret": "s"}`:   `Invalid body. invalid webhook url: "ftp://partner.example"`,
+			`{"url": "https://partner.example", "events": ["product.renamed"], "secret": "s"}`: `Invalid body. invalid webhook event type: "product.renamed", expected one of product.price_changed, product.stock_changed, product.inserted, product.updated, product.deleted, product.published, product.unpublished`,
+			`{"url": "https://partner.example", "events": ["product.deleted"]}`:                `Invalid body. empty field: Secret`,
+			`{"url": "https://partner.example", "events": "product.deleted", "secret": "s"}`:   `Invalid body.`,
+		} {
+			/* Prepare the request and the response */
+			req := httptest.NewRequest("POST", "/webhooks", strings.NewReader(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+			res := httptest.NewRecorder()
+			handler.AddNewWebhook()(res, req)
+
+			/* Assertions */
+			require.Equal(t, http.StatusBadRequest, res.Code, reqBody)
+			require.Equal(t, expectedBody, res.Body.String(), reqBody)
+		}
+	})
+}
+
+// TestUpdateWebhook tests the UpdateWebhook handler
+func TestUpdateWebhook(t *testing.T) {
+	// Test 1: should keep the secret when the body has none
+	t.Run("should keep the secret when the body has none", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialWebhooks, initialDeliveries := webhookTestData()
+		webhookService := initWebhookService(initialWebhooks, initialDeliveries)
+		handler := handlers.NewWebhookHandler(webhookService)
+
+		/* Prepare the request and the response */
+		reqBody := `{"url": "https://partner.example/v2/hooks", "events": ["product.stock_changed"], "active": false}`
+		req := httptest.NewRequest("PUT", "/webhooks/1", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+		handler.UpdateWebhook()(res, req)
+
+		/* Expected values definition */
+		expectedCode := http.StatusOK
+		expectedBody := `{"data": {"id": 1, "url": "https://partner.example/v2/hooks", "events": ["product.stock_changed"],
+			"active": false, "created_at": "2024-03-01T09:00:00Z"}, "message": "Webhook updated successfully."}`
+
+		/* Assertions */
+		require.Equal(t, expectedCode, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+		webhook, err := webhookService.GetWebhookByID(1)
+		require.NoError(t, err)
+		require.Equal(t, "partner-secret", webhook.Secret)
+	})
+}
+
+// TestGetDeliveries tests the GetDeliveries, GetDeadLetters and RedeliverDelivery handlers
+func TestGetDeliveries(t *testing.T) {
+	// Test 1: should return the delivery history of a webhook, newest first
+	t.Run("should return the delivery history of a webhook, newest first", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialWebhooks, initialDeliveries := webhookTestData()
+		handler := handlers.NewWebhookHandler(initWebhookService(initialWebhooks, initialDeliveries))
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("GET", "/webhooks/1/deliveries", nil)
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+		handler.GetDeliveries()(res, req)
+
+		/* Expected values definition */
+		expectedCode := http.StatusOK
+		expectedBody := `{"data": [
+			{"id": 2, "webhook_id": 1, "event_id": 4, "event_type": "product.price_changed", "payload": {"type": "product.price_changed"},
+				"status": "dead", "attempts": [{"date": "2024-03-01T09:00:00Z", "status_code": 500, "error": "unexpected status 500", "duration_ms": 7}],
+				"failures": 1, "created_at": "2024-03-01T09:00:00Z"},
+			{"id": 1, "webhook_id": 1, "event_id": 3, "event_type": "product.price_changed", "payload": {"type": "product.price_changed"},
+				"status": "delivered", "attempts": [{"date": "2024-03-01T09:00:00Z", "status_code": 200, "duration_ms": 12}],
+				"failures": 0, "created_at": "2024-03-01T09:00:00Z"}
+		]}`
+
+		/* Assertions */
+		require.Equal(t, expectedCode, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+	})
+
+	// Test 2: should return a not found error when the webhook doesn't exist
+	t.Run("should return a not found error when the webhook doesn't exist", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialWebhooks, initialDeliveries := webhookTestData()
+		handler := handlers.NewWebhookHandler(initWebhookService(initialWebhooks, initialDeliveries))
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("GET", "/webhooks/9/deliveries", nil)
+		req = addURLParams(req, map[string]string{"id": "9"})
+		res := httptest.NewRecorder()
+		handler.GetDeliveries()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusNotFound, res.Code)
+		require.Equal(t, "Webhook not found.", res.Body.String())
+	})
+
+	// Test 3: should list and redeliver the dead deliveries only
+	t.Run("should list and redeliver the dead deliveries only", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialWebhooks, initialDeliveries := webhookTestData()
+		webhookService := initWebhookService(initialWebhooks, initialDeliveries)
+		webhookService.SetClock(clock.NewClockFixed(time.Date(2024, 3, 2, 9, 0, 0, 0, time.UTC)))
+		handler := handlers.NewWebhookHandler(webhookService)
+
+		/* Read the dead-letter list */
+		req := httptest.NewRequest("GET", "/webhooks/dead-letters", nil)
+		res := httptest.NewRecorder()
+		handler.GetDeadLetters()(res, req)
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Contains(t, res.Body.String(), `"id":2`)
+		require.NotContains(t, res.Body.String(), `"id":1`)
+
+		/* A delivered delivery can't be redelivered */
+		req = httptest.NewRequest("POST", "/webhooks/deliveries/1/redeliver", nil)
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res = httptest.NewRecorder()
+		handler.RedeliverDelivery()(res, req)
+		require.Equal(t, http.StatusConflict, res.Code)
+		require.Equal(t, "Only dead deliveries can be redelivered.", res.Body.String())
+
+		/* The dead one is queued again */
+		req = httptest.NewRequest("POST", "/webhooks/deliveries/2/redeliver", nil)
+		req = addURLParams(req, map[string]string{"id": "2"})
+		res = httptest.NewRecorder()
+		handler.RedeliverDelivery()(res, req)
+		require.Equal(t, http.StatusAccepted, res.Code)
+		due, err := webhookService.DueDeliveries()
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		require.Equal(t, 2, due[0].ID)
+		require.Equal(t, 0, due[0].Failures)
+	})
+
+	// Test 4: should dead-letter the pending deliveries of a deleted webhook
+	t.Run("should dead-letter the pending deliveries of a deleted webhook", func(t *testing.T) {
+		/* Initialize dependencies */
+		initialWebhooks, initialDeliveries := webhookTestData()
+		webhookService := initWebhookService(initialWebhooks, initialDeliveries)
+		_, err := webhookService.Enqueue(internal.TProductEvent{ID: 5, Type: internal.ProductEventUpdated, ProductID: 1, Changes: []string{internal.ProductChangePrice}})
+		require.NoError(t, err)
+		handler := handlers.NewWebhookHandler(webhookService)
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("DELETE", "/webhooks/1", nil)
+		req = addURLParams(req, map[string]string{"id": "1"})
+		res := httptest.NewRecorder()
+		handler.DeleteWebhook()(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusNoContent, res.Code)
+		dead, err := webhookService.GetDeadLetters()
+		require.NoError(t, err)
+		require.Len(t, dead, 2)
+		require.Equal(t, int64(5), dead[0].EventID)
+		_, ok := webhookService.NextAttempt()
+		require.False(t, ok)
+	})
+}
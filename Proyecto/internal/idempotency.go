@@ -0,0 +1,37 @@
+package internal
+
+import (
+	"errors"
+	"time"
+)
+
+// TIdempotencyRecord represents the first response given to a request sent with an Idempotency-Key.
+type TIdempotencyRecord struct {
+	Key         string              `json:"key"`         // Idempotency-Key sent by the client.
+	Principal   string              `json:"principal"`   // Hash of the credentials the request was sent with.
+	Fingerprint string              `json:"fingerprint"` // Hash of the method, path and body of the request.
+	Status      int                 `json:"status"`      // Status code of the stored response.
+	Header      map[string][]string `json:"header"`      // Headers of the stored response.
+	Body        []byte              `json:"body"`        // Body of the stored response.
+	CreatedAt   time.Time           `json:"created_at"`
+	ExpiresAt   time.Time           `json:"expires_at"` // The record is forgotten past this moment.
+}
+
+// IdempotencyRecordKey returns the key a record is looked up by. Keys are scoped by principal,
+// so two clients can't see each other's responses
+func IdempotencyRecordKey(principal, key string) string {
+	return principal + ":" + key
+}
+
+/* Idempotency errors */
+var (
+	ErrIdempotencyKeyInvalid  = errors.New("idempotency key invalid")
+	ErrIdempotencyKeyReused   = errors.New("idempotency key reused with a different request")
+	ErrIdempotencyKeyInFlight = errors.New("idempotency key in use by a request in progress")
+)
+
+/* Idempotency storage definition */
+type IdempotencyStorage interface {
+	GetAll() (map[string]TIdempotencyRecord, error) // Get all records from storage, keyed by principal and key
+	WriteAll(map[string]TIdempotencyRecord) error   // Write all records to storage
+}
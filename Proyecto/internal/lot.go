@@ -0,0 +1,21 @@
+package internal
+
+import "time"
+
+/* Lot consumption policies */
+const (
+	LotPolicyFIFO = "fifo" // The lots received first are consumed first.
+	LotPolicyFEFO = "fefo" // The lots expiring first are consumed first.
+)
+
+// TLot represents a batch of a product received in a warehouse with its own expiration.
+type TLot struct {
+	ID               int       `json:"id"`
+	ProductID        int       `json:"product_id"`
+	WarehouseID      int       `json:"warehouse_id"`
+	Quantity         int       `json:"quantity"`          // Units of the lot still in stock.
+	ReceivedQuantity int       `json:"received_quantity"` // Units the lot arrived with.
+	Expiration       string    `json:"expiration"`        // Expiration date of the lot with the format dd/mm/yyyy.
+	ReceivedAt       time.Time `json:"received_at"`       // Moment the lot entered the stock. Transfers keep the original one.
+	MovementID       int       `json:"movement_id"`       // Inbound movement that created the lot.
+}
@@ -0,0 +1,16 @@
+package internal
+
+import "errors"
+
+/* Errors definition */
+var (
+	ErrLotNotFound = errors.New("lot not found")
+)
+
+/* Lot repository definition */
+type LotRepository interface {
+	GetAllLots() ([]TLot, error)                    // Return all the lots in the repository.
+	GetLotsByProduct(productID int) ([]TLot, error) // Return the lots of a product.
+	SaveLots(lots []TLot) error                     // Add the new lots and update the existing ones in a single write.
+	DeleteLotsByProduct(productID int) error        // Delete the lots of a product.
+}
@@ -0,0 +1,7 @@
+package internal
+
+/* Lot storage definition */
+type LotStorage interface {
+	GetAll() (map[int]TLot, error) // Get all lots from storage
+	WriteAll(map[int]TLot) error   // Write all lots to storage
+}
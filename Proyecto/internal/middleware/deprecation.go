@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"proyecto/platform/web/request"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Deprecation announces on the responses of a deprecated API version the moment it was
+// deprecated and the one it stops being served, with the Deprecation and Sunset headers. The
+// routes served by the successor version are linked with a successor-version Link header
+type Deprecation struct {
+	version      string     // Version deprecated
+	deprecatedAt time.Time  // Moment the version was deprecated (Optional)
+	sunsetAt     time.Time  // Moment the version stops being served (Optional)
+	prefix       string     // Path prefix the successor version is served under
+	successor    chi.Routes // Routes of the successor version (Optional)
+}
+
+// NewDeprecation creates a new Deprecation
+// NewDeprecation(version string, deprecatedAt time.Time, sunsetAt time.Time) -> *Deprecation
+// Args:
+//		version:      Version deprecated
+//		deprecatedAt: Moment the version was deprecated. Zero to leave the Deprecation header out
+//		sunsetAt:     Moment the version stops being served. Zero to leave the Sunset header out
+// Return:
+//		*Deprecation: New Deprecation
+
+func NewDeprecation(version string, deprecatedAt time.Time, sunsetAt time.Time) *Deprecation {
+	return &Deprecation{version: version, deprecatedAt: deprecatedAt, sunsetAt: sunsetAt}
+}
+
+// SetSuccessor sets the routes of the version replacing the deprecated one
+// SetSuccessor(prefix string, routes chi.Routes)
+// Args:
+//		prefix: Path prefix the successor version is served under
+//		routes: Routes of the successor version, relative to the prefix
+
+func (d *Deprecation) SetSuccessor(prefix string, routes chi.Routes) {
+	d.prefix = prefix
+	d.successor = routes
+}
+
+// successorOf returns the path of the successor version serving a request, if it has one
+// successorOf(r *http.Request) -> (string, bool)
+
+func (d *Deprecation) successorOf(r *http.Request) (string, bool) {
+	if d.successor == nil {
+		return "", false
+	}
+
+	/* The path relative to the mount point of the deprecated version */
+	path := r.URL.Path
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePath != "" {
+		path = rctx.RoutePath
+	}
+	if !d.successor.Match(chi.NewRouteContext(), r.Method, path) {
+		return "", false
+	}
+	return d.prefix + path, true
+}
+
+// Announce is a middleware setting the deprecation headers on the responses of the requests
+// served with the deprecated version (see request.Version)
+// Announce(handler http.Handler) -> http.Handler
+// Args:
+//		handler: HTTP handler
+// Return:
+//		http.Handler: HTTP handler
+
+func (d *Deprecation) Announce(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if request.Version(r) != d.version || (d.deprecatedAt.IsZero() && d.sunsetAt.IsZero()) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		if !d.deprecatedAt.IsZero() {
+			w.Header().Set("Deprecation", "@"+strconv.FormatInt(d.deprecatedAt.Unix(), 10))
+		}
+		if !d.sunsetAt.IsZero() {
+			w.Header().Set("Sunset", d.sunsetAt.UTC().Format(http.TimeFormat))
+		}
+		if successor, ok := d.successorOf(r); ok {
+			w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
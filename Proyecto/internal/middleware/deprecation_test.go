@@ -0,0 +1,96 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"proyecto/internal/middleware"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// newDeprecatedRouter serves v1 unprefixed and under /v1, and its successor v2 under /v2
+// newDeprecatedRouter(deprecation *middleware.Deprecation) -> *chi.Mux
+
+func newDeprecatedRouter(deprecation *middleware.Deprecation) *chi.Mux {
+	versions := middleware.NewVersioning("1", "1", "2")
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+	v1 := func(r chi.Router) {
+		r.Get("/products/{id}", ok)
+		r.Get("/products/{id}/movements", ok)
+	}
+	router := chi.NewRouter()
+	router.Group(func(r chi.Router) {
+		r.Use(versions.Negotiate, deprecation.Announce)
+		v1(r)
+	})
+	router.Route("/v1", func(r chi.Router) {
+		r.Use(versions.Pin("1"), deprecation.Announce)
+		v1(r)
+	})
+	v2 := router.Route("/v2", func(r chi.Router) {
+		r.Use(versions.Pin("2"), deprecation.Announce)
+		r.Get("/products/{id}", ok)
+	})
+	deprecation.SetSuccessor("/v2", v2)
+	return router
+}
+
+// TestDeprecation tests the Deprecation middleware
+func TestDeprecation(t *testing.T) {
+	deprecatedAt := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	sunsetAt := time.Date(2027, 4, 30, 0, 0, 0, 0, time.UTC)
+
+	// Test 1: should announce the deprecation on every v1 route, linking the successor if it exists
+	t.Run("should announce the deprecation on every v1 route, linking the successor if it exists", func(t *testing.T) {
+		router := newDeprecatedRouter(middleware.NewDeprecation("1", deprecatedAt, sunsetAt))
+
+		/* Requests and expected headers */
+		cases := []struct {
+			target, accept, deprecation, link string
+		}{
+			{"/products/3", "", "@1792281600", `</v2/products/3>; rel="successor-version"`},
+			{"/v1/products/3", "", "@1792281600", `</v2/products/3>; rel="successor-version"`},
+			{"/v1/products/3/movements", "", "@1792281600", ""},
+			{"/products/3/movements", "", "@1792281600", ""},
+			{"/products/3", "application/json; version=2", "", ""},
+			{"/v2/products/3", "", "", ""},
+		}
+		for _, c := range cases {
+			req := httptest.NewRequest("GET", c.target, nil)
+			if c.accept != "" {
+				req.Header.Set("Accept", c.accept)
+			}
+			res := httptest.NewRecorder()
+			router.ServeHTTP(res, req)
+
+			/* Assertions */
+			require.Equal(t, http.StatusOK, res.Code, c.target)
+			require.Equal(t, c.deprecation, res.Header().Get("Deprecation"), c.target)
+			require.Equal(t, c.link, res.Header().Get("Link"), c.target)
+			if c.deprecation != "" {
+				require.Equal(t, "Fri, 30 Apr 2027 00:00:00 GMT", res.Header().Get("Sunset"), c.target)
+			} else {
+				require.Empty(t, res.Header().Get("Sunset"), c.target)
+			}
+		}
+	})
+
+	// Test 2: should leave the headers out without a deprecation set
+	t.Run("should leave the headers out without a deprecation set", func(t *testing.T) {
+		router := newDeprecatedRouter(middleware.NewDeprecation("1", time.Time{}, time.Time{}))
+
+		/* Prepare the request and the response */
+		req := httptest.NewRequest("GET", "/v1/products/3", nil)
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Empty(t, res.Header().Get("Deprecation"))
+		require.Empty(t, res.Header().Get("Sunset"))
+		require.Empty(t, res.Header().Get("Link"))
+	})
+}
@@ -0,0 +1,266 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"proyecto/internal"
+	"proyecto/platform/web/response"
+	"sync"
+	"time"
+)
+
+/* Headers of the idempotent requests */
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"     // Header the key is read from
+	IdempotentReplayedHeader = "Idempotent-Replayed" // Header set on the replayed responses
+)
+
+// idempotencyProblems maps the errors of the idempotency keys to their problem types
+var idempotencyProblems = response.NewProblems().
+	Register(internal.ErrIdempotencyKeyInvalid, http.StatusBadRequest, "invalid_idempotency_key", "Invalid idempotency key").
+	Register(internal.ErrIdempotencyKeyReused, http.StatusUnprocessableEntity, "idempotency_key_reused", "Idempotency key reused").
+	Register(internal.ErrIdempotencyKeyInFlight, http.StatusConflict, "idempotency_key_in_flight", "Idempotency key in flight")
+
+/* Response writer which keeps a copy of what is written */
+type recordingWriter struct {
+	http.ResponseWriter
+	status int          // Status code written
+	body   bytes.Buffer // Body written
+}
+
+// WriteHeader writes the status code keeping a copy
+func (rw *recordingWriter) WriteHeader(status int) {
+	if rw.status == 0 {
+		rw.status = status
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+// Write writes the body keeping a copy
+func (rw *recordingWriter) Write(data []byte) (int, error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	rw.body.Write(data)
+	return rw.ResponseWriter.Write(data)
+}
+
+// Idempotency stores the first response given to each Idempotency-Key and replays it on the
+// retries of the same request
+type Idempotency struct {
+	storage  internal.IdempotencyStorage            // Storage the records are persisted in
+	clock    internal.Clock                         // Clock the records expire by
+	ttl      time.Duration                          // Time a record is kept
+	records  map[string]internal.TIdempotencyRecord // Records loaded from the storage. Nil until the first use
+	inFlight map[string]bool                        // Keys of the requests being served
+	mu       sync.Mutex                             // Guards records and inFlight
+}
+
+// NewIdempotency creates a new Idempotency
+// NewIdempotency(storage internal.IdempotencyStorage, clock internal.Clock, ttl time.Duration) -> *Idempotency
+// Args:
+//		storage: Storage the records are persisted in
+//		clock: 	 Clock the records expire by
+//		ttl: 	 Time a record is kept
+// Return:
+//		*Idempotency: New Idempotency instance
+
+func NewIdempotency(storage internal.IdempotencyStorage, clock internal.Clock, ttl time.Duration) *Idempotency {
+	return &Idempotency{
+		storage:  storage,
+		clock:    clock,
+		ttl:      ttl,
+		inFlight: make(map[string]bool),
+	}
+}
+
+// principalOf identifies who sent a request without keeping their credentials
+// principalOf(r *http.Request) -> string
+
+func principalOf(r *http.Request) string {
+	sum := sha256.Sum256([]byte(r.Header.Get("TOKEN")))
+	return hex.EncodeToString(sum[:])
+}
+
+// fingerprintOf hashes the method, path and body of a request. JSON bodies are compacted
+// first, so a retry only differing in whitespace matches
+// fingerprintOf(r *http.Request, body []byte) -> string
+
+func fingerprintOf(r *http.Request, body []byte) string {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, body); err == nil {
+		body = compact.Bytes()
+	}
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s\n", r.Method, r.URL.RequestURI())
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// validKey checks an Idempotency-Key is made of 1 to 255 printable ASCII characters
+// validKey(key string) -> bool
+
+func validKey(key string) bool {
+	if len(key) == 0 || len(key) > 255 {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// begin looks up the record of a key, reserving the key when there is none
+// begin(id string, fingerprint string) -> (*internal.TIdempotencyRecord, error)
+// Args:
+//		id: 		 Key of the record, scoped by principal
+//		fingerprint: Fingerprint of the request
+// Return:
+//		*internal.TIdempotencyRecord: Stored record to replay. Nil if the request must be served
+//		error: 						  Error raised during the execution (if exists)
+
+func (i *Idempotency) begin(id string, fingerprint string) (*internal.TIdempotencyRecord, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	/* Load the records on the first use */
+	if i.records == nil {
+		records, err := i.storage.GetAll()
+		if err != nil {
+			return nil, err
+		}
+		i.records = records
+	}
+
+	if record, ok := i.records[id]; ok && i.clock.Now().Before(record.ExpiresAt) {
+		if record.Fingerprint != fingerprint {
+			return nil, internal.ErrIdempotencyKeyReused
+		}
+		return &record, nil
+	}
+	if i.inFlight[id] {
+		return nil, internal.ErrIdempotencyKeyInFlight
+	}
+	i.inFlight[id] = true
+	return nil, nil
+}
+
+// finish releases a key storing the response given to its request. Server errors are not
+// stored, so the client can retry them
+// finish(id string, record *internal.TIdempotencyRecord)
+// Args:
+//		id: 	Key of the record, scoped by principal
+//		record: Record to store. Nil to only release the key
+
+func (i *Idempotency) finish(id string, record *internal.TIdempotencyRecord) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	delete(i.inFlight, id)
+	if record == nil {
+		return
+	}
+
+	/* Forget the expired records */
+	now := i.clock.Now()
+	for key, value := range i.records {
+		if !now.Before(value.ExpiresAt) {
+			delete(i.records, key)
+		}
+	}
+	i.records[id] = *record
+
+	/* The response is already sent. A failed write only loses the record on restart */
+	i.storage.WriteAll(i.records)
+}
+
+// replay writes a stored response
+// replay(w http.ResponseWriter, record internal.TIdempotencyRecord)
+
+func replay(w http.ResponseWriter, record internal.TIdempotencyRecord) {
+	for name, values := range record.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(record.Status)
+	w.Write(record.Body)
+}
+
+// Middleware serves the POST, PUT, PATCH and DELETE requests sent with an Idempotency-Key
+// once per principal and key, replaying the stored response on the retries. A key reused with
+// a different request is rejected
+// Middleware(handler http.Handler) -> http.Handler
+// Args:
+//		handler: HTTP handler
+// Return:
+//		http.Handler: HTTP handler
+
+func (i *Idempotency) Middleware(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, sent := r.Header[IdempotencyKeyHeader]
+		switch r.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			sent = false
+		}
+		if !sent {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		if len(key) != 1 || !validKey(key[0]) {
+			idempotencyProblems.Write(w, r, fmt.Errorf("%w: expected 1 to 255 printable ASCII characters", internal.ErrIdempotencyKeyInvalid))
+			return
+		}
+
+		/* Read the body, leaving a copy for the handler */
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			idempotencyProblems.Write(w, r, err)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		/* Replay the stored response or reserve the key */
+		principal := principalOf(r)
+		id := internal.IdempotencyRecordKey(principal, key[0])
+		fingerprint := fingerprintOf(r, body)
+		stored, err := i.begin(id, fingerprint)
+		if err != nil {
+			idempotencyProblems.Write(w, r, err)
+			return
+		}
+		if stored != nil {
+			replay(w, *stored)
+			return
+		}
+
+		/* Serve the request keeping a copy of the response */
+		recorder := &recordingWriter{ResponseWriter: w}
+		defer func() {
+			if recorder.status == 0 || recorder.status >= http.StatusInternalServerError {
+				i.finish(id, nil)
+				return
+			}
+			header := w.Header().Clone()
+			header.Del(RequestIDHeader)
+			now := i.clock.Now()
+			i.finish(id, &internal.TIdempotencyRecord{
+				Key:         key[0],
+				Principal:   principal,
+				Fingerprint: fingerprint,
+				Status:      recorder.status,
+				Header:      header,
+				Body:        recorder.body.Bytes(),
+				CreatedAt:   now,
+				ExpiresAt:   now.Add(i.ttl),
+			})
+		}()
+		handler.ServeHTTP(recorder, r)
+	})
+}
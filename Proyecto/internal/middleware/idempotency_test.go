@@ -0,0 +1,146 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"proyecto/internal"
+	"proyecto/internal/clock"
+	"proyecto/internal/handlers"
+	"proyecto/internal/middleware"
+	"proyecto/internal/repository"
+	"proyecto/internal/service"
+	"proyecto/internal/storage"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// initIdempotency creates an Idempotency over an empty storage and a product handler to wrap
+// initIdempotency(fixedClock *clock.ClockFixed) -> (*storage.IdempotencyStorageDefault, *handlers.ProductHandler, *repository.ProductMap)
+
+func initIdempotency(fixedClock *clock.ClockFixed) (*storage.IdempotencyStorageDefault, *handlers.ProductHandler, *repository.ProductMap) {
+	/* Storage creation */
+	recordsPath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/internal/middleware/idempotency_keys_test.json"
+	productsPath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/internal/middleware/products_test.json"
+	records := storage.NewIdempotencyStorageDefault(recordsPath)
+	if err := records.WriteAll(map[string]internal.TIdempotencyRecord{}); err != nil {
+		panic(err)
+	}
+	products := storage.NewProductStorageDefault(productsPath)
+	if err := products.WriteAll(map[int]internal.TProduct{
+		1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: true, Expiration: "11/11/2001", Price: 10.5},
+	}); err != nil {
+		panic(err)
+	}
+
+	repository := repository.NewProductMap(products)
+	service := service.NewProductServiceDefault(repository)
+	service.SetClock(fixedClock)
+	return records, handlers.NewProductHandler(service), repository
+}
+
+// postProduct sends a product creation through a handler
+// postProduct(handler http.Handler, key string, body string) -> *httptest.ResponseRecorder
+
+func postProduct(handler http.Handler, key string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/products/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("TOKEN", "123456")
+	req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	return res
+}
+
+// TestIdempotency_Middleware tests the Idempotency middleware
+func TestIdempotency_Middleware(t *testing.T) {
+	reqBody := `{"name": "new product", "quantity": 1000, "is_published": true, "code_value": "AX04", "expiration": "01/01/2000", "price": 20}`
+
+	// Test 1: should replay the first response on a retry without creating the product twice
+	t.Run("should replay the first response on a retry without creating the product twice", func(t *testing.T) {
+		/* Initialize dependencies */
+		fixedClock := clock.NewClockFixed(time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC))
+		records, handler, repository := initIdempotency(fixedClock)
+		idempotency := middleware.NewIdempotency(records, fixedClock, time.Hour)
+		server := idempotency.Middleware(handler.AddNewProduct())
+
+		/* First attempt and a retry only differing in whitespace */
+		first := postProduct(server, "key-1", reqBody)
+		retry := postProduct(server, "key-1", strings.ReplaceAll(reqBody, ", ", ",\n\t"))
+
+		/* Assertions */
+		require.Equal(t, http.StatusCreated, first.Code)
+		require.Equal(t, http.StatusCreated, retry.Code)
+		require.JSONEq(t, first.Body.String(), retry.Body.String())
+		require.Empty(t, first.Header().Get(middleware.IdempotentReplayedHeader))
+		require.Equal(t, "true", retry.Header().Get(middleware.IdempotentReplayedHeader))
+		require.Equal(t, "application/json", retry.Header().Get("Content-Type"))
+		require.Len(t, repository.GetAllProducts(), 2)
+	})
+
+	// Test 2: should reject a key reused with a different payload
+	t.Run("should reject a key reused with a different payload", func(t *testing.T) {
+		/* Initialize dependencies */
+		fixedClock := clock.NewClockFixed(time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC))
+		records, handler, repository := initIdempotency(fixedClock)
+		server := middleware.NewIdempotency(records, fixedClock, time.Hour).Middleware(handler.AddNewProduct())
+
+		/* Same key, other product */
+		postProduct(server, "key-1", reqBody)
+		res := postProduct(server, "key-1", strings.Replace(reqBody, "AX04", "AX05", 1))
+
+		/* Expected values definition */
+		expectedBody := `{"type": "/problems/idempotency_key_reused", "code": "idempotency_key_reused", "title": "Idempotency key reused",
+			"status": 422, "detail": "idempotency key reused with a different request", "instance": "/products/"}`
+
+		/* Assertions */
+		require.Equal(t, http.StatusUnprocessableEntity, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+		require.Len(t, repository.GetAllProducts(), 2)
+	})
+
+	// Test 3: should keep the records across restarts until they expire
+	t.Run("should keep the records across restarts until they expire", func(t *testing.T) {
+		/* Initialize dependencies */
+		fixedClock := clock.NewClockFixed(time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC))
+		records, handler, repository := initIdempotency(fixedClock)
+		first := postProduct(middleware.NewIdempotency(records, fixedClock, time.Hour).Middleware(handler.AddNewProduct()), "key-1", reqBody)
+
+		/* A new instance reads the records from the storage */
+		restarted := middleware.NewIdempotency(records, fixedClock, time.Hour).Middleware(handler.AddNewProduct())
+		retry := postProduct(restarted, "key-1", reqBody)
+		require.Equal(t, http.StatusCreated, retry.Code)
+		require.JSONEq(t, first.Body.String(), retry.Body.String())
+		require.Equal(t, "true", retry.Header().Get(middleware.IdempotentReplayedHeader))
+
+		/* Past the TTL the request is served again */
+		fixedClock.Advance(time.Hour)
+		expired := postProduct(restarted, "key-1", reqBody)
+		require.Equal(t, http.StatusConflict, expired.Code)
+		require.Empty(t, expired.Header().Get(middleware.IdempotentReplayedHeader))
+		require.Len(t, repository.GetAllProducts(), 2)
+	})
+
+	// Test 4: should scope the keys by principal
+	t.Run("should scope the keys by principal", func(t *testing.T) {
+		/* Initialize dependencies */
+		fixedClock := clock.NewClockFixed(time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC))
+		records, handler, _ := initIdempotency(fixedClock)
+		server := middleware.NewIdempotency(records, fixedClock, time.Hour).Middleware(handler.AddNewProduct())
+		postProduct(server, "key-1", reqBody)
+
+		/* Another principal sending the same key gets its own response */
+		req := httptest.NewRequest("POST", "/products/", strings.NewReader(strings.Replace(reqBody, "AX04", "AX05", 1)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("TOKEN", "654321")
+		req.Header.Set(middleware.IdempotencyKeyHeader, "key-1")
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusCreated, res.Code)
+		require.Empty(t, res.Header().Get(middleware.IdempotentReplayedHeader))
+	})
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"proyecto/platform/web/request"
+)
+
+// RequestIDHeader is the header the request ID is read from and echoed in
+const RequestIDHeader = "X-Request-ID"
+
+// newRequestID generates a random request ID
+// newRequestID() -> string
+
+func newRequestID() string {
+	bytes := make([]byte, 8)
+	rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
+
+// MiddlewareRequestID is a middleware that gives every request an ID, keeping the one sent
+// by the client (if any), and echoes it in the response
+// MiddlewareRequestID(http.Handler) -> http.Handler
+// Args:
+//		handler: HTTP handler
+// Return:
+//		http.Handler: HTTP handler
+
+func MiddlewareRequestID(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if id == "" || len(id) > 128 {
+			id = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		handler.ServeHTTP(w, request.WithID(r, id))
+	})
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"proyecto/platform/web/request"
+	"proyecto/platform/web/response"
+	"strings"
+)
+
+// ErrVersionNotAcceptable is raised when a request asks for an API version which isn't served
+var ErrVersionNotAcceptable = errors.New("api version not acceptable")
+
+// versionProblems maps the errors of the version negotiation to their problem types
+var versionProblems = response.NewProblems().
+	Register(ErrVersionNotAcceptable, http.StatusNotAcceptable, "version_not_acceptable", "API version not acceptable")
+
+// Versioning picks the API version a request is served with, stored in the request context
+// (see request.Version). The path prefix pins it; otherwise it is negotiated with the version
+// parameter of the Accept header, or the one of the Content-Type header of the body, falling
+// back to the default version
+type Versioning struct {
+	versions []string // Versions served
+	fallback string   // Version of the requests asking for none
+}
+
+// NewVersioning creates a new Versioning
+// NewVersioning(fallback string, versions ...string) -> *Versioning
+// Args:
+//		fallback: Version of the requests asking for none. Kept as the oldest one so the existing clients don't break
+//		versions: Versions served
+// Return:
+//		*Versioning: New Versioning
+
+func NewVersioning(fallback string, versions ...string) *Versioning {
+	return &Versioning{versions: versions, fallback: fallback}
+}
+
+// Negotiate is a middleware that serves a request with the version asked for in its Accept
+// header, the version its body is written in, or the default one. A version not served is
+// answered with a 406 problem
+// Negotiate(handler http.Handler) -> http.Handler
+// Args:
+//		handler: HTTP handler
+// Return:
+//		http.Handler: HTTP handler
+
+func (v *Versioning) Negotiate(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+		version := request.AcceptVersion(r)
+		if version == "" {
+			version = request.ContentVersion(r)
+		}
+		if version == "" {
+			version = v.fallback
+		}
+		for _, served := range v.versions {
+			if served == version {
+				handler.ServeHTTP(w, request.WithVersion(r, version))
+				return
+			}
+		}
+		versionProblems.Write(w, r, fmt.Errorf("%w: %s=%q, available: %s", ErrVersionNotAcceptable, request.VersionParam, version, strings.Join(v.versions, ", ")))
+	})
+}
+
+// Pin returns a middleware serving every request with a version, as the routes mounted under
+// a version prefix do. The version asked for in the Accept header is ignored: the path wins
+// Pin(version string) -> func(http.Handler) http.Handler
+// Args:
+//		version: Version the requests are served with
+// Return:
+//		func(http.Handler) http.Handler: Middleware
+
+func (v *Versioning) Pin(version string) func(http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handler.ServeHTTP(w, request.WithVersion(r, version))
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"proyecto/internal/middleware"
+	"proyecto/platform/web/request"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// serveVersion sends a request through a middleware to a handler writing the version picked
+// serveVersion(middleware func(http.Handler) http.Handler, accept string) -> *httptest.ResponseRecorder
+
+func serveVersion(mw func(http.Handler) http.Handler, accept string) *httptest.ResponseRecorder {
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(request.Version(r)))
+	}))
+	req := httptest.NewRequest("GET", "/products/", nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	return res
+}
+
+// TestVersioning tests the Versioning middlewares
+func TestVersioning(t *testing.T) {
+	versions := middleware.NewVersioning("1", "1", "2")
+
+	// Test 1: should fall back to the default version
+	t.Run("should fall back to the default version", func(t *testing.T) {
+		res := serveVersion(versions.Negotiate, "application/json")
+
+		/* Assertions */
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Equal(t, "1", res.Body.String())
+		require.Equal(t, "Accept", res.Header().Get("Vary"))
+	})
+
+	// Test 2: should serve the version asked for in the Accept header
+	t.Run("should serve the version asked for in the Accept header", func(t *testing.T) {
+		res := serveVersion(versions.Negotiate, "text/csv;q=0.5, application/json; version=2")
+
+		/* Assertions */
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Equal(t, "2", res.Body.String())
+	})
+
+	// Test 3: should fail if the version asked for is not served
+	t.Run("should fail if the version asked for is not served", func(t *testing.T) {
+		res := serveVersion(versions.Negotiate, "application/json; version=3")
+
+		/* Assertions */
+		require.Equal(t, http.StatusNotAcceptable, res.Code)
+		require.Equal(t, "application/problem+json", res.Header().Get("Content-Type"))
+		require.Contains(t, res.Body.String(), `"code":"version_not_acceptable"`)
+	})
+
+	// Test 4: should serve the version of the body when the Accept header asks for none
+	t.Run("should serve the version of the body when the Accept header asks for none", func(t *testing.T) {
+		handler := versions.Negotiate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(request.Version(r)))
+		}))
+		req := httptest.NewRequest("POST", "/products/", nil)
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("Content-Type", "application/json; version=2")
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+
+		/* Assertions */
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Equal(t, "2", res.Body.String())
+	})
+
+	// Test 5: should serve the pinned version whatever the Accept header asks for
+	t.Run("should serve the pinned version whatever the Accept header asks for", func(t *testing.T) {
+		res := serveVersion(versions.Pin("1"), "application/json; version=3")
+
+		/* Assertions */
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Equal(t, "1", res.Body.String())
+	})
+}
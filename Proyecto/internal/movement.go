@@ -0,0 +1,44 @@
+package internal
+
+import "time"
+
+/* Movement types */
+const (
+	MovementReceipt    = "receipt"    // Stock received from a supplier.
+	MovementSale       = "sale"       // Stock sold to a customer.
+	MovementWriteOff   = "write_off"  // Stock discarded (damaged, expired, lost).
+	MovementCorrection = "correction" // Manual adjustment after a count.
+	MovementTransfer   = "transfer"   // Stock moved between warehouses. Recorded as an outbound and inbound pair.
+)
+
+// TMovement represents a stock movement of a product on the ledger.
+type TMovement struct {
+	ID          int       `json:"id"`
+	ProductID   int       `json:"product_id"`
+	WarehouseID int       `json:"warehouse_id"` // Warehouse whose stock is affected. Zero means the default warehouse.
+	Type        string    `json:"type"`
+	Quantity    int       `json:"quantity"` // Signed quantity delta applied to the stock.
+	Reason      string    `json:"reason"`
+	Reference   string    `json:"reference"`
+	LotID       int       `json:"lot_id,omitempty"`     // Lot an outbound movement draws from, or the lot a receipt creates.
+	Expiration  string    `json:"expiration,omitempty"` // Expiration of the lot a receipt creates with the format dd/mm/yyyy.
+	Date        time.Time `json:"date"`
+}
+
+// TReconciliation represents the difference between the ledger and the stored quantity of a product.
+type TReconciliation struct {
+	ProductID      int `json:"product_id"`
+	StoredQuantity int `json:"stored_quantity"`
+	LedgerQuantity int `json:"ledger_quantity"`
+	Drift          int `json:"drift"`
+}
+
+// TTransfer represents a request to move stock of a product between two warehouses.
+type TTransfer struct {
+	ProductID       int    `json:"product_id"`
+	FromWarehouseID int    `json:"from_warehouse_id"`
+	ToWarehouseID   int    `json:"to_warehouse_id"`
+	Quantity        int    `json:"quantity"`
+	Reason          string `json:"reason"`
+	Reference       string `json:"reference"`
+}
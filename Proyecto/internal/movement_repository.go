@@ -0,0 +1,11 @@
+package internal
+
+import "time"
+
+/* Movement repository definition */
+type MovementRepository interface {
+	GetAllMovements() ([]TMovement, error)                                        // Return all the movements in the repository.
+	GetMovementsByProduct(productID int, from, to time.Time) ([]TMovement, error) // Return the movements of a product between two dates.
+	InsertNewMovements(movements []TMovement) error                               // Add new movements into the repository.
+	DeleteMovementsByProduct(productID int) error                                 // Delete the movements of a product.
+}
@@ -0,0 +1,28 @@
+package internal
+
+import (
+	"errors"
+	"time"
+)
+
+/* Errors definition */
+var (
+	ErrInvalidMovementType     = errors.New("invalid movement type")
+	ErrInvalidMovementQuantity = errors.New("invalid movement quantity")
+	ErrInsufficientStock       = errors.New("insufficient stock")
+	ErrInvalidTransfer         = errors.New("invalid transfer")
+	ErrLotNotExists            = errors.New("lot not exists")
+)
+
+/* Movement service definition */
+type MovementService interface {
+	PostMovement(movement *TMovement) error                                       // Record a new movement and update the product quantity.
+	PostMovements(movements []TMovement) error                                    // Record several movements at once, all or none of them.
+	GetMovementsByProduct(productID int, from, to time.Time) ([]TMovement, error) // Return the movements of a product between two dates.
+	Reconcile() ([]TReconciliation, error)                                        // Return the products whose stored quantity drifted from the ledger.
+	TransferStock(transfer TTransfer) ([]TMovement, error)                        // Move stock of a product between two warehouses.
+	GetStockLevels(productID int) ([]TStockLevel, error)                          // Return the stock of a product by warehouse.
+	GetWarehouseStock(warehouseID int) ([]TStockLevel, error)                     // Return the stock of every product in a warehouse.
+	GetLotsByProduct(productID int) ([]TLot, error)                               // Return the lots of a product still in stock.
+	WriteOffExpiredLots() ([]TMovement, error)                                    // Write off the stock left in the expired lots.
+}
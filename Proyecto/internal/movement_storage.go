@@ -0,0 +1,7 @@
+package internal
+
+/* Movement storage definition */
+type MovementStorage interface {
+	GetAll() (map[int]TMovement, error) // Get all movements from storage
+	WriteAll(map[int]TMovement) error   // Write all movements to storage
+}
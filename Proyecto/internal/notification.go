@@ -0,0 +1,21 @@
+package internal
+
+import "time"
+
+/* Notification types */
+const (
+	NotificationReorder = "reorder" // A product crossed below its reorder point.
+)
+
+// TNotification represents an event the operators are notified about.
+type TNotification struct {
+	Type      string    `json:"type"`
+	ProductID int       `json:"product_id"`
+	Message   string    `json:"message"`
+	Date      time.Time `json:"date"`
+}
+
+/* Notifier definition */
+type Notifier interface {
+	Notify(notification TNotification) error // Deliver a notification.
+}
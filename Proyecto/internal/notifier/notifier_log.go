@@ -0,0 +1,42 @@
+package notifier
+
+import (
+	"fmt"
+	"io"
+	"proyecto/internal"
+	"strings"
+	"sync"
+)
+
+// NotifierLog delivers the notifications as lines of a log
+type NotifierLog struct {
+	output io.Writer  // Where the notifications are written
+	mu     sync.Mutex // Serializes the writes
+}
+
+// NewNotifierLog creates a new NotifierLog
+// NewNotifierLog(output io.Writer) -> *NotifierLog
+// Args:
+//		output: Writer where the notifications are written
+// Return:
+//		*NotifierLog: New NotifierLog
+
+func NewNotifierLog(output io.Writer) *NotifierLog {
+	return &NotifierLog{output: output}
+}
+
+// Notify writes a notification as a log line tagged with its type
+// Notify(notification internal.TNotification) -> error
+// Args:
+//		notification: Notification to deliver
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (n *NotifierLog) Notify(notification internal.TNotification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	_, err := fmt.Fprintf(n.output, "[%s] %s %s\n", notification.Date.Format("2006-01-02 15:04:05"),
+		strings.ToUpper(notification.Type), notification.Message)
+	return err
+}
@@ -0,0 +1,30 @@
+package internal
+
+import "time"
+
+/* Order statuses */
+const (
+	OrderDraft     = "draft"     // Being edited, no stock is reserved.
+	OrderPlaced    = "placed"    // Checked against the catalog and waiting for confirmation.
+	OrderConfirmed = "confirmed" // Stock taken from the products.
+	OrderShipped   = "shipped"   // Delivered to the carrier. Final.
+	OrderCancelled = "cancelled" // Cancelled, the taken stock is restored. Final.
+)
+
+// TOrderItem represents a line of an order.
+type TOrderItem struct {
+	ProductID int     `json:"product_id"`
+	Quantity  int     `json:"quantity"`
+	UnitPrice float64 `json:"unit_price"` // Price of the product when the order was placed.
+}
+
+// TOrder represents an order taken against the catalog.
+type TOrder struct {
+	ID        int          `json:"id"`
+	Customer  string       `json:"customer"`
+	Status    string       `json:"status"`
+	Items     []TOrderItem `json:"items"`
+	Total     float64      `json:"total"` // Sum of the lines once the order is placed.
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
@@ -0,0 +1,16 @@
+package internal
+
+import "errors"
+
+/* Errors definition */
+var (
+	ErrOrderNotFound = errors.New("order not found")
+)
+
+/* Order repository definition */
+type OrderRepository interface {
+	GetAllOrders() ([]TOrder, error)     // Return all the orders in the repository.
+	GetOrderByID(id int) (TOrder, error) // Return an order by its id.
+	InsertNewOrder(order *TOrder) error  // Add a new order into the repository.
+	UpdateOrder(order *TOrder) error     // Update an order from the repository if it exists.
+}
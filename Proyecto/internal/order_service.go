@@ -0,0 +1,23 @@
+package internal
+
+import "errors"
+
+/* Errors definition */
+var (
+	ErrOrderNotExists         = errors.New("order not exists")
+	ErrInvalidOrderItems      = errors.New("invalid order items")
+	ErrInvalidOrderTransition = errors.New("invalid order status transition")
+	ErrProductNotAvailable    = errors.New("product not available")
+)
+
+/* Order service definition */
+type OrderService interface {
+	GetAllOrders() ([]TOrder, error)     // Return all the orders.
+	GetOrderByID(id int) (TOrder, error) // Return an order by its id.
+	InsertNewOrder(order *TOrder) error  // Add a new draft order.
+	UpdateOrder(order *TOrder) error     // Update the customer and the items of a draft order.
+	PlaceOrder(id int) (TOrder, error)   // Check a draft order against the catalog and place it.
+	ConfirmOrder(id int) (TOrder, error) // Take the stock of a placed order.
+	ShipOrder(id int) (TOrder, error)    // Mark a confirmed order as shipped.
+	CancelOrder(id int) (TOrder, error)  // Cancel an order restoring the stock it took.
+}
@@ -0,0 +1,7 @@
+package internal
+
+/* Order storage definition */
+type OrderStorage interface {
+	GetAll() (map[int]TOrder, error) // Get all orders from storage
+	WriteAll(map[int]TOrder) error   // Write all orders to storage
+}
@@ -0,0 +1,20 @@
+package internal
+
+import "time"
+
+// TPriceChange represents a list price a product had from a given moment.
+type TPriceChange struct {
+	ID        int       `json:"id"`
+	ProductID int       `json:"product_id"`
+	Price     float64   `json:"price"`
+	Date      time.Time `json:"date"` // Moment the price came into effect.
+}
+
+// TPricePoint represents a segment of the price timeline of a product.
+type TPricePoint struct {
+	From           time.Time  `json:"from"`
+	To             *time.Time `json:"to,omitempty"` // Nil for the segment in effect until further notice.
+	ListPrice      float64    `json:"list_price"`
+	EffectivePrice float64    `json:"effective_price"`
+	PromotionID    *int       `json:"promotion_id,omitempty"` // Promotion applied on the segment (if any).
+}
@@ -0,0 +1,8 @@
+package internal
+
+/* Price repository definition */
+type PriceRepository interface {
+	GetPriceHistory(productID int) ([]TPriceChange, error) // Return the price changes of a product ordered by date.
+	InsertNewPriceChange(change *TPriceChange) error       // Add a new price change into the repository.
+	DeletePriceHistory(productID int) error                // Delete the price changes of a product.
+}
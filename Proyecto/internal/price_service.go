@@ -0,0 +1,23 @@
+package internal
+
+import (
+	"errors"
+	"time"
+)
+
+/* Errors definition */
+var (
+	ErrInvalidPromotion   = errors.New("invalid promotion")
+	ErrPromotionNotExists = errors.New("promotion not exists")
+)
+
+/* Price service definition */
+type PriceService interface {
+	RecordPrice(productID int, price float64) error                                // Record a new list price on the history of a product.
+	GetPriceTimeline(productID int) ([]TPricePoint, error)                         // Return the list and effective prices of a product over time.
+	GetEffectivePrices(products []TProduct, at time.Time) (map[int]float64, error) // Return the effective price of the products at a moment.
+	GetPromotionsByProduct(productID int) ([]TPromotion, error)                    // Return the promotions of a product.
+	InsertNewPromotion(promotion *TPromotion) error                                // Add a new promotion.
+	UpdatePromotion(promotion *TPromotion) error                                   // Update a promotion if it exists.
+	DeletePromotion(id int) error                                                  // Delete a promotion.
+}
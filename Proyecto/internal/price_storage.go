@@ -0,0 +1,7 @@
+package internal
+
+/* Price storage definition */
+type PriceStorage interface {
+	GetAll() (map[int]TPriceChange, error) // Get all price changes from storage
+	WriteAll(map[int]TPriceChange) error   // Write all price changes to storage
+}
@@ -1,12 +1,39 @@
 package internal
 
+import "time"
+
 // TProduct representens a product on the website.
 type TProduct struct {
-	ID          int     `json:"id"`
-	Name        string  `json:"name"`
-	Quantity    int     `json:"quantity"`
-	CodeValue   string  `json:"code_value"`
-	IsPublished bool    `json:"is_published"`
-	Expiration  string  `json:"expiration"`
-	Price       float64 `json:"price"`
+	ID              int               `json:"id"`
+	Name            string            `json:"name"`
+	Quantity        int               `json:"quantity"`
+	CodeValue       string            `json:"code_value"`
+	IsPublished     bool              `json:"is_published"`
+	Expiration      string            `json:"expiration"`
+	Price           float64           `json:"price"`
+	PublishAt       *time.Time        `json:"publish_at,omitempty"`       // Moment the product goes live. (Optional)
+	UnpublishAt     *time.Time        `json:"unpublish_at,omitempty"`     // Moment the product is withdrawn. (Optional)
+	Tags            []string          `json:"tags,omitempty"`             // Free-form lowercase labels. (Optional)
+	ParentID        *int              `json:"parent_id,omitempty"`        // Parent product of a variant. Nil for the top-level products.
+	Attributes      map[string]string `json:"attributes,omitempty"`       // Descriptive attributes (size, flavor, pack...). (Optional)
+	ReorderPoint    *int              `json:"reorder_point,omitempty"`    // Quantity at or below which the product must be restocked. (Optional)
+	ReorderQuantity int               `json:"reorder_quantity,omitempty"` // Quantity usually purchased when restocking. (Optional)
+	TaxClass        string            `json:"tax_class,omitempty"`        // Tax class the rates are looked up by. Empty means the standard one. (Optional)
+	EffectivePrice  *float64          `json:"effective_price,omitempty"`  // Price after the active promotions. Computed at read time, never stored.
+}
+
+// TTagCount represents how many products use a tag.
+type TTagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// TReorderSuggestion represents a product at or below its reorder point and the quantity to purchase.
+type TReorderSuggestion struct {
+	ProductID         int    `json:"product_id"`
+	Name              string `json:"name"`
+	CodeValue         string `json:"code_value"`
+	Quantity          int    `json:"quantity"`
+	ReorderPoint      int    `json:"reorder_point"`
+	SuggestedQuantity int    `json:"suggested_quantity"`
 }
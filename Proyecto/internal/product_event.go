@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"errors"
+	"time"
+)
+
+/* Product event types */
+const (
+	ProductEventInserted    = "product.inserted"    // A product or variant was created.
+	ProductEventUpdated     = "product.updated"     // A product changed without changing its publish state.
+	ProductEventDeleted     = "product.deleted"     // A product or variant was deleted.
+	ProductEventPublished   = "product.published"   // A product became published.
+	ProductEventUnpublished = "product.unpublished" // A product stopped being published.
+)
+
+/* Product attributes whose changes are told apart on the update events */
+const (
+	ProductChangePrice    = "price"    // The base price or the promotions changed.
+	ProductChangeQuantity = "quantity" // The stock changed.
+)
+
+// ProductEventTypes lists the types of the product events
+var ProductEventTypes = []string{
+	ProductEventInserted, ProductEventUpdated, ProductEventDeleted, ProductEventPublished, ProductEventUnpublished,
+}
+
+// TProductEvent represents a change of the catalog. Every stored change of a product emits one event.
+type TProductEvent struct {
+	ID        int64     `json:"id"`                // Sequential id, the SSE event id.
+	Type      string    `json:"type"`              // One of ProductEventTypes.
+	ProductID int       `json:"product_id"`        // Product changed.
+	Product   *TProduct `json:"product,omitempty"` // Product after the change. Nil on deletions.
+	Changes   []string  `json:"changes,omitempty"` // ProductChange attributes changed by an update.
+	Date      time.Time `json:"date"`
+}
+
+/* Product event errors */
+var (
+	ErrProductEventsEvicted = errors.New("product events no longer retained")
+)
+
+/* Product event storage definition */
+type ProductEventStorage interface {
+	GetAll() ([]TProductEvent, error)      // Get the retained events from storage, oldest first
+	WriteAll(events []TProductEvent) error // Write the retained events to storage
+}
+
+/* Product event bus definition */
+type ProductEventBus interface {
+	Publish(event *TProductEvent) error                      // Number, date, retain and deliver an event to the subscribers.
+	Since(lastID int64) ([]TProductEvent, error)             // Return the retained events after an id. ErrProductEventsEvicted if some were dropped.
+	Subscribe() (events <-chan TProductEvent, cancel func()) // Receive the events published from now on. Closed when the subscriber lags or the bus closes.
+}
@@ -13,10 +13,12 @@ var (
 
 /* Product repository definition */
 type ProductRepository interface {
-	GetAllProducts() []TProduct                   // Return all the products in the repository.
-	GetProductByID(id int) (TProduct, error)      // Return a product by its id.
-	GetProductByPriceGt(price float64) []TProduct // Return a slice of products with a price greater than the given price.
-	InsertNewProduct(product *TProduct) error     // Add a new product into the repository.
-	UpdateProduct(product *TProduct) error        // Update a product from the repository if it exists.
-	DeleteProduct(id int) error                   // Delete a product from the repository.
+	GetAllProducts() []TProduct                                         // Return all the products in the repository.
+	GetProductByID(id int) (TProduct, error)                            // Return a product by its id.
+	GetProductByPriceGt(price float64) []TProduct                       // Return a slice of products with a price greater than the given price.
+	InsertNewProduct(product *TProduct) error                           // Add a new product into the repository.
+	UpdateProduct(product *TProduct) error                              // Update a product from the repository if it exists.
+	DeleteProduct(id int) error                                         // Delete a product from the repository.
+	GetProductsByTags(tags []string, matchAll bool) ([]TProduct, error) // Return the products with all (or any) of the tags.
+	GetTagCounts() (map[string]int, error)                              // Return how many products use each tag.
 }
@@ -1,6 +1,9 @@
 package internal
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 /* Errors definition */
 var (
@@ -8,14 +11,37 @@ var (
 	ErrInvalidDate          = errors.New("invalid date")
 	ErrProductAlreadyExists = errors.New("product already exists")
 	ErrProductNotExists     = errors.New("product not exists")
+	ErrInvalidPublishWindow = errors.New("invalid publish window")
+	ErrInvalidVariantParent = errors.New("invalid variant parent")
+	ErrProductHasVariants   = errors.New("product has variants")
+	ErrInvalidReorderLevels = errors.New("invalid reorder levels")
 )
 
 /* Product service definition */
 type ProductService interface {
-	GetAllProducts() []TProduct                   // Return all the products.
-	GetProductByID(id int) (TProduct, error)      // Return a product by its id.
-	GetProductByPriceGt(price float64) []TProduct // Return a slice of products with a price greater than the given price.
-	InsertNewProduct(product *TProduct) error     // Add a new product into the repository.
-	UpdateProduct(product *TProduct) error        // Update a product from the repository if it exists.
-	DeleteProduct(id int) error                   // Delete a product from the repository.
+	GetAllProducts() []TProduct                                            // Return all the products.
+	GetProductByID(id int) (TProduct, error)                               // Return a product by its id.
+	GetStoredProductByID(id int) (TProduct, error)                         // Return a product by its id as stored, without resolving it.
+	GetProductByPriceGt(price float64) []TProduct                          // Return a slice of products with a price greater than the given price.
+	InsertNewProduct(product *TProduct) error                              // Add a new product into the repository.
+	UpdateProduct(product *TProduct) error                                 // Update a product from the repository if it exists.
+	DeleteProduct(id int) error                                            // Delete a product from the repository.
+	GetExpiredProducts(at time.Time) []TProduct                            // Return the published products expired before the given time.
+	GetExpiringProducts(days int) []TProduct                               // Return the products expiring within the next days.
+	UnpublishProduct(id int) error                                         // Unpublish a product if it exists.
+	ApplyPublicationWindows() ([]TProduct, error)                          // Flip the publish state of the products whose window is due.
+	NextPublicationChange() (time.Time, bool)                              // Return the next moment a publish window is due (if any).
+	SearchProductsByTags(tags []string, matchAll bool) ([]TProduct, error) // Return the products with all (or any) of the tags.
+	GetTagCounts() ([]TTagCount, error)                                    // Return the tags ordered by usage.
+	GetVariants(parentID int) ([]TProduct, error)                          // Return the variants of a product.
+	InsertNewVariant(parentID int, variant *TProduct) error                // Add a new variant under a product.
+	UpdateVariant(parentID int, variant *TProduct) error                   // Update a variant of a product if it exists.
+	DeleteVariant(parentID, variantID int) error                           // Delete a variant of a product.
+	GetReorderReport() []TReorderSuggestion                                // Return the products at or below their reorder point.
+}
+
+// ProductDependent is a service keeping records of the products by their id. The records of a
+// deleted product are removed so a new product reusing its id doesn't inherit them
+type ProductDependent interface {
+	RemoveProduct(productID int) error // Remove the records of a deleted product.
 }
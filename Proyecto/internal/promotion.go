@@ -0,0 +1,21 @@
+package internal
+
+import "time"
+
+/* Promotion types */
+const (
+	PromotionPercentage = "percentage" // Discount of a percentage of the list price.
+	PromotionFixed      = "fixed"      // Discount of a fixed amount of the list price.
+)
+
+// TPromotion represents a time-bound discount over the list price of a product.
+// The promotions of a parent product also apply to its variants.
+type TPromotion struct {
+	ID          int       `json:"id"`
+	ProductID   int       `json:"product_id"`
+	Type        string    `json:"type"`
+	Value       float64   `json:"value"` // Percentage (0-100] or amount of the discount.
+	Description string    `json:"description"`
+	StartAt     time.Time `json:"start_at"` // Moment the promotion starts (inclusive).
+	EndAt       time.Time `json:"end_at"`   // Moment the promotion ends (exclusive).
+}
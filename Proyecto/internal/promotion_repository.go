@@ -0,0 +1,17 @@
+package internal
+
+import "errors"
+
+/* Errors definition */
+var (
+	ErrPromotionNotFound = errors.New("promotion not found")
+)
+
+/* Promotion repository definition */
+type PromotionRepository interface {
+	GetAllPromotions() ([]TPromotion, error)        // Return all the promotions in the repository.
+	GetPromotionByID(id int) (TPromotion, error)    // Return a promotion by its id.
+	InsertNewPromotion(promotion *TPromotion) error // Add a new promotion into the repository.
+	UpdatePromotion(promotion *TPromotion) error    // Update a promotion from the repository if it exists.
+	DeletePromotion(id int) error                   // Delete a promotion from the repository.
+}
@@ -0,0 +1,7 @@
+package internal
+
+/* Promotion storage definition */
+type PromotionStorage interface {
+	GetAll() (map[int]TPromotion, error) // Get all promotions from storage
+	WriteAll(map[int]TPromotion) error   // Write all promotions to storage
+}
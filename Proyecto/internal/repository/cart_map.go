@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"proyecto/internal"
+	"sync"
+)
+
+type CartMap struct {
+	storage internal.CartStorage // Storage
+	mu      sync.RWMutex         // Guards the storage against concurrent writers
+}
+
+// NewCartMap creates a new CartMap
+// NewCartMap(storage internal.CartStorage) -> *CartMap
+// Args:
+//		storage: Cart storage
+// Return:
+//		*CartMap: New CartMap
+
+func NewCartMap(storage internal.CartStorage) *CartMap {
+	return &CartMap{storage: storage}
+}
+
+// GetCartByID returns a cart by its id
+// GetCartByID(id int) -> (internal.TCart, error)
+// Args:
+//		id: Cart id
+// Return:
+//		internal.TCart: Cart found in the database
+//		error: 			 Error raised during the execution (if exists)
+
+func (c *CartMap) GetCartByID(id int) (internal.TCart, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	/* Get the data from the storage */
+	db, err := c.storage.GetAll()
+	if err != nil {
+		return internal.TCart{}, internal.ErrStorageError
+	}
+
+	/* Check if the cart exists */
+	cart, ok := db[id]
+	if !ok {
+		return internal.TCart{}, internal.ErrCartNotFound
+	}
+	return cart, nil
+}
+
+// InsertNewCart inserts a new cart in the database
+// InsertNewCart(cart *internal.TCart) -> error
+// Args:
+//		cart: Cart to insert. Its id is updated in place
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (c *CartMap) InsertNewCart(cart *internal.TCart) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	/* Get the data from the storage */
+	db, err := c.storage.GetAll()
+	if err != nil {
+		return internal.ErrStorageError
+	}
+
+	/* Insert the new cart */
+	var lastID int
+	for key := range db {
+		if key > lastID {
+			lastID = key
+		}
+	}
+	cart.ID = lastID + 1
+	db[cart.ID] = *cart
+
+	/* Save the changes in the storage */
+	if err = c.storage.WriteAll(db); err != nil {
+		return internal.ErrStorageError
+	}
+	return nil
+}
+
+// UpdateCart updates a cart in the database
+// UpdateCart(cart *internal.TCart) -> error
+// Args:
+//		cart: Cart to update
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (c *CartMap) UpdateCart(cart *internal.TCart) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	/* Get the data from the storage */
+	db, err := c.storage.GetAll()
+	if err != nil {
+		return internal.ErrStorageError
+	}
+
+	/* Check if the cart exists */
+	if _, ok := db[cart.ID]; !ok {
+		return internal.ErrCartNotFound
+	}
+
+	/* Update the cart */
+	db[cart.ID] = *cart
+	if err = c.storage.WriteAll(db); err != nil {
+		return internal.ErrStorageError
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"proyecto/internal"
+	"sort"
+	"sync"
+)
+
+type CategoryMap struct {
+	storage internal.CategoryStorage // Storage
+	mu      sync.RWMutex             // Guards the storage against concurrent writers
+}
+
+// NewCategoryMap creates a new CategoryMap
+// NewCategoryMap(storage internal.CategoryStorage) -> *CategoryMap
+// Args:
+//		storage: Category storage
+// Return:
+//		*CategoryMap: New CategoryMap
+
+func NewCategoryMap(storage internal.CategoryStorage) *CategoryMap {
+	return &CategoryMap{storage: storage}
+}
+
+// GetAllCategories returns all the categories ordered by id
+// GetAllCategories() -> ([]internal.TCategory, error)
+// Return:
+//		[]internal.TCategory: Categories of the taxonomy
+//		error: 				  Error raised during the execution (if exists)
+
+func (c *CategoryMap) GetAllCategories() ([]internal.TCategory, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	/* Get the data from the storage */
+	db, err := c.storage.GetAll()
+	if err != nil {
+		return nil, internal.ErrStorageError
+	}
+
+	/* Convert the map to a slice */
+	categories := make([]internal.TCategory, 0, len(db))
+	for _, category := range db {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].ID < categories[j].ID
+	})
+	return categories, nil
+}
+
+// GetCategoryByID returns a category by its id
+// GetCategoryByID(id int) -> (internal.TCategory, error)
+// Args:
+//		id: Category id
+// Return:
+//		internal.TCategory: Category found in the database
+//		error: 				Error raised during the execution (if exists)
+
+func (c *CategoryMap) GetCategoryByID(id int) (internal.TCategory, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	/* Get the data from the storage */
+	db, err := c.storage.GetAll()
+	if err != nil {
+		return internal.TCategory{}, internal.ErrStorageError
+	}
+
+	/* Check if the category exists */
+	category, ok := db[id]
+	if !ok {
+		return internal.TCategory{}, internal.ErrCategoryNotFound
+	}
+	return category, nil
+}
+
+// InsertNewCategory inserts a new category in the database
+// InsertNewCategory(category *internal.TCategory) -> error
+// Args:
+//		category: Category to insert. Its id is updated in place
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (c *CategoryMap) InsertNewCategory(category *internal.TCategory) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	/* Get the data from the storage */
+	db, err := c.storage.GetAll()
+	if err != nil {
+		return internal.ErrStorageError
+	}
+
+	/* Insert the new category */
+	var lastID int
+	for key := range db {
+		if key > lastID {
+			lastID = key
+		}
+	}
+	category.ID = lastID + 1
+	db[category.ID] = *category
+
+	/* Save the changes in the storage */
+	if err = c.storage.WriteAll(db); err != nil {
+		return internal.ErrStorageError
+	}
+	return nil
+}
+
+// UpdateCategories updates a group of categories in a single write
+// UpdateCategories(categories []internal.TCategory) -> error
+// Args:
+//		categories: Categories to update. All of them must exist
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (c *CategoryMap) UpdateCategories(categories []internal.TCategory) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	/* Get the data from the storage */
+	db, err := c.storage.GetAll()
+	if err != nil {
+		return internal.ErrStorageError
+	}
+
+	/* Check all the categories exist before updating them */
+	for _, category := range categories {
+		if _, ok := db[category.ID]; !ok {
+			return internal.ErrCategoryNotFound
+		}
+	}
+	for _, category := range categories {
+		db[category.ID] = category
+	}
+
+	/* Save the changes in the storage */
+	if err = c.storage.WriteAll(db); err != nil {
+		return internal.ErrStorageError
+	}
+	return nil
+}
+
+// DeleteCategory deletes a category from the database
+// DeleteCategory(id int) -> error
+// Args:
+//		id: Category id
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (c *CategoryMap) DeleteCategory(id int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	/* Get the data from the storage */
+	db, err := c.storage.GetAll()
+	if err != nil {
+		return internal.ErrStorageError
+	}
+
+	/* Check if the category exists */
+	if _, ok := db[id]; !ok {
+		return internal.ErrCategoryNotFound
+	}
+
+	/* Delete the category */
+	delete(db, id)
+	if err = c.storage.WriteAll(db); err != nil {
+		return internal.ErrStorageError
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"proyecto/internal"
+	"sort"
+	"sync"
+)
+
+type LotMap struct {
+	storage internal.LotStorage // Storage
+	mu      sync.RWMutex        // Guards the storage against concurrent writers
+}
+
+// NewLotMap creates a new LotMap
+// NewLotMap(storage internal.LotStorage) -> *LotMap
+// Args:
+//		storage: Lot storage
+// Return:
+//		*LotMap: New LotMap
+
+func NewLotMap(storage internal.LotStorage) *LotMap {
+	return &LotMap{storage: storage}
+}
+
+// filterLots returns the lots matching a condition ordered by id
+// filterLots(match func(internal.TLot) bool) -> ([]internal.TLot, error)
+// Args:
+//		match: Condition the lots must meet
+// Return:
+//		[]internal.TLot: Lots found in the database
+//		error: 			 Error raised during the execution (if exists)
+
+func (l *LotMap) filterLots(match func(internal.TLot) bool) ([]internal.TLot, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	/* Get the data from the storage */
+	db, err := l.storage.GetAll()
+	if err != nil {
+		return nil, internal.ErrStorageError
+	}
+
+	/* Convert the map to a slice */
+	lots := make([]internal.TLot, 0)
+	for _, lot := range db {
+		if match(lot) {
+			lots = append(lots, lot)
+		}
+	}
+	sort.Slice(lots, func(i, j int) bool {
+		return lots[i].ID < lots[j].ID
+	})
+	return lots, nil
+}
+
+// GetAllLots returns all the lots ordered by id
+// GetAllLots() -> ([]internal.TLot, error)
+// Return:
+//		[]internal.TLot: Lots in the database
+//		error: 			 Error raised during the execution (if exists)
+
+func (l *LotMap) GetAllLots() ([]internal.TLot, error) {
+	return l.filterLots(func(internal.TLot) bool { return true })
+}
+
+// GetLotsByProduct returns the lots of a product ordered by id
+// GetLotsByProduct(productID int) -> ([]internal.TLot, error)
+// Args:
+//		productID: Product id
+// Return:
+//		[]internal.TLot: Lots of the product
+//		error: 			 Error raised during the execution (if exists)
+
+func (l *LotMap) GetLotsByProduct(productID int) ([]internal.TLot, error) {
+	return l.filterLots(func(lot internal.TLot) bool { return lot.ProductID == productID })
+}
+
+// SaveLots inserts the new lots and updates the existing ones in a single write
+// SaveLots(lots []internal.TLot) -> error
+// Args:
+//		lots: Lots to save. The ones with a zero id are inserted and get their id updated in place
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (l *LotMap) SaveLots(lots []internal.TLot) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	/* Get the data from the storage */
+	db, err := l.storage.GetAll()
+	if err != nil {
+		return internal.ErrStorageError
+	}
+
+	/* Get the last id used */
+	var lastID int
+	for key := range db {
+		if key > lastID {
+			lastID = key
+		}
+	}
+
+	/* Check the lots to update exist before changing anything */
+	for _, lot := range lots {
+		if _, ok := db[lot.ID]; lot.ID != 0 && !ok {
+			return internal.ErrLotNotFound
+		}
+	}
+
+	/* Insert or update the lots */
+	for i := range lots {
+		if lots[i].ID == 0 {
+			lastID++
+			lots[i].ID = lastID
+		}
+		db[lots[i].ID] = lots[i]
+	}
+
+	/* Save the changes in the storage */
+	if err = l.storage.WriteAll(db); err != nil {
+		return internal.ErrStorageError
+	}
+	return nil
+}
+
+// DeleteLotsByProduct deletes the lots of a product
+// DeleteLotsByProduct(productID int) -> error
+// Args:
+//		productID: Product id
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (l *LotMap) DeleteLotsByProduct(productID int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	/* Get the data from the storage */
+	db, err := l.storage.GetAll()
+	if err != nil {
+		return internal.ErrStorageError
+	}
+
+	/* Delete the lots of the product */
+	for id, lot := range db {
+		if lot.ProductID == productID {
+			delete(db, id)
+		}
+	}
+
+	/* Save the changes in the storage */
+	if err = l.storage.WriteAll(db); err != nil {
+		return internal.ErrStorageError
+	}
+	return nil
+}
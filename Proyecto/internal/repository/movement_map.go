@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"proyecto/internal"
+	"sort"
+	"time"
+)
+
+type MovementMap struct {
+	storage internal.MovementStorage // Storage
+}
+
+// NewMovementMap creates a new MovementMap
+// NewMovementMap(storage internal.MovementStorage) -> *MovementMap
+// Args:
+//		storage: Movement storage
+// Return:
+//		*MovementMap: New MovementMap
+
+func NewMovementMap(storage internal.MovementStorage) *MovementMap {
+	return &MovementMap{storage: storage}
+}
+
+// sortMovements sorts a slice of movements by date (and id on ties)
+// sortMovements(movements []internal.TMovement)
+// Args:
+//		movements: Slice of movements to sort
+
+func sortMovements(movements []internal.TMovement) {
+	sort.Slice(movements, func(i, j int) bool {
+		if movements[i].Date.Equal(movements[j].Date) {
+			return movements[i].ID < movements[j].ID
+		}
+		return movements[i].Date.Before(movements[j].Date)
+	})
+}
+
+// GetAllMovements returns all the movements of the ledger
+// GetAllMovements() -> ([]internal.TMovement, error)
+// Return:
+//		[]internal.TMovement: Movements ordered by date
+//		error: 				  Error raised during the execution (if exists)
+
+func (m *MovementMap) GetAllMovements() ([]internal.TMovement, error) {
+	/* Get the data from the storage */
+	db, err := m.storage.GetAll()
+	if err != nil {
+		return nil, internal.ErrStorageError
+	}
+
+	/* Convert the map to a slice */
+	movements := make([]internal.TMovement, 0, len(db))
+	for _, movement := range db {
+		movements = append(movements, movement)
+	}
+	sortMovements(movements)
+	return movements, nil
+}
+
+// GetMovementsByProduct returns the movements of a product between two dates
+// GetMovementsByProduct(productID int, from, to time.Time) -> ([]internal.TMovement, error)
+// Args:
+//		productID: Product id
+//		from: 	   Lower bound (inclusive). Zero value means no lower bound
+//		to: 	   Upper bound (exclusive). Zero value means no upper bound
+// Return:
+//		[]internal.TMovement: Movements of the product ordered by date
+//		error: 				  Error raised during the execution (if exists)
+
+func (m *MovementMap) GetMovementsByProduct(productID int, from, to time.Time) ([]internal.TMovement, error) {
+	/* Get the data from the storage */
+	db, err := m.storage.GetAll()
+	if err != nil {
+		return nil, internal.ErrStorageError
+	}
+
+	/* Filter the movements by product and date range */
+	movements := make([]internal.TMovement, 0)
+	for _, movement := range db {
+		if movement.ProductID != productID {
+			continue
+		}
+		if !from.IsZero() && movement.Date.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !movement.Date.Before(to) {
+			continue
+		}
+		movements = append(movements, movement)
+	}
+	sortMovements(movements)
+	return movements, nil
+}
+
+// InsertNewMovements inserts new movements in the ledger in a single write
+// InsertNewMovements(movements []internal.TMovement) -> error
+// Args:
+//		movements: Movements to insert. Their ids are updated in place
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (m *MovementMap) InsertNewMovements(movements []internal.TMovement) error {
+	/* Get the data from the storage */
+	db, err := m.storage.GetAll()
+	if err != nil {
+		return internal.ErrStorageError
+	}
+
+	/* Get the last id used */
+	var lastID int
+	for key := range db {
+		if key > lastID {
+			lastID = key
+		}
+	}
+
+	/* Insert the new movements */
+	for i := range movements {
+		lastID++
+		movements[i].ID = lastID
+		db[lastID] = movements[i]
+	}
+
+	/* Save the changes in the storage */
+	if err = m.storage.WriteAll(db); err != nil {
+		return internal.ErrStorageError
+	}
+	return nil
+}
+
+// DeleteMovementsByProduct deletes the movements of a product
+// DeleteMovementsByProduct(productID int) -> error
+// Args:
+//		productID: Product id
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (m *MovementMap) DeleteMovementsByProduct(productID int) error {
+	/* Get the data from the storage */
+	db, err := m.storage.GetAll()
+	if err != nil {
+		return internal.ErrStorageError
+	}
+
+	/* Delete the movements of the product */
+	for id, movement := range db {
+		if movement.ProductID == productID {
+			delete(db, id)
+		}
+	}
+
+	/* Save the changes in the storage */
+	if err = m.storage.WriteAll(db); err != nil {
+		return internal.ErrStorageError
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"proyecto/internal"
+	"sort"
+	"sync"
+)
+
+type OrderMap struct {
+	storage internal.OrderStorage // Storage
+	mu      sync.RWMutex          // Guards the storage against concurrent writers
+}
+
+// NewOrderMap creates a new OrderMap
+// NewOrderMap(storage internal.OrderStorage) -> *OrderMap
+// Args:
+//		storage: Order storage
+// Return:
+//		*OrderMap: New OrderMap
+
+func NewOrderMap(storage internal.OrderStorage) *OrderMap {
+	return &OrderMap{storage: storage}
+}
+
+// GetAllOrders returns all the orders ordered by id
+// GetAllOrders() -> ([]internal.TOrder, error)
+// Return:
+//		[]internal.TOrder: Orders in the database
+//		error: 			   Error raised during the execution (if exists)
+
+func (o *OrderMap) GetAllOrders() ([]internal.TOrder, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	/* Get the data from the storage */
+	db, err := o.storage.GetAll()
+	if err != nil {
+		return nil, internal.ErrStorageError
+	}
+
+	/* Convert the map to a slice */
+	orders := make([]internal.TOrder, 0, len(db))
+	for _, order := range db {
+		orders = append(orders, order)
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].ID < orders[j].ID
+	})
+	return orders, nil
+}
+
+// GetOrderByID returns an order by its id
+// GetOrderByID(id int) -> (internal.TOrder, error)
+// Args:
+//		id: Order id
+// Return:
+//		internal.TOrder: Order found in the database
+//		error: 			 Error raised during the execution (if exists)
+
+func (o *OrderMap) GetOrderByID(id int) (internal.TOrder, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	/* Get the data from the storage */
+	db, err := o.storage.GetAll()
+	if err != nil {
+		return internal.TOrder{}, internal.ErrStorageError
+	}
+
+	/* Check if the order exists */
+	order, ok := db[id]
+	if !ok {
+		return internal.TOrder{}, internal.ErrOrderNotFound
+	}
+	return order, nil
+}
+
+// InsertNewOrder inserts a new order in the database
+// InsertNewOrder(order *internal.TOrder) -> error
+// Args:
+//		order: Order to insert. Its id is updated in place
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (o *OrderMap) InsertNewOrder(order *internal.TOrder) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	/* Get the data from the storage */
+	db, err := o.storage.GetAll()
+	if err != nil {
+		return internal.ErrStorageError
+	}
+
+	/* Insert the new order */
+	var lastID int
+	for key := range db {
+		if key > lastID {
+			lastID = key
+		}
+	}
+	order.ID = lastID + 1
+	db[order.ID] = *order
+
+	/* Save the changes in the storage */
+	if err = o.storage.WriteAll(db); err != nil {
+		return internal.ErrStorageError
+	}
+	return nil
+}
+
+// UpdateOrder updates an order in the database
+// UpdateOrder(order *internal.TOrder) -> error
+// Args:
+//		order: Order to update
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (o *OrderMap) UpdateOrder(order *internal.TOrder) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	/* Get the data from the storage */
+	db, err := o.storage.GetAll()
+	if err != nil {
+		return internal.ErrStorageError
+	}
+
+	/* Check if the order exists */
+	if _, ok := db[order.ID]; !ok {
+		return internal.ErrOrderNotFound
+	}
+
+	/* Update the order */
+	db[order.ID] = *order
+	if err = o.storage.WriteAll(db); err != nil {
+		return internal.ErrStorageError
+	}
+	return nil
+}
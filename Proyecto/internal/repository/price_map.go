@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"proyecto/internal"
+	"sort"
+	"sync"
+)
+
+type PriceMap struct {
+	storage internal.PriceStorage // Storage
+	mu      sync.RWMutex          // Guards the storage against concurrent writers
+}
+
+// NewPriceMap creates a new PriceMap
+// NewPriceMap(storage internal.PriceStorage) -> *PriceMap
+// Args:
+//		storage: Price storage
+// Return:
+//		*PriceMap: New PriceMap
+
+func NewPriceMap(storage internal.PriceStorage) *PriceMap {
+	return &PriceMap{storage: storage}
+}
+
+// GetPriceHistory returns the price changes of a product
+// GetPriceHistory(productID int) -> ([]internal.TPriceChange, error)
+// Args:
+//		productID: Product id
+// Return:
+//		[]internal.TPriceChange: Price changes of the product ordered by date (and id on ties)
+//		error: 					 Error raised during the execution (if exists)
+
+func (p *PriceMap) GetPriceHistory(productID int) ([]internal.TPriceChange, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	/* Get the data from the storage */
+	db, err := p.storage.GetAll()
+	if err != nil {
+		return nil, internal.ErrStorageError
+	}
+
+	/* Filter the changes by product */
+	changes := make([]internal.TPriceChange, 0)
+	for _, change := range db {
+		if change.ProductID == productID {
+			changes = append(changes, change)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Date.Equal(changes[j].Date) {
+			return changes[i].ID < changes[j].ID
+		}
+		return changes[i].Date.Before(changes[j].Date)
+	})
+	return changes, nil
+}
+
+// InsertNewPriceChange inserts a new price change in the history
+// InsertNewPriceChange(change *internal.TPriceChange) -> error
+// Args:
+//		change: Price change to insert. Its id is updated in place
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (p *PriceMap) InsertNewPriceChange(change *internal.TPriceChange) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	/* Get the data from the storage */
+	db, err := p.storage.GetAll()
+	if err != nil {
+		return internal.ErrStorageError
+	}
+
+	/* Insert the new change */
+	var lastID int
+	for key := range db {
+		if key > lastID {
+			lastID = key
+		}
+	}
+	change.ID = lastID + 1
+	db[change.ID] = *change
+
+	/* Save the changes in the storage */
+	if err = p.storage.WriteAll(db); err != nil {
+		return internal.ErrStorageError
+	}
+	return nil
+}
+
+// DeletePriceHistory deletes the price changes of a product
+// DeletePriceHistory(productID int) -> error
+// Args:
+//		productID: Product id
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (p *PriceMap) DeletePriceHistory(productID int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	/* Get the data from the storage */
+	db, err := p.storage.GetAll()
+	if err != nil {
+		return internal.ErrStorageError
+	}
+
+	/* Delete the changes of the product */
+	for id, change := range db {
+		if change.ProductID == productID {
+			delete(db, id)
+		}
+	}
+
+	/* Save the changes in the storage */
+	if err = p.storage.WriteAll(db); err != nil {
+		return internal.ErrStorageError
+	}
+	return nil
+}
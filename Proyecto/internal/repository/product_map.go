@@ -3,10 +3,13 @@ package repository
 import (
 	"proyecto/internal"
 	"sort"
+	"sync"
 )
 
 type ProductMap struct {
-	storage internal.ProductStorage // Storage
+	storage  internal.ProductStorage // Storage
+	mu       sync.RWMutex            // Guards the storage against concurrent writers
+	tagIndex map[string]map[int]bool // Inverted index: tag -> ids of the products using it (nil until loaded)
 }
 
 // NewProductMap creates a new ProductMap
@@ -26,6 +29,9 @@ func NewProductMap(storage internal.ProductStorage) *ProductMap {
 //		internal.Tproduct: Database of products
 
 func (p *ProductMap) GetAllProducts() []internal.TProduct {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
 	/* Get the data from the storage */
 	productMap, err := p.storage.GetAll()
 	if err != nil {
@@ -54,6 +60,9 @@ func (p *ProductMap) GetAllProducts() []internal.TProduct {
 //		error: 			   Error raised during the execution (if exists)
 
 func (p *ProductMap) GetProductByID(id int) (internal.TProduct, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
 	/* Get the data from the storage */
 	db, err := p.storage.GetAll()
 	if err != nil {
@@ -76,6 +85,9 @@ func (p *ProductMap) GetProductByID(id int) (internal.TProduct, error) {
 //		[]internal.TProduct: Slice of products with a price greater than the given price
 
 func (p *ProductMap) GetProductByPriceGt(price float64) []internal.TProduct {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
 	/* Get the data from the storage */
 	db, err := p.storage.GetAll()
 	if err != nil {
@@ -134,6 +146,9 @@ func getNewID(db map[int]internal.TProduct) int {
 //		error: Error raised during the execution (if exists)
 
 func (p *ProductMap) InsertNewProduct(product *internal.TProduct) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	/* Get the data from the storage */
 	db, err := p.storage.GetAll()
 	if err != nil {
@@ -146,6 +161,7 @@ func (p *ProductMap) InsertNewProduct(product *internal.TProduct) error {
 	}
 
 	/* Insert the new product */
+	p.loadTagIndex(db)
 	newID := getNewID(db) // Get a new id for the product
 	product.ID = newID    // Update the product's ID
 	db[newID] = *product  // Insert the new product
@@ -155,6 +171,7 @@ func (p *ProductMap) InsertNewProduct(product *internal.TProduct) error {
 		return internal.ErrStorageError
 	}
 
+	p.indexTags(newID, nil, product.Tags)
 	return nil
 }
 
@@ -168,6 +185,9 @@ func (p *ProductMap) InsertNewProduct(product *internal.TProduct) error {
 //
 //	error: Error raised during the execution (if exists)
 func (p *ProductMap) UpdateProduct(product *internal.TProduct) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	/* Get the data from the storage */
 	db, err := p.storage.GetAll()
 	if err != nil {
@@ -175,7 +195,7 @@ func (p *ProductMap) UpdateProduct(product *internal.TProduct) error {
 	}
 
 	/* Check if the product exists */
-	_, ok := db[product.ID]
+	previous, ok := db[product.ID]
 	if !ok {
 		return internal.ErrProductNotFound
 	}
@@ -186,6 +206,7 @@ func (p *ProductMap) UpdateProduct(product *internal.TProduct) error {
 	}
 
 	/* Update the product */
+	p.loadTagIndex(db)
 	db[product.ID] = *product
 
 	/* Save the changes in the storage */
@@ -193,6 +214,7 @@ func (p *ProductMap) UpdateProduct(product *internal.TProduct) error {
 		return internal.ErrStorageError
 	}
 
+	p.indexTags(product.ID, previous.Tags, product.Tags)
 	return nil
 }
 
@@ -204,6 +226,9 @@ func (p *ProductMap) UpdateProduct(product *internal.TProduct) error {
 //		error: Error raised during the execution (if exists)
 
 func (p *ProductMap) DeleteProduct(id int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	/* Get the data from the storage */
 	db, err := p.storage.GetAll()
 	if err != nil {
@@ -211,14 +236,153 @@ func (p *ProductMap) DeleteProduct(id int) error {
 	}
 
 	/* Check if the product exists */
-	if _, ok := db[id]; !ok {
+	previous, ok := db[id]
+	if !ok {
 		return internal.ErrProductNotFound
 	}
 
 	/* Delete the product */
+	p.loadTagIndex(db)
 	delete(db, id)
 	if err = p.storage.WriteAll(db); err != nil {
 		return internal.ErrStorageError
 	}
+
+	p.indexTags(id, previous.Tags, nil)
 	return nil
 }
+
+// loadTagIndex builds the inverted index of tags if it isn't loaded yet. The caller must hold the write lock
+// loadTagIndex(db map[int]internal.TProduct)
+// Args:
+//		db: Database of products
+
+func (p *ProductMap) loadTagIndex(db map[int]internal.TProduct) {
+	if p.tagIndex != nil {
+		return
+	}
+	p.tagIndex = make(map[string]map[int]bool)
+	for id, product := range db {
+		for _, tag := range product.Tags {
+			if p.tagIndex[tag] == nil {
+				p.tagIndex[tag] = make(map[int]bool)
+			}
+			p.tagIndex[tag][id] = true
+		}
+	}
+}
+
+// rLockTagIndex takes the read lock making sure the inverted index is loaded. Once loaded the index is
+// kept up to date by the writes, so the storage is only read the first time
+// rLockTagIndex() -> error
+// Return:
+//		error: Error raised during the execution (if exists). The read lock is only held without error
+
+func (p *ProductMap) rLockTagIndex() error {
+	p.mu.RLock()
+	if p.tagIndex != nil {
+		return nil
+	}
+	p.mu.RUnlock()
+
+	/* Load the index under the write lock */
+	p.mu.Lock()
+	if p.tagIndex == nil {
+		db, err := p.storage.GetAll()
+		if err != nil {
+			p.mu.Unlock()
+			return internal.ErrStorageError
+		}
+		p.loadTagIndex(db)
+	}
+	p.mu.Unlock()
+
+	p.mu.RLock()
+	return nil
+}
+
+// indexTags moves a product from its old tags to its new ones in the inverted index
+// indexTags(id int, oldTags, newTags []string)
+// Args:
+//		id: 	 Product id
+//		oldTags: Tags the product had
+//		newTags: Tags the product has now
+
+func (p *ProductMap) indexTags(id int, oldTags, newTags []string) {
+	for _, tag := range oldTags {
+		delete(p.tagIndex[tag], id)
+		if len(p.tagIndex[tag]) == 0 {
+			delete(p.tagIndex, tag)
+		}
+	}
+	for _, tag := range newTags {
+		if p.tagIndex[tag] == nil {
+			p.tagIndex[tag] = make(map[int]bool)
+		}
+		p.tagIndex[tag][id] = true
+	}
+}
+
+// GetProductsByTags returns the products with all (or any) of the given tags using the inverted index
+// GetProductsByTags(tags []string, matchAll bool) -> ([]internal.TProduct, error)
+// Args:
+//		tags: 	  Tags to search
+//		matchAll: If true the products must have every tag, otherwise at least one
+// Return:
+//		[]internal.TProduct: Products ordered by id
+//		error: 				 Error raised during the execution (if exists)
+
+func (p *ProductMap) GetProductsByTags(tags []string, matchAll bool) ([]internal.TProduct, error) {
+	if err := p.rLockTagIndex(); err != nil {
+		return nil, err
+	}
+	defer p.mu.RUnlock()
+
+	/* Count how many of the tags each product matches */
+	matches := make(map[int]int)
+	for _, tag := range tags {
+		for id := range p.tagIndex[tag] {
+			matches[id]++
+		}
+	}
+	if len(matches) == 0 {
+		return []internal.TProduct{}, nil
+	}
+
+	/* Get the data from the storage */
+	db, err := p.storage.GetAll()
+	if err != nil {
+		return nil, internal.ErrStorageError
+	}
+
+	/* Keep the products matching all (or any) of the tags */
+	productSlice := make([]internal.TProduct, 0)
+	for id, count := range matches {
+		if product, ok := db[id]; ok && (!matchAll || count == len(tags)) {
+			productSlice = append(productSlice, product)
+		}
+	}
+	sort.Slice(productSlice, func(i, j int) bool {
+		return productSlice[i].ID < productSlice[j].ID
+	})
+	return productSlice, nil
+}
+
+// GetTagCounts returns how many products use each tag
+// GetTagCounts() -> (map[string]int, error)
+// Return:
+//		map[string]int: Tag usage counts
+//		error: 			Error raised during the execution (if exists)
+
+func (p *ProductMap) GetTagCounts() (map[string]int, error) {
+	if err := p.rLockTagIndex(); err != nil {
+		return nil, err
+	}
+	defer p.mu.RUnlock()
+
+	counts := make(map[string]int, len(p.tagIndex))
+	for tag, ids := range p.tagIndex {
+		counts[tag] = len(ids)
+	}
+	return counts, nil
+}
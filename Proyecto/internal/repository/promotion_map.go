@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"proyecto/internal"
+	"sort"
+	"sync"
+)
+
+type PromotionMap struct {
+	storage internal.PromotionStorage // Storage
+	mu      sync.RWMutex              // Guards the storage against concurrent writers
+}
+
+// NewPromotionMap creates a new PromotionMap
+// NewPromotionMap(storage internal.PromotionStorage) -> *PromotionMap
+// Args:
+//		storage: Promotion storage
+// Return:
+//		*PromotionMap: New PromotionMap
+
+func NewPromotionMap(storage internal.PromotionStorage) *PromotionMap {
+	return &PromotionMap{storage: storage}
+}
+
+// GetAllPromotions returns all the promotions ordered by start (and id on ties)
+// GetAllPromotions() -> ([]internal.TPromotion, error)
+// Return:
+//		[]internal.TPromotion: Promotions in the database
+//		error: 				   Error raised during the execution (if exists)
+
+func (p *PromotionMap) GetAllPromotions() ([]internal.TPromotion, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	/* Get the data from the storage */
+	db, err := p.storage.GetAll()
+	if err != nil {
+		return nil, internal.ErrStorageError
+	}
+
+	/* Convert the map to a slice */
+	promotions := make([]internal.TPromotion, 0, len(db))
+	for _, promotion := range db {
+		promotions = append(promotions, promotion)
+	}
+	sort.Slice(promotions, func(i, j int) bool {
+		if promotions[i].StartAt.Equal(promotions[j].StartAt) {
+			return promotions[i].ID < promotions[j].ID
+		}
+		return promotions[i].StartAt.Before(promotions[j].StartAt)
+	})
+	return promotions, nil
+}
+
+// GetPromotionByID returns a promotion by its id
+// GetPromotionByID(id int) -> (internal.TPromotion, error)
+// Args:
+//		id: Promotion id
+// Return:
+//		internal.TPromotion: Promotion found in the database
+//		error: 				 Error raised during the execution (if exists)
+
+func (p *PromotionMap) GetPromotionByID(id int) (internal.TPromotion, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	/* Get the data from the storage */
+	db, err := p.storage.GetAll()
+	if err != nil {
+		return internal.TPromotion{}, internal.ErrStorageError
+	}
+
+	/* Check if the promotion exists */
+	promotion, ok := db[id]
+	if !ok {
+		return internal.TPromotion{}, internal.ErrPromotionNotFound
+	}
+	return promotion, nil
+}
+
+// InsertNewPromotion inserts a new promotion in the database
+// InsertNewPromotion(promotion *internal.TPromotion) -> error
+// Args:
+//		promotion: Promotion to insert. Its id is updated in place
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (p *PromotionMap) InsertNewPromotion(promotion *internal.TPromotion) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	/* Get the data from the storage */
+	db, err := p.storage.GetAll()
+	if err != nil {
+		return internal.ErrStorageError
+	}
+
+	/* Insert the new promotion */
+	var lastID int
+	for key := range db {
+		if key > lastID {
+			lastID = key
+		}
+	}
+	promotion.ID = lastID + 1
+	db[promotion.ID] = *promotion
+
+	/* Save the changes in the storage */
+	if err = p.storage.WriteAll(db); err != nil {
+		return internal.ErrStorageError
+	}
+	return nil
+}
+
+// UpdatePromotion updates a promotion in the database
+// UpdatePromotion(promotion *internal.TPromotion) -> error
+// Args:
+//		promotion: Promotion to update
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (p *PromotionMap) UpdatePromotion(promotion *internal.TPromotion) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	/* Get the data from the storage */
+	db, err := p.storage.GetAll()
+	if err != nil {
+		return internal.ErrStorageError
+	}
+
+	/* Check if the promotion exists */
+	if _, ok := db[promotion.ID]; !ok {
+		return internal.ErrPromotionNotFound
+	}
+
+	/* Update the promotion */
+	db[promotion.ID] = *promotion
+	if err = p.storage.WriteAll(db); err != nil {
+		return internal.ErrStorageError
+	}
+	return nil
+}
+
+// DeletePromotion deletes a promotion from the database
+// DeletePromotion(id int) -> error
+// Args:
+//		id: Promotion id
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (p *PromotionMap) DeletePromotion(id int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	/* Get the data from the storage */
+	db, err := p.storage.GetAll()
+	if err != nil {
+		return internal.ErrStorageError
+	}
+
+	/* Check if the promotion exists */
+	if _, ok := db[id]; !ok {
+		return internal.ErrPromotionNotFound
+	}
+
+	/* Delete the promotion */
+	delete(db, id)
+	if err = p.storage.WriteAll(db); err != nil {
+		return internal.ErrStorageError
+	}
+	return nil
+}
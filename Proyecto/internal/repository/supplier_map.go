@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"proyecto/internal"
+	"sort"
+	"sync"
+)
+
+type SupplierMap struct {
+	storage internal.SupplierStorage // Storage
+	mu      sync.RWMutex             // Guards the storage against concurrent writers
+}
+
+// NewSupplierMap creates a new SupplierMap
+// NewSupplierMap(storage internal.SupplierStorage) -> *SupplierMap
+// Args:
+//		storage: Supplier storage
+// Return:
+//		*SupplierMap: New SupplierMap
+
+func NewSupplierMap(storage internal.SupplierStorage) *SupplierMap {
+	return &SupplierMap{storage: storage}
+}
+
+// GetAllSuppliers returns all the suppliers ordered by id
+// GetAllSuppliers() -> ([]internal.TSupplier, error)
+// Return:
+//		[]internal.TSupplier: Suppliers in the database
+//		error: 				   Error raised during the execution (if exists)
+
+func (s *SupplierMap) GetAllSuppliers() ([]internal.TSupplier, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	/* Get the data from the storage */
+	db, err := s.storage.GetAll()
+	if err != nil {
+		return nil, internal.ErrStorageError
+	}
+
+	/* Convert the map to a slice */
+	suppliers := make([]internal.TSupplier, 0, len(db))
+	for _, supplier := range db {
+		suppliers = append(suppliers, supplier)
+	}
+	sort.Slice(suppliers, func(i, j int) bool {
+		return suppliers[i].ID < suppliers[j].ID
+	})
+	return suppliers, nil
+}
+
+// GetSupplierByID returns a supplier by its id
+// GetSupplierByID(id int) -> (internal.TSupplier, error)
+// Args:
+//		id: Supplier id
+// Return:
+//		internal.TSupplier: Supplier found in the database
+//		error: 				 Error raised during the execution (if exists)
+
+func (s *SupplierMap) GetSupplierByID(id int) (internal.TSupplier, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	/* Get the data from the storage */
+	db, err := s.storage.GetAll()
+	if err != nil {
+		return internal.TSupplier{}, internal.ErrStorageError
+	}
+
+	/* Check if the supplier exists */
+	supplier, ok := db[id]
+	if !ok {
+		return internal.TSupplier{}, internal.ErrSupplierNotFound
+	}
+	return supplier, nil
+}
+
+// InsertNewSupplier inserts a new supplier in the database
+// InsertNewSupplier(supplier *internal.TSupplier) -> error
+// Args:
+//		supplier: Supplier to insert. Its id is updated in place
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (s *SupplierMap) InsertNewSupplier(supplier *internal.TSupplier) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	/* Get the data from the storage */
+	db, err := s.storage.GetAll()
+	if err != nil {
+		return internal.ErrStorageError
+	}
+
+	/* Insert the new supplier */
+	var lastID int
+	for key := range db {
+		if key > lastID {
+			lastID = key
+		}
+	}
+	supplier.ID = lastID + 1
+	db[supplier.ID] = *supplier
+
+	/* Save the changes in the storage */
+	if err = s.storage.WriteAll(db); err != nil {
+		return internal.ErrStorageError
+	}
+	return nil
+}
+
+// UpdateSupplier updates a supplier in the database
+// UpdateSupplier(supplier *internal.TSupplier) -> error
+// Args:
+//		supplier: Supplier to update
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (s *SupplierMap) UpdateSupplier(supplier *internal.TSupplier) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	/* Get the data from the storage */
+	db, err := s.storage.GetAll()
+	if err != nil {
+		return internal.ErrStorageError
+	}
+
+	/* Check if the supplier exists */
+	if _, ok := db[supplier.ID]; !ok {
+		return internal.ErrSupplierNotFound
+	}
+
+	/* Update the supplier */
+	db[supplier.ID] = *supplier
+	if err = s.storage.WriteAll(db); err != nil {
+		return internal.ErrStorageError
+	}
+	return nil
+}
+
+// DeleteSupplier deletes a supplier from the database
+// DeleteSupplier(id int) -> error
+// Args:
+//		id: Supplier id
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (s *SupplierMap) DeleteSupplier(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	/* Get the data from the storage */
+	db, err := s.storage.GetAll()
+	if err != nil {
+		return internal.ErrStorageError
+	}
+
+	/* Check if the supplier exists */
+	if _, ok := db[id]; !ok {
+		return internal.ErrSupplierNotFound
+	}
+
+	/* Delete the supplier */
+	delete(db, id)
+	if err = s.storage.WriteAll(db); err != nil {
+		return internal.ErrStorageError
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"proyecto/internal"
+	"sort"
+	"sync"
+)
+
+type WarehouseMap struct {
+	storage internal.WarehouseStorage // Storage
+	mu      sync.RWMutex              // Guards the storage against concurrent writers
+}
+
+// NewWarehouseMap creates a new WarehouseMap
+// NewWarehouseMap(storage internal.WarehouseStorage) -> *WarehouseMap
+// Args:
+//		storage: Warehouse storage
+// Return:
+//		*WarehouseMap: New WarehouseMap
+
+func NewWarehouseMap(storage internal.WarehouseStorage) *WarehouseMap {
+	return &WarehouseMap{storage: storage}
+}
+
+// GetAllWarehouses returns all the warehouses ordered by id
+// GetAllWarehouses() -> ([]internal.TWarehouse, error)
+// Return:
+//		[]internal.TWarehouse: Warehouses in the database
+//		error: 				   Error raised during the execution (if exists)
+
+func (w *WarehouseMap) GetAllWarehouses() ([]internal.TWarehouse, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	/* Get the data from the storage */
+	db, err := w.storage.GetAll()
+	if err != nil {
+		return nil, internal.ErrStorageError
+	}
+
+	/* Convert the map to a slice */
+	warehouses := make([]internal.TWarehouse, 0, len(db))
+	for _, warehouse := range db {
+		warehouses = append(warehouses, warehouse)
+	}
+	sort.Slice(warehouses, func(i, j int) bool {
+		return warehouses[i].ID < warehouses[j].ID
+	})
+	return warehouses, nil
+}
+
+// GetWarehouseByID returns a warehouse by its id
+// GetWarehouseByID(id int) -> (internal.TWarehouse, error)
+// Args:
+//		id: Warehouse id
+// Return:
+//		internal.TWarehouse: Warehouse found in the database
+//		error: 				 Error raised during the execution (if exists)
+
+func (w *WarehouseMap) GetWarehouseByID(id int) (internal.TWarehouse, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	/* Get the data from the storage */
+	db, err := w.storage.GetAll()
+	if err != nil {
+		return internal.TWarehouse{}, internal.ErrStorageError
+	}
+
+	/* Check if the warehouse exists */
+	warehouse, ok := db[id]
+	if !ok {
+		return internal.TWarehouse{}, internal.ErrWarehouseNotFound
+	}
+	return warehouse, nil
+}
+
+// InsertNewWarehouse inserts a new warehouse in the database
+// InsertNewWarehouse(warehouse *internal.TWarehouse) -> error
+// Args:
+//		warehouse: Warehouse to insert. Its id is updated in place
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (w *WarehouseMap) InsertNewWarehouse(warehouse *internal.TWarehouse) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	/* Get the data from the storage */
+	db, err := w.storage.GetAll()
+	if err != nil {
+		return internal.ErrStorageError
+	}
+
+	/* Insert the new warehouse */
+	var lastID int
+	for key := range db {
+		if key > lastID {
+			lastID = key
+		}
+	}
+	warehouse.ID = lastID + 1
+	db[warehouse.ID] = *warehouse
+
+	/* Save the changes in the storage */
+	if err = w.storage.WriteAll(db); err != nil {
+		return internal.ErrStorageError
+	}
+	return nil
+}
+
+// UpdateWarehouse updates a warehouse in the database
+// UpdateWarehouse(warehouse *internal.TWarehouse) -> error
+// Args:
+//		warehouse: Warehouse to update
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (w *WarehouseMap) UpdateWarehouse(warehouse *internal.TWarehouse) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	/* Get the data from the storage */
+	db, err := w.storage.GetAll()
+	if err != nil {
+		return internal.ErrStorageError
+	}
+
+	/* Check if the warehouse exists */
+	if _, ok := db[warehouse.ID]; !ok {
+		return internal.ErrWarehouseNotFound
+	}
+
+	/* Update the warehouse */
+	db[warehouse.ID] = *warehouse
+	if err = w.storage.WriteAll(db); err != nil {
+		return internal.ErrStorageError
+	}
+	return nil
+}
+
+// DeleteWarehouse deletes a warehouse from the database
+// DeleteWarehouse(id int) -> error
+// Args:
+//		id: Warehouse id
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (w *WarehouseMap) DeleteWarehouse(id int) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	/* Get the data from the storage */
+	db, err := w.storage.GetAll()
+	if err != nil {
+		return internal.ErrStorageError
+	}
+
+	/* Check if the warehouse exists */
+	if _, ok := db[id]; !ok {
+		return internal.ErrWarehouseNotFound
+	}
+
+	/* Delete the warehouse */
+	delete(db, id)
+	if err = w.storage.WriteAll(db); err != nil {
+		return internal.ErrStorageError
+	}
+	return nil
+}
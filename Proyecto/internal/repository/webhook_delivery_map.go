@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"proyecto/internal"
+	"sort"
+	"sync"
+)
+
+type WebhookDeliveryMap struct {
+	storage internal.WebhookDeliveryStorage // Storage
+	mu      sync.RWMutex                    // Guards the storage against concurrent writers
+}
+
+// NewWebhookDeliveryMap creates a new WebhookDeliveryMap
+// NewWebhookDeliveryMap(storage internal.WebhookDeliveryStorage) -> *WebhookDeliveryMap
+// Args:
+//		storage: Webhook delivery storage
+// Return:
+//		*WebhookDeliveryMap: New WebhookDeliveryMap
+
+func NewWebhookDeliveryMap(storage internal.WebhookDeliveryStorage) *WebhookDeliveryMap {
+	return &WebhookDeliveryMap{storage: storage}
+}
+
+// GetAllDeliveries returns all the deliveries ordered by id
+// GetAllDeliveries() -> ([]internal.TWebhookDelivery, error)
+// Return:
+//		[]internal.TWebhookDelivery: Deliveries in the database, oldest first
+//		error: 						 Error raised during the execution (if exists)
+
+func (d *WebhookDeliveryMap) GetAllDeliveries() ([]internal.TWebhookDelivery, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	/* Get the data from the storage */
+	db, err := d.storage.GetAll()
+	if err != nil {
+		return nil, internal.ErrStorageError
+	}
+
+	/* Convert the map to a slice */
+	deliveries := make([]internal.TWebhookDelivery, 0, len(db))
+	for _, delivery := range db {
+		deliveries = append(deliveries, delivery)
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].ID < deliveries[j].ID
+	})
+	return deliveries, nil
+}
+
+// GetDeliveryByID returns a delivery by its id
+// GetDeliveryByID(id int) -> (internal.TWebhookDelivery, error)
+// Args:
+//		id: Delivery id
+// Return:
+//		internal.TWebhookDelivery: Delivery found in the database
+//		error: 					   Error raised during the execution (if exists)
+
+func (d *WebhookDeliveryMap) GetDeliveryByID(id int) (internal.TWebhookDelivery, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	/* Get the data from the storage */
+	db, err := d.storage.GetAll()
+	if err != nil {
+		return internal.TWebhookDelivery{}, internal.ErrStorageError
+	}
+
+	/* Check if the delivery exists */
+	delivery, ok := db[id]
+	if !ok {
+		return internal.TWebhookDelivery{}, internal.ErrWebhookDeliveryNotFound
+	}
+	return delivery, nil
+}
+
+// InsertNewDeliveries inserts new deliveries in the database with a single write
+// InsertNewDeliveries(deliveries []internal.TWebhookDelivery) -> error
+// Args:
+//		deliveries: Deliveries to insert. Their ids are updated in place
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (d *WebhookDeliveryMap) InsertNewDeliveries(deliveries []internal.TWebhookDelivery) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	/* Get the data from the storage */
+	db, err := d.storage.GetAll()
+	if err != nil {
+		return internal.ErrStorageError
+	}
+
+	/* Insert the new deliveries */
+	var lastID int
+	for key := range db {
+		if key > lastID {
+			lastID = key
+		}
+	}
+	for i := range deliveries {
+		lastID++
+		deliveries[i].ID = lastID
+		db[lastID] = deliveries[i]
+	}
+
+	/* Save the changes in the storage */
+	if err = d.storage.WriteAll(db); err != nil {
+		return internal.ErrStorageError
+	}
+	return nil
+}
+
+// UpdateDelivery updates a delivery in the database
+// UpdateDelivery(delivery *internal.TWebhookDelivery) -> error
+// Args:
+//		delivery: Delivery to update
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (d *WebhookDeliveryMap) UpdateDelivery(delivery *internal.TWebhookDelivery) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	/* Get the data from the storage */
+	db, err := d.storage.GetAll()
+	if err != nil {
+		return internal.ErrStorageError
+	}
+
+	/* Check if the delivery exists */
+	if _, ok := db[delivery.ID]; !ok {
+		return internal.ErrWebhookDeliveryNotFound
+	}
+
+	/* Update the delivery */
+	db[delivery.ID] = *delivery
+	if err = d.storage.WriteAll(db); err != nil {
+		return internal.ErrStorageError
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"proyecto/internal"
+	"sort"
+	"sync"
+)
+
+type WebhookMap struct {
+	storage internal.WebhookStorage // Storage
+	mu      sync.RWMutex            // Guards the storage against concurrent writers
+}
+
+// NewWebhookMap creates a new WebhookMap
+// NewWebhookMap(storage internal.WebhookStorage) -> *WebhookMap
+// Args:
+//		storage: Webhook storage
+// Return:
+//		*WebhookMap: New WebhookMap
+
+func NewWebhookMap(storage internal.WebhookStorage) *WebhookMap {
+	return &WebhookMap{storage: storage}
+}
+
+// GetAllWebhooks returns all the webhooks ordered by id
+// GetAllWebhooks() -> ([]internal.TWebhook, error)
+// Return:
+//		[]internal.TWebhook: Webhooks in the database
+//		error: 				   Error raised during the execution (if exists)
+
+func (h *WebhookMap) GetAllWebhooks() ([]internal.TWebhook, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	/* Get the data from the storage */
+	db, err := h.storage.GetAll()
+	if err != nil {
+		return nil, internal.ErrStorageError
+	}
+
+	/* Convert the map to a slice */
+	webhooks := make([]internal.TWebhook, 0, len(db))
+	for _, webhook := range db {
+		webhooks = append(webhooks, webhook)
+	}
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].ID < webhooks[j].ID
+	})
+	return webhooks, nil
+}
+
+// GetWebhookByID returns a webhook by its id
+// GetWebhookByID(id int) -> (internal.TWebhook, error)
+// Args:
+//		id: Webhook id
+// Return:
+//		internal.TWebhook: Webhook found in the database
+//		error: 				 Error raised during the execution (if exists)
+
+func (h *WebhookMap) GetWebhookByID(id int) (internal.TWebhook, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	/* Get the data from the storage */
+	db, err := h.storage.GetAll()
+	if err != nil {
+		return internal.TWebhook{}, internal.ErrStorageError
+	}
+
+	/* Check if the webhook exists */
+	webhook, ok := db[id]
+	if !ok {
+		return internal.TWebhook{}, internal.ErrWebhookNotFound
+	}
+	return webhook, nil
+}
+
+// InsertNewWebhook inserts a new webhook in the database
+// InsertNewWebhook(webhook *internal.TWebhook) -> error
+// Args:
+//		webhook: Webhook to insert. Its id is updated in place
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (h *WebhookMap) InsertNewWebhook(webhook *internal.TWebhook) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	/* Get the data from the storage */
+	db, err := h.storage.GetAll()
+	if err != nil {
+		return internal.ErrStorageError
+	}
+
+	/* Insert the new webhook */
+	var lastID int
+	for key := range db {
+		if key > lastID {
+			lastID = key
+		}
+	}
+	webhook.ID = lastID + 1
+	db[webhook.ID] = *webhook
+
+	/* Save the changes in the storage */
+	if err = h.storage.WriteAll(db); err != nil {
+		return internal.ErrStorageError
+	}
+	return nil
+}
+
+// UpdateWebhook updates a webhook in the database
+// UpdateWebhook(webhook *internal.TWebhook) -> error
+// Args:
+//		webhook: Webhook to update
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (h *WebhookMap) UpdateWebhook(webhook *internal.TWebhook) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	/* Get the data from the storage */
+	db, err := h.storage.GetAll()
+	if err != nil {
+		return internal.ErrStorageError
+	}
+
+	/* Check if the webhook exists */
+	if _, ok := db[webhook.ID]; !ok {
+		return internal.ErrWebhookNotFound
+	}
+
+	/* Update the webhook */
+	db[webhook.ID] = *webhook
+	if err = h.storage.WriteAll(db); err != nil {
+		return internal.ErrStorageError
+	}
+	return nil
+}
+
+// DeleteWebhook deletes a webhook from the database
+// DeleteWebhook(id int) -> error
+// Args:
+//		id: Webhook id
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (h *WebhookMap) DeleteWebhook(id int) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	/* Get the data from the storage */
+	db, err := h.storage.GetAll()
+	if err != nil {
+		return internal.ErrStorageError
+	}
+
+	/* Check if the webhook exists */
+	if _, ok := db[id]; !ok {
+		return internal.ErrWebhookNotFound
+	}
+
+	/* Delete the webhook */
+	delete(db, id)
+	if err = h.storage.WriteAll(db); err != nil {
+		return internal.ErrStorageError
+	}
+	return nil
+}
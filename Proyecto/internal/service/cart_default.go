@@ -0,0 +1,437 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"proyecto/internal"
+	"proyecto/internal/clock"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCartTTL is the time a cart lives untouched when no other is configured
+const DefaultCartTTL = 24 * time.Hour
+
+type CartServiceDefault struct {
+	repository internal.CartRepository // Repository of the carts
+	products   internal.ProductService // Service of the products in the carts
+	orders     internal.OrderService   // Service the carts are checked out into
+	ttl        time.Duration           // Time a cart lives untouched
+	clock      internal.Clock          // Clock the carts are dated and expired with
+	mu         sync.Mutex              // Serializes the cart updates
+}
+
+// NewCartServiceDefault creates a new CartServiceDefault instance
+// NewCartServiceDefault(cr internal.CartRepository, ps internal.ProductService, os internal.OrderService, ttl time.Duration) -> *CartServiceDefault
+// Args:
+//		cr:  Cart repository
+//		ps:  Product service the lines are priced and checked against
+//		os:  Order service the carts are checked out into
+//		ttl: Time a cart lives untouched. Non positive values fall back to DefaultCartTTL
+// Return:
+//		*CartServiceDefault: New CartServiceDefault instance
+
+func NewCartServiceDefault(cr internal.CartRepository, ps internal.ProductService, os internal.OrderService, ttl time.Duration) *CartServiceDefault {
+	if ttl <= 0 {
+		ttl = DefaultCartTTL
+	}
+	return &CartServiceDefault{
+		repository: cr,
+		products:   ps,
+		orders:     os,
+		ttl:        ttl,
+		clock:      clock.NewClockSystem(),
+	}
+}
+
+// SetClock sets the clock the carts are dated and expired with
+// SetClock(clk internal.Clock)
+// Args:
+//		clk: Clock to use
+
+func (c *CartServiceDefault) SetClock(clk internal.Clock) {
+	c.clock = clk
+}
+
+// load returns a cart which has not expired
+// load(id int) -> (internal.TCart, error)
+// Args:
+//		id: Cart id
+// Return:
+//		internal.TCart: Cart found in the repository
+//		error: 			Error raised during the execution (if exists)
+
+func (c *CartServiceDefault) load(id int) (internal.TCart, error) {
+	cart, err := c.repository.GetCartByID(id)
+	if err == internal.ErrCartNotFound {
+		return internal.TCart{}, internal.ErrCartNotExists
+	} else if err != nil {
+		return internal.TCart{}, err
+	}
+	if cart.Status == internal.CartOpen && !cart.ExpiresAt.After(c.clock.Now()) {
+		return internal.TCart{}, internal.ErrCartExpired
+	}
+	return cart, nil
+}
+
+// loadOpen returns a cart which can still be changed
+// loadOpen(id int) -> (internal.TCart, error)
+
+func (c *CartServiceDefault) loadOpen(id int) (internal.TCart, error) {
+	cart, err := c.load(id)
+	if err != nil {
+		return internal.TCart{}, err
+	}
+	if cart.Status != internal.CartOpen {
+		return internal.TCart{}, fmt.Errorf("%w: cart is %s", internal.ErrCartClosed, cart.Status)
+	}
+	return cart, nil
+}
+
+// refresh compares the lines of a cart against the live products and computes its totals
+// refresh(cart *internal.TCart)
+// Args:
+//		cart: Cart to refresh. It is updated in place
+
+func (c *CartServiceDefault) refresh(cart *internal.TCart) {
+	now := c.clock.Now()
+	var total, currentTotal float64
+	for i := range cart.Lines {
+		line := &cart.Lines[i]
+		product, err := c.products.GetProductByID(line.ProductID)
+		if err != nil {
+			line.CurrentPrice, line.PriceChanged, line.Unavailable = 0, false, true
+		} else {
+			line.CurrentPrice = livePrice(product)
+			line.PriceChanged = line.CurrentPrice != line.UnitPrice
+			line.Unavailable = checkAvailable(product, line.Quantity, now) != nil
+		}
+		total += line.UnitPrice * float64(line.Quantity)
+		currentTotal += line.CurrentPrice * float64(line.Quantity)
+	}
+	cart.Total = math.Round(total*100) / 100
+	cart.CurrentTotal = math.Round(currentTotal*100) / 100
+}
+
+// save stores a cart extending its life
+// save(cart *internal.TCart) -> error
+// Args:
+//		cart: Cart to save. It is refreshed after saving
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (c *CartServiceDefault) save(cart *internal.TCart) error {
+	cart.UpdatedAt = c.clock.Now()
+	cart.ExpiresAt = cart.UpdatedAt.Add(c.ttl)
+	if err := c.repository.UpdateCart(cart); err != nil {
+		return err
+	}
+	c.refresh(cart)
+	return nil
+}
+
+// lineIndex returns the position of the line of a product (-1 if the product is not in the cart)
+// lineIndex(lines []internal.TCartLine, productID int) -> int
+
+func lineIndex(lines []internal.TCartLine, productID int) int {
+	for i, line := range lines {
+		if line.ProductID == productID {
+			return i
+		}
+	}
+	return -1
+}
+
+// sellable returns a product checking it can be sold in the given quantity
+// sellable(productID, quantity int) -> (internal.TProduct, error)
+// Args:
+//		productID: Product id
+//		quantity:  Units to sell
+// Return:
+//		internal.TProduct: Product as resolved by the product service
+//		error: 			   Error raised during the execution (if exists)
+
+func (c *CartServiceDefault) sellable(productID, quantity int) (internal.TProduct, error) {
+	if quantity <= 0 {
+		return internal.TProduct{}, fmt.Errorf("%w: quantity must be positive", internal.ErrInvalidCartLine)
+	}
+	product, err := c.products.GetProductByID(productID)
+	if err != nil {
+		return internal.TProduct{}, err
+	}
+	if err := checkAvailable(product, quantity, c.clock.Now()); err != nil {
+		return internal.TProduct{}, err
+	}
+	return product, nil
+}
+
+// CreateCart creates a new empty cart
+// CreateCart(cart *internal.TCart) -> error
+// Args:
+//		cart: Cart to create. Only its customer is kept, the rest is updated in place
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (c *CartServiceDefault) CreateCart(cart *internal.TCart) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	*cart = internal.TCart{
+		Customer:  strings.TrimSpace(cart.Customer),
+		Status:    internal.CartOpen,
+		Lines:     []internal.TCartLine{},
+		CreatedAt: now,
+		UpdatedAt: now,
+		ExpiresAt: now.Add(c.ttl),
+	}
+	return c.repository.InsertNewCart(cart)
+}
+
+// GetCartByID returns a cart with its lines compared against the live products
+// GetCartByID(id int) -> (internal.TCart, error)
+// Args:
+//		id: Cart id
+// Return:
+//		internal.TCart: Cart found in the repository
+//		error: 			Error raised during the execution (if exists)
+
+func (c *CartServiceDefault) GetCartByID(id int) (internal.TCart, error) {
+	cart, err := c.load(id)
+	if err != nil {
+		return internal.TCart{}, err
+	}
+	c.refresh(&cart)
+	return cart, nil
+}
+
+// AddLine adds units of a product to a cart. A product already in the cart keeps the price
+// of its line
+// AddLine(id, productID, quantity int) -> (internal.TCart, error)
+// Args:
+//		id: 	   Cart id
+//		productID: Product id
+//		quantity:  Units to add
+// Return:
+//		internal.TCart: Updated cart
+//		error: 			Error raised during the execution (if exists)
+
+func (c *CartServiceDefault) AddLine(id, productID, quantity int) (internal.TCart, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cart, err := c.loadOpen(id)
+	if err != nil {
+		return internal.TCart{}, err
+	}
+
+	/* The whole line must be available */
+	if quantity <= 0 {
+		return internal.TCart{}, fmt.Errorf("%w: quantity must be positive", internal.ErrInvalidCartLine)
+	}
+	index := lineIndex(cart.Lines, productID)
+	total := quantity
+	if index >= 0 {
+		total += cart.Lines[index].Quantity
+	}
+	product, err := c.sellable(productID, total)
+	if err != nil {
+		return internal.TCart{}, err
+	}
+
+	/* Snapshot the price of new lines */
+	if index >= 0 {
+		cart.Lines[index].Quantity = total
+	} else {
+		cart.Lines = append(cart.Lines, internal.TCartLine{ProductID: productID, Quantity: quantity, UnitPrice: livePrice(product)})
+	}
+	if err := c.save(&cart); err != nil {
+		return internal.TCart{}, err
+	}
+	return cart, nil
+}
+
+// UpdateLine sets the units of a line taking the live price of the product again
+// UpdateLine(id, productID, quantity int) -> (internal.TCart, error)
+// Args:
+//		id: 	   Cart id
+//		productID: Product id of the line
+//		quantity:  New units of the line
+// Return:
+//		internal.TCart: Updated cart
+//		error: 			Error raised during the execution (if exists)
+
+func (c *CartServiceDefault) UpdateLine(id, productID, quantity int) (internal.TCart, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cart, err := c.loadOpen(id)
+	if err != nil {
+		return internal.TCart{}, err
+	}
+	index := lineIndex(cart.Lines, productID)
+	if index < 0 {
+		return internal.TCart{}, internal.ErrCartLineNotExists
+	}
+	product, err := c.sellable(productID, quantity)
+	if err != nil {
+		return internal.TCart{}, err
+	}
+
+	cart.Lines[index].Quantity = quantity
+	cart.Lines[index].UnitPrice = livePrice(product)
+	if err := c.save(&cart); err != nil {
+		return internal.TCart{}, err
+	}
+	return cart, nil
+}
+
+// RemoveLine removes a line from a cart
+// RemoveLine(id, productID int) -> (internal.TCart, error)
+// Args:
+//		id: 	   Cart id
+//		productID: Product id of the line
+// Return:
+//		internal.TCart: Updated cart
+//		error: 			Error raised during the execution (if exists)
+
+func (c *CartServiceDefault) RemoveLine(id, productID int) (internal.TCart, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cart, err := c.loadOpen(id)
+	if err != nil {
+		return internal.TCart{}, err
+	}
+	index := lineIndex(cart.Lines, productID)
+	if index < 0 {
+		return internal.TCart{}, internal.ErrCartLineNotExists
+	}
+
+	cart.Lines = append(cart.Lines[:index], cart.Lines[index+1:]...)
+	if err := c.save(&cart); err != nil {
+		return internal.TCart{}, err
+	}
+	return cart, nil
+}
+
+// checkoutOrder returns the order a previous checkout of a cart left behind, if it is still in
+// use: a draft it could not place, or an order placed before the cart could be closed
+// checkoutOrder(cart internal.TCart) -> (internal.TOrder, bool, error)
+// Args:
+//		cart: Cart being checked out
+// Return:
+//		internal.TOrder: Order of the previous checkout
+//		bool: 			 Whether the cart has an order in use
+//		error: 			 Error raised during the execution (if exists)
+
+func (c *CartServiceDefault) checkoutOrder(cart internal.TCart) (internal.TOrder, bool, error) {
+	if cart.OrderID == 0 {
+		return internal.TOrder{}, false, nil
+	}
+	order, err := c.orders.GetOrderByID(cart.OrderID)
+	if err == internal.ErrOrderNotExists {
+		return internal.TOrder{}, false, nil
+	} else if err != nil {
+		return internal.TOrder{}, false, err
+	}
+	if order.Status == internal.OrderCancelled {
+		return internal.TOrder{}, false, nil
+	}
+	return order, true, nil
+}
+
+// Checkout converts a cart into a placed order. The lines are checked against the live
+// products first: a changed price must be accepted by updating its line. The order is
+// recorded on the cart before it is placed, so a checkout retried after a failure resumes
+// the same order instead of creating another one. A draft which can't be placed is
+// cancelled, and the next checkout creates a new order
+// Checkout(id int) -> (internal.TOrder, error)
+// Args:
+//		id: Cart id
+// Return:
+//		internal.TOrder: Placed order
+//		error: 			 Error raised during the execution (if exists)
+
+func (c *CartServiceDefault) Checkout(id int) (internal.TOrder, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cart, err := c.loadOpen(id)
+	if err != nil {
+		return internal.TOrder{}, err
+	}
+
+	/* An order placed by a previous checkout only needs the cart closed */
+	order, ok, err := c.checkoutOrder(cart)
+	if err != nil {
+		return internal.TOrder{}, err
+	}
+	if ok && order.Status != internal.OrderDraft {
+		if err := c.close(&cart, order); err != nil {
+			return internal.TOrder{}, err
+		}
+		return order, nil
+	}
+
+	if len(cart.Lines) == 0 {
+		return internal.TOrder{}, fmt.Errorf("%w: %s", internal.ErrEmptyField, "Lines")
+	}
+
+	/* Re-validate the lines against the live products */
+	c.refresh(&cart)
+	items := make([]internal.TOrderItem, 0, len(cart.Lines))
+	for _, line := range cart.Lines {
+		if line.Unavailable {
+			return internal.TOrder{}, fmt.Errorf("%w: product %d is not available", internal.ErrCartOutdated, line.ProductID)
+		}
+		if line.PriceChanged {
+			return internal.TOrder{}, fmt.Errorf("%w: product %d changed its price", internal.ErrCartOutdated, line.ProductID)
+		}
+		items = append(items, internal.TOrderItem{ProductID: line.ProductID, Quantity: line.Quantity})
+	}
+
+	/* Create the draft, or bring the one left behind up to date, and record it on the cart */
+	order.Customer, order.Items = cart.Customer, items
+	if ok {
+		err = c.orders.UpdateOrder(&order)
+	} else {
+		err = c.orders.InsertNewOrder(&order)
+	}
+	if err != nil {
+		return internal.TOrder{}, err
+	}
+	if cart.OrderID != order.ID {
+		cart.OrderID = order.ID
+		if err := c.save(&cart); err != nil {
+			c.orders.CancelOrder(order.ID)
+			return internal.TOrder{}, err
+		}
+	}
+
+	/* Place the order. A draft which can't be placed is cancelled */
+	placed, err := c.orders.PlaceOrder(order.ID)
+	if err != nil {
+		c.orders.CancelOrder(order.ID)
+		return internal.TOrder{}, err
+	}
+	if err := c.close(&cart, placed); err != nil {
+		return internal.TOrder{}, err
+	}
+	return placed, nil
+}
+
+// close closes a cart checked out into an order
+// close(cart *internal.TCart, order internal.TOrder) -> error
+// Args:
+//		cart:  Cart to close. It is updated in place
+//		order: Order the cart was converted into
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (c *CartServiceDefault) close(cart *internal.TCart, order internal.TOrder) error {
+	cart.Status = internal.CartCheckedOut
+	cart.OrderID = order.ID
+	return c.save(cart)
+}
@@ -0,0 +1,435 @@
+package service
+
+import (
+	"fmt"
+	"proyecto/internal"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type CategoryServiceDefault struct {
+	repository   internal.CategoryRepository // Repository of the categories
+	products     internal.ProductService     // Service of the products assigned to the categories
+	deletePolicy string                      // What happens to the products of a deleted category
+	mu           sync.Mutex                  // Serializes the taxonomy updates
+}
+
+// NewCategoryServiceDefault creates a new CategoryServiceDefault instance
+// NewCategoryServiceDefault(cr internal.CategoryRepository, ps internal.ProductService, deletePolicy string) -> *CategoryServiceDefault
+// Args:
+//		cr: 		  Category repository
+//		ps: 		  Product service used to resolve the assigned products
+//		deletePolicy: One of internal.CategoryDeleteBlock, internal.CategoryDeleteReassign or internal.CategoryDeleteOrphan.
+//					  Unknown values fall back to internal.CategoryDeleteBlock
+// Return:
+//		*CategoryServiceDefault: New CategoryServiceDefault instance
+
+func NewCategoryServiceDefault(cr internal.CategoryRepository, ps internal.ProductService, deletePolicy string) *CategoryServiceDefault {
+	switch deletePolicy {
+	case internal.CategoryDeleteReassign, internal.CategoryDeleteOrphan:
+	default:
+		deletePolicy = internal.CategoryDeleteBlock
+	}
+	return &CategoryServiceDefault{
+		repository:   cr,
+		products:     ps,
+		deletePolicy: deletePolicy,
+	}
+}
+
+// GetAllCategories returns all the categories
+// GetAllCategories() -> ([]internal.TCategory, error)
+// Return:
+//		[]internal.TCategory: Slice of categories
+//		error: 				  Error raised during the execution (if exists)
+
+func (c *CategoryServiceDefault) GetAllCategories() ([]internal.TCategory, error) {
+	return c.repository.GetAllCategories()
+}
+
+// GetCategoryByID returns a category by its id
+// GetCategoryByID(id int) -> (internal.TCategory, error)
+// Args:
+//		id: Category id
+// Return:
+//		internal.TCategory: Category found in the repository
+//		error: 				Error raised during the execution (if exists)
+
+func (c *CategoryServiceDefault) GetCategoryByID(id int) (internal.TCategory, error) {
+	category, err := c.repository.GetCategoryByID(id)
+	if err == internal.ErrCategoryNotFound {
+		return internal.TCategory{}, internal.ErrCategoryNotExists
+	}
+	return category, err
+}
+
+// validateCategory checks the name and the parent of a category against the taxonomy
+// validateCategory(category internal.TCategory, categories []internal.TCategory) -> error
+// Args:
+//		category:   Category to validate
+//		categories: Current taxonomy
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func validateCategory(category internal.TCategory, categories []internal.TCategory) error {
+	/* Empty fields validation */
+	if strings.TrimSpace(category.Name) == "" {
+		return fmt.Errorf("%w: %s", internal.ErrEmptyField, "Name")
+	}
+
+	/* Index the taxonomy by id */
+	byID := make(map[int]internal.TCategory, len(categories))
+	for _, value := range categories {
+		byID[value.ID] = value
+	}
+
+	/* Parent validation. Walking up from the parent must never reach the category itself */
+	if category.ParentID != nil {
+		if _, ok := byID[*category.ParentID]; !ok {
+			return internal.ErrInvalidCategoryParent
+		}
+		steps := 0
+		for ancestor := category.ParentID; ancestor != nil && steps <= len(byID); ancestor = byID[*ancestor].ParentID {
+			if *ancestor == category.ID {
+				return internal.ErrCategoryCycle
+			}
+			steps++
+		}
+	}
+
+	/* Sibling names must be unique */
+	for _, value := range categories {
+		if value.ID != category.ID && sameParent(value.ParentID, category.ParentID) &&
+			strings.EqualFold(value.Name, category.Name) {
+			return internal.ErrCategoryAlreadyExists
+		}
+	}
+	return nil
+}
+
+// sameParent checks if two parent references point to the same category
+// sameParent(a, b *int) -> bool
+
+func sameParent(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// InsertNewCategory inserts a new category with no products
+// InsertNewCategory(category *internal.TCategory) -> error
+// Args:
+//		category: Category to insert
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (c *CategoryServiceDefault) InsertNewCategory(category *internal.TCategory) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	/* Category validation */
+	categories, err := c.repository.GetAllCategories()
+	if err != nil {
+		return err
+	}
+	category.ID = 0
+	if err := validateCategory(*category, categories); err != nil {
+		return err
+	}
+
+	/* Products are assigned through AssignProductCategories */
+	category.ProductIDs = []int{}
+	return c.repository.InsertNewCategory(category)
+}
+
+// UpdateCategory updates the name and the parent of a category
+// UpdateCategory(category *internal.TCategory) -> error
+// Args:
+//		category: Category to update. Its products are kept
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (c *CategoryServiceDefault) UpdateCategory(category *internal.TCategory) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	/* Retrieve the stored category */
+	current, err := c.repository.GetCategoryByID(category.ID)
+	if err == internal.ErrCategoryNotFound {
+		return internal.ErrCategoryNotExists
+	} else if err != nil {
+		return err
+	}
+
+	/* Category validation */
+	categories, err := c.repository.GetAllCategories()
+	if err != nil {
+		return err
+	}
+	if err := validateCategory(*category, categories); err != nil {
+		return err
+	}
+
+	/* Update the category */
+	category.ProductIDs = current.ProductIDs
+	return c.repository.UpdateCategories([]internal.TCategory{*category})
+}
+
+// DeleteCategory deletes a category. Its children move to its parent and its products
+// follow the delete policy
+// DeleteCategory(id int) -> error
+// Args:
+//		id: Category id
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (c *CategoryServiceDefault) DeleteCategory(id int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	/* Retrieve the category */
+	category, err := c.repository.GetCategoryByID(id)
+	if err == internal.ErrCategoryNotFound {
+		return internal.ErrCategoryNotExists
+	} else if err != nil {
+		return err
+	}
+	categories, err := c.repository.GetAllCategories()
+	if err != nil {
+		return err
+	}
+
+	/* Apply the delete policy to the products */
+	var changes []internal.TCategory
+	if len(category.ProductIDs) > 0 {
+		switch c.deletePolicy {
+		case internal.CategoryDeleteOrphan:
+		case internal.CategoryDeleteReassign:
+			if category.ParentID == nil {
+				return fmt.Errorf("%w: a root category can't reassign its products", internal.ErrCategoryHasProducts)
+			}
+			parent, err := c.repository.GetCategoryByID(*category.ParentID)
+			if err != nil {
+				return err
+			}
+			parent.ProductIDs = mergeIDs(parent.ProductIDs, category.ProductIDs)
+			changes = append(changes, parent)
+		default:
+			return internal.ErrCategoryHasProducts
+		}
+	}
+
+	/* The children move to the parent of the deleted category */
+	for _, child := range categories {
+		if child.ParentID != nil && *child.ParentID == id {
+			child.ParentID = category.ParentID
+			changes = append(changes, child)
+		}
+	}
+
+	/* Save the changes and delete the category */
+	if len(changes) > 0 {
+		if err := c.repository.UpdateCategories(changes); err != nil {
+			return err
+		}
+	}
+	return c.repository.DeleteCategory(id)
+}
+
+// mergeIDs returns the sorted union of two slices of ids
+// mergeIDs(a, b []int) -> []int
+
+func mergeIDs(a, b []int) []int {
+	set := make(map[int]bool, len(a)+len(b))
+	for _, id := range append(append([]int{}, a...), b...) {
+		set[id] = true
+	}
+	merged := make([]int, 0, len(set))
+	for id := range set {
+		merged = append(merged, id)
+	}
+	sort.Ints(merged)
+	return merged
+}
+
+// GetProductsByCategory returns the products of a category
+// GetProductsByCategory(id int, includeDescendants bool) -> ([]internal.TProduct, error)
+// Args:
+//		id: 				Category id
+//		includeDescendants: Also return the products of the descendant categories
+// Return:
+//		[]internal.TProduct: Products ordered by id
+//		error: 				 Error raised during the execution (if exists)
+
+func (c *CategoryServiceDefault) GetProductsByCategory(id int, includeDescendants bool) ([]internal.TProduct, error) {
+	/* Retrieve the category */
+	category, err := c.repository.GetCategoryByID(id)
+	if err == internal.ErrCategoryNotFound {
+		return nil, internal.ErrCategoryNotExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	/* Collect the product ids of the category (and its descendants) */
+	productIDs := category.ProductIDs
+	if includeDescendants {
+		categories, err := c.repository.GetAllCategories()
+		if err != nil {
+			return nil, err
+		}
+		pending := []int{id}
+		for len(pending) > 0 {
+			parentID := pending[0]
+			pending = pending[1:]
+			for _, child := range categories {
+				if child.ParentID != nil && *child.ParentID == parentID {
+					productIDs = mergeIDs(productIDs, child.ProductIDs)
+					pending = append(pending, child.ID)
+				}
+			}
+		}
+	}
+
+	/* Resolve the products. Deleted products are skipped */
+	products := make([]internal.TProduct, 0, len(productIDs))
+	for _, productID := range mergeIDs(productIDs, nil) {
+		product, err := c.products.GetProductByID(productID)
+		if err == internal.ErrProductNotExists {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+	return products, nil
+}
+
+// GetCategoriesByProduct returns the categories a product is assigned to
+// GetCategoriesByProduct(productID int) -> ([]internal.TCategory, error)
+// Args:
+//		productID: Product id
+// Return:
+//		[]internal.TCategory: Categories of the product
+//		error: 				  Error raised during the execution (if exists)
+
+func (c *CategoryServiceDefault) GetCategoriesByProduct(productID int) ([]internal.TCategory, error) {
+	/* Check the product exists */
+	if _, err := c.products.GetProductByID(productID); err != nil {
+		return nil, err
+	}
+
+	/* Filter the categories by product */
+	categories, err := c.repository.GetAllCategories()
+	if err != nil {
+		return nil, err
+	}
+	assigned := make([]internal.TCategory, 0)
+	for _, category := range categories {
+		for _, id := range category.ProductIDs {
+			if id == productID {
+				assigned = append(assigned, category)
+				break
+			}
+		}
+	}
+	return assigned, nil
+}
+
+// AssignProductCategories replaces the categories a product is assigned to
+// AssignProductCategories(productID int, categoryIDs []int) -> error
+// Args:
+//		productID: 	 Product id
+//		categoryIDs: Categories of the product. An empty slice removes every assignment
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (c *CategoryServiceDefault) AssignProductCategories(productID int, categoryIDs []int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	/* Check the product exists */
+	if _, err := c.products.GetProductByID(productID); err != nil {
+		return err
+	}
+
+	/* Check the categories exist */
+	categories, err := c.repository.GetAllCategories()
+	if err != nil {
+		return err
+	}
+	wanted := make(map[int]bool, len(categoryIDs))
+	for _, id := range categoryIDs {
+		wanted[id] = true
+	}
+	found := 0
+	for _, category := range categories {
+		if wanted[category.ID] {
+			found++
+		}
+	}
+	if found != len(wanted) {
+		return internal.ErrCategoryNotExists
+	}
+
+	/* Add or remove the product from every category which changes */
+	var changes []internal.TCategory
+	for _, category := range categories {
+		has := false
+		remaining := make([]int, 0, len(category.ProductIDs))
+		for _, id := range category.ProductIDs {
+			if id == productID {
+				has = true
+				continue
+			}
+			remaining = append(remaining, id)
+		}
+		switch {
+		case wanted[category.ID] && !has:
+			category.ProductIDs = mergeIDs(category.ProductIDs, []int{productID})
+			changes = append(changes, category)
+		case !wanted[category.ID] && has:
+			category.ProductIDs = remaining
+			changes = append(changes, category)
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return c.repository.UpdateCategories(changes)
+}
+
+// RemoveProduct removes a deleted product from every category it is assigned to
+// RemoveProduct(productID int) -> error
+// Args:
+//		productID: Id of the deleted product
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (c *CategoryServiceDefault) RemoveProduct(productID int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	categories, err := c.repository.GetAllCategories()
+	if err != nil {
+		return err
+	}
+	var changes []internal.TCategory
+	for _, category := range categories {
+		remaining := make([]int, 0, len(category.ProductIDs))
+		for _, id := range category.ProductIDs {
+			if id != productID {
+				remaining = append(remaining, id)
+			}
+		}
+		if len(remaining) != len(category.ProductIDs) {
+			category.ProductIDs = remaining
+			changes = append(changes, category)
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return c.repository.UpdateCategories(changes)
+}
@@ -0,0 +1,968 @@
+package service
+
+import (
+	"fmt"
+	"proyecto/internal"
+	"proyecto/internal/clock"
+	"sort"
+	"sync"
+	"time"
+)
+
+type MovementServiceDefault struct {
+	products   internal.ProductRepository   // Repository of the products affected by the movements
+	movements  internal.MovementRepository  // Repository of the ledger
+	warehouses internal.WarehouseRepository // Repository of the warehouses (optional, only the default one exists without it)
+	lots       internal.LotRepository       // Repository of the lots (optional, the stock is not tracked by lot without it)
+	lotPolicy  string                       // Order the lots are consumed in
+	clock      internal.Clock               // Clock the movements are dated with
+	events     internal.ProductEventBus     // Bus the quantity changes are published on (optional)
+	reader     internal.ProductService      // Service the changed products are read through before publishing them
+	mu         sync.Mutex                   // Serializes the ledger updates
+}
+
+// NewMovementServiceDefault creates a new MovementServiceDefault instance
+// NewMovementServiceDefault(pr internal.ProductRepository, mr internal.MovementRepository) -> *MovementServiceDefault
+// Args:
+//		pr: Product repository whose quantities are kept in sync with the ledger
+//		mr: Movement repository where the ledger is stored
+// Return:
+//		*MovementServiceDefault: New MovementServiceDefault instance
+
+func NewMovementServiceDefault(pr internal.ProductRepository, mr internal.MovementRepository) *MovementServiceDefault {
+	return &MovementServiceDefault{
+		products:  pr,
+		movements: mr,
+		lotPolicy: internal.LotPolicyFIFO,
+		clock:     clock.NewClockSystem(),
+	}
+}
+
+// SetClock sets the clock the movements are dated with and the lots are expired against
+// SetClock(c internal.Clock)
+// Args:
+//		c: Clock to use
+
+func (m *MovementServiceDefault) SetClock(c internal.Clock) {
+	m.clock = c
+}
+
+// SetLots sets the repository of the lots the stock is tracked by. Once set, every receipt
+// creates a lot and the outbound movements consume the lots following the policy
+// SetLots(lr internal.LotRepository, policy string)
+// Args:
+//		lr: 	Lot repository
+//		policy: One of internal.LotPolicyFIFO or internal.LotPolicyFEFO. Unknown values fall back
+//				to internal.LotPolicyFIFO
+
+func (m *MovementServiceDefault) SetLots(lr internal.LotRepository, policy string) {
+	if policy != internal.LotPolicyFEFO {
+		policy = internal.LotPolicyFIFO
+	}
+	m.lots = lr
+	m.lotPolicy = policy
+}
+
+// SetWarehouses sets the repository of the warehouses the stock is located at
+// SetWarehouses(wr internal.WarehouseRepository)
+// Args:
+//		wr: Warehouse repository
+
+func (m *MovementServiceDefault) SetWarehouses(wr internal.WarehouseRepository) {
+	m.warehouses = wr
+}
+
+// SetEvents sets the bus the quantity changes of the products are published on
+// SetEvents(bus internal.ProductEventBus, ps internal.ProductService)
+// Args:
+//		bus: Product event bus
+//		ps:  Product service the changed products are read through, so they are published as the clients read them
+
+func (m *MovementServiceDefault) SetEvents(bus internal.ProductEventBus, ps internal.ProductService) {
+	m.events = bus
+	m.reader = ps
+}
+
+// emitQuantity publishes the quantity change of a product (if there is a bus)
+// emitQuantity(productID int)
+// Args:
+//		productID: Product whose quantity changed
+
+func (m *MovementServiceDefault) emitQuantity(productID int) {
+	if m.events == nil || m.reader == nil {
+		return
+	}
+	if product, err := m.reader.GetProductByID(productID); err == nil {
+		emitProductEvent(m.events, internal.ProductEventUpdated, productID, &product, internal.ProductChangeQuantity)
+	}
+}
+
+// checkWarehouse checks a warehouse exists
+// checkWarehouse(id int) -> error
+// Args:
+//		id: Warehouse id
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (m *MovementServiceDefault) checkWarehouse(id int) error {
+	if m.warehouses == nil {
+		if id != internal.DefaultWarehouseID {
+			return internal.ErrWarehouseNotExists
+		}
+		return nil
+	}
+	if _, err := m.warehouses.GetWarehouseByID(id); err == internal.ErrWarehouseNotFound {
+		return internal.ErrWarehouseNotExists
+	} else {
+		return err
+	}
+}
+
+// warehouseOf returns the warehouse a movement affects. Movements recorded before the
+// warehouses existed belong to the default one
+// warehouseOf(movement internal.TMovement) -> int
+
+func warehouseOf(movement internal.TMovement) int {
+	if movement.WarehouseID == 0 {
+		return internal.DefaultWarehouseID
+	}
+	return movement.WarehouseID
+}
+
+// signedQuantity returns the quantity delta a movement applies to the stock
+// signedQuantity(movementType string, quantity int) -> (int, error)
+// Args:
+//		movementType: Type of the movement
+//		quantity: 	  Quantity of the movement. Receipts, sales and write-offs take a positive
+//					  amount, corrections take a signed one
+// Return:
+//		int:   Signed quantity delta
+//		error: Error raised during the execution (if exists)
+
+func signedQuantity(movementType string, quantity int) (int, error) {
+	switch movementType {
+	case internal.MovementReceipt:
+		if quantity <= 0 {
+			return 0, internal.ErrInvalidMovementQuantity
+		}
+		return quantity, nil
+	case internal.MovementSale, internal.MovementWriteOff:
+		if quantity <= 0 {
+			return 0, internal.ErrInvalidMovementQuantity
+		}
+		return -quantity, nil
+	case internal.MovementCorrection:
+		if quantity == 0 {
+			return 0, internal.ErrInvalidMovementQuantity
+		}
+		return quantity, nil
+	default:
+		return 0, internal.ErrInvalidMovementType
+	}
+}
+
+// ledgerQuantity returns the stock of a product according to its movements
+// ledgerQuantity(movements []internal.TMovement) -> int
+// Args:
+//		movements: Movements of the product
+// Return:
+//		int: Sum of the movement quantities
+
+func ledgerQuantity(movements []internal.TMovement) int {
+	var total int
+	for _, movement := range movements {
+		total += movement.Quantity
+	}
+	return total
+}
+
+// warehouseQuantities returns the stock of a product by warehouse according to its movements
+// warehouseQuantities(movements []internal.TMovement) -> map[int]int
+// Args:
+//		movements: Movements of the product
+// Return:
+//		map[int]int: Sum of the movement quantities by warehouse id
+
+func warehouseQuantities(movements []internal.TMovement) map[int]int {
+	quantities := make(map[int]int)
+	for _, movement := range movements {
+		quantities[warehouseOf(movement)] += movement.Quantity
+	}
+	return quantities
+}
+
+// history returns the movements of a product. Products created before the ledger existed
+// get an opening balance on the default warehouse, returned apart to be recorded with the
+// next batch
+// history(product internal.TProduct, now time.Time) -> ([]internal.TMovement, []internal.TMovement, error)
+// Args:
+//		product: Product as stored
+//		now: 	 Moment the opening balance is dated with
+// Return:
+//		[]internal.TMovement: Movements of the product including the opening balance
+//		[]internal.TMovement: Opening balance to record (empty if the product has history)
+//		error: 				  Error raised during the execution (if exists)
+
+func (m *MovementServiceDefault) history(product internal.TProduct, now time.Time) ([]internal.TMovement, []internal.TMovement, error) {
+	history, err := m.movements.GetMovementsByProduct(product.ID, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(history) != 0 || product.Quantity == 0 {
+		return history, nil, nil
+	}
+	opening := []internal.TMovement{{
+		ProductID:   product.ID,
+		WarehouseID: internal.DefaultWarehouseID,
+		Type:        internal.MovementCorrection,
+		Quantity:    product.Quantity,
+		Reason:      "opening balance",
+		Date:        now,
+	}}
+	return opening, opening, nil
+}
+
+// getProduct returns a product as stored
+// getProduct(productID int) -> (internal.TProduct, error)
+
+func (m *MovementServiceDefault) getProduct(productID int) (internal.TProduct, error) {
+	product, err := m.products.GetProductByID(productID)
+	if err == internal.ErrProductNotFound {
+		return internal.TProduct{}, internal.ErrProductNotExists
+	}
+	return product, err
+}
+
+// lotDraw is the quantity an outbound movement takes from a lot
+type lotDraw struct {
+	lot      internal.TLot // Lot with its quantity already reduced
+	quantity int           // Units taken from the lot
+}
+
+// productLots returns the lots of a product, none if the stock is not tracked by lot
+// productLots(productID int) -> ([]internal.TLot, error)
+
+func (m *MovementServiceDefault) productLots(productID int) ([]internal.TLot, error) {
+	if m.lots == nil {
+		return nil, nil
+	}
+	return m.lots.GetLotsByProduct(productID)
+}
+
+// sortLots sorts the lots in the order the policy consumes them. Lots with an unreadable
+// expiration go last under FEFO
+// sortLots(lots []internal.TLot, policy string)
+// Args:
+//		lots:   Lots to sort
+//		policy: Consumption policy
+
+func sortLots(lots []internal.TLot, policy string) {
+	sort.SliceStable(lots, func(i, j int) bool {
+		if policy == internal.LotPolicyFEFO {
+			ei, erri := expirationTime(lots[i].Expiration)
+			ej, errj := expirationTime(lots[j].Expiration)
+			switch {
+			case erri != nil || errj != nil:
+				if (erri == nil) != (errj == nil) {
+					return erri == nil
+				}
+			case !ei.Equal(ej):
+				return ei.Before(ej)
+			}
+		}
+		if !lots[i].ReceivedAt.Equal(lots[j].ReceivedAt) {
+			return lots[i].ReceivedAt.Before(lots[j].ReceivedAt)
+		}
+		return lots[i].ID < lots[j].ID
+	})
+}
+
+// drawLots picks the lots an outbound movement consumes. The stock received before the lots
+// existed is not tracked by lot and, being the oldest one, goes out first
+// drawLots(lots []internal.TLot, warehouseID, stock, quantity, lotID int) -> ([]lotDraw, error)
+// Args:
+//		lots: 		 Lots of the product
+//		warehouseID: Warehouse the stock leaves
+//		stock: 		 Stock of the product in the warehouse according to the ledger
+//		quantity: 	 Units to take
+//		lotID: 		 Lot to take the units from. Zero lets the policy choose
+// Return:
+//		[]lotDraw: Lots affected by the movement
+//		error: 	   Error raised during the execution (if exists)
+
+func (m *MovementServiceDefault) drawLots(lots []internal.TLot, warehouseID, stock, quantity, lotID int) ([]lotDraw, error) {
+	/* Lots still in stock in the warehouse */
+	open := make([]internal.TLot, 0)
+	for _, lot := range lots {
+		if lot.WarehouseID == warehouseID && lot.Quantity > 0 {
+			open = append(open, lot)
+			stock -= lot.Quantity
+		}
+	}
+
+	/* A specific lot was requested */
+	if lotID != 0 {
+		for _, lot := range open {
+			if lot.ID != lotID {
+				continue
+			}
+			if lot.Quantity < quantity {
+				return nil, internal.ErrInsufficientStock
+			}
+			lot.Quantity -= quantity
+			return []lotDraw{{lot: lot, quantity: quantity}}, nil
+		}
+		return nil, internal.ErrLotNotExists
+	}
+
+	/* The untracked stock goes first, then the lots in the policy order */
+	if stock > 0 {
+		quantity -= min(stock, quantity)
+	}
+	sortLots(open, m.lotPolicy)
+	draws := make([]lotDraw, 0)
+	for _, lot := range open {
+		if quantity == 0 {
+			break
+		}
+		taken := min(lot.Quantity, quantity)
+		lot.Quantity -= taken
+		quantity -= taken
+		draws = append(draws, lotDraw{lot: lot, quantity: taken})
+	}
+	return draws, nil
+}
+
+// earliestExpiration returns the earliest expiration among the lots still in stock
+// earliestExpiration(lots []internal.TLot) -> (string, bool)
+// Args:
+//		lots: Lots of the product
+// Return:
+//		string: Earliest expiration with the format dd/mm/yyyy
+//		bool: 	False if no lot is in stock
+
+func earliestExpiration(lots []internal.TLot) (string, bool) {
+	var earliest string
+	var earliestTime time.Time
+	for _, lot := range lots {
+		if lot.Quantity <= 0 {
+			continue
+		}
+		expiration, err := expirationTime(lot.Expiration)
+		if err != nil {
+			continue
+		}
+		if earliest == "" || expiration.Before(earliestTime) {
+			earliest, earliestTime = lot.Expiration, expiration
+		}
+	}
+	return earliest, earliest != ""
+}
+
+// mergeLots returns the lots of a product with the changes applied
+// mergeLots(lots, changes []internal.TLot) -> []internal.TLot
+
+func mergeLots(lots, changes []internal.TLot) []internal.TLot {
+	merged := make([]internal.TLot, 0, len(lots)+len(changes))
+	changed := make(map[int]bool)
+	for _, lot := range changes {
+		changed[lot.ID] = true
+		merged = append(merged, lot)
+	}
+	for _, lot := range lots {
+		if !changed[lot.ID] {
+			merged = append(merged, lot)
+		}
+	}
+	return merged
+}
+
+// lotsOf returns the lots of the draws
+// lotsOf(draws []lotDraw) -> []internal.TLot
+
+func lotsOf(draws []lotDraw) []internal.TLot {
+	lots := make([]internal.TLot, 0, len(draws))
+	for _, draw := range draws {
+		lots = append(lots, draw.lot)
+	}
+	return lots
+}
+
+// validateMovement checks a movement can be recorded and defaults its warehouse
+// validateMovement(movement *internal.TMovement) -> (int, error)
+// Args:
+//		movement: Movement to validate
+// Return:
+//		int:   Signed quantity delta the movement applies to the stock
+//		error: Error raised during the execution (if exists)
+
+func (m *MovementServiceDefault) validateMovement(movement *internal.TMovement) (int, error) {
+	delta, err := signedQuantity(movement.Type, movement.Quantity)
+	if err != nil {
+		return 0, err
+	}
+	if movement.Reason == "" {
+		return 0, fmt.Errorf("%w: %s", internal.ErrEmptyField, "Reason")
+	}
+	if m.lots != nil && movement.Type == internal.MovementReceipt {
+		if movement.Expiration == "" {
+			return 0, fmt.Errorf("%w: %s", internal.ErrEmptyField, "Expiration")
+		}
+		if _, err := expirationTime(movement.Expiration); err != nil {
+			return 0, err
+		}
+	}
+	if movement.WarehouseID == 0 {
+		movement.WarehouseID = internal.DefaultWarehouseID
+	}
+	if err := m.checkWarehouse(movement.WarehouseID); err != nil {
+		return 0, err
+	}
+	return delta, nil
+}
+
+// PostMovement records a new movement on the ledger and updates the product quantity. When
+// the stock is tracked by lot, receipts create a lot and outbound movements consume them
+// PostMovement(movement *internal.TMovement) -> error
+// Args:
+//		movement: Movement to record. Without a warehouse it affects the default one. On success
+//				  its id, date, signed quantity and lot are updated
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (m *MovementServiceDefault) PostMovement(movement *internal.TMovement) error {
+	delta, err := m.validateMovement(movement)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.post([]*internal.TMovement{movement}, []int{delta})
+}
+
+// PostMovements records several movements at once: either all of them are recorded or none
+// PostMovements(movements []internal.TMovement) -> error
+// Args:
+//		movements: Movements to record, applied in order. On success they are updated like
+//				   in PostMovement
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (m *MovementServiceDefault) PostMovements(movements []internal.TMovement) error {
+	pending := make([]*internal.TMovement, len(movements))
+	deltas := make([]int, len(movements))
+	for i := range movements {
+		delta, err := m.validateMovement(&movements[i])
+		if err != nil {
+			return err
+		}
+		pending[i], deltas[i] = &movements[i], delta
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.post(pending, deltas)
+}
+
+// productState is the state of a product while a batch of movements is applied
+type productState struct {
+	history []internal.TMovement // Movements of the product including the opening balance
+	stock   map[int]int          // Stock by warehouse including the movements of the batch
+	lots    []internal.TLot      // Lots including the changes of the batch
+	delta   int                  // Quantity delta of the movements of the batch
+}
+
+// post records a batch of validated movements with a single write on each repository. The
+// stock of every movement is checked against the ones before it, so the batch fails as a
+// whole. The caller must hold the ledger lock
+// post(movements []*internal.TMovement, deltas []int) -> error
+// Args:
+//		movements: Movements to record
+//		deltas:    Signed quantity each movement applies to the stock
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (m *MovementServiceDefault) post(movements []*internal.TMovement, deltas []int) error {
+	now := m.clock.Now()
+	states := make(map[int]*productState)
+	order := make([]int, 0)
+	batch := make([]internal.TMovement, 0, len(movements))
+	positions := make([]int, len(movements))
+	changed := make(map[int]bool)
+
+	for i, movement := range movements {
+		/* Retrieve the product, its ledger and its lots the first time it shows up */
+		state, ok := states[movement.ProductID]
+		if !ok {
+			product, err := m.getProduct(movement.ProductID)
+			if err != nil {
+				return err
+			}
+			history, opening, err := m.history(product, now)
+			if err != nil {
+				return err
+			}
+			lots, err := m.productLots(product.ID)
+			if err != nil {
+				return err
+			}
+			state = &productState{history: history, stock: warehouseQuantities(history), lots: lots}
+			states[product.ID] = state
+			order = append(order, product.ID)
+			batch = append(batch, opening...)
+		}
+
+		/* Stock validation on the affected warehouse */
+		stock := state.stock[movement.WarehouseID]
+		if stock+deltas[i] < 0 {
+			return internal.ErrInsufficientStock
+		}
+
+		/* Outbound movements consume the lots */
+		if deltas[i] < 0 && m.lots != nil {
+			draws, err := m.drawLots(state.lots, movement.WarehouseID, stock, -deltas[i], movement.LotID)
+			if err != nil {
+				return err
+			}
+			state.lots = mergeLots(state.lots, lotsOf(draws))
+			for _, draw := range draws {
+				changed[draw.lot.ID] = true
+			}
+			if len(draws) == 1 {
+				movement.LotID = draws[0].lot.ID
+			}
+		} else {
+			movement.LotID = 0
+		}
+		state.stock[movement.WarehouseID] += deltas[i]
+		state.delta += deltas[i]
+
+		movement.Quantity = deltas[i]
+		if movement.Date.IsZero() {
+			movement.Date = now
+		}
+		positions[i] = len(batch)
+		batch = append(batch, *movement)
+	}
+
+	/* Record the movements */
+	if err := m.movements.InsertNewMovements(batch); err != nil {
+		return err
+	}
+	for i, movement := range movements {
+		*movement = batch[positions[i]]
+	}
+
+	/* Save the consumed lots along with the ones the receipts create */
+	if m.lots != nil {
+		changes := make([]internal.TLot, 0)
+		for _, productID := range order {
+			for _, lot := range states[productID].lots {
+				if changed[lot.ID] {
+					changes = append(changes, lot)
+				}
+			}
+		}
+		receipts := make([]*internal.TMovement, 0)
+		for _, movement := range movements {
+			if movement.Type != internal.MovementReceipt {
+				continue
+			}
+			receipts = append(receipts, movement)
+			changes = append(changes, internal.TLot{
+				ProductID:        movement.ProductID,
+				WarehouseID:      movement.WarehouseID,
+				Quantity:         movement.Quantity,
+				ReceivedQuantity: movement.Quantity,
+				Expiration:       movement.Expiration,
+				ReceivedAt:       movement.Date,
+				MovementID:       movement.ID,
+			})
+		}
+		if len(changes) != 0 {
+			if err := m.lots.SaveLots(changes); err != nil {
+				return err
+			}
+		}
+		created := changes[len(changes)-len(receipts):]
+		for i, movement := range receipts {
+			movement.LotID = created[i].ID
+			state := states[movement.ProductID]
+			state.lots = append(state.lots, created[i])
+		}
+	}
+
+	/* Keep the product quantities in sync with the ledger and their expiration with the lots. The
+	   product is read again so the changes made meanwhile by the product service are kept */
+	for _, productID := range order {
+		state := states[productID]
+		product, err := m.getProduct(productID)
+		if err != nil {
+			return err
+		}
+		product.Quantity = ledgerQuantity(state.history) + state.delta
+		if expiration, ok := earliestExpiration(state.lots); ok {
+			product.Expiration = expiration
+		}
+		if err := m.products.UpdateProduct(&product); err != nil {
+			return err
+		}
+		m.emitQuantity(productID)
+	}
+	return nil
+}
+
+// TransferStock moves stock of a product between two warehouses. The transfer is recorded as
+// an outbound and an inbound movement in a single write, so the product quantity is kept
+// TransferStock(transfer internal.TTransfer) -> ([]internal.TMovement, error)
+// Args:
+//		transfer: Transfer to record
+// Return:
+//		[]internal.TMovement: Outbound and inbound movements recorded
+//		error: 				  Error raised during the execution (if exists)
+
+func (m *MovementServiceDefault) TransferStock(transfer internal.TTransfer) ([]internal.TMovement, error) {
+	/* Transfer validation */
+	if transfer.Quantity <= 0 {
+		return nil, internal.ErrInvalidMovementQuantity
+	}
+	if transfer.Reason == "" {
+		return nil, fmt.Errorf("%w: %s", internal.ErrEmptyField, "Reason")
+	}
+	if transfer.FromWarehouseID == transfer.ToWarehouseID {
+		return nil, fmt.Errorf("%w: %s", internal.ErrInvalidTransfer, "same origin and destination")
+	}
+	for _, id := range []int{transfer.FromWarehouseID, transfer.ToWarehouseID} {
+		if err := m.checkWarehouse(id); err != nil {
+			return nil, err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	/* Retrieve the product and its ledger */
+	product, err := m.getProduct(transfer.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	now := m.clock.Now()
+	history, batch, err := m.history(product, now)
+	if err != nil {
+		return nil, err
+	}
+
+	/* Stock validation on the origin */
+	stock := warehouseQuantities(history)[transfer.FromWarehouseID]
+	if stock < transfer.Quantity {
+		return nil, internal.ErrInsufficientStock
+	}
+
+	/* The lots leaving the origin */
+	var draws []lotDraw
+	if m.lots != nil {
+		lots, err := m.lots.GetLotsByProduct(product.ID)
+		if err != nil {
+			return nil, err
+		}
+		if draws, err = m.drawLots(lots, transfer.FromWarehouseID, stock, transfer.Quantity, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	/* Record both sides of the transfer */
+	opening := len(batch)
+	for _, side := range []struct{ warehouseID, quantity int }{
+		{transfer.FromWarehouseID, -transfer.Quantity},
+		{transfer.ToWarehouseID, transfer.Quantity},
+	} {
+		batch = append(batch, internal.TMovement{
+			ProductID:   product.ID,
+			WarehouseID: side.warehouseID,
+			Type:        internal.MovementTransfer,
+			Quantity:    side.quantity,
+			Reason:      transfer.Reason,
+			Reference:   transfer.Reference,
+			Date:        now,
+		})
+	}
+	if err := m.movements.InsertNewMovements(batch); err != nil {
+		return nil, err
+	}
+
+	/* The lots keep their expiration and age on the destination */
+	if len(draws) != 0 {
+		changes := make([]internal.TLot, 0, 2*len(draws))
+		for _, draw := range draws {
+			changes = append(changes, draw.lot, internal.TLot{
+				ProductID:        product.ID,
+				WarehouseID:      transfer.ToWarehouseID,
+				Quantity:         draw.quantity,
+				ReceivedQuantity: draw.quantity,
+				Expiration:       draw.lot.Expiration,
+				ReceivedAt:       draw.lot.ReceivedAt,
+				MovementID:       batch[len(batch)-1].ID,
+			})
+		}
+		if err := m.lots.SaveLots(changes); err != nil {
+			return nil, err
+		}
+	}
+
+	/* An opening balance brings the product quantity in sync with the ledger */
+	if opening != 0 {
+		product.Quantity = ledgerQuantity(history)
+		if err := m.products.UpdateProduct(&product); err != nil {
+			return nil, err
+		}
+		m.emitQuantity(product.ID)
+	}
+	return batch[opening:], nil
+}
+
+// stockLevels converts the stock by warehouse of a product into stock levels ordered by warehouse
+// stockLevels(productID int, quantities map[int]int) -> []internal.TStockLevel
+
+func stockLevels(productID int, quantities map[int]int) []internal.TStockLevel {
+	levels := make([]internal.TStockLevel, 0, len(quantities))
+	for warehouseID, quantity := range quantities {
+		if quantity == 0 {
+			continue
+		}
+		levels = append(levels, internal.TStockLevel{ProductID: productID, WarehouseID: warehouseID, Quantity: quantity})
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		return levels[i].WarehouseID < levels[j].WarehouseID
+	})
+	return levels
+}
+
+// GetStockLevels returns the stock of a product by warehouse
+// GetStockLevels(productID int) -> ([]internal.TStockLevel, error)
+// Args:
+//		productID: Product id
+// Return:
+//		[]internal.TStockLevel: Warehouses holding stock of the product ordered by id
+//		error: 					Error raised during the execution (if exists)
+
+func (m *MovementServiceDefault) GetStockLevels(productID int) ([]internal.TStockLevel, error) {
+	product, err := m.getProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+	history, _, err := m.history(product, m.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	return stockLevels(productID, warehouseQuantities(history)), nil
+}
+
+// GetWarehouseStock returns the stock of every product in a warehouse
+// GetWarehouseStock(warehouseID int) -> ([]internal.TStockLevel, error)
+// Args:
+//		warehouseID: Warehouse id
+// Return:
+//		[]internal.TStockLevel: Products stocked in the warehouse ordered by id
+//		error: 					Error raised during the execution (if exists)
+
+func (m *MovementServiceDefault) GetWarehouseStock(warehouseID int) ([]internal.TStockLevel, error) {
+	if err := m.checkWarehouse(warehouseID); err != nil {
+		return nil, err
+	}
+
+	/* Sum the ledger by product */
+	movements, err := m.movements.GetAllMovements()
+	if err != nil {
+		return nil, err
+	}
+	recorded := make(map[int]bool)
+	quantities := make(map[int]int)
+	for _, movement := range movements {
+		recorded[movement.ProductID] = true
+		if warehouseOf(movement) == warehouseID {
+			quantities[movement.ProductID] += movement.Quantity
+		}
+	}
+
+	/* The products without history hold their stock on the default warehouse */
+	if warehouseID == internal.DefaultWarehouseID {
+		for _, product := range m.products.GetAllProducts() {
+			if !recorded[product.ID] {
+				quantities[product.ID] = product.Quantity
+			}
+		}
+	}
+
+	levels := make([]internal.TStockLevel, 0, len(quantities))
+	for productID, quantity := range quantities {
+		if quantity == 0 {
+			continue
+		}
+		levels = append(levels, internal.TStockLevel{ProductID: productID, WarehouseID: warehouseID, Quantity: quantity})
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		return levels[i].ProductID < levels[j].ProductID
+	})
+	return levels, nil
+}
+
+// GetMovementsByProduct returns the movements of a product between two dates
+// GetMovementsByProduct(productID int, from, to time.Time) -> ([]internal.TMovement, error)
+// Args:
+//		productID: Product id
+//		from: 	   Lower bound (inclusive). Zero value means no lower bound
+//		to: 	   Upper bound (exclusive). Zero value means no upper bound
+// Return:
+//		[]internal.TMovement: Movements of the product ordered by date
+//		error: 				  Error raised during the execution (if exists)
+
+func (m *MovementServiceDefault) GetMovementsByProduct(productID int, from, to time.Time) ([]internal.TMovement, error) {
+	/* Check the product exists */
+	if _, err := m.getProduct(productID); err != nil {
+		return nil, err
+	}
+
+	return m.movements.GetMovementsByProduct(productID, from, to)
+}
+
+// Reconcile compares the ledger against the stored quantity of every product
+// Reconcile() -> ([]internal.TReconciliation, error)
+// Return:
+//		[]internal.TReconciliation: Products whose stored quantity differs from the ledger
+//		error: 						Error raised during the execution (if exists)
+
+func (m *MovementServiceDefault) Reconcile() ([]internal.TReconciliation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	/* Sum the ledger by product */
+	movements, err := m.movements.GetAllMovements()
+	if err != nil {
+		return nil, err
+	}
+	ledger := make(map[int]int)
+	for _, movement := range movements {
+		ledger[movement.ProductID] += movement.Quantity
+	}
+
+	/* Flag the products with drift */
+	drifts := make([]internal.TReconciliation, 0)
+	for _, product := range m.products.GetAllProducts() {
+		if ledger[product.ID] == product.Quantity {
+			continue
+		}
+		drifts = append(drifts, internal.TReconciliation{
+			ProductID:      product.ID,
+			StoredQuantity: product.Quantity,
+			LedgerQuantity: ledger[product.ID],
+			Drift:          product.Quantity - ledger[product.ID],
+		})
+	}
+	return drifts, nil
+}
+
+// GetLotsByProduct returns the lots of a product still in stock
+// GetLotsByProduct(productID int) -> ([]internal.TLot, error)
+// Args:
+//		productID: Product id
+// Return:
+//		[]internal.TLot: Lots of the product in the order the policy consumes them
+//		error: 			 Error raised during the execution (if exists)
+
+func (m *MovementServiceDefault) GetLotsByProduct(productID int) ([]internal.TLot, error) {
+	if _, err := m.getProduct(productID); err != nil {
+		return nil, err
+	}
+	lots, err := m.productLots(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	open := make([]internal.TLot, 0, len(lots))
+	for _, lot := range lots {
+		if lot.Quantity > 0 {
+			open = append(open, lot)
+		}
+	}
+	sortLots(open, m.lotPolicy)
+	return open, nil
+}
+
+// WriteOffExpiredLots writes off the stock left in the lots expired according to the clock.
+// Every lot gets its own write-off movement, all of them recorded at once
+// WriteOffExpiredLots() -> ([]internal.TMovement, error)
+// Return:
+//		[]internal.TMovement: Write-off movements recorded
+//		error: 				  Error raised during the execution (if exists)
+
+func (m *MovementServiceDefault) WriteOffExpiredLots() ([]internal.TMovement, error) {
+	written := make([]internal.TMovement, 0)
+	if m.lots == nil {
+		return written, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	/* Search the expired lots with stock left */
+	lots, err := m.lots.GetAllLots()
+	if err != nil {
+		return nil, err
+	}
+	now := m.clock.Now()
+	deltas := make([]int, 0)
+	for _, lot := range lots {
+		if lot.Quantity <= 0 {
+			continue
+		}
+		if expiration, err := expirationTime(lot.Expiration); err != nil || expiration.After(now) {
+			continue
+		}
+		written = append(written, internal.TMovement{
+			ProductID:   lot.ProductID,
+			WarehouseID: lot.WarehouseID,
+			Type:        internal.MovementWriteOff,
+			Reason:      "expired lot",
+			Reference:   fmt.Sprintf("lot %d", lot.ID),
+			LotID:       lot.ID,
+		})
+		deltas = append(deltas, -lot.Quantity)
+	}
+	if len(written) == 0 {
+		return written, nil
+	}
+
+	/* Record the write-offs */
+	pending := make([]*internal.TMovement, len(written))
+	for i := range written {
+		pending[i] = &written[i]
+	}
+	if err := m.post(pending, deltas); err != nil {
+		return nil, err
+	}
+	return written, nil
+}
+
+// RemoveProduct deletes the movements and the lots of a deleted product, and with them its
+// stock by warehouse, so a new product reusing its id starts with an empty ledger
+// RemoveProduct(productID int) -> error
+// Args:
+//		productID: Id of the deleted product
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (m *MovementServiceDefault) RemoveProduct(productID int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.movements.DeleteMovementsByProduct(productID); err != nil {
+		return err
+	}
+	if m.lots == nil {
+		return nil
+	}
+	return m.lots.DeleteLotsByProduct(productID)
+}
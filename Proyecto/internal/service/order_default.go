@@ -0,0 +1,505 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"proyecto/internal"
+	"proyecto/internal/clock"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type OrderServiceDefault struct {
+	repository internal.OrderRepository // Repository of the orders
+	products   internal.ProductService  // Service of the ordered products
+	ledger     internal.MovementService // Ledger the stock of the orders is taken from
+	clock      internal.Clock           // Clock the orders are dated with
+	mu         sync.Mutex               // Serializes the order updates
+}
+
+// orderTransitions are the statuses an order can move to from each status
+var orderTransitions = map[string][]string{
+	internal.OrderDraft:     {internal.OrderPlaced, internal.OrderCancelled},
+	internal.OrderPlaced:    {internal.OrderConfirmed, internal.OrderCancelled},
+	internal.OrderConfirmed: {internal.OrderShipped, internal.OrderCancelled},
+}
+
+// NewOrderServiceDefault creates a new OrderServiceDefault instance
+// NewOrderServiceDefault(or internal.OrderRepository, ps internal.ProductService, ms internal.MovementService) -> *OrderServiceDefault
+// Args:
+//		or: Order repository
+//		ps: Product service the items are checked against
+//		ms: Movement service the stock is taken from and restored to
+// Return:
+//		*OrderServiceDefault: New OrderServiceDefault instance
+
+func NewOrderServiceDefault(or internal.OrderRepository, ps internal.ProductService, ms internal.MovementService) *OrderServiceDefault {
+	return &OrderServiceDefault{
+		repository: or,
+		products:   ps,
+		ledger:     ms,
+		clock:      clock.NewClockSystem(),
+	}
+}
+
+// SetClock sets the clock the orders are dated with and the expirations evaluated against
+// SetClock(c internal.Clock)
+// Args:
+//		c: Clock to use
+
+func (o *OrderServiceDefault) SetClock(c internal.Clock) {
+	o.clock = c
+}
+
+// GetAllOrders returns all the orders
+// GetAllOrders() -> ([]internal.TOrder, error)
+// Return:
+//		[]internal.TOrder: Slice of orders
+//		error: 			   Error raised during the execution (if exists)
+
+func (o *OrderServiceDefault) GetAllOrders() ([]internal.TOrder, error) {
+	return o.repository.GetAllOrders()
+}
+
+// GetOrderByID returns an order by its id
+// GetOrderByID(id int) -> (internal.TOrder, error)
+// Args:
+//		id: Order id
+// Return:
+//		internal.TOrder: Order found in the repository
+//		error: 			 Error raised during the execution (if exists)
+
+func (o *OrderServiceDefault) GetOrderByID(id int) (internal.TOrder, error) {
+	order, err := o.repository.GetOrderByID(id)
+	if err == internal.ErrOrderNotFound {
+		return internal.TOrder{}, internal.ErrOrderNotExists
+	}
+	return order, err
+}
+
+// validateItems checks every line orders a positive quantity of a different product
+// validateItems(items []internal.TOrderItem) -> error
+// Args:
+//		items: Lines of the order
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func validateItems(items []internal.TOrderItem) error {
+	if len(items) == 0 {
+		return fmt.Errorf("%w: %s", internal.ErrEmptyField, "Items")
+	}
+	seen := make(map[int]bool)
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return fmt.Errorf("%w: product %d has no quantity", internal.ErrInvalidOrderItems, item.ProductID)
+		}
+		if seen[item.ProductID] {
+			return fmt.Errorf("%w: product %d is repeated", internal.ErrInvalidOrderItems, item.ProductID)
+		}
+		seen[item.ProductID] = true
+	}
+	return nil
+}
+
+// InsertNewOrder inserts a new draft order
+// InsertNewOrder(order *internal.TOrder) -> error
+// Args:
+//		order: Order to insert. Its id, status and dates are updated in place
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (o *OrderServiceDefault) InsertNewOrder(order *internal.TOrder) error {
+	if err := validateItems(order.Items); err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	/* The prices are taken when the order is placed */
+	for i := range order.Items {
+		order.Items[i].UnitPrice = 0
+	}
+	order.ID = 0
+	order.Customer = strings.TrimSpace(order.Customer)
+	order.Status = internal.OrderDraft
+	order.Total = 0
+	order.CreatedAt = o.clock.Now()
+	order.UpdatedAt = order.CreatedAt
+	return o.repository.InsertNewOrder(order)
+}
+
+// UpdateOrder updates the customer and the items of a draft order
+// UpdateOrder(order *internal.TOrder) -> error
+// Args:
+//		order: Order to update. The rest of its fields are kept
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (o *OrderServiceDefault) UpdateOrder(order *internal.TOrder) error {
+	if err := validateItems(order.Items); err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	/* Only drafts can be edited */
+	current, err := o.GetOrderByID(order.ID)
+	if err != nil {
+		return err
+	}
+	if current.Status != internal.OrderDraft {
+		return fmt.Errorf("%w: %s order can't be edited", internal.ErrInvalidOrderTransition, current.Status)
+	}
+
+	for i := range order.Items {
+		order.Items[i].UnitPrice = 0
+	}
+	current.Customer = strings.TrimSpace(order.Customer)
+	current.Items = order.Items
+	current.UpdatedAt = o.clock.Now()
+	if err := o.repository.UpdateOrder(&current); err != nil {
+		return err
+	}
+	*order = current
+	return nil
+}
+
+// transition moves an order to a new status if the state machine allows it. The caller
+// must hold the order lock
+// transition(id int, status string, apply, revert func(order *internal.TOrder) error) -> (internal.TOrder, error)
+// Args:
+//		id: 	Order id
+//		status: Status to move the order to
+//		apply: 	Side effects of the transition, run before the order is saved (optional)
+//		revert: Undo of the side effects, run if the order can't be saved (optional)
+// Return:
+//		internal.TOrder: Updated order
+//		error: 			 Error raised during the execution (if exists)
+
+func (o *OrderServiceDefault) transition(id int, status string, apply, revert func(order *internal.TOrder) error) (internal.TOrder, error) {
+	order, err := o.GetOrderByID(id)
+	if err != nil {
+		return internal.TOrder{}, err
+	}
+
+	/* Check the state machine */
+	allowed := false
+	for _, next := range orderTransitions[order.Status] {
+		if next == status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return internal.TOrder{}, fmt.Errorf("%w: %s -> %s", internal.ErrInvalidOrderTransition, order.Status, status)
+	}
+
+	/* Run the side effects and save the new status */
+	if apply != nil {
+		if err := apply(&order); err != nil {
+			return internal.TOrder{}, err
+		}
+	}
+	previous := order.Status
+	order.Status = status
+	order.UpdatedAt = o.clock.Now()
+	if err := o.repository.UpdateOrder(&order); err != nil {
+		/* The order keeps its status, so its side effects are undone */
+		if apply != nil && revert != nil {
+			order.Status = previous
+			if revertErr := revert(&order); revertErr != nil {
+				return internal.TOrder{}, fmt.Errorf("%w (order %d left out of sync with the stock: %v)", err, id, revertErr)
+			}
+		}
+		return internal.TOrder{}, err
+	}
+	return order, nil
+}
+
+// checkAvailable checks a product can be sold: it is published, unexpired and has enough stock
+// checkAvailable(product internal.TProduct, quantity int, now time.Time) -> error
+// Args:
+//		product:  Product as resolved by the product service
+//		quantity: Units to sell
+//		now: 	  Moment the expiration is evaluated against
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func checkAvailable(product internal.TProduct, quantity int, now time.Time) error {
+	if !product.IsPublished {
+		return fmt.Errorf("%w: product %d is not published", internal.ErrProductNotAvailable, product.ID)
+	}
+	if expiration, err := expirationTime(product.Expiration); err != nil || !expiration.After(now) {
+		return fmt.Errorf("%w: product %d is expired", internal.ErrProductNotAvailable, product.ID)
+	}
+	if product.Quantity < quantity {
+		return fmt.Errorf("%w: product %d", internal.ErrInsufficientStock, product.ID)
+	}
+	return nil
+}
+
+// livePrice returns the price a product is sold at: the effective one if promotions apply
+// livePrice(product internal.TProduct) -> float64
+
+func livePrice(product internal.TProduct) float64 {
+	if product.EffectivePrice != nil {
+		return *product.EffectivePrice
+	}
+	return product.Price
+}
+
+// PlaceOrder checks every product of a draft order is published, unexpired and in stock,
+// takes their current prices and places the order
+// PlaceOrder(id int) -> (internal.TOrder, error)
+// Args:
+//		id: Order id
+// Return:
+//		internal.TOrder: Placed order
+//		error: 			 Error raised during the execution (if exists)
+
+func (o *OrderServiceDefault) PlaceOrder(id int) (internal.TOrder, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.transition(id, internal.OrderPlaced, func(order *internal.TOrder) error {
+		now := o.clock.Now()
+		var total float64
+		for i, item := range order.Items {
+			product, err := o.products.GetProductByID(item.ProductID)
+			if err == internal.ErrProductNotExists {
+				return fmt.Errorf("%w: product %d not exists", internal.ErrProductNotAvailable, item.ProductID)
+			} else if err != nil {
+				return err
+			}
+			if err := checkAvailable(product, item.Quantity, now); err != nil {
+				return err
+			}
+
+			/* The price is kept for the rest of the life of the order */
+			order.Items[i].UnitPrice = livePrice(product)
+			total += order.Items[i].UnitPrice * float64(item.Quantity)
+		}
+		order.Total = math.Round(total*100) / 100
+		return nil
+	}, nil)
+}
+
+// orderReference returns the reference of the movements moving the stock of an order
+// orderReference(order internal.TOrder) -> string
+
+func orderReference(order internal.TOrder) string {
+	return fmt.Sprintf("order %d", order.ID)
+}
+
+// saleMovements returns the sales taking the stock of an order. The products are checked
+// again, as they may have changed since the order was placed, and every item is drawn from
+// the default warehouse first and then from the others by id, as its stock may be spread
+// across them
+// saleMovements(order internal.TOrder) -> ([]internal.TMovement, error)
+// Args:
+//		order: Order whose items are taken
+// Return:
+//		[]internal.TMovement: One sale per item and warehouse drawn from
+//		error: 				  Error raised during the execution (if exists)
+
+func (o *OrderServiceDefault) saleMovements(order internal.TOrder) ([]internal.TMovement, error) {
+	now := o.clock.Now()
+	movements := make([]internal.TMovement, 0, len(order.Items))
+	for _, item := range order.Items {
+		product, err := o.products.GetProductByID(item.ProductID)
+		if err == internal.ErrProductNotExists {
+			return nil, fmt.Errorf("%w: product %d not exists", internal.ErrProductNotAvailable, item.ProductID)
+		} else if err != nil {
+			return nil, err
+		}
+		if err := checkAvailable(product, item.Quantity, now); err != nil {
+			return nil, err
+		}
+
+		levels, err := o.ledger.GetStockLevels(item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(levels, func(i, j int) bool {
+			return levels[i].WarehouseID == internal.DefaultWarehouseID && levels[j].WarehouseID != internal.DefaultWarehouseID
+		})
+		pending := item.Quantity
+		for _, level := range levels {
+			drawn := min(pending, level.Quantity)
+			if drawn <= 0 {
+				continue
+			}
+			movements = append(movements, internal.TMovement{
+				ProductID:   item.ProductID,
+				WarehouseID: level.WarehouseID,
+				Type:        internal.MovementSale,
+				Quantity:    drawn,
+				Reason:      "order confirmed",
+				Reference:   orderReference(order),
+			})
+			pending -= drawn
+		}
+		if pending > 0 {
+			return nil, fmt.Errorf("%w: product %d", internal.ErrInsufficientStock, item.ProductID)
+		}
+	}
+	return movements, nil
+}
+
+// outstandingMovements returns the corrections giving back the stock an order still holds,
+// to the warehouses it was drawn from. What the order holds is read from the movements
+// referencing it in the ledger
+// outstandingMovements(order internal.TOrder, reason string) -> ([]internal.TMovement, error)
+// Args:
+//		order:  Order whose stock is given back
+//		reason: Reason of the corrections
+// Return:
+//		[]internal.TMovement: One correction per item and warehouse holding stock
+//		error: 				  Error raised during the execution (if exists)
+
+func (o *OrderServiceDefault) outstandingMovements(order internal.TOrder, reason string) ([]internal.TMovement, error) {
+	reference := orderReference(order)
+	movements := make([]internal.TMovement, 0, len(order.Items))
+	for _, item := range order.Items {
+		history, err := o.ledger.GetMovementsByProduct(item.ProductID, time.Time{}, time.Time{})
+		if err != nil {
+			return nil, err
+		}
+
+		/* Net quantity moved by the order in every warehouse, in the order first seen */
+		held := make(map[int]int)
+		var warehouses []int
+		for _, movement := range history {
+			if movement.Reference != reference {
+				continue
+			}
+			warehouseID := movement.WarehouseID
+			if _, ok := held[warehouseID]; !ok {
+				warehouses = append(warehouses, warehouseID)
+			}
+			held[warehouseID] += movement.Quantity
+		}
+		for _, warehouseID := range warehouses {
+			if held[warehouseID] == 0 {
+				continue
+			}
+			movements = append(movements, internal.TMovement{
+				ProductID:   item.ProductID,
+				WarehouseID: warehouseID,
+				Type:        internal.MovementCorrection,
+				Quantity:    -held[warehouseID],
+				Reason:      reason,
+				Reference:   reference,
+			})
+		}
+	}
+	return movements, nil
+}
+
+// reversalMovements returns the corrections taking back posted movements
+// reversalMovements(movements []internal.TMovement, reason string) -> []internal.TMovement
+// Args:
+//		movements: Posted movements, with their signed quantities
+//		reason:    Reason of the corrections
+// Return:
+//		[]internal.TMovement: One correction per movement
+
+func reversalMovements(movements []internal.TMovement, reason string) []internal.TMovement {
+	reversal := make([]internal.TMovement, 0, len(movements))
+	for _, movement := range movements {
+		reversal = append(reversal, internal.TMovement{
+			ProductID:   movement.ProductID,
+			WarehouseID: movement.WarehouseID,
+			Type:        internal.MovementCorrection,
+			Quantity:    -movement.Quantity,
+			Reason:      reason,
+			Reference:   movement.Reference,
+		})
+	}
+	return reversal
+}
+
+// ConfirmOrder takes the stock of a placed order, from every warehouse holding it. Either every
+// item is taken or none, and the stock is given back through corrections if the order can't
+// be saved
+// ConfirmOrder(id int) -> (internal.TOrder, error)
+// Args:
+//		id: Order id
+// Return:
+//		internal.TOrder: Confirmed order
+//		error: 			 Error raised during the execution (if exists)
+
+func (o *OrderServiceDefault) ConfirmOrder(id int) (internal.TOrder, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var sales []internal.TMovement
+	return o.transition(id, internal.OrderConfirmed, func(order *internal.TOrder) error {
+		movements, err := o.saleMovements(*order)
+		if err != nil {
+			return err
+		}
+		if err := o.ledger.PostMovements(movements); err != nil {
+			return err
+		}
+		sales = movements
+		return nil
+	}, func(order *internal.TOrder) error {
+		return o.ledger.PostMovements(reversalMovements(sales, "order confirmation reverted"))
+	})
+}
+
+// ShipOrder marks a confirmed order as shipped
+// ShipOrder(id int) -> (internal.TOrder, error)
+// Args:
+//		id: Order id
+// Return:
+//		internal.TOrder: Shipped order
+//		error: 			 Error raised during the execution (if exists)
+
+func (o *OrderServiceDefault) ShipOrder(id int) (internal.TOrder, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.transition(id, internal.OrderShipped, nil, nil)
+}
+
+// CancelOrder cancels an order which was not shipped. A confirmed order gets its stock
+// back through corrections to the warehouses it was drawn from, as the units return
+// untracked by lot. The corrections are taken back if the order can't be saved
+// CancelOrder(id int) -> (internal.TOrder, error)
+// Args:
+//		id: Order id
+// Return:
+//		internal.TOrder: Cancelled order
+//		error: 			 Error raised during the execution (if exists)
+
+func (o *OrderServiceDefault) CancelOrder(id int) (internal.TOrder, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var corrections []internal.TMovement
+	return o.transition(id, internal.OrderCancelled, func(order *internal.TOrder) error {
+		if order.Status != internal.OrderConfirmed {
+			return nil
+		}
+		movements, err := o.outstandingMovements(*order, "order cancelled")
+		if err != nil {
+			return err
+		}
+		if err := o.ledger.PostMovements(movements); err != nil {
+			return err
+		}
+		corrections = movements
+		return nil
+	}, func(order *internal.TOrder) error {
+		if len(corrections) == 0 {
+			return nil
+		}
+		return o.ledger.PostMovements(reversalMovements(corrections, "order cancellation reverted"))
+	})
+}
@@ -0,0 +1,451 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"proyecto/internal"
+	"proyecto/internal/clock"
+	"sort"
+	"time"
+)
+
+type PriceServiceDefault struct {
+	products   internal.ProductRepository   // Repository of the priced products
+	prices     internal.PriceRepository     // Repository of the price history
+	promotions internal.PromotionRepository // Repository of the promotions
+	clock      internal.Clock               // Clock the price changes are dated with
+	events     internal.ProductEventBus     // Bus the promotion changes are published on (optional)
+	reader     internal.ProductService      // Service the products are published through (optional)
+}
+
+// NewPriceServiceDefault creates a new PriceServiceDefault instance
+// NewPriceServiceDefault(pr internal.ProductRepository, hr internal.PriceRepository, mr internal.PromotionRepository) -> *PriceServiceDefault
+// Args:
+//		pr: Product repository of the priced products
+//		hr: Price repository where the history is stored
+//		mr: Promotion repository where the promotions are stored
+// Return:
+//		*PriceServiceDefault: New PriceServiceDefault instance
+
+func NewPriceServiceDefault(pr internal.ProductRepository, hr internal.PriceRepository, mr internal.PromotionRepository) *PriceServiceDefault {
+	return &PriceServiceDefault{
+		products:   pr,
+		prices:     hr,
+		promotions: mr,
+		clock:      clock.NewClockSystem(),
+	}
+}
+
+// SetClock sets the clock the price changes are dated with
+// SetClock(c internal.Clock)
+// Args:
+//		c: Clock to use
+
+func (p *PriceServiceDefault) SetClock(c internal.Clock) {
+	p.clock = c
+}
+
+// SetEvents sets the bus the effective price changes caused by the promotions are published on.
+// The promotions starting or ending as time goes by publish nothing: the event comes when the
+// promotion is inserted, updated or deleted
+// SetEvents(bus internal.ProductEventBus, ps internal.ProductService)
+// Args:
+//		bus: Product event bus
+//		ps:  Product service the changed products are read through, so they are published with their effective price
+
+func (p *PriceServiceDefault) SetEvents(bus internal.ProductEventBus, ps internal.ProductService) {
+	p.events = bus
+	p.reader = ps
+}
+
+// emitPrice publishes the price change of a product and its variants, as they share its promotions (if there is a bus)
+// emitPrice(productID int)
+// Args:
+//		productID: Product whose promotions changed
+
+func (p *PriceServiceDefault) emitPrice(productID int) {
+	if p.events == nil || p.reader == nil {
+		return
+	}
+	product, err := p.reader.GetProductByID(productID)
+	if err != nil {
+		return
+	}
+	emitProductEvent(p.events, internal.ProductEventUpdated, productID, &product, internal.ProductChangePrice)
+	variants, _ := p.reader.GetVariants(productID)
+	for i := range variants {
+		emitProductEvent(p.events, internal.ProductEventUpdated, variants[i].ID, &variants[i], internal.ProductChangePrice)
+	}
+}
+
+// validatePromotion checks the type, value and period of a promotion
+// validatePromotion(promotion internal.TPromotion) -> error
+// Args:
+//		promotion: Promotion to validate
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func validatePromotion(promotion internal.TPromotion) error {
+	switch promotion.Type {
+	case internal.PromotionPercentage:
+		if promotion.Value <= 0 || promotion.Value > 100 {
+			return fmt.Errorf("%w: %s", internal.ErrInvalidPromotion, "percentage must be in (0, 100]")
+		}
+	case internal.PromotionFixed:
+		if promotion.Value <= 0 {
+			return fmt.Errorf("%w: %s", internal.ErrInvalidPromotion, "amount must be positive")
+		}
+	default:
+		return fmt.Errorf("%w: %s", internal.ErrInvalidPromotion, "unknown type")
+	}
+	if promotion.StartAt.IsZero() || !promotion.EndAt.After(promotion.StartAt) {
+		return fmt.Errorf("%w: %s", internal.ErrInvalidPromotion, "end must be after start")
+	}
+	return nil
+}
+
+// applies checks if a promotion applies to a product: its own promotions and the ones of its parent
+// applies(promotion internal.TPromotion, product internal.TProduct) -> bool
+
+func applies(promotion internal.TPromotion, product internal.TProduct) bool {
+	if promotion.ProductID == product.ID {
+		return true
+	}
+	return product.ParentID != nil && promotion.ProductID == *product.ParentID
+}
+
+// discountedPrice returns the list price after a promotion, rounded to cents and never negative
+// discountedPrice(listPrice float64, promotion internal.TPromotion) -> float64
+
+func discountedPrice(listPrice float64, promotion internal.TPromotion) float64 {
+	price := listPrice
+	switch promotion.Type {
+	case internal.PromotionPercentage:
+		price = listPrice * (1 - promotion.Value/100)
+	case internal.PromotionFixed:
+		price = listPrice - promotion.Value
+	}
+	return math.Max(0, math.Round(price*100)/100)
+}
+
+// bestPrice returns the lowest price among the promotions active at a moment. Overlapping
+// promotions don't stack, the one most favorable to the customer wins
+// bestPrice(listPrice float64, promotions []internal.TPromotion, at time.Time) -> (float64, *int)
+// Args:
+//		listPrice:  List price of the product
+//		promotions: Promotions applying to the product
+//		at: 		Moment of the evaluation
+// Return:
+//		float64: Effective price
+//		*int: 	 Id of the promotion applied (nil if none is active)
+
+func bestPrice(listPrice float64, promotions []internal.TPromotion, at time.Time) (float64, *int) {
+	price := listPrice
+	var promotionID *int
+	for _, promotion := range promotions {
+		if at.Before(promotion.StartAt) || !at.Before(promotion.EndAt) {
+			continue
+		}
+		if discounted := discountedPrice(listPrice, promotion); discounted < price {
+			price = discounted
+			id := promotion.ID
+			promotionID = &id
+		}
+	}
+	return price, promotionID
+}
+
+// getProduct returns a product as stored
+// getProduct(productID int) -> (internal.TProduct, error)
+
+func (p *PriceServiceDefault) getProduct(productID int) (internal.TProduct, error) {
+	product, err := p.products.GetProductByID(productID)
+	if err == internal.ErrProductNotFound {
+		return internal.TProduct{}, internal.ErrProductNotExists
+	}
+	return product, err
+}
+
+// RecordPrice records a new list price on the history of a product. Repeated prices are skipped
+// RecordPrice(productID int, price float64) -> error
+// Args:
+//		productID: Product id
+//		price: 	   New list price
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (p *PriceServiceDefault) RecordPrice(productID int, price float64) error {
+	history, err := p.prices.GetPriceHistory(productID)
+	if err != nil {
+		return err
+	}
+	if len(history) > 0 && history[len(history)-1].Price == price {
+		return nil
+	}
+
+	change := internal.TPriceChange{
+		ProductID: productID,
+		Price:     price,
+		Date:      p.clock.Now(),
+	}
+	return p.prices.InsertNewPriceChange(&change)
+}
+
+// GetPriceTimeline returns the list and effective prices of a product over time. Products priced
+// before the history existed start with their current price at the first known moment
+// GetPriceTimeline(productID int) -> ([]internal.TPricePoint, error)
+// Args:
+//		productID: Product id
+// Return:
+//		[]internal.TPricePoint: Consecutive segments ordered by date
+//		error: 					Error raised during the execution (if exists)
+
+func (p *PriceServiceDefault) GetPriceTimeline(productID int) ([]internal.TPricePoint, error) {
+	/* Retrieve the product, its history and its promotions */
+	product, err := p.getProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := p.prices.GetPriceHistory(productID)
+	if err != nil {
+		return nil, err
+	}
+	all, err := p.promotions.GetAllPromotions()
+	if err != nil {
+		return nil, err
+	}
+	promotions := make([]internal.TPromotion, 0)
+	for _, promotion := range all {
+		if applies(promotion, product) {
+			promotions = append(promotions, promotion)
+		}
+	}
+	if len(history) == 0 {
+		start := p.clock.Now()
+		for _, promotion := range promotions {
+			if promotion.StartAt.Before(start) {
+				start = promotion.StartAt
+			}
+		}
+		history = append(history, internal.TPriceChange{ProductID: productID, Price: product.Price, Date: start})
+	}
+
+	/* Every price change and promotion boundary opens a new segment */
+	origin := history[0].Date
+	moments := []time.Time{origin}
+	for _, change := range history[1:] {
+		moments = append(moments, change.Date)
+	}
+	for _, promotion := range promotions {
+		for _, moment := range []time.Time{promotion.StartAt, promotion.EndAt} {
+			if moment.After(origin) {
+				moments = append(moments, moment)
+			}
+		}
+	}
+	sort.Slice(moments, func(i, j int) bool {
+		return moments[i].Before(moments[j])
+	})
+
+	/* Evaluate the prices at the start of every segment merging the repeated ones */
+	timeline := make([]internal.TPricePoint, 0)
+	var listPrice float64
+	next := 0
+	for _, moment := range moments {
+		for next < len(history) && !history[next].Date.After(moment) {
+			listPrice = history[next].Price
+			next++
+		}
+		effectivePrice, promotionID := bestPrice(listPrice, promotions, moment)
+		if last := len(timeline) - 1; last >= 0 {
+			previous := timeline[last]
+			if previous.ListPrice == listPrice && previous.EffectivePrice == effectivePrice && equalIDs(previous.PromotionID, promotionID) {
+				continue
+			}
+			if previous.From.Equal(moment) {
+				timeline = timeline[:last]
+			} else {
+				to := moment
+				timeline[last].To = &to
+			}
+		}
+		timeline = append(timeline, internal.TPricePoint{
+			From:           moment,
+			ListPrice:      listPrice,
+			EffectivePrice: effectivePrice,
+			PromotionID:    promotionID,
+		})
+	}
+	return timeline, nil
+}
+
+// equalIDs checks if two optional ids are equal
+// equalIDs(a, b *int) -> bool
+
+func equalIDs(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// GetEffectivePrices returns the effective price of the products at a moment
+// GetEffectivePrices(products []internal.TProduct, at time.Time) -> (map[int]float64, error)
+// Args:
+//		products: Products to price
+//		at: 	  Moment of the evaluation
+// Return:
+//		map[int]float64: Effective price by product id
+//		error: 			 Error raised during the execution (if exists)
+
+func (p *PriceServiceDefault) GetEffectivePrices(products []internal.TProduct, at time.Time) (map[int]float64, error) {
+	all, err := p.promotions.GetAllPromotions()
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make(map[int]float64, len(products))
+	for _, product := range products {
+		promotions := make([]internal.TPromotion, 0)
+		for _, promotion := range all {
+			if applies(promotion, product) {
+				promotions = append(promotions, promotion)
+			}
+		}
+		prices[product.ID], _ = bestPrice(product.Price, promotions, at)
+	}
+	return prices, nil
+}
+
+// GetPromotionsByProduct returns the promotions of a product
+// GetPromotionsByProduct(productID int) -> ([]internal.TPromotion, error)
+// Args:
+//		productID: Product id
+// Return:
+//		[]internal.TPromotion: Promotions of the product ordered by start
+//		error: 				   Error raised during the execution (if exists)
+
+func (p *PriceServiceDefault) GetPromotionsByProduct(productID int) ([]internal.TPromotion, error) {
+	/* Check the product exists */
+	if _, err := p.getProduct(productID); err != nil {
+		return nil, err
+	}
+
+	all, err := p.promotions.GetAllPromotions()
+	if err != nil {
+		return nil, err
+	}
+	promotions := make([]internal.TPromotion, 0)
+	for _, promotion := range all {
+		if promotion.ProductID == productID {
+			promotions = append(promotions, promotion)
+		}
+	}
+	return promotions, nil
+}
+
+// InsertNewPromotion inserts a new promotion
+// InsertNewPromotion(promotion *internal.TPromotion) -> error
+// Args:
+//		promotion: Promotion to insert. Its id is updated in place
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (p *PriceServiceDefault) InsertNewPromotion(promotion *internal.TPromotion) error {
+	/* Promotion validation */
+	if err := validatePromotion(*promotion); err != nil {
+		return err
+	}
+	if _, err := p.getProduct(promotion.ProductID); err != nil {
+		return err
+	}
+
+	if err := p.promotions.InsertNewPromotion(promotion); err != nil {
+		return err
+	}
+	p.emitPrice(promotion.ProductID)
+	return nil
+}
+
+// UpdatePromotion updates a promotion if it exists. The product it applies to can't change
+// UpdatePromotion(promotion *internal.TPromotion) -> error
+// Args:
+//		promotion: Promotion to update
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (p *PriceServiceDefault) UpdatePromotion(promotion *internal.TPromotion) error {
+	/* Retrieve the stored promotion */
+	current, err := p.promotions.GetPromotionByID(promotion.ID)
+	if err == internal.ErrPromotionNotFound {
+		return internal.ErrPromotionNotExists
+	} else if err != nil {
+		return err
+	}
+
+	/* Promotion validation */
+	promotion.ProductID = current.ProductID
+	if err := validatePromotion(*promotion); err != nil {
+		return err
+	}
+
+	if err := p.promotions.UpdatePromotion(promotion); err == internal.ErrPromotionNotFound {
+		return internal.ErrPromotionNotExists
+	} else if err != nil {
+		return err
+	}
+	p.emitPrice(promotion.ProductID)
+	return nil
+}
+
+// DeletePromotion deletes a promotion
+// DeletePromotion(id int) -> error
+// Args:
+//		id: Promotion id
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (p *PriceServiceDefault) DeletePromotion(id int) error {
+	promotion, err := p.promotions.GetPromotionByID(id)
+	if err == internal.ErrPromotionNotFound {
+		return internal.ErrPromotionNotExists
+	} else if err != nil {
+		return err
+	}
+
+	if err := p.promotions.DeletePromotion(id); err == internal.ErrPromotionNotFound {
+		return internal.ErrPromotionNotExists
+	} else if err != nil {
+		return err
+	}
+	p.emitPrice(promotion.ProductID)
+	return nil
+}
+
+// RemoveProduct deletes the price history and the promotions of a deleted product, so a new
+// product reusing its id starts with a clean history
+// RemoveProduct(productID int) -> error
+// Args:
+//		productID: Id of the deleted product
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (p *PriceServiceDefault) RemoveProduct(productID int) error {
+	if err := p.prices.DeletePriceHistory(productID); err != nil {
+		return err
+	}
+
+	promotions, err := p.promotions.GetAllPromotions()
+	if err != nil {
+		return err
+	}
+	for _, promotion := range promotions {
+		if promotion.ProductID != productID {
+			continue
+		}
+		if err := p.promotions.DeletePromotion(promotion.ID); err != nil && err != internal.ErrPromotionNotFound {
+			return err
+		}
+	}
+	return nil
+}
@@ -3,12 +3,23 @@ package service
 import (
 	"fmt"
 	"proyecto/internal"
+	"proyecto/internal/clock"
+	"proyecto/platform/barcode"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type ProductServiceDefault struct {
 	repository internal.ProductRepository
+	ledger     internal.MovementService    // Ledger where the quantity changes are recorded (optional)
+	clock      internal.Clock              // Clock the publish windows are evaluated against
+	pricing    internal.PriceService       // Pricing where the list prices are recorded and promotions applied (optional)
+	taxes      internal.TaxService         // Taxes the tax classes are checked against (optional)
+	codePrefix string                      // Prefix of the EAN-13 codes generated for products without one (optional)
+	events     internal.ProductEventBus    // Bus the changes of the products are published on (optional)
+	dependents []internal.ProductDependent // Services whose records of a deleted product are removed (optional)
 }
 
 // NewProductServiceDefault creates a new ProductServiceDefault instance
@@ -21,16 +32,291 @@ type ProductServiceDefault struct {
 func NewProductServiceDefault(rp internal.ProductRepository) *ProductServiceDefault {
 	return &ProductServiceDefault{
 		repository: rp,
+		clock:      clock.NewClockSystem(),
 	}
 }
 
+// SetClock sets the clock the publish windows and expirations are evaluated against
+// SetClock(c internal.Clock)
+// Args:
+//		c: Clock to use
+
+func (p *ProductServiceDefault) SetClock(c internal.Clock) {
+	p.clock = c
+}
+
+// SetLedger sets the ledger where the quantity changes of the products are recorded
+// SetLedger(ms internal.MovementService)
+// Args:
+//		ms: Movement service of the ledger
+
+func (p *ProductServiceDefault) SetLedger(ms internal.MovementService) {
+	p.ledger = ms
+}
+
+// SetPricing sets the pricing where the list prices of the products are recorded and their
+// promotions applied
+// SetPricing(ps internal.PriceService)
+// Args:
+//		ps: Price service
+
+func (p *ProductServiceDefault) SetPricing(ps internal.PriceService) {
+	p.pricing = ps
+}
+
+// SetTaxes sets the taxes the tax classes of the products are checked against
+// SetTaxes(ts internal.TaxService)
+// Args:
+//		ts: Tax service
+
+func (p *ProductServiceDefault) SetTaxes(ts internal.TaxService) {
+	p.taxes = ts
+}
+
+// SetCodeGeneration enables the generation of EAN-13 codes for the products created without one
+// SetCodeGeneration(prefix string)
+// Args:
+//		prefix: Leading digits of the generated codes, e.g. "200" for the in-store range. Empty disables it
+
+func (p *ProductServiceDefault) SetCodeGeneration(prefix string) {
+	p.codePrefix = prefix
+}
+
+// SetEvents sets the bus the changes of the products are published on
+// SetEvents(bus internal.ProductEventBus)
+// Args:
+//		bus: Product event bus
+
+func (p *ProductServiceDefault) SetEvents(bus internal.ProductEventBus) {
+	p.events = bus
+}
+
+// AddDependent adds a service whose records of a product are removed when the product is deleted
+// AddDependent(d internal.ProductDependent)
+// Args:
+//		d: Service keeping records of the products by their id
+
+func (p *ProductServiceDefault) AddDependent(d internal.ProductDependent) {
+	p.dependents = append(p.dependents, d)
+}
+
+// generateCode fills the empty code of a product with the next free EAN-13 under the code prefix
+// generateCode(product *internal.TProduct) -> error
+// Args:
+//		product: Product to fill. Products with a code are left as they are
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (p *ProductServiceDefault) generateCode(product *internal.TProduct) error {
+	if p.codePrefix == "" || product.CodeValue != "" {
+		return nil
+	}
+
+	/* Continue after the highest code generated so far */
+	width := 12 - len(p.codePrefix)
+	if width <= 0 {
+		return fmt.Errorf("%w: code prefix too long", barcode.ErrNotEncodable)
+	}
+	next := 1
+	for _, stored := range p.repository.GetAllProducts() {
+		code := stored.CodeValue
+		if !strings.HasPrefix(code, p.codePrefix) || barcode.ValidateEAN13(code) != nil {
+			continue
+		}
+		if sequence, err := strconv.Atoi(code[len(p.codePrefix):12]); err == nil && sequence >= next {
+			next = sequence + 1
+		}
+	}
+
+	code, err := barcode.NewEAN13(fmt.Sprintf("%s%0*d", p.codePrefix, width, next))
+	if err != nil {
+		return err
+	}
+	product.CodeValue = code
+	return nil
+}
+
+// checkTaxClass normalizes the tax class of a product and checks it has a rate everywhere
+// checkTaxClass(product *internal.TProduct) -> error
+// Args:
+//		product: Product to check. Its tax class is normalized in place
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (p *ProductServiceDefault) checkTaxClass(product *internal.TProduct) error {
+	product.TaxClass = strings.ToLower(strings.TrimSpace(product.TaxClass))
+	if p.taxes == nil || product.TaxClass == "" {
+		return nil
+	}
+	return p.taxes.ValidateTaxClass(product.TaxClass)
+}
+
+// recordPrice records the list price of a product on the pricing (if any)
+// recordPrice(product internal.TProduct) -> error
+// Args:
+//		product: Product whose price is recorded
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (p *ProductServiceDefault) recordPrice(product internal.TProduct) error {
+	if p.pricing == nil {
+		return nil
+	}
+	return p.pricing.RecordPrice(product.ID, product.Price)
+}
+
+// isExpired checks if a product is past its expiration date at the given moment. Products with
+// an unreadable expiration are not considered expired
+// isExpired(product internal.TProduct, now time.Time) -> bool
+
+func isExpired(product internal.TProduct, now time.Time) bool {
+	expiration, err := expirationTime(product.Expiration)
+	return err == nil && !expiration.After(now)
+}
+
+// isVisible evaluates if a product is published at the given moment honoring its publish window.
+// A due publish moment doesn't publish an expired product
+// isVisible(product internal.TProduct, now time.Time) -> bool
+// Args:
+//		product: Product to evaluate
+//		now: 	 Moment of the evaluation
+// Return:
+//		bool: True if the product is published, false otherwise
+
+func isVisible(product internal.TProduct, now time.Time) bool {
+	visible := product.IsPublished
+	if product.PublishAt != nil && !product.PublishAt.After(now) && !isExpired(product, now) {
+		visible = true
+	}
+	if product.UnpublishAt != nil && !product.UnpublishAt.After(now) {
+		visible = false
+	}
+	return visible
+}
+
+// resolve evaluates the publish state of the products against the current clock, fills
+// the fields the variants inherit from their parent and computes their effective price
+// resolve(products []internal.TProduct) -> []internal.TProduct
+// Args:
+//		products: Products to evaluate. The slice is updated in place
+// Return:
+//		[]internal.TProduct: Evaluated products
+
+func (p *ProductServiceDefault) resolve(products []internal.TProduct) []internal.TProduct {
+	/* Index the parents as stored */
+	parents := make(map[int]internal.TProduct)
+	for _, product := range products {
+		if product.ParentID == nil {
+			parents[product.ID] = product
+		}
+	}
+	for _, product := range products {
+		if product.ParentID == nil {
+			continue
+		}
+		if _, ok := parents[*product.ParentID]; !ok {
+			if parent, err := p.repository.GetProductByID(*product.ParentID); err == nil {
+				parents[parent.ID] = parent
+			}
+		}
+	}
+
+	/* Evaluate every product */
+	now := p.clock.Now()
+	for i := range products {
+		if products[i].ParentID == nil {
+			products[i].IsPublished = isVisible(products[i], now)
+			continue
+		}
+		inherit(&products[i], parents[*products[i].ParentID], now)
+	}
+
+	/* Price the products with the active promotions */
+	if p.pricing == nil {
+		return products
+	}
+	if prices, err := p.pricing.GetEffectivePrices(products, now); err == nil {
+		for i := range products {
+			price := prices[products[i].ID]
+			products[i].EffectivePrice = &price
+		}
+	}
+	return products
+}
+
+// publishState returns the event type of a change of the publish state of a product
+// publishState(published bool) -> string
+
+func publishState(published bool) string {
+	if published {
+		return internal.ProductEventPublished
+	}
+	return internal.ProductEventUnpublished
+}
+
+// productChanges returns the attributes told apart on the events which differ between two
+// stored versions of a product
+// productChanges(product internal.TProduct, current *internal.TProduct) -> []string
+
+func productChanges(product internal.TProduct, current *internal.TProduct) []string {
+	if current == nil {
+		return nil
+	}
+	var changes []string
+	if product.Price != current.Price {
+		changes = append(changes, internal.ProductChangePrice)
+	}
+	if product.Quantity != current.Quantity {
+		changes = append(changes, internal.ProductChangeQuantity)
+	}
+	return changes
+}
+
+// emit publishes an event of a product as the clients read it
+// emit(eventType string, product internal.TProduct, changes []string)
+// Args:
+//		eventType: Type of the event
+//		product:   Product as stored after the change
+//		changes:   Attributes changed (ProductChange constants)
+
+func (p *ProductServiceDefault) emit(eventType string, product internal.TProduct, changes []string) {
+	if p.events == nil {
+		return
+	}
+	resolved := p.resolve([]internal.TProduct{product})[0]
+	emitProductEvent(p.events, eventType, product.ID, &resolved, changes...)
+}
+
+// emitChange publishes the event of a stored change of a product: published or unpublished when
+// its publish state as read by the clients flipped, inserted when there was no product before,
+// updated otherwise
+// emitChange(product internal.TProduct, current *internal.TProduct)
+// Args:
+//		product: Product as stored after the change
+//		current: Product as stored before the change. Nil on insertions
+
+func (p *ProductServiceDefault) emitChange(product internal.TProduct, current *internal.TProduct) {
+	if p.events == nil {
+		return
+	}
+	eventType := internal.ProductEventInserted
+	if current != nil {
+		eventType = internal.ProductEventUpdated
+		before := p.resolve([]internal.TProduct{*current})[0]
+		if after := p.resolve([]internal.TProduct{product})[0]; before.IsPublished != after.IsPublished {
+			eventType = publishState(after.IsPublished)
+		}
+	}
+	p.emit(eventType, product, productChanges(product, current))
+}
+
 // GetAllProducts returns all the products in the repository
 // GetAllProducts() -> []internal.TProduct
 // Return:
 //		[]internal.TProduct: Slice of products
 
 func (p *ProductServiceDefault) GetAllProducts() []internal.TProduct {
-	return p.repository.GetAllProducts()
+	return p.resolve(p.repository.GetAllProducts())
 }
 
 // GetProductByID returns a product by its id
@@ -48,8 +334,25 @@ func (p *ProductServiceDefault) GetProductByID(id int) (internal.TProduct, error
 	} else if err != nil {
 		return internal.TProduct{}, err
 	} else {
-		return product, nil
+		return p.resolve([]internal.TProduct{product})[0], nil
+	}
+}
+
+// GetStoredProductByID returns a product by its id as stored: its publish state is not
+// evaluated, it doesn't inherit from its parent and its price has no promotions applied
+// GetStoredProductByID(id int) -> (internal.TProduct, error)
+// Args:
+//		id: Product id
+// Return:
+//		internal.TProduct: Product found in the repository
+//		error: 			   Error raised during the execution (if exists)
+
+func (p *ProductServiceDefault) GetStoredProductByID(id int) (internal.TProduct, error) {
+	product, err := p.repository.GetProductByID(id)
+	if err == internal.ErrProductNotFound {
+		return internal.TProduct{}, internal.ErrProductNotExists
 	}
+	return product, err
 }
 
 // GetProductByPriceGt returns a slice of products with a price greater than the given price
@@ -60,7 +363,7 @@ func (p *ProductServiceDefault) GetProductByID(id int) (internal.TProduct, error
 //		[]internal.TProduct: Slice of products with a price greater than the given price
 
 func (p *ProductServiceDefault) GetProductByPriceGt(price float64) []internal.TProduct {
-	return p.repository.GetProductByPriceGt(price)
+	return p.resolve(p.repository.GetProductByPriceGt(price))
 }
 
 // EmptyValues checks if the product has empty values
@@ -124,33 +427,169 @@ func validateDate(date string) bool {
 		return false
 	}
 
-	/* Validate the date */
-	return day > 0 && day <= 31 && month > 0 && month <= 12 && year > 1900 && year <= 2024
+	/* Validate the date exists. Future years are valid: products expire ahead of today */
+	if day <= 0 || month <= 0 || month > 12 || year <= 1900 {
+		return false
+	}
+	normalized := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	return normalized.Day() == day && normalized.Month() == time.Month(month)
 }
 
-// InsertNewProduct inserts a new product into the repository
-// InsertNewProduct(product internal.TProduct) -> error
+// validatePublishWindow checks the product is withdrawn after it goes live
+// validatePublishWindow(product internal.TProduct) -> bool
 // Args:
-//		product: Product to insert
+//		product: Product to validate
+// Return:
+//		bool: True if the publish window is valid, false otherwise
+
+func validatePublishWindow(product internal.TProduct) bool {
+	if product.PublishAt == nil || product.UnpublishAt == nil {
+		return true
+	}
+	return product.UnpublishAt.After(*product.PublishAt)
+}
+
+// NormalizeTags lowercases and trims the tags, dropping the empty and duplicated ones
+// NormalizeTags(tags []string) -> []string
+// Args:
+//		tags: Tags to normalize
+// Return:
+//		[]string: Normalized tags in their original order (nil if there are none)
+
+func NormalizeTags(tags []string) []string {
+	var normalized []string
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// validateProduct validates the fields of a product and normalizes its tags
+// validateProduct(product *internal.TProduct) -> error
+// Args:
+//		product: Product to validate
 // Return:
 //		error: Error raised during the execution (if exists)
 
-func (p *ProductServiceDefault) InsertNewProduct(product *internal.TProduct) error {
+func validateProduct(product *internal.TProduct) error {
 	/* Empty fields validation */
 	if emptyFields := EmptyValues(*product); len(emptyFields) != 0 {
 		return fmt.Errorf("%w: %s", internal.ErrEmptyField, strings.Join(emptyFields, ", "))
 	}
+
+	/* Quantity validation */
+	if product.Quantity < 0 {
+		return internal.ErrInvalidMovementQuantity
+	}
+
 	/* Date validation */
 	if !validateDate(product.Expiration) {
 		return internal.ErrInvalidDate
 	}
 
-	/* Insert the new product into the repository */
+	/* Publish window validation */
+	if !validatePublishWindow(*product) {
+		return internal.ErrInvalidPublishWindow
+	}
+
+	/* Reorder levels validation */
+	if (product.ReorderPoint != nil && *product.ReorderPoint < 0) || product.ReorderQuantity < 0 {
+		return internal.ErrInvalidReorderLevels
+	}
+
+	/* Tags normalization */
+	product.Tags = NormalizeTags(product.Tags)
+	return nil
+}
+
+// InsertNewProduct inserts a new product into the repository
+// InsertNewProduct(product internal.TProduct) -> error
+// Args:
+//		product: Product to insert
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (p *ProductServiceDefault) InsertNewProduct(product *internal.TProduct) error {
+	/* Product validation */
+	if err := p.generateCode(product); err != nil {
+		return err
+	}
+	if err := validateProduct(product); err != nil {
+		return err
+	}
+
+	/* Variants are inserted through InsertNewVariant */
+	product.ParentID = nil
+	return p.insertProduct(product)
+}
+
+// insertProduct inserts an already validated product into the repository
+// insertProduct(product *internal.TProduct) -> error
+// Args:
+//		product: Product to insert
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (p *ProductServiceDefault) insertProduct(product *internal.TProduct) error {
+	product.EffectivePrice = nil
+	if err := p.checkTaxClass(product); err != nil {
+		return err
+	}
+
+	/* Without a ledger the quantity is stored as is */
+	if p.ledger == nil {
+		if err := p.repository.InsertNewProduct(product); err == internal.ErrProductCodeAlreadyExists {
+			return internal.ErrProductAlreadyExists
+		} else if err != nil {
+			return err
+		}
+		p.emitChange(*product, nil)
+		return p.recordPrice(*product)
+	}
+
+	/* The initial stock must be a valid receipt before the product is stored */
+	quantity := product.Quantity
+	if _, err := signedQuantity(internal.MovementReceipt, quantity); err != nil {
+		return err
+	}
+
+	/* Insert the new product into the repository with no stock. The ledger publishes the stock change */
+	product.Quantity = 0
 	if err := p.repository.InsertNewProduct(product); err == internal.ErrProductCodeAlreadyExists {
+		product.Quantity = quantity
 		return internal.ErrProductAlreadyExists
-	} else {
+	} else if err != nil {
+		product.Quantity = quantity
 		return err
 	}
+	p.emitChange(*product, nil)
+
+	/* Record the initial stock on the ledger as a lot expiring with the product. A product whose stock
+	   can't be recorded is removed */
+	movement := internal.TMovement{
+		ProductID:  product.ID,
+		Type:       internal.MovementReceipt,
+		Quantity:   quantity,
+		Reason:     "initial stock",
+		Reference:  fmt.Sprintf("product:%d", product.ID),
+		Expiration: product.Expiration,
+	}
+	product.Quantity = quantity
+	if err := p.ledger.PostMovement(&movement); err != nil {
+		if deleteErr := p.repository.DeleteProduct(product.ID); deleteErr != nil {
+			return fmt.Errorf("%w (product %d left without stock: %v)", err, product.ID, deleteErr)
+		}
+		emitProductEvent(p.events, internal.ProductEventDeleted, product.ID, nil)
+		product.ID = 0
+		return err
+	}
+	return p.recordPrice(*product)
 }
 
 // UpdateProduct updates a product it if it already exists
@@ -161,24 +600,128 @@ func (p *ProductServiceDefault) InsertNewProduct(product *internal.TProduct) err
 //		error: Error raised during the execution (if exists)
 
 func (p *ProductServiceDefault) UpdateProduct(product *internal.TProduct) error {
-	/* Empty fields validation */
-	if emptyFields := EmptyValues(*product); len(emptyFields) != 0 {
-		return fmt.Errorf("%w: %s", internal.ErrEmptyField, strings.Join(emptyFields, ", "))
+	/* Retrieve the stored product */
+	current, err := p.repository.GetProductByID(product.ID)
+	if err == internal.ErrProductNotFound {
+		return internal.ErrProductNotExists
+	} else if err != nil {
+		return err
 	}
 
-	/* Date validation */
-	if !validateDate(product.Expiration) {
-		return internal.ErrInvalidDate
+	/* Variants keep their parent and inherit the empty fields */
+	product.ParentID = current.ParentID
+	if current.ParentID != nil {
+		return p.updateVariant(product, current)
+	}
+
+	/* Product validation */
+	if err := validateProduct(product); err != nil {
+		return err
+	}
+	return p.updateProduct(product, current)
+}
+
+// updateProduct updates an already validated product recording its quantity change
+// updateProduct(product *internal.TProduct, current internal.TProduct) -> error
+// Args:
+//		product: Product to update
+//		current: Product as stored before the update
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (p *ProductServiceDefault) updateProduct(product *internal.TProduct, current internal.TProduct) error {
+	product.EffectivePrice = nil
+	if err := p.checkTaxClass(product); err != nil {
+		return err
 	}
 
-	/* Insert the new product into the repository */
+	/* Without a ledger the quantity is stored as is */
+	if p.ledger == nil {
+		if err := p.repository.UpdateProduct(product); err == internal.ErrProductCodeAlreadyExists {
+			return internal.ErrProductAlreadyExists
+		} else if err == internal.ErrProductNotFound {
+			return internal.ErrProductNotExists
+		} else if err != nil {
+			return err
+		}
+		p.emitChange(*product, &current)
+		return p.updatePrice(*product, current)
+	}
+
+	/* The quantity change must fit the stock of the default warehouse before anything is written */
+	quantity := product.Quantity
+	delta := quantity - current.Quantity
+	if delta < 0 {
+		if err := p.checkStock(product.ID, delta); err != nil {
+			return err
+		}
+	}
+
+	/* Update the product keeping the stored quantity. The ledger publishes the stock change */
+	product.Quantity = current.Quantity
 	if err := p.repository.UpdateProduct(product); err == internal.ErrProductCodeAlreadyExists {
 		return internal.ErrProductAlreadyExists
-	} else if err == internal.ErrProductNotFound {
-		return internal.ErrProductNotExists
-	} else {
+	} else if err != nil {
+		return err
+	}
+	p.emitChange(*product, &current)
+
+	/* Record the quantity change on the ledger */
+	if delta != 0 {
+		movement := internal.TMovement{
+			ProductID: product.ID,
+			Type:      internal.MovementCorrection,
+			Quantity:  delta,
+			Reason:    "product update",
+			Reference: fmt.Sprintf("product:%d", product.ID),
+		}
+		if err := p.ledger.PostMovement(&movement); err != nil {
+			return err
+		}
+	}
+	product.Quantity = quantity
+	return p.updatePrice(*product, current)
+}
+
+// checkStock checks a quantity change fits the stock the ledger holds on the default warehouse,
+// the one a product update corrects
+// checkStock(productID, delta int) -> error
+// Args:
+//		productID: Product id
+//		delta: 	   Signed quantity change
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (p *ProductServiceDefault) checkStock(productID, delta int) error {
+	levels, err := p.ledger.GetStockLevels(productID)
+	if err != nil {
 		return err
 	}
+	var stock int
+	for _, level := range levels {
+		if level.WarehouseID == internal.DefaultWarehouseID {
+			stock = level.Quantity
+		}
+	}
+	if stock+delta < 0 {
+		return internal.ErrInsufficientStock
+	}
+	return nil
+}
+
+// updatePrice records the list price of a product on the pricing if it changed
+// updatePrice(product, current internal.TProduct) -> error
+// Args:
+//		product: Product as updated
+//		current: Product as stored before the update
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (p *ProductServiceDefault) updatePrice(product, current internal.TProduct) error {
+	if product.Price == current.Price {
+		return nil
+	}
+	return p.recordPrice(product)
 }
 
 // DeleteProduct deletes a product from the repository
@@ -189,10 +732,248 @@ func (p *ProductServiceDefault) UpdateProduct(product *internal.TProduct) error
 //		error: Error raised during the execution (if exists)
 
 func (p *ProductServiceDefault) DeleteProduct(id int) error {
+	/* A product with variants can't be deleted */
+	for _, product := range p.repository.GetAllProducts() {
+		if product.ParentID != nil && *product.ParentID == id {
+			return internal.ErrProductHasVariants
+		}
+	}
+
 	/* Delete the product from the repository */
 	if err := p.repository.DeleteProduct(id); err == internal.ErrProductNotFound {
 		return internal.ErrProductNotExists
-	} else {
+	} else if err != nil {
+		return err
+	}
+	emitProductEvent(p.events, internal.ProductEventDeleted, id, nil)
+
+	/* Remove the records kept by id, so a product reusing it starts clean */
+	for _, dependent := range p.dependents {
+		if err := dependent.RemoveProduct(id); err != nil {
+			return fmt.Errorf("product %d deleted but its records could not be removed: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// expirationTime returns the moment a product expires: the end of its expiration day
+// expirationTime(date string) -> (time.Time, error)
+// Args:
+//		date: Expiration date with the format dd/mm/yyyy
+// Return:
+//		time.Time: Moment the product expires
+//		error: 	   Error raised during the execution (if exists)
+
+func expirationTime(date string) (time.Time, error) {
+	day, err := time.ParseInLocation("02/01/2006", date, time.Local)
+	if err != nil {
+		return time.Time{}, internal.ErrInvalidDate
+	}
+	return day.AddDate(0, 0, 1), nil
+}
+
+// GetExpiredProducts returns the published products which expired before the given time
+// GetExpiredProducts(at time.Time) -> []internal.TProduct
+// Args:
+//		at: Moment to compare the expiration against
+// Return:
+//		[]internal.TProduct: Slice of expired published products
+
+func (p *ProductServiceDefault) GetExpiredProducts(at time.Time) []internal.TProduct {
+	expired := make([]internal.TProduct, 0)
+	for _, product := range p.GetAllProducts() {
+		if !product.IsPublished {
+			continue
+		}
+		if expiration, err := expirationTime(product.Expiration); err == nil && !expiration.After(at) {
+			expired = append(expired, product)
+		}
+	}
+	return expired
+}
+
+// GetExpiringProducts returns the products which expire within the next days
+// GetExpiringProducts(days int) -> []internal.TProduct
+// Args:
+//		days: Amount of days to look ahead
+// Return:
+//		[]internal.TProduct: Slice of products expiring in the period
+
+func (p *ProductServiceDefault) GetExpiringProducts(days int) []internal.TProduct {
+	now := p.clock.Now()
+	limit := now.AddDate(0, 0, days)
+	expiring := make([]internal.TProduct, 0)
+	for _, product := range p.GetAllProducts() {
+		expiration, err := expirationTime(product.Expiration)
+		if err != nil {
+			continue
+		}
+		if expiration.After(now) && !expiration.After(limit) {
+			expiring = append(expiring, product)
+		}
+	}
+	return expiring
+}
+
+// GetReorderReport returns the products at or below their reorder point along with the
+// quantity to purchase: the reorder quantity, or more if needed to get above the point
+// GetReorderReport() -> []internal.TReorderSuggestion
+// Return:
+//		[]internal.TReorderSuggestion: Suggestions ordered by product id
+
+func (p *ProductServiceDefault) GetReorderReport() []internal.TReorderSuggestion {
+	report := make([]internal.TReorderSuggestion, 0)
+	for _, product := range p.GetAllProducts() {
+		if product.ReorderPoint == nil || product.Quantity > *product.ReorderPoint {
+			continue
+		}
+		suggested := *product.ReorderPoint - product.Quantity + 1
+		if product.ReorderQuantity > suggested {
+			suggested = product.ReorderQuantity
+		}
+		report = append(report, internal.TReorderSuggestion{
+			ProductID:         product.ID,
+			Name:              product.Name,
+			CodeValue:         product.CodeValue,
+			Quantity:          product.Quantity,
+			ReorderPoint:      *product.ReorderPoint,
+			SuggestedQuantity: suggested,
+		})
+	}
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].ProductID < report[j].ProductID
+	})
+	return report
+}
+
+// UnpublishProduct marks a product as not published
+// UnpublishProduct(id int) -> error
+// Args:
+//		id: Product id
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (p *ProductServiceDefault) UnpublishProduct(id int) error {
+	/* Get the product by its ID */
+	product, err := p.repository.GetProductByID(id)
+	if err == internal.ErrProductNotFound {
+		return internal.ErrProductNotExists
+	} else if err != nil {
+		return err
+	}
+
+	/* Unpublish the product dropping a publish moment already due */
+	current := product
+	product.IsPublished = false
+	if product.PublishAt != nil && !product.PublishAt.After(p.clock.Now()) {
+		product.PublishAt = nil
+	}
+	if err := p.repository.UpdateProduct(&product); err != nil {
 		return err
 	}
+	p.emitChange(product, &current)
+	return nil
+}
+
+// ApplyPublicationWindows flips the publish state of the products whose window is due.
+// The applied moments are cleared so they don't override later manual changes
+// ApplyPublicationWindows() -> ([]internal.TProduct, error)
+// Return:
+//		[]internal.TProduct: Products whose publish state was updated
+//		error: 				 Error raised during the execution (if exists)
+
+func (p *ProductServiceDefault) ApplyPublicationWindows() ([]internal.TProduct, error) {
+	now := p.clock.Now()
+	changed := make([]internal.TProduct, 0)
+	for _, product := range p.repository.GetAllProducts() {
+		publishDue := product.PublishAt != nil && !product.PublishAt.After(now)
+		unpublishDue := product.UnpublishAt != nil && !product.UnpublishAt.After(now)
+		if !publishDue && !unpublishDue {
+			continue
+		}
+
+		/* Store the evaluated state and clear the applied moments */
+		current := product
+		product.IsPublished = isVisible(product, now)
+		if publishDue {
+			product.PublishAt = nil
+		}
+		if unpublishDue {
+			product.UnpublishAt = nil
+		}
+		if err := p.repository.UpdateProduct(&product); err != nil {
+			return changed, err
+		}
+		/* The due window already shows on reads, so the stored state tells the flip */
+		eventType := internal.ProductEventUpdated
+		if product.IsPublished != current.IsPublished {
+			eventType = publishState(product.IsPublished)
+		}
+		p.emit(eventType, product, nil)
+		changed = append(changed, product)
+	}
+	return changed, nil
+}
+
+// NextPublicationChange returns the next moment a publish window of a product is due
+// NextPublicationChange() -> (time.Time, bool)
+// Return:
+//		time.Time: Next due moment
+//		bool: 	   False if there is no pending window
+
+func (p *ProductServiceDefault) NextPublicationChange() (time.Time, bool) {
+	var next time.Time
+	for _, product := range p.repository.GetAllProducts() {
+		for _, moment := range []*time.Time{product.PublishAt, product.UnpublishAt} {
+			if moment != nil && (next.IsZero() || moment.Before(next)) {
+				next = *moment
+			}
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// SearchProductsByTags returns the products with all (or any) of the given tags
+// SearchProductsByTags(tags []string, matchAll bool) -> ([]internal.TProduct, error)
+// Args:
+//		tags: 	  Tags to search. They are normalized before the search
+//		matchAll: If true the products must have every tag, otherwise at least one
+// Return:
+//		[]internal.TProduct: Products ordered by id
+//		error: 				 Error raised during the execution (if exists)
+
+func (p *ProductServiceDefault) SearchProductsByTags(tags []string, matchAll bool) ([]internal.TProduct, error) {
+	tags = NormalizeTags(tags)
+	if len(tags) == 0 {
+		return []internal.TProduct{}, nil
+	}
+	products, err := p.repository.GetProductsByTags(tags, matchAll)
+	if err != nil {
+		return nil, err
+	}
+	return p.resolve(products), nil
+}
+
+// GetTagCounts returns the tags in use ordered by usage (and name on ties)
+// GetTagCounts() -> ([]internal.TTagCount, error)
+// Return:
+//		[]internal.TTagCount: Tag usage counts
+//		error: 				  Error raised during the execution (if exists)
+
+func (p *ProductServiceDefault) GetTagCounts() ([]internal.TTagCount, error) {
+	tagCounts, err := p.repository.GetTagCounts()
+	if err != nil {
+		return nil, err
+	}
+	counts := make([]internal.TTagCount, 0, len(tagCounts))
+	for tag, count := range tagCounts {
+		counts = append(counts, internal.TTagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count == counts[j].Count {
+			return counts[i].Tag < counts[j].Tag
+		}
+		return counts[i].Count > counts[j].Count
+	})
+	return counts, nil
 }
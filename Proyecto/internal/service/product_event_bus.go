@@ -0,0 +1,205 @@
+package service
+
+import (
+	"proyecto/internal"
+	"proyecto/internal/clock"
+	"sync"
+)
+
+/* Product event bus defaults */
+const (
+	DefaultProductEventCapacity = 1000 // Events retained for the subscribers resuming a stream
+	productEventSubscriberQueue = 64   // Events a subscriber can lag behind before being dropped
+)
+
+// ProductEventBusDefault numbers the product events, retains the last ones on a storage so the
+// streams can be resumed, and delivers them to the subscribers
+type ProductEventBusDefault struct {
+	storage     internal.ProductEventStorage // Storage of the retained events
+	capacity    int                          // Events retained
+	clock       internal.Clock               // Clock the events are dated with
+	events      []internal.TProductEvent     // Retained events, oldest first. Loaded on first use
+	lastID      int64                        // Id of the last event published
+	loaded      bool                         // The retained events were read from the storage
+	subscribers map[chan internal.TProductEvent]struct{}
+	closed      bool       // Close was called: no more subscriptions are served
+	mu          sync.Mutex // Serializes the publications and the subscriptions
+}
+
+// NewProductEventBusDefault creates a new ProductEventBusDefault
+// NewProductEventBusDefault(storage internal.ProductEventStorage, capacity int) -> *ProductEventBusDefault
+// Args:
+//		storage:  Storage where the retained events are persisted
+//		capacity: Events retained. DefaultProductEventCapacity when not positive
+// Return:
+//		*ProductEventBusDefault: New ProductEventBusDefault instance
+
+func NewProductEventBusDefault(storage internal.ProductEventStorage, capacity int) *ProductEventBusDefault {
+	if capacity <= 0 {
+		capacity = DefaultProductEventCapacity
+	}
+	return &ProductEventBusDefault{
+		storage:     storage,
+		capacity:    capacity,
+		clock:       clock.NewClockSystem(),
+		subscribers: make(map[chan internal.TProductEvent]struct{}),
+	}
+}
+
+// SetClock sets the clock the events are dated with
+// SetClock(c internal.Clock)
+// Args:
+//		c: Clock to use
+
+func (b *ProductEventBusDefault) SetClock(c internal.Clock) {
+	b.clock = c
+}
+
+// load reads the retained events from the storage once. Must be called with the lock held
+// load() -> error
+
+func (b *ProductEventBusDefault) load() error {
+	if b.loaded {
+		return nil
+	}
+	events, err := b.storage.GetAll()
+	if err != nil {
+		return err
+	}
+	b.events = events
+	if len(events) > 0 {
+		b.lastID = events[len(events)-1].ID
+	}
+	b.loaded = true
+	return nil
+}
+
+// Publish numbers and dates an event, retains it and delivers it to the subscribers. The
+// subscribers lagging behind are dropped, so they resume from the retained events
+// Publish(event *internal.TProductEvent) -> error
+// Args:
+//		event: Event to publish. Its id and date are filled
+// Return:
+//		error: Error raised during the execution (if exists). The event is delivered even if it can't be retained
+
+func (b *ProductEventBusDefault) Publish(event *internal.TProductEvent) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	err := b.load()
+
+	/* Number the event after the retained ones */
+	b.lastID++
+	event.ID = b.lastID
+	event.Date = b.clock.Now()
+
+	/* Deliver it to the subscribers */
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- *event:
+		default:
+			delete(b.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	/* Retain the last events */
+	b.events = append(b.events, *event)
+	if len(b.events) > b.capacity {
+		b.events = append([]internal.TProductEvent(nil), b.events[len(b.events)-b.capacity:]...)
+	}
+	return b.storage.WriteAll(b.events)
+}
+
+// Since returns the retained events published after an event
+// Since(lastID int64) -> ([]internal.TProductEvent, error)
+// Args:
+//		lastID: Id of the last event received. 0 to get every retained event
+// Return:
+//		[]internal.TProductEvent: Events after lastID, oldest first
+//		error: 					  ErrProductEventsEvicted if some events after lastID are no longer
+//								  retained, or lastID was never published. The retained events
+//								  are returned along with it
+
+func (b *ProductEventBusDefault) Since(lastID int64) ([]internal.TProductEvent, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.load(); err != nil {
+		return nil, err
+	}
+
+	/* An id never published or older than the retained events can't be resumed */
+	var err error
+	if lastID > b.lastID || (lastID > 0 && len(b.events) > 0 && b.events[0].ID > lastID+1) {
+		err = internal.ErrProductEventsEvicted
+	}
+	if lastID > b.lastID {
+		lastID = 0
+	}
+
+	events := make([]internal.TProductEvent, 0)
+	for _, event := range b.events {
+		if event.ID > lastID {
+			events = append(events, event)
+		}
+	}
+	return events, err
+}
+
+// Subscribe registers a subscriber of the events published from now on
+// Subscribe() -> (<-chan internal.TProductEvent, func())
+// Return:
+//		<-chan internal.TProductEvent: Events published. Closed when the subscriber lags behind or the bus closes
+//		func(): 					   Cancels the subscription
+
+func (b *ProductEventBusDefault) Subscribe() (<-chan internal.TProductEvent, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subscriber := make(chan internal.TProductEvent, productEventSubscriberQueue)
+	if b.closed {
+		close(subscriber)
+		return subscriber, func() {}
+	}
+	b.subscribers[subscriber] = struct{}{}
+	return subscriber, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[subscriber]; ok {
+			delete(b.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
+
+// Close ends the subscriptions, so the open streams finish before a shutdown
+// Close()
+
+func (b *ProductEventBusDefault) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for subscriber := range b.subscribers {
+		delete(b.subscribers, subscriber)
+		close(subscriber)
+	}
+}
+
+// emitProductEvent publishes the event of a product change on a bus (if any). The change is
+// already stored, so a failure to retain the event doesn't undo it
+// emitProductEvent(bus internal.ProductEventBus, eventType string, productID int, product *internal.TProduct, changes ...string)
+// Args:
+//		bus: 	   Bus to publish on. Nil to skip the event
+//		eventType: Type of the event
+//		productID: Product changed
+//		product:   Product after the change. Nil on deletions
+//		changes:   Attributes changed (ProductChange constants)
+
+func emitProductEvent(bus internal.ProductEventBus, eventType string, productID int, product *internal.TProduct, changes ...string) {
+	if bus == nil {
+		return
+	}
+	bus.Publish(&internal.TProductEvent{Type: eventType, ProductID: productID, Product: product, Changes: changes})
+}
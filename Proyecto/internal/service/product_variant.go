@@ -0,0 +1,258 @@
+package service
+
+import (
+	"proyecto/internal"
+	"time"
+)
+
+// inherit fills the fields a variant shares with its parent
+// inherit(variant *internal.TProduct, parent internal.TProduct, now time.Time)
+// Args:
+//		variant: Variant to fill
+//		parent:  Parent product as stored
+//		now: 	 Moment the publish state is evaluated at
+
+func inherit(variant *internal.TProduct, parent internal.TProduct, now time.Time) {
+	if variant.Name == "" {
+		variant.Name = parent.Name
+	}
+	if len(variant.Tags) == 0 {
+		variant.Tags = parent.Tags
+	}
+	if variant.TaxClass == "" {
+		variant.TaxClass = parent.TaxClass
+	}
+	if len(parent.Attributes) > 0 {
+		attributes := make(map[string]string, len(parent.Attributes)+len(variant.Attributes))
+		for key, value := range parent.Attributes {
+			attributes[key] = value
+		}
+		for key, value := range variant.Attributes {
+			attributes[key] = value
+		}
+		variant.Attributes = attributes
+	}
+
+	/* A variant is only visible while its parent is */
+	variant.IsPublished = isVisible(*variant, now) && isVisible(parent, now)
+}
+
+// detach clears the fields of a variant which only repeat the values inherited from its parent,
+// so later changes on the parent keep reaching it
+// detach(variant *internal.TProduct, parent internal.TProduct)
+// Args:
+//		variant: Variant to clear
+//		parent:  Parent product as stored
+
+func detach(variant *internal.TProduct, parent internal.TProduct) {
+	if variant.Name == parent.Name {
+		variant.Name = ""
+	}
+	if equalTags(variant.Tags, parent.Tags) {
+		variant.Tags = nil
+	}
+	if variant.TaxClass == parent.TaxClass {
+		variant.TaxClass = ""
+	}
+	for key, value := range variant.Attributes {
+		if parentValue, ok := parent.Attributes[key]; ok && parentValue == value {
+			delete(variant.Attributes, key)
+		}
+	}
+	if len(variant.Attributes) == 0 {
+		variant.Attributes = nil
+	}
+
+	/* Variants follow the publish window of their parent */
+	variant.PublishAt = nil
+	variant.UnpublishAt = nil
+}
+
+// equalTags checks if two slices of tags are equal
+// equalTags(a, b []string) -> bool
+
+func equalTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// getParent returns a product which can hold variants
+// getParent(parentID int) -> (internal.TProduct, error)
+// Args:
+//		parentID: Parent product id
+// Return:
+//		internal.TProduct: Parent product as stored
+//		error: 			   Error raised during the execution (if exists)
+
+func (p *ProductServiceDefault) getParent(parentID int) (internal.TProduct, error) {
+	parent, err := p.repository.GetProductByID(parentID)
+	if err == internal.ErrProductNotFound {
+		return internal.TProduct{}, internal.ErrProductNotExists
+	} else if err != nil {
+		return internal.TProduct{}, err
+	}
+
+	/* Variants can't have variants */
+	if parent.ParentID != nil {
+		return internal.TProduct{}, internal.ErrInvalidVariantParent
+	}
+	return parent, nil
+}
+
+// validateVariant validates a variant filling the inherited fields on a copy
+// validateVariant(variant *internal.TProduct, parent internal.TProduct) -> error
+// Args:
+//		variant: Variant to validate. Its tags are normalized
+//		parent:  Parent product
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func validateVariant(variant *internal.TProduct, parent internal.TProduct) error {
+	checked := *variant
+	if checked.Name == "" {
+		checked.Name = parent.Name
+	}
+	if err := validateProduct(&checked); err != nil {
+		return err
+	}
+	variant.Tags = checked.Tags
+	return nil
+}
+
+// GetVariants returns the variants of a product
+// GetVariants(parentID int) -> ([]internal.TProduct, error)
+// Args:
+//		parentID: Parent product id
+// Return:
+//		[]internal.TProduct: Variants with their inherited fields, ordered by id
+//		error: 				 Error raised during the execution (if exists)
+
+func (p *ProductServiceDefault) GetVariants(parentID int) ([]internal.TProduct, error) {
+	/* Check the parent exists */
+	if _, err := p.getParent(parentID); err != nil {
+		return nil, err
+	}
+
+	/* Filter the variants */
+	variants := make([]internal.TProduct, 0)
+	for _, product := range p.repository.GetAllProducts() {
+		if product.ParentID != nil && *product.ParentID == parentID {
+			variants = append(variants, product)
+		}
+	}
+	return p.resolve(variants), nil
+}
+
+// InsertNewVariant inserts a new variant under a product. Its code is unique across the whole
+// catalog as variants are stored along with the products
+// InsertNewVariant(parentID int, variant *internal.TProduct) -> error
+// Args:
+//		parentID: Parent product id
+//		variant:  Variant to insert. Empty name, tags and attributes are inherited from the parent
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (p *ProductServiceDefault) InsertNewVariant(parentID int, variant *internal.TProduct) error {
+	/* Retrieve the parent */
+	parent, err := p.getParent(parentID)
+	if err != nil {
+		return err
+	}
+
+	/* Variant validation */
+	variant.ParentID = &parentID
+	variant.IsPublished = true
+	if err := p.generateCode(variant); err != nil {
+		return err
+	}
+	if err := validateVariant(variant, parent); err != nil {
+		return err
+	}
+	detach(variant, parent)
+
+	/* Insert the variant */
+	if err := p.insertProduct(variant); err != nil {
+		return err
+	}
+	*variant = p.resolve([]internal.TProduct{*variant})[0]
+	return nil
+}
+
+// updateVariant updates a variant keeping the inherited fields linked to its parent
+// updateVariant(variant *internal.TProduct, current internal.TProduct) -> error
+// Args:
+//		variant: Variant to update
+//		current: Variant as stored before the update
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (p *ProductServiceDefault) updateVariant(variant *internal.TProduct, current internal.TProduct) error {
+	/* Retrieve the parent */
+	parent, err := p.getParent(*current.ParentID)
+	if err != nil {
+		return err
+	}
+
+	/* Variant validation */
+	if err := validateVariant(variant, parent); err != nil {
+		return err
+	}
+
+	/* While the parent is hidden the evaluated state says nothing about the variant */
+	if !isVisible(parent, p.clock.Now()) {
+		variant.IsPublished = current.IsPublished
+	}
+	detach(variant, parent)
+	return p.updateProduct(variant, current)
+}
+
+// UpdateVariant updates a variant of a product
+// UpdateVariant(parentID int, variant *internal.TProduct) -> error
+// Args:
+//		parentID: Parent product id
+//		variant:  Variant to update
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (p *ProductServiceDefault) UpdateVariant(parentID int, variant *internal.TProduct) error {
+	/* Check the variant belongs to the parent */
+	current, err := p.repository.GetProductByID(variant.ID)
+	if err == internal.ErrProductNotFound || (err == nil && (current.ParentID == nil || *current.ParentID != parentID)) {
+		return internal.ErrProductNotExists
+	} else if err != nil {
+		return err
+	}
+
+	if err := p.UpdateProduct(variant); err != nil {
+		return err
+	}
+	*variant = p.resolve([]internal.TProduct{*variant})[0]
+	return nil
+}
+
+// DeleteVariant deletes a variant of a product
+// DeleteVariant(parentID, variantID int) -> error
+// Args:
+//		parentID:  Parent product id
+//		variantID: Variant id
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (p *ProductServiceDefault) DeleteVariant(parentID, variantID int) error {
+	/* Check the variant belongs to the parent */
+	current, err := p.repository.GetProductByID(variantID)
+	if err == internal.ErrProductNotFound || (err == nil && (current.ParentID == nil || *current.ParentID != parentID)) {
+		return internal.ErrProductNotExists
+	} else if err != nil {
+		return err
+	}
+
+	return p.DeleteProduct(variantID)
+}
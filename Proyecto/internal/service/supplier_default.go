@@ -0,0 +1,381 @@
+package service
+
+import (
+	"fmt"
+	"proyecto/internal"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type SupplierServiceDefault struct {
+	repository internal.SupplierRepository // Repository of the suppliers
+	products   internal.ProductService     // Service of the supplied products
+	mu         sync.Mutex                  // Serializes the supplier updates
+}
+
+// NewSupplierServiceDefault creates a new SupplierServiceDefault instance
+// NewSupplierServiceDefault(sr internal.SupplierRepository, ps internal.ProductService) -> *SupplierServiceDefault
+// Args:
+//		sr: Supplier repository
+//		ps: Product service used to resolve the supplied products
+// Return:
+//		*SupplierServiceDefault: New SupplierServiceDefault instance
+
+func NewSupplierServiceDefault(sr internal.SupplierRepository, ps internal.ProductService) *SupplierServiceDefault {
+	return &SupplierServiceDefault{
+		repository: sr,
+		products:   ps,
+	}
+}
+
+// GetAllSuppliers returns all the suppliers
+// GetAllSuppliers() -> ([]internal.TSupplier, error)
+// Return:
+//		[]internal.TSupplier: Slice of suppliers
+//		error: 				  Error raised during the execution (if exists)
+
+func (s *SupplierServiceDefault) GetAllSuppliers() ([]internal.TSupplier, error) {
+	return s.repository.GetAllSuppliers()
+}
+
+// GetSupplierByID returns a supplier by its id
+// GetSupplierByID(id int) -> (internal.TSupplier, error)
+// Args:
+//		id: Supplier id
+// Return:
+//		internal.TSupplier: Supplier found in the repository
+//		error: 				Error raised during the execution (if exists)
+
+func (s *SupplierServiceDefault) GetSupplierByID(id int) (internal.TSupplier, error) {
+	supplier, err := s.repository.GetSupplierByID(id)
+	if err == internal.ErrSupplierNotFound {
+		return internal.TSupplier{}, internal.ErrSupplierNotExists
+	}
+	return supplier, err
+}
+
+// validateSupplier checks the name of a supplier is present and unique
+// validateSupplier(supplier internal.TSupplier, suppliers []internal.TSupplier) -> error
+// Args:
+//		supplier:  Supplier to validate
+//		suppliers: Current suppliers
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func validateSupplier(supplier internal.TSupplier, suppliers []internal.TSupplier) error {
+	if strings.TrimSpace(supplier.Name) == "" {
+		return fmt.Errorf("%w: %s", internal.ErrEmptyField, "Name")
+	}
+	for _, other := range suppliers {
+		if other.ID != supplier.ID && strings.EqualFold(other.Name, supplier.Name) {
+			return internal.ErrSupplierAlreadyExists
+		}
+	}
+	return nil
+}
+
+// InsertNewSupplier inserts a new supplier with no products
+// InsertNewSupplier(supplier *internal.TSupplier) -> error
+// Args:
+//		supplier: Supplier to insert
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (s *SupplierServiceDefault) InsertNewSupplier(supplier *internal.TSupplier) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	/* Supplier validation */
+	suppliers, err := s.repository.GetAllSuppliers()
+	if err != nil {
+		return err
+	}
+	supplier.ID = 0
+	if err := validateSupplier(*supplier, suppliers); err != nil {
+		return err
+	}
+
+	/* Products are linked through LinkProduct */
+	supplier.Products = []internal.TSupplyTerms{}
+	return s.repository.InsertNewSupplier(supplier)
+}
+
+// UpdateSupplier updates the contact data of a supplier
+// UpdateSupplier(supplier *internal.TSupplier) -> error
+// Args:
+//		supplier: Supplier to update. Its products are kept
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (s *SupplierServiceDefault) UpdateSupplier(supplier *internal.TSupplier) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	/* Retrieve the stored supplier */
+	current, err := s.GetSupplierByID(supplier.ID)
+	if err != nil {
+		return err
+	}
+
+	/* Supplier validation */
+	suppliers, err := s.repository.GetAllSuppliers()
+	if err != nil {
+		return err
+	}
+	if err := validateSupplier(*supplier, suppliers); err != nil {
+		return err
+	}
+
+	supplier.Products = current.Products
+	return s.repository.UpdateSupplier(supplier)
+}
+
+// checkOtherSources checks every published product of a supplier has another supplier
+// checkOtherSources(supplierID int, productIDs []int) -> error
+// Args:
+//		supplierID: Supplier about to stop supplying the products
+//		productIDs: Products the supplier stops supplying
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (s *SupplierServiceDefault) checkOtherSources(supplierID int, productIDs []int) error {
+	suppliers, err := s.repository.GetAllSuppliers()
+	if err != nil {
+		return err
+	}
+	for _, productID := range productIDs {
+		product, err := s.products.GetProductByID(productID)
+		if err == internal.ErrProductNotExists {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if !product.IsPublished {
+			continue
+		}
+		sourced := false
+		for _, other := range suppliers {
+			if other.ID != supplierID && termsIndex(other.Products, productID) >= 0 {
+				sourced = true
+				break
+			}
+		}
+		if !sourced {
+			return fmt.Errorf("%w: product %d", internal.ErrSupplierIsOnlySource, productID)
+		}
+	}
+	return nil
+}
+
+// termsIndex returns the position of the terms of a product (-1 if the product is not supplied)
+// termsIndex(terms []internal.TSupplyTerms, productID int) -> int
+
+func termsIndex(terms []internal.TSupplyTerms, productID int) int {
+	for i, value := range terms {
+		if value.ProductID == productID {
+			return i
+		}
+	}
+	return -1
+}
+
+// DeleteSupplier deletes a supplier unless it is the only source of a published product
+// DeleteSupplier(id int) -> error
+// Args:
+//		id: Supplier id
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (s *SupplierServiceDefault) DeleteSupplier(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	/* Retrieve the supplier */
+	supplier, err := s.GetSupplierByID(id)
+	if err != nil {
+		return err
+	}
+
+	/* The published products must keep a source */
+	productIDs := make([]int, 0, len(supplier.Products))
+	for _, terms := range supplier.Products {
+		productIDs = append(productIDs, terms.ProductID)
+	}
+	if err := s.checkOtherSources(id, productIDs); err != nil {
+		return err
+	}
+
+	if err := s.repository.DeleteSupplier(id); err == internal.ErrSupplierNotFound {
+		return internal.ErrSupplierNotExists
+	} else {
+		return err
+	}
+}
+
+// GetProductsBySupplier returns the products of a supplier along with their terms
+// GetProductsBySupplier(id int) -> ([]internal.TSuppliedProduct, error)
+// Args:
+//		id: Supplier id
+// Return:
+//		[]internal.TSuppliedProduct: Products ordered by id. Deleted products are skipped
+//		error: 						 Error raised during the execution (if exists)
+
+func (s *SupplierServiceDefault) GetProductsBySupplier(id int) ([]internal.TSuppliedProduct, error) {
+	supplier, err := s.GetSupplierByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	products := make([]internal.TSuppliedProduct, 0, len(supplier.Products))
+	for _, terms := range supplier.Products {
+		product, err := s.products.GetProductByID(terms.ProductID)
+		if err == internal.ErrProductNotExists {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		products = append(products, internal.TSuppliedProduct{
+			TProduct:     product,
+			Cost:         terms.Cost,
+			LeadTimeDays: terms.LeadTimeDays,
+		})
+	}
+	sort.Slice(products, func(i, j int) bool {
+		return products[i].ID < products[j].ID
+	})
+	return products, nil
+}
+
+// GetSuppliersByProduct returns the suppliers of a product along with their terms
+// GetSuppliersByProduct(productID int) -> ([]internal.TProductSupplier, error)
+// Args:
+//		productID: Product id
+// Return:
+//		[]internal.TProductSupplier: Suppliers ordered by cost (and id on ties)
+//		error: 						 Error raised during the execution (if exists)
+
+func (s *SupplierServiceDefault) GetSuppliersByProduct(productID int) ([]internal.TProductSupplier, error) {
+	/* Check the product exists */
+	if _, err := s.products.GetProductByID(productID); err != nil {
+		return nil, err
+	}
+
+	suppliers, err := s.repository.GetAllSuppliers()
+	if err != nil {
+		return nil, err
+	}
+	sources := make([]internal.TProductSupplier, 0)
+	for _, supplier := range suppliers {
+		if i := termsIndex(supplier.Products, productID); i >= 0 {
+			sources = append(sources, internal.TProductSupplier{
+				SupplierID:   supplier.ID,
+				Name:         supplier.Name,
+				Cost:         supplier.Products[i].Cost,
+				LeadTimeDays: supplier.Products[i].LeadTimeDays,
+			})
+		}
+	}
+	sort.SliceStable(sources, func(i, j int) bool {
+		return sources[i].Cost < sources[j].Cost
+	})
+	return sources, nil
+}
+
+// LinkProduct adds a product to a supplier or updates its terms if already supplied
+// LinkProduct(id int, terms internal.TSupplyTerms) -> error
+// Args:
+//		id: 	Supplier id
+//		terms: 	Terms of the product
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (s *SupplierServiceDefault) LinkProduct(id int, terms internal.TSupplyTerms) error {
+	/* Terms validation */
+	if terms.Cost <= 0 || terms.LeadTimeDays < 0 {
+		return fmt.Errorf("%w: %s", internal.ErrInvalidSupplyTerms, "cost must be positive and lead time not negative")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	/* Retrieve the supplier and the product */
+	supplier, err := s.GetSupplierByID(id)
+	if err != nil {
+		return err
+	}
+	if _, err := s.products.GetProductByID(terms.ProductID); err != nil {
+		return err
+	}
+
+	/* Add or replace the terms */
+	if i := termsIndex(supplier.Products, terms.ProductID); i >= 0 {
+		supplier.Products[i] = terms
+	} else {
+		supplier.Products = append(supplier.Products, terms)
+		sort.Slice(supplier.Products, func(i, j int) bool {
+			return supplier.Products[i].ProductID < supplier.Products[j].ProductID
+		})
+	}
+	return s.repository.UpdateSupplier(&supplier)
+}
+
+// UnlinkProduct removes a product from a supplier unless it is its only source while published
+// UnlinkProduct(id, productID int) -> error
+// Args:
+//		id: 	   Supplier id
+//		productID: Product id
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (s *SupplierServiceDefault) UnlinkProduct(id, productID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	/* Retrieve the supplier */
+	supplier, err := s.GetSupplierByID(id)
+	if err != nil {
+		return err
+	}
+	i := termsIndex(supplier.Products, productID)
+	if i < 0 {
+		return internal.ErrProductNotExists
+	}
+
+	/* The product must keep a source while published */
+	if err := s.checkOtherSources(id, []int{productID}); err != nil {
+		return err
+	}
+
+	supplier.Products = append(supplier.Products[:i], supplier.Products[i+1:]...)
+	return s.repository.UpdateSupplier(&supplier)
+}
+
+// RemoveProduct removes the terms of a deleted product from every supplier, so a new product
+// reusing its id doesn't inherit them
+// RemoveProduct(productID int) -> error
+// Args:
+//		productID: Id of the deleted product
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (s *SupplierServiceDefault) RemoveProduct(productID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	suppliers, err := s.repository.GetAllSuppliers()
+	if err != nil {
+		return err
+	}
+	for _, supplier := range suppliers {
+		i := termsIndex(supplier.Products, productID)
+		if i < 0 {
+			continue
+		}
+		supplier.Products = append(supplier.Products[:i], supplier.Products[i+1:]...)
+		if err := s.repository.UpdateSupplier(&supplier); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"proyecto/internal"
+	"strings"
+)
+
+type TaxServiceDefault struct {
+	storage internal.TaxRateStorage // Storage of the tax rates table, read on every use so edits apply without a restart
+}
+
+// NewTaxServiceDefault creates a new TaxServiceDefault instance
+// NewTaxServiceDefault(ts internal.TaxRateStorage) -> *TaxServiceDefault
+// Args:
+//		ts: Storage of the tax rates table
+// Return:
+//		*TaxServiceDefault: New TaxServiceDefault instance
+
+func NewTaxServiceDefault(ts internal.TaxRateStorage) *TaxServiceDefault {
+	return &TaxServiceDefault{storage: ts}
+}
+
+// roundCents rounds an amount to cents
+// roundCents(amount float64) -> float64
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// taxClassOf returns the tax class of a line, the standard one if it has none
+// taxClassOf(taxClass string) -> string
+
+func taxClassOf(taxClass string) string {
+	if taxClass == "" {
+		return internal.TaxClassStandard
+	}
+	return strings.ToLower(taxClass)
+}
+
+// ValidateTaxClass checks a tax class has a rate in every jurisdiction
+// ValidateTaxClass(taxClass string) -> error
+// Args:
+//		taxClass: Tax class to check. Empty means the standard one
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (t *TaxServiceDefault) ValidateTaxClass(taxClass string) error {
+	rates, err := t.storage.Load()
+	if err != nil {
+		return err
+	}
+	taxClass = taxClassOf(taxClass)
+	for jurisdiction, classes := range rates.Jurisdictions {
+		if _, ok := classes[taxClass]; !ok {
+			return fmt.Errorf("%w: %s in %s", internal.ErrUnknownTaxClass, taxClass, jurisdiction)
+		}
+	}
+	return nil
+}
+
+// ComputeTaxes computes the rate, tax and gross of lines holding their net amount
+// ComputeTaxes(lines []internal.TTaxLine, jurisdiction string) -> (internal.TTaxBreakdown, error)
+// Args:
+//		lines: 		  Lines with their product, quantity, tax class and net amount
+//		jurisdiction: Jurisdiction whose rates apply. Empty means the default one
+// Return:
+//		internal.TTaxBreakdown: Taxes of the lines and their totals
+//		error: 					Error raised during the execution (if exists)
+
+func (t *TaxServiceDefault) ComputeTaxes(lines []internal.TTaxLine, jurisdiction string) (internal.TTaxBreakdown, error) {
+	rates, err := t.storage.Load()
+	if err != nil {
+		return internal.TTaxBreakdown{}, err
+	}
+
+	/* Pick the jurisdiction and the rounding policy */
+	if jurisdiction == "" {
+		jurisdiction = rates.DefaultJurisdiction
+	}
+	jurisdiction = strings.ToUpper(jurisdiction)
+	classes, ok := rates.Jurisdictions[jurisdiction]
+	if !ok {
+		return internal.TTaxBreakdown{}, fmt.Errorf("%w: %s", internal.ErrJurisdictionNotExists, jurisdiction)
+	}
+	breakdown := internal.TTaxBreakdown{
+		Jurisdiction: jurisdiction,
+		Rounding:     internal.TaxRoundPerLine,
+		Lines:        make([]internal.TTaxLine, 0, len(lines)),
+	}
+	if rates.Rounding == internal.TaxRoundPerTotal {
+		breakdown.Rounding = internal.TaxRoundPerTotal
+	}
+
+	/* Tax every line */
+	var net, tax float64
+	for _, line := range lines {
+		line.TaxClass = taxClassOf(line.TaxClass)
+		rate, ok := classes[line.TaxClass]
+		if !ok {
+			return internal.TTaxBreakdown{}, fmt.Errorf("%w: %s in %s", internal.ErrUnknownTaxClass, line.TaxClass, jurisdiction)
+		}
+		line.Rate = rate
+		line.Net = roundCents(line.Net)
+		lineTax := line.Net * rate
+		line.Tax = roundCents(lineTax)
+		line.Gross = roundCents(line.Net + line.Tax)
+		breakdown.Lines = append(breakdown.Lines, line)
+
+		net += line.Net
+		if breakdown.Rounding == internal.TaxRoundPerLine {
+			tax += line.Tax
+		} else {
+			tax += lineTax
+		}
+	}
+
+	breakdown.Net = roundCents(net)
+	breakdown.Tax = roundCents(tax)
+	breakdown.Gross = roundCents(breakdown.Net + breakdown.Tax)
+	return breakdown, nil
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"fmt"
+	"proyecto/internal"
+	"strings"
+	"sync"
+)
+
+type WarehouseServiceDefault struct {
+	repository internal.WarehouseRepository // Repository of the warehouses
+	stock      internal.MovementService     // Ledger holding the stock of the warehouses
+	mu         sync.Mutex                   // Serializes the warehouse updates
+}
+
+// NewWarehouseServiceDefault creates a new WarehouseServiceDefault instance
+// NewWarehouseServiceDefault(wr internal.WarehouseRepository, ms internal.MovementService) -> *WarehouseServiceDefault
+// Args:
+//		wr: Warehouse repository
+//		ms: Movement service used to check the stock of a warehouse before deleting it
+// Return:
+//		*WarehouseServiceDefault: New WarehouseServiceDefault instance
+
+func NewWarehouseServiceDefault(wr internal.WarehouseRepository, ms internal.MovementService) *WarehouseServiceDefault {
+	return &WarehouseServiceDefault{
+		repository: wr,
+		stock:      ms,
+	}
+}
+
+// GetAllWarehouses returns all the warehouses
+// GetAllWarehouses() -> ([]internal.TWarehouse, error)
+// Return:
+//		[]internal.TWarehouse: Slice of warehouses
+//		error: 				   Error raised during the execution (if exists)
+
+func (w *WarehouseServiceDefault) GetAllWarehouses() ([]internal.TWarehouse, error) {
+	return w.repository.GetAllWarehouses()
+}
+
+// GetWarehouseByID returns a warehouse by its id
+// GetWarehouseByID(id int) -> (internal.TWarehouse, error)
+// Args:
+//		id: Warehouse id
+// Return:
+//		internal.TWarehouse: Warehouse found in the repository
+//		error: 				 Error raised during the execution (if exists)
+
+func (w *WarehouseServiceDefault) GetWarehouseByID(id int) (internal.TWarehouse, error) {
+	warehouse, err := w.repository.GetWarehouseByID(id)
+	if err == internal.ErrWarehouseNotFound {
+		return internal.TWarehouse{}, internal.ErrWarehouseNotExists
+	}
+	return warehouse, err
+}
+
+// validateWarehouse checks the required fields of a warehouse and the uniqueness of its code
+// validateWarehouse(warehouse *internal.TWarehouse) -> error
+// Args:
+//		warehouse: Warehouse to validate. Its code is trimmed and uppercased
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (w *WarehouseServiceDefault) validateWarehouse(warehouse *internal.TWarehouse) error {
+	/* Empty fields validation */
+	warehouse.Code = strings.ToUpper(strings.TrimSpace(warehouse.Code))
+	var emptyFields []string
+	if warehouse.Code == "" {
+		emptyFields = append(emptyFields, "Code")
+	}
+	if strings.TrimSpace(warehouse.Name) == "" {
+		emptyFields = append(emptyFields, "Name")
+	}
+	if len(emptyFields) != 0 {
+		return fmt.Errorf("%w: %s", internal.ErrEmptyField, strings.Join(emptyFields, ", "))
+	}
+
+	/* Code uniqueness validation */
+	warehouses, err := w.repository.GetAllWarehouses()
+	if err != nil {
+		return err
+	}
+	for _, other := range warehouses {
+		if other.ID != warehouse.ID && other.Code == warehouse.Code {
+			return internal.ErrWarehouseAlreadyExists
+		}
+	}
+	return nil
+}
+
+// InsertNewWarehouse inserts a new warehouse
+// InsertNewWarehouse(warehouse *internal.TWarehouse) -> error
+// Args:
+//		warehouse: Warehouse to insert. Its id is updated in place
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (w *WarehouseServiceDefault) InsertNewWarehouse(warehouse *internal.TWarehouse) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	warehouse.ID = 0
+	if err := w.validateWarehouse(warehouse); err != nil {
+		return err
+	}
+	return w.repository.InsertNewWarehouse(warehouse)
+}
+
+// UpdateWarehouse updates a warehouse if it exists
+// UpdateWarehouse(warehouse *internal.TWarehouse) -> error
+// Args:
+//		warehouse: Warehouse to update
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (w *WarehouseServiceDefault) UpdateWarehouse(warehouse *internal.TWarehouse) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, err := w.GetWarehouseByID(warehouse.ID); err != nil {
+		return err
+	}
+	if err := w.validateWarehouse(warehouse); err != nil {
+		return err
+	}
+	if err := w.repository.UpdateWarehouse(warehouse); err == internal.ErrWarehouseNotFound {
+		return internal.ErrWarehouseNotExists
+	} else {
+		return err
+	}
+}
+
+// DeleteWarehouse deletes a warehouse. The default warehouse and the ones holding stock are kept
+// DeleteWarehouse(id int) -> error
+// Args:
+//		id: Warehouse id
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (w *WarehouseServiceDefault) DeleteWarehouse(id int) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	/* Retrieve the warehouse */
+	if _, err := w.GetWarehouseByID(id); err != nil {
+		return err
+	}
+	if id == internal.DefaultWarehouseID {
+		return internal.ErrWarehouseIsDefault
+	}
+
+	/* Only empty warehouses can be deleted */
+	levels, err := w.stock.GetWarehouseStock(id)
+	if err != nil {
+		return err
+	}
+	if len(levels) != 0 {
+		return internal.ErrWarehouseHasStock
+	}
+
+	if err := w.repository.DeleteWarehouse(id); err == internal.ErrWarehouseNotFound {
+		return internal.ErrWarehouseNotExists
+	} else {
+		return err
+	}
+}
@@ -0,0 +1,479 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"proyecto/internal"
+	"proyecto/internal/clock"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+/* Webhook delivery defaults */
+const (
+	DefaultWebhookMaxAttempts = 6                // Failed attempts before a delivery is dead-lettered
+	DefaultWebhookBackoff     = 30 * time.Second // Wait after the first failed attempt, doubled on every new failure
+	DefaultWebhookMaxBackoff  = time.Hour        // Longest wait between two attempts
+)
+
+type WebhookServiceDefault struct {
+	webhooks    internal.WebhookRepository         // Repository of the webhooks
+	deliveries  internal.WebhookDeliveryRepository // Repository of the deliveries
+	clock       internal.Clock                     // Clock the deliveries are scheduled with
+	maxAttempts int                                // Failed attempts before a delivery is dead-lettered
+	backoff     time.Duration                      // Wait after the first failed attempt
+	maxBackoff  time.Duration                      // Longest wait between two attempts
+	mu          sync.Mutex                         // Serializes the webhook and delivery updates
+}
+
+// NewWebhookServiceDefault creates a new WebhookServiceDefault instance
+// NewWebhookServiceDefault(wr internal.WebhookRepository, dr internal.WebhookDeliveryRepository) -> *WebhookServiceDefault
+// Args:
+//		wr: Webhook repository
+//		dr: Webhook delivery repository
+// Return:
+//		*WebhookServiceDefault: New WebhookServiceDefault instance
+
+func NewWebhookServiceDefault(wr internal.WebhookRepository, dr internal.WebhookDeliveryRepository) *WebhookServiceDefault {
+	return &WebhookServiceDefault{
+		webhooks:    wr,
+		deliveries:  dr,
+		clock:       clock.NewClockSystem(),
+		maxAttempts: DefaultWebhookMaxAttempts,
+		backoff:     DefaultWebhookBackoff,
+		maxBackoff:  DefaultWebhookMaxBackoff,
+	}
+}
+
+// SetClock sets the clock the deliveries are scheduled with
+// SetClock(c internal.Clock)
+// Args:
+//		c: Clock to use
+
+func (s *WebhookServiceDefault) SetClock(c internal.Clock) {
+	s.clock = c
+}
+
+// SetRetryPolicy sets how the failed deliveries are retried. Non positive values keep the current ones
+// SetRetryPolicy(maxAttempts int, backoff, maxBackoff time.Duration)
+// Args:
+//		maxAttempts: Failed attempts before a delivery is dead-lettered
+//		backoff: 	 Wait after the first failed attempt, doubled on every new failure
+//		maxBackoff:  Longest wait between two attempts
+
+func (s *WebhookServiceDefault) SetRetryPolicy(maxAttempts int, backoff, maxBackoff time.Duration) {
+	if maxAttempts > 0 {
+		s.maxAttempts = maxAttempts
+	}
+	if backoff > 0 {
+		s.backoff = backoff
+	}
+	if maxBackoff > 0 {
+		s.maxBackoff = maxBackoff
+	}
+}
+
+// GetAllWebhooks returns all the webhooks
+// GetAllWebhooks() -> ([]internal.TWebhook, error)
+// Return:
+//		[]internal.TWebhook: Slice of webhooks
+//		error: 				 Error raised during the execution (if exists)
+
+func (s *WebhookServiceDefault) GetAllWebhooks() ([]internal.TWebhook, error) {
+	return s.webhooks.GetAllWebhooks()
+}
+
+// GetWebhookByID returns a webhook by its id
+// GetWebhookByID(id int) -> (internal.TWebhook, error)
+// Args:
+//		id: Webhook id
+// Return:
+//		internal.TWebhook: Webhook found in the repository
+//		error: 			   Error raised during the execution (if exists)
+
+func (s *WebhookServiceDefault) GetWebhookByID(id int) (internal.TWebhook, error) {
+	webhook, err := s.webhooks.GetWebhookByID(id)
+	if err == internal.ErrWebhookNotFound {
+		return internal.TWebhook{}, internal.ErrWebhookNotExists
+	}
+	return webhook, err
+}
+
+// validateWebhook checks the url, the event types and the secret of a webhook
+// validateWebhook(webhook *internal.TWebhook) -> error
+// Args:
+//		webhook: Webhook to validate. Its url is trimmed and its event types deduplicated
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func validateWebhook(webhook *internal.TWebhook) error {
+	/* Empty fields validation */
+	webhook.URL = strings.TrimSpace(webhook.URL)
+	var emptyFields []string
+	if webhook.URL == "" {
+		emptyFields = append(emptyFields, "URL")
+	}
+	if len(webhook.Events) == 0 {
+		emptyFields = append(emptyFields, "Events")
+	}
+	if webhook.Secret == "" {
+		emptyFields = append(emptyFields, "Secret")
+	}
+	if len(emptyFields) != 0 {
+		return fmt.Errorf("%w: %s", internal.ErrEmptyField, strings.Join(emptyFields, ", "))
+	}
+
+	/* The events are posted to an absolute http(s) url */
+	target, err := url.Parse(webhook.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Errorf("%w: %q", internal.ErrInvalidWebhookURL, webhook.URL)
+	}
+
+	/* Known event types, each one once */
+	events := make([]string, 0, len(webhook.Events))
+	seen := make(map[string]bool)
+	for _, event := range webhook.Events {
+		known := false
+		for _, eventType := range internal.WebhookEventTypes {
+			known = known || eventType == event
+		}
+		if !known {
+			return fmt.Errorf("%w: %q, expected one of %s", internal.ErrInvalidWebhookEvent, event, strings.Join(internal.WebhookEventTypes, ", "))
+		}
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+	webhook.Events = events
+	return nil
+}
+
+// InsertNewWebhook inserts a new webhook
+// InsertNewWebhook(webhook *internal.TWebhook) -> error
+// Args:
+//		webhook: Webhook to insert. Its id and creation date are updated in place
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (s *WebhookServiceDefault) InsertNewWebhook(webhook *internal.TWebhook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	webhook.ID = 0
+	if err := validateWebhook(webhook); err != nil {
+		return err
+	}
+	webhook.CreatedAt = s.clock.Now()
+	return s.webhooks.InsertNewWebhook(webhook)
+}
+
+// UpdateWebhook updates a webhook if it exists. An empty secret keeps the current one
+// UpdateWebhook(webhook *internal.TWebhook) -> error
+// Args:
+//		webhook: Webhook to update. Its secret and creation date are filled from the stored one
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (s *WebhookServiceDefault) UpdateWebhook(webhook *internal.TWebhook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, err := s.GetWebhookByID(webhook.ID)
+	if err != nil {
+		return err
+	}
+	if webhook.Secret == "" {
+		webhook.Secret = current.Secret
+	}
+	webhook.CreatedAt = current.CreatedAt
+	if err := validateWebhook(webhook); err != nil {
+		return err
+	}
+	if err := s.webhooks.UpdateWebhook(webhook); err == internal.ErrWebhookNotFound {
+		return internal.ErrWebhookNotExists
+	} else {
+		return err
+	}
+}
+
+// DeleteWebhook deletes a webhook. Its pending deliveries are dead-lettered, so the history is kept
+// DeleteWebhook(id int) -> error
+// Args:
+//		id: Webhook id
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func (s *WebhookServiceDefault) DeleteWebhook(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.webhooks.DeleteWebhook(id); err == internal.ErrWebhookNotFound {
+		return internal.ErrWebhookNotExists
+	} else if err != nil {
+		return err
+	}
+
+	deliveries, err := s.deliveries.GetAllDeliveries()
+	if err != nil {
+		return err
+	}
+	for _, delivery := range deliveries {
+		if delivery.WebhookID != id || delivery.Status != internal.WebhookDeliveryPending {
+			continue
+		}
+		delivery.Status = internal.WebhookDeliveryDead
+		delivery.NextAttemptAt = nil
+		if err := s.deliveries.UpdateDelivery(&delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Enqueue creates a delivery of a product event for every active webhook subscribed to it.
+// A webhook subscribed to several of the types the event matches gets a single delivery, made
+// for the most specific type
+// Enqueue(event internal.TProductEvent) -> ([]internal.TWebhookDelivery, error)
+// Args:
+//		event: Product event to deliver
+// Return:
+//		[]internal.TWebhookDelivery: Deliveries created, due immediately
+//		error: 						 Error raised during the execution (if exists)
+
+func (s *WebhookServiceDefault) Enqueue(event internal.TProductEvent) ([]internal.TWebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	webhooks, err := s.webhooks.GetAllWebhooks()
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	types := internal.WebhookEventTypesOf(event)
+	deliveries := make([]internal.TWebhookDelivery, 0)
+	for _, webhook := range webhooks {
+		eventType, ok := webhook.Subscribed(types)
+		if !webhook.Active || !ok {
+			continue
+		}
+		payload, err := json.Marshal(internal.TWebhookPayload{Type: eventType, Event: event})
+		if err != nil {
+			return nil, err
+		}
+		next := now
+		deliveries = append(deliveries, internal.TWebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventID:       event.ID,
+			EventType:     eventType,
+			Payload:       payload,
+			Status:        internal.WebhookDeliveryPending,
+			Attempts:      []internal.TWebhookAttempt{},
+			NextAttemptAt: &next,
+			CreatedAt:     now,
+		})
+	}
+	if len(deliveries) == 0 {
+		return deliveries, nil
+	}
+	if err := s.deliveries.InsertNewDeliveries(deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// newestFirst sorts deliveries from the newest to the oldest
+// newestFirst(deliveries []internal.TWebhookDelivery) -> []internal.TWebhookDelivery
+
+func newestFirst(deliveries []internal.TWebhookDelivery) []internal.TWebhookDelivery {
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].ID > deliveries[j].ID
+	})
+	return deliveries
+}
+
+// GetDeliveries returns the delivery history of a webhook
+// GetDeliveries(webhookID int) -> ([]internal.TWebhookDelivery, error)
+// Args:
+//		webhookID: Webhook id
+// Return:
+//		[]internal.TWebhookDelivery: Deliveries of the webhook with their attempts, newest first
+//		error: 						 Error raised during the execution (if exists)
+
+func (s *WebhookServiceDefault) GetDeliveries(webhookID int) ([]internal.TWebhookDelivery, error) {
+	if _, err := s.GetWebhookByID(webhookID); err != nil {
+		return nil, err
+	}
+	deliveries, err := s.deliveries.GetAllDeliveries()
+	if err != nil {
+		return nil, err
+	}
+	history := make([]internal.TWebhookDelivery, 0)
+	for _, delivery := range deliveries {
+		if delivery.WebhookID == webhookID {
+			history = append(history, delivery)
+		}
+	}
+	return newestFirst(history), nil
+}
+
+// GetDeadLetters returns the deliveries whose attempts all failed
+// GetDeadLetters() -> ([]internal.TWebhookDelivery, error)
+// Return:
+//		[]internal.TWebhookDelivery: Dead deliveries, newest first
+//		error: 						 Error raised during the execution (if exists)
+
+func (s *WebhookServiceDefault) GetDeadLetters() ([]internal.TWebhookDelivery, error) {
+	deliveries, err := s.deliveries.GetAllDeliveries()
+	if err != nil {
+		return nil, err
+	}
+	dead := make([]internal.TWebhookDelivery, 0)
+	for _, delivery := range deliveries {
+		if delivery.Status == internal.WebhookDeliveryDead {
+			dead = append(dead, delivery)
+		}
+	}
+	return newestFirst(dead), nil
+}
+
+// Redeliver queues a dead delivery for a new round of attempts, due immediately
+// Redeliver(id int) -> (internal.TWebhookDelivery, error)
+// Args:
+//		id: Delivery id
+// Return:
+//		internal.TWebhookDelivery: Delivery queued
+//		error: 					   Error raised during the execution (if exists)
+
+func (s *WebhookServiceDefault) Redeliver(id int) (internal.TWebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delivery, err := s.deliveries.GetDeliveryByID(id)
+	if err == internal.ErrWebhookDeliveryNotFound {
+		return internal.TWebhookDelivery{}, internal.ErrWebhookDeliveryNotExists
+	} else if err != nil {
+		return internal.TWebhookDelivery{}, err
+	}
+	if delivery.Status != internal.WebhookDeliveryDead {
+		return internal.TWebhookDelivery{}, internal.ErrWebhookDeliveryNotDead
+	}
+	if _, err := s.GetWebhookByID(delivery.WebhookID); err != nil {
+		return internal.TWebhookDelivery{}, err
+	}
+
+	next := s.clock.Now()
+	delivery.Status = internal.WebhookDeliveryPending
+	delivery.Failures = 0
+	delivery.NextAttemptAt = &next
+	if err := s.deliveries.UpdateDelivery(&delivery); err != nil {
+		return internal.TWebhookDelivery{}, err
+	}
+	return delivery, nil
+}
+
+// DueDeliveries returns the pending deliveries whose next attempt is due
+// DueDeliveries() -> ([]internal.TWebhookDelivery, error)
+// Return:
+//		[]internal.TWebhookDelivery: Due deliveries, oldest first
+//		error: 						 Error raised during the execution (if exists)
+
+func (s *WebhookServiceDefault) DueDeliveries() ([]internal.TWebhookDelivery, error) {
+	deliveries, err := s.deliveries.GetAllDeliveries()
+	if err != nil {
+		return nil, err
+	}
+	now := s.clock.Now()
+	due := make([]internal.TWebhookDelivery, 0)
+	for _, delivery := range deliveries {
+		if delivery.Status == internal.WebhookDeliveryPending && delivery.NextAttemptAt != nil && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	return due, nil
+}
+
+// NextAttempt returns the moment the next pending attempt is due
+// NextAttempt() -> (time.Time, bool)
+// Return:
+//		time.Time: Earliest next attempt of the pending deliveries
+//		bool: 	   False if there are no pending deliveries
+
+func (s *WebhookServiceDefault) NextAttempt() (time.Time, bool) {
+	deliveries, err := s.deliveries.GetAllDeliveries()
+	if err != nil {
+		return time.Time{}, false
+	}
+	var next time.Time
+	found := false
+	for _, delivery := range deliveries {
+		if delivery.Status != internal.WebhookDeliveryPending || delivery.NextAttemptAt == nil {
+			continue
+		}
+		if !found || delivery.NextAttemptAt.Before(next) {
+			next = *delivery.NextAttemptAt
+			found = true
+		}
+	}
+	return next, found
+}
+
+// retryWait returns the wait before the next attempt of a delivery: the backoff doubled on every
+// failure after the first one, up to the maximum backoff
+// retryWait(failures int) -> time.Duration
+
+func (s *WebhookServiceDefault) retryWait(failures int) time.Duration {
+	wait := s.backoff
+	for i := 1; i < failures && wait < s.maxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > s.maxBackoff {
+		wait = s.maxBackoff
+	}
+	return wait
+}
+
+// RecordAttempt stores an attempt of a delivery. A 2xx answer delivers it, otherwise the next
+// attempt is scheduled with exponential backoff until the failures reach the maximum attempts and
+// the delivery is dead-lettered
+// RecordAttempt(id int, attempt internal.TWebhookAttempt) -> (internal.TWebhookDelivery, error)
+// Args:
+//		id: 	 Delivery id
+//		attempt: Attempt made
+// Return:
+//		internal.TWebhookDelivery: Delivery updated
+//		error: 					   Error raised during the execution (if exists)
+
+func (s *WebhookServiceDefault) RecordAttempt(id int, attempt internal.TWebhookAttempt) (internal.TWebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delivery, err := s.deliveries.GetDeliveryByID(id)
+	if err == internal.ErrWebhookDeliveryNotFound {
+		return internal.TWebhookDelivery{}, internal.ErrWebhookDeliveryNotExists
+	} else if err != nil {
+		return internal.TWebhookDelivery{}, err
+	}
+
+	delivery.Attempts = append(delivery.Attempts, attempt)
+	delivery.NextAttemptAt = nil
+	switch {
+	case attempt.Error == "" && attempt.StatusCode >= 200 && attempt.StatusCode < 300:
+		delivery.Status = internal.WebhookDeliveryDelivered
+	case delivery.Failures+1 >= s.maxAttempts:
+		delivery.Failures++
+		delivery.Status = internal.WebhookDeliveryDead
+	default:
+		delivery.Failures++
+		next := attempt.Date.Add(s.retryWait(delivery.Failures))
+		delivery.NextAttemptAt = &next
+	}
+	if err := s.deliveries.UpdateDelivery(&delivery); err != nil {
+		return internal.TWebhookDelivery{}, err
+	}
+	return delivery, nil
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"proyecto/internal"
+	"sort"
+)
+
+// CartStorageDefault is the default implementation of CartStorage
+type CartStorageDefault struct {
+	filePath string // File path
+}
+
+// NewCartStorageDefault creates a new CartStorageDefault
+// NewCartStorageDefault(filePath string) -> *CartStorageDefault
+// Args:
+// 	filePath string: File path
+// Returns:
+// 	*CartStorageDefault: New CartStorageDefault
+
+func NewCartStorageDefault(filePath string) *CartStorageDefault {
+	return &CartStorageDefault{filePath: filePath}
+}
+
+// GetAll gets all the carts from the storage
+// GetAll() -> (map[int]TCart, error)
+// Return:
+//		map[int]TCart: Map of carts.
+//		error: 		    Error raised during the execution (if exists).
+
+func (c *CartStorageDefault) GetAll() (map[int]internal.TCart, error) {
+	/* Read the file content */
+	data, err := os.ReadFile(c.filePath)
+	if err != nil {
+		return nil, internal.ErrBadFile
+	}
+
+	/* Decode the carts */
+	var carts []internal.TCart
+	if err := json.Unmarshal(data, &carts); err != nil {
+		return nil, internal.ErrBadFile
+	}
+
+	/* Convert []TCart -> map[int]TCart */
+	cartMap := make(map[int]internal.TCart)
+	for _, cart := range carts {
+		cartMap[cart.ID] = cart
+	}
+	return cartMap, nil
+}
+
+// WriteAll writes all the carts to the storage
+// WriteAll(map[int]TCart) -> error
+// Args:
+//		carts: Map of carts.
+// Return:
+//		error: Error raised during the execution (if exists).
+
+func (c *CartStorageDefault) WriteAll(carts map[int]internal.TCart) error {
+	/* Open a file descriptor */
+	file, err := os.Create(c.filePath)
+	if err != nil {
+		return internal.ErrBadFile
+	}
+	defer file.Close()
+
+	/* Write all the carts into the storage carted by id */
+	cartSlice := make([]internal.TCart, 0, len(carts))
+	for _, value := range carts {
+		cartSlice = append(cartSlice, value)
+	}
+	sort.Slice(cartSlice, func(i, j int) bool {
+		return cartSlice[i].ID < cartSlice[j].ID
+	})
+	return json.NewEncoder(file).Encode(cartSlice)
+}
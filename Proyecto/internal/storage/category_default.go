@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"proyecto/internal"
+	"sort"
+)
+
+// CategoryStorageDefault is the default implementation of CategoryStorage
+type CategoryStorageDefault struct {
+	filePath string // File path
+}
+
+// NewCategoryStorageDefault creates a new CategoryStorageDefault
+// NewCategoryStorageDefault(filePath string) -> *CategoryStorageDefault
+// Args:
+// 	filePath string: File path
+// Returns:
+// 	*CategoryStorageDefault: New CategoryStorageDefault
+
+func NewCategoryStorageDefault(filePath string) *CategoryStorageDefault {
+	return &CategoryStorageDefault{filePath: filePath}
+}
+
+// GetAll gets all the categories from the storage
+// GetAll() -> (map[int]TCategory, error)
+// Return:
+//		map[int]TCategory: Map of categories.
+//		error: 		    Error raised during the execution (if exists).
+
+func (c *CategoryStorageDefault) GetAll() (map[int]internal.TCategory, error) {
+	/* Read the file content */
+	data, err := os.ReadFile(c.filePath)
+	if err != nil {
+		return nil, internal.ErrBadFile
+	}
+
+	/* Decode the categories */
+	var categories []internal.TCategory
+	if err := json.Unmarshal(data, &categories); err != nil {
+		return nil, internal.ErrBadFile
+	}
+
+	/* Convert []TCategory -> map[int]TCategory */
+	categoryMap := make(map[int]internal.TCategory)
+	for _, category := range categories {
+		categoryMap[category.ID] = category
+	}
+	return categoryMap, nil
+}
+
+// WriteAll writes all the categories to the storage
+// WriteAll(map[int]TCategory) -> error
+// Args:
+//		categories: Map of categories.
+// Return:
+//		error: Error raised during the execution (if exists).
+
+func (c *CategoryStorageDefault) WriteAll(categories map[int]internal.TCategory) error {
+	/* Open a file descriptor */
+	file, err := os.Create(c.filePath)
+	if err != nil {
+		return internal.ErrBadFile
+	}
+	defer file.Close()
+
+	/* Write all the categories into the storage ordered by id */
+	categorySlice := make([]internal.TCategory, 0, len(categories))
+	for _, value := range categories {
+		categorySlice = append(categorySlice, value)
+	}
+	sort.Slice(categorySlice, func(i, j int) bool {
+		return categorySlice[i].ID < categorySlice[j].ID
+	})
+	return json.NewEncoder(file).Encode(categorySlice)
+}
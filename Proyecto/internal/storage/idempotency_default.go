@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"proyecto/internal"
+	"sort"
+)
+
+// IdempotencyStorageDefault is the default implementation of IdempotencyStorage
+type IdempotencyStorageDefault struct {
+	filePath string // File path
+}
+
+// NewIdempotencyStorageDefault creates a new IdempotencyStorageDefault
+// NewIdempotencyStorageDefault(filePath string) -> *IdempotencyStorageDefault
+// Args:
+// 	filePath string: File path
+// Returns:
+// 	*IdempotencyStorageDefault: New IdempotencyStorageDefault
+
+func NewIdempotencyStorageDefault(filePath string) *IdempotencyStorageDefault {
+	return &IdempotencyStorageDefault{filePath: filePath}
+}
+
+// GetAll gets all the idempotency records from the storage
+// GetAll() -> (map[string]TIdempotencyRecord, error)
+// Return:
+//		map[string]TIdempotencyRecord: Map of records keyed by principal and key.
+//		error: 		    			   Error raised during the execution (if exists).
+
+func (i *IdempotencyStorageDefault) GetAll() (map[string]internal.TIdempotencyRecord, error) {
+	/* Read the file content */
+	data, err := os.ReadFile(i.filePath)
+	if err != nil {
+		return nil, internal.ErrBadFile
+	}
+
+	/* Decode the records */
+	var records []internal.TIdempotencyRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, internal.ErrBadFile
+	}
+
+	/* Convert []TIdempotencyRecord -> map[string]TIdempotencyRecord */
+	recordMap := make(map[string]internal.TIdempotencyRecord)
+	for _, record := range records {
+		recordMap[internal.IdempotencyRecordKey(record.Principal, record.Key)] = record
+	}
+	return recordMap, nil
+}
+
+// WriteAll writes all the idempotency records to the storage
+// WriteAll(map[string]TIdempotencyRecord) -> error
+// Args:
+//		records: Map of records.
+// Return:
+//		error: Error raised during the execution (if exists).
+
+func (i *IdempotencyStorageDefault) WriteAll(records map[string]internal.TIdempotencyRecord) error {
+	/* Open a file descriptor */
+	file, err := os.Create(i.filePath)
+	if err != nil {
+		return internal.ErrBadFile
+	}
+	defer file.Close()
+
+	/* Write all the records into the storage ordered by creation */
+	recordSlice := make([]internal.TIdempotencyRecord, 0, len(records))
+	for _, value := range records {
+		recordSlice = append(recordSlice, value)
+	}
+	sort.Slice(recordSlice, func(a, b int) bool {
+		if !recordSlice[a].CreatedAt.Equal(recordSlice[b].CreatedAt) {
+			return recordSlice[a].CreatedAt.Before(recordSlice[b].CreatedAt)
+		}
+		return internal.IdempotencyRecordKey(recordSlice[a].Principal, recordSlice[a].Key) <
+			internal.IdempotencyRecordKey(recordSlice[b].Principal, recordSlice[b].Key)
+	})
+	return json.NewEncoder(file).Encode(recordSlice)
+}
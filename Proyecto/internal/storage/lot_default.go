@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"proyecto/internal"
+	"sort"
+)
+
+// LotStorageDefault is the default implementation of LotStorage
+type LotStorageDefault struct {
+	filePath string // File path
+}
+
+// NewLotStorageDefault creates a new LotStorageDefault
+// NewLotStorageDefault(filePath string) -> *LotStorageDefault
+// Args:
+// 	filePath string: File path
+// Returns:
+// 	*LotStorageDefault: New LotStorageDefault
+
+func NewLotStorageDefault(filePath string) *LotStorageDefault {
+	return &LotStorageDefault{filePath: filePath}
+}
+
+// GetAll gets all the lots from the storage
+// GetAll() -> (map[int]TLot, error)
+// Return:
+//		map[int]TLot: Map of lots.
+//		error: 		    Error raised during the execution (if exists).
+
+func (l *LotStorageDefault) GetAll() (map[int]internal.TLot, error) {
+	/* Read the file content */
+	data, err := os.ReadFile(l.filePath)
+	if err != nil {
+		return nil, internal.ErrBadFile
+	}
+
+	/* Decode the lots */
+	var lots []internal.TLot
+	if err := json.Unmarshal(data, &lots); err != nil {
+		return nil, internal.ErrBadFile
+	}
+
+	/* Convert []TLot -> map[int]TLot */
+	lotMap := make(map[int]internal.TLot)
+	for _, lot := range lots {
+		lotMap[lot.ID] = lot
+	}
+	return lotMap, nil
+}
+
+// WriteAll writes all the lots to the storage
+// WriteAll(map[int]TLot) -> error
+// Args:
+//		lots: Map of lots.
+// Return:
+//		error: Error raised during the execution (if exists).
+
+func (l *LotStorageDefault) WriteAll(lots map[int]internal.TLot) error {
+	/* Open a file descriptor */
+	file, err := os.Create(l.filePath)
+	if err != nil {
+		return internal.ErrBadFile
+	}
+	defer file.Close()
+
+	/* Write all the lots into the storage ordered by id */
+	lotSlice := make([]internal.TLot, 0, len(lots))
+	for _, value := range lots {
+		lotSlice = append(lotSlice, value)
+	}
+	sort.Slice(lotSlice, func(i, j int) bool {
+		return lotSlice[i].ID < lotSlice[j].ID
+	})
+	return json.NewEncoder(file).Encode(lotSlice)
+}
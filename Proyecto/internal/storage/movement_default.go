@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"proyecto/internal"
+	"sort"
+)
+
+// MovementStorageDefault is the default implementation of MovementStorage
+type MovementStorageDefault struct {
+	filePath string // File path
+}
+
+// NewMovementStorageDefault creates a new MovementStorageDefault
+// NewMovementStorageDefault(filePath string) -> *MovementStorageDefault
+// Args:
+// 	filePath string: File path
+// Returns:
+// 	*MovementStorageDefault: New MovementStorageDefault
+
+func NewMovementStorageDefault(filePath string) *MovementStorageDefault {
+	return &MovementStorageDefault{filePath: filePath}
+}
+
+// GetAll gets all the movements from the storage
+// GetAll() -> (map[int]TMovement, error)
+// Return:
+//		map[int]TMovement: Map of movements.
+//		error: 		    Error raised during the execution (if exists).
+
+func (m *MovementStorageDefault) GetAll() (map[int]internal.TMovement, error) {
+	/* Read the file content */
+	data, err := os.ReadFile(m.filePath)
+	if err != nil {
+		return nil, internal.ErrBadFile
+	}
+
+	/* Decode the movements */
+	var movements []internal.TMovement
+	if err := json.Unmarshal(data, &movements); err != nil {
+		return nil, internal.ErrBadFile
+	}
+
+	/* Convert []TMovement -> map[int]TMovement */
+	movementMap := make(map[int]internal.TMovement)
+	for _, movement := range movements {
+		movementMap[movement.ID] = movement
+	}
+	return movementMap, nil
+}
+
+// WriteAll writes all the movements to the storage
+// WriteAll(map[int]TMovement) -> error
+// Args:
+//		movements: Map of movements.
+// Return:
+//		error: Error raised during the execution (if exists).
+
+func (m *MovementStorageDefault) WriteAll(movements map[int]internal.TMovement) error {
+	/* Open a file descriptor */
+	file, err := os.Create(m.filePath)
+	if err != nil {
+		return internal.ErrBadFile
+	}
+	defer file.Close()
+
+	/* Write all the movements into the storage ordered by id */
+	movementSlice := make([]internal.TMovement, 0, len(movements))
+	for _, value := range movements {
+		movementSlice = append(movementSlice, value)
+	}
+	sort.Slice(movementSlice, func(i, j int) bool {
+		return movementSlice[i].ID < movementSlice[j].ID
+	})
+	return json.NewEncoder(file).Encode(movementSlice)
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"proyecto/internal"
+	"sort"
+)
+
+// OrderStorageDefault is the default implementation of OrderStorage
+type OrderStorageDefault struct {
+	filePath string // File path
+}
+
+// NewOrderStorageDefault creates a new OrderStorageDefault
+// NewOrderStorageDefault(filePath string) -> *OrderStorageDefault
+// Args:
+// 	filePath string: File path
+// Returns:
+// 	*OrderStorageDefault: New OrderStorageDefault
+
+func NewOrderStorageDefault(filePath string) *OrderStorageDefault {
+	return &OrderStorageDefault{filePath: filePath}
+}
+
+// GetAll gets all the orders from the storage
+// GetAll() -> (map[int]TOrder, error)
+// Return:
+//		map[int]TOrder: Map of orders.
+//		error: 		    Error raised during the execution (if exists).
+
+func (o *OrderStorageDefault) GetAll() (map[int]internal.TOrder, error) {
+	/* Read the file content */
+	data, err := os.ReadFile(o.filePath)
+	if err != nil {
+		return nil, internal.ErrBadFile
+	}
+
+	/* Decode the orders */
+	var orders []internal.TOrder
+	if err := json.Unmarshal(data, &orders); err != nil {
+		return nil, internal.ErrBadFile
+	}
+
+	/* Convert []TOrder -> map[int]TOrder */
+	orderMap := make(map[int]internal.TOrder)
+	for _, order := range orders {
+		orderMap[order.ID] = order
+	}
+	return orderMap, nil
+}
+
+// WriteAll writes all the orders to the storage
+// WriteAll(map[int]TOrder) -> error
+// Args:
+//		orders: Map of orders.
+// Return:
+//		error: Error raised during the execution (if exists).
+
+func (o *OrderStorageDefault) WriteAll(orders map[int]internal.TOrder) error {
+	/* Open a file descriptor */
+	file, err := os.Create(o.filePath)
+	if err != nil {
+		return internal.ErrBadFile
+	}
+	defer file.Close()
+
+	/* Write all the orders into the storage ordered by id */
+	orderSlice := make([]internal.TOrder, 0, len(orders))
+	for _, value := range orders {
+		orderSlice = append(orderSlice, value)
+	}
+	sort.Slice(orderSlice, func(i, j int) bool {
+		return orderSlice[i].ID < orderSlice[j].ID
+	})
+	return json.NewEncoder(file).Encode(orderSlice)
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"proyecto/internal"
+	"sort"
+)
+
+// PriceStorageDefault is the default implementation of PriceStorage
+type PriceStorageDefault struct {
+	filePath string // File path
+}
+
+// NewPriceStorageDefault creates a new PriceStorageDefault
+// NewPriceStorageDefault(filePath string) -> *PriceStorageDefault
+// Args:
+// 	filePath string: File path
+// Returns:
+// 	*PriceStorageDefault: New PriceStorageDefault
+
+func NewPriceStorageDefault(filePath string) *PriceStorageDefault {
+	return &PriceStorageDefault{filePath: filePath}
+}
+
+// GetAll gets all the price changes from the storage
+// GetAll() -> (map[int]TPriceChange, error)
+// Return:
+//		map[int]TPriceChange: Map of price changes.
+//		error: 		    Error raised during the execution (if exists).
+
+func (p *PriceStorageDefault) GetAll() (map[int]internal.TPriceChange, error) {
+	/* Read the file content */
+	data, err := os.ReadFile(p.filePath)
+	if err != nil {
+		return nil, internal.ErrBadFile
+	}
+
+	/* Decode the price changes */
+	var changes []internal.TPriceChange
+	if err := json.Unmarshal(data, &changes); err != nil {
+		return nil, internal.ErrBadFile
+	}
+
+	/* Convert []TPriceChange -> map[int]TPriceChange */
+	changeMap := make(map[int]internal.TPriceChange)
+	for _, change := range changes {
+		changeMap[change.ID] = change
+	}
+	return changeMap, nil
+}
+
+// WriteAll writes all the price changes to the storage
+// WriteAll(map[int]TPriceChange) -> error
+// Args:
+//		changes: Map of price changes.
+// Return:
+//		error: Error raised during the execution (if exists).
+
+func (p *PriceStorageDefault) WriteAll(changes map[int]internal.TPriceChange) error {
+	/* Open a file descriptor */
+	file, err := os.Create(p.filePath)
+	if err != nil {
+		return internal.ErrBadFile
+	}
+	defer file.Close()
+
+	/* Write all the price changes into the storage ordered by id */
+	changeSlice := make([]internal.TPriceChange, 0, len(changes))
+	for _, value := range changes {
+		changeSlice = append(changeSlice, value)
+	}
+	sort.Slice(changeSlice, func(i, j int) bool {
+		return changeSlice[i].ID < changeSlice[j].ID
+	})
+	return json.NewEncoder(file).Encode(changeSlice)
+}
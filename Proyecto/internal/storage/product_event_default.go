@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"proyecto/internal"
+)
+
+// ProductEventStorageDefault is the default implementation of ProductEventStorage
+type ProductEventStorageDefault struct {
+	filePath string // File path
+}
+
+// NewProductEventStorageDefault creates a new ProductEventStorageDefault
+// NewProductEventStorageDefault(filePath string) -> *ProductEventStorageDefault
+// Args:
+// 	filePath string: File path
+// Returns:
+// 	*ProductEventStorageDefault: New ProductEventStorageDefault
+
+func NewProductEventStorageDefault(filePath string) *ProductEventStorageDefault {
+	return &ProductEventStorageDefault{filePath: filePath}
+}
+
+// GetAll gets the retained product events from the storage
+// GetAll() -> ([]TProductEvent, error)
+// Return:
+//		[]TProductEvent: Events, oldest first.
+//		error: 		     Error raised during the execution (if exists).
+
+func (e *ProductEventStorageDefault) GetAll() ([]internal.TProductEvent, error) {
+	/* Read the file content */
+	data, err := os.ReadFile(e.filePath)
+	if err != nil {
+		return nil, internal.ErrBadFile
+	}
+
+	/* Decode the events */
+	var events []internal.TProductEvent
+	if err := json.Unmarshal(data, &events); err != nil {
+		return nil, internal.ErrBadFile
+	}
+	return events, nil
+}
+
+// WriteAll writes the retained product events to the storage
+// WriteAll([]TProductEvent) -> error
+// Args:
+//		events: Events, oldest first.
+// Return:
+//		error: Error raised during the execution (if exists).
+
+func (e *ProductEventStorageDefault) WriteAll(events []internal.TProductEvent) error {
+	/* Open a file descriptor */
+	file, err := os.Create(e.filePath)
+	if err != nil {
+		return internal.ErrBadFile
+	}
+	defer file.Close()
+
+	/* Write the events */
+	if events == nil {
+		events = []internal.TProductEvent{}
+	}
+	return json.NewEncoder(file).Encode(events)
+}
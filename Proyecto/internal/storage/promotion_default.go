@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"proyecto/internal"
+	"sort"
+)
+
+// PromotionStorageDefault is the default implementation of PromotionStorage
+type PromotionStorageDefault struct {
+	filePath string // File path
+}
+
+// NewPromotionStorageDefault creates a new PromotionStorageDefault
+// NewPromotionStorageDefault(filePath string) -> *PromotionStorageDefault
+// Args:
+// 	filePath string: File path
+// Returns:
+// 	*PromotionStorageDefault: New PromotionStorageDefault
+
+func NewPromotionStorageDefault(filePath string) *PromotionStorageDefault {
+	return &PromotionStorageDefault{filePath: filePath}
+}
+
+// GetAll gets all the promotions from the storage
+// GetAll() -> (map[int]TPromotion, error)
+// Return:
+//		map[int]TPromotion: Map of promotions.
+//		error: 		    Error raised during the execution (if exists).
+
+func (p *PromotionStorageDefault) GetAll() (map[int]internal.TPromotion, error) {
+	/* Read the file content */
+	data, err := os.ReadFile(p.filePath)
+	if err != nil {
+		return nil, internal.ErrBadFile
+	}
+
+	/* Decode the promotions */
+	var promotions []internal.TPromotion
+	if err := json.Unmarshal(data, &promotions); err != nil {
+		return nil, internal.ErrBadFile
+	}
+
+	/* Convert []TPromotion -> map[int]TPromotion */
+	promotionMap := make(map[int]internal.TPromotion)
+	for _, promotion := range promotions {
+		promotionMap[promotion.ID] = promotion
+	}
+	return promotionMap, nil
+}
+
+// WriteAll writes all the promotions to the storage
+// WriteAll(map[int]TPromotion) -> error
+// Args:
+//		promotions: Map of promotions.
+// Return:
+//		error: Error raised during the execution (if exists).
+
+func (p *PromotionStorageDefault) WriteAll(promotions map[int]internal.TPromotion) error {
+	/* Open a file descriptor */
+	file, err := os.Create(p.filePath)
+	if err != nil {
+		return internal.ErrBadFile
+	}
+	defer file.Close()
+
+	/* Write all the promotions into the storage ordered by id */
+	promotionSlice := make([]internal.TPromotion, 0, len(promotions))
+	for _, value := range promotions {
+		promotionSlice = append(promotionSlice, value)
+	}
+	sort.Slice(promotionSlice, func(i, j int) bool {
+		return promotionSlice[i].ID < promotionSlice[j].ID
+	})
+	return json.NewEncoder(file).Encode(promotionSlice)
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"proyecto/internal"
+	"sort"
+)
+
+// SupplierStorageDefault is the default implementation of SupplierStorage
+type SupplierStorageDefault struct {
+	filePath string // File path
+}
+
+// NewSupplierStorageDefault creates a new SupplierStorageDefault
+// NewSupplierStorageDefault(filePath string) -> *SupplierStorageDefault
+// Args:
+// 	filePath string: File path
+// Returns:
+// 	*SupplierStorageDefault: New SupplierStorageDefault
+
+func NewSupplierStorageDefault(filePath string) *SupplierStorageDefault {
+	return &SupplierStorageDefault{filePath: filePath}
+}
+
+// GetAll gets all the suppliers from the storage
+// GetAll() -> (map[int]TSupplier, error)
+// Return:
+//		map[int]TSupplier: Map of suppliers.
+//		error: 		    Error raised during the execution (if exists).
+
+func (s *SupplierStorageDefault) GetAll() (map[int]internal.TSupplier, error) {
+	/* Read the file content */
+	data, err := os.ReadFile(s.filePath)
+	if err != nil {
+		return nil, internal.ErrBadFile
+	}
+
+	/* Decode the suppliers */
+	var suppliers []internal.TSupplier
+	if err := json.Unmarshal(data, &suppliers); err != nil {
+		return nil, internal.ErrBadFile
+	}
+
+	/* Convert []TSupplier -> map[int]TSupplier */
+	supplierMap := make(map[int]internal.TSupplier)
+	for _, supplier := range suppliers {
+		supplierMap[supplier.ID] = supplier
+	}
+	return supplierMap, nil
+}
+
+// WriteAll writes all the suppliers to the storage
+// WriteAll(map[int]TSupplier) -> error
+// Args:
+//		suppliers: Map of suppliers.
+// Return:
+//		error: Error raised during the execution (if exists).
+
+func (s *SupplierStorageDefault) WriteAll(suppliers map[int]internal.TSupplier) error {
+	/* Open a file descriptor */
+	file, err := os.Create(s.filePath)
+	if err != nil {
+		return internal.ErrBadFile
+	}
+	defer file.Close()
+
+	/* Write all the suppliers into the storage ordered by id */
+	supplierSlice := make([]internal.TSupplier, 0, len(suppliers))
+	for _, value := range suppliers {
+		supplierSlice = append(supplierSlice, value)
+	}
+	sort.Slice(supplierSlice, func(i, j int) bool {
+		return supplierSlice[i].ID < supplierSlice[j].ID
+	})
+	return json.NewEncoder(file).Encode(supplierSlice)
+}
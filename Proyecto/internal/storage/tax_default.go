@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"proyecto/internal"
+)
+
+// TaxRateStorageDefault is the default implementation of TaxRateStorage
+type TaxRateStorageDefault struct {
+	filePath string // File path
+}
+
+// NewTaxRateStorageDefault creates a new TaxRateStorageDefault
+// NewTaxRateStorageDefault(filePath string) -> *TaxRateStorageDefault
+// Args:
+// 	filePath string: File path of the tax rates config
+// Returns:
+// 	*TaxRateStorageDefault: New TaxRateStorageDefault
+
+func NewTaxRateStorageDefault(filePath string) *TaxRateStorageDefault {
+	return &TaxRateStorageDefault{filePath: filePath}
+}
+
+// Load reads the tax rates table from the config file
+// Load() -> (internal.TTaxRates, error)
+// Return:
+//		internal.TTaxRates: Tax rates table.
+//		error: 				Error raised during the execution (if exists).
+
+func (t *TaxRateStorageDefault) Load() (internal.TTaxRates, error) {
+	/* Read the file content */
+	data, err := os.ReadFile(t.filePath)
+	if err != nil {
+		return internal.TTaxRates{}, internal.ErrBadFile
+	}
+
+	/* Decode the table */
+	var rates internal.TTaxRates
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return internal.TTaxRates{}, internal.ErrBadFile
+	}
+	return rates, nil
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"proyecto/internal"
+	"sort"
+)
+
+// WarehouseStorageDefault is the default implementation of WarehouseStorage
+type WarehouseStorageDefault struct {
+	filePath string // File path
+}
+
+// NewWarehouseStorageDefault creates a new WarehouseStorageDefault
+// NewWarehouseStorageDefault(filePath string) -> *WarehouseStorageDefault
+// Args:
+// 	filePath string: File path
+// Returns:
+// 	*WarehouseStorageDefault: New WarehouseStorageDefault
+
+func NewWarehouseStorageDefault(filePath string) *WarehouseStorageDefault {
+	return &WarehouseStorageDefault{filePath: filePath}
+}
+
+// GetAll gets all the warehouses from the storage
+// GetAll() -> (map[int]TWarehouse, error)
+// Return:
+//		map[int]TWarehouse: Map of warehouses.
+//		error: 		    Error raised during the execution (if exists).
+
+func (w *WarehouseStorageDefault) GetAll() (map[int]internal.TWarehouse, error) {
+	/* Read the file content */
+	data, err := os.ReadFile(w.filePath)
+	if err != nil {
+		return nil, internal.ErrBadFile
+	}
+
+	/* Decode the warehouses */
+	var warehouses []internal.TWarehouse
+	if err := json.Unmarshal(data, &warehouses); err != nil {
+		return nil, internal.ErrBadFile
+	}
+
+	/* Convert []TWarehouse -> map[int]TWarehouse */
+	warehouseMap := make(map[int]internal.TWarehouse)
+	for _, warehouse := range warehouses {
+		warehouseMap[warehouse.ID] = warehouse
+	}
+	return warehouseMap, nil
+}
+
+// WriteAll writes all the warehouses to the storage
+// WriteAll(map[int]TWarehouse) -> error
+// Args:
+//		warehouses: Map of warehouses.
+// Return:
+//		error: Error raised during the execution (if exists).
+
+func (w *WarehouseStorageDefault) WriteAll(warehouses map[int]internal.TWarehouse) error {
+	/* Open a file descriptor */
+	file, err := os.Create(w.filePath)
+	if err != nil {
+		return internal.ErrBadFile
+	}
+	defer file.Close()
+
+	/* Write all the warehouses into the storage ordered by id */
+	warehouseSlice := make([]internal.TWarehouse, 0, len(warehouses))
+	for _, value := range warehouses {
+		warehouseSlice = append(warehouseSlice, value)
+	}
+	sort.Slice(warehouseSlice, func(i, j int) bool {
+		return warehouseSlice[i].ID < warehouseSlice[j].ID
+	})
+	return json.NewEncoder(file).Encode(warehouseSlice)
+}
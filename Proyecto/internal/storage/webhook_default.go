@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"proyecto/internal"
+	"sort"
+)
+
+// WebhookStorageDefault is the default implementation of WebhookStorage
+type WebhookStorageDefault struct {
+	filePath string // File path
+}
+
+// NewWebhookStorageDefault creates a new WebhookStorageDefault
+// NewWebhookStorageDefault(filePath string) -> *WebhookStorageDefault
+// Args:
+// 	filePath string: File path
+// Returns:
+// 	*WebhookStorageDefault: New WebhookStorageDefault
+
+func NewWebhookStorageDefault(filePath string) *WebhookStorageDefault {
+	return &WebhookStorageDefault{filePath: filePath}
+}
+
+// GetAll gets all the webhooks from the storage
+// GetAll() -> (map[int]TWebhook, error)
+// Return:
+//		map[int]TWebhook: Map of webhooks.
+//		error: 		    Error raised during the execution (if exists).
+
+func (s *WebhookStorageDefault) GetAll() (map[int]internal.TWebhook, error) {
+	/* Read the file content */
+	data, err := os.ReadFile(s.filePath)
+	if err != nil {
+		return nil, internal.ErrBadFile
+	}
+
+	/* Decode the webhooks */
+	var webhooks []internal.TWebhook
+	if err := json.Unmarshal(data, &webhooks); err != nil {
+		return nil, internal.ErrBadFile
+	}
+
+	/* Convert []TWebhook -> map[int]TWebhook */
+	webhooksMap := make(map[int]internal.TWebhook)
+	for _, webhook := range webhooks {
+		webhooksMap[webhook.ID] = webhook
+	}
+	return webhooksMap, nil
+}
+
+// WriteAll writes all the webhooks to the storage
+// WriteAll(map[int]TWebhook) -> error
+// Args:
+//		webhooks: Map of webhooks.
+// Return:
+//		error: Error raised during the execution (if exists).
+
+func (s *WebhookStorageDefault) WriteAll(webhooks map[int]internal.TWebhook) error {
+	/* Open a file descriptor */
+	file, err := os.Create(s.filePath)
+	if err != nil {
+		return internal.ErrBadFile
+	}
+	defer file.Close()
+
+	/* Write all the webhooks into the storage ordered by id */
+	webhooksSlice := make([]internal.TWebhook, 0, len(webhooks))
+	for _, value := range webhooks {
+		webhooksSlice = append(webhooksSlice, value)
+	}
+	sort.Slice(webhooksSlice, func(i, j int) bool {
+		return webhooksSlice[i].ID < webhooksSlice[j].ID
+	})
+	return json.NewEncoder(file).Encode(webhooksSlice)
+}
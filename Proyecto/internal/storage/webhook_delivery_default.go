@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"proyecto/internal"
+	"sort"
+)
+
+// WebhookDeliveryStorageDefault is the default implementation of WebhookDeliveryStorage
+type WebhookDeliveryStorageDefault struct {
+	filePath string // File path
+}
+
+// NewWebhookDeliveryStorageDefault creates a new WebhookDeliveryStorageDefault
+// NewWebhookDeliveryStorageDefault(filePath string) -> *WebhookDeliveryStorageDefault
+// Args:
+// 	filePath string: File path
+// Returns:
+// 	*WebhookDeliveryStorageDefault: New WebhookDeliveryStorageDefault
+
+func NewWebhookDeliveryStorageDefault(filePath string) *WebhookDeliveryStorageDefault {
+	return &WebhookDeliveryStorageDefault{filePath: filePath}
+}
+
+// GetAll gets all the deliveries from the storage
+// GetAll() -> (map[int]TWebhookDelivery, error)
+// Return:
+//		map[int]TWebhookDelivery: Map of deliveries.
+//		error: 						  Error raised during the execution (if exists).
+
+func (s *WebhookDeliveryStorageDefault) GetAll() (map[int]internal.TWebhookDelivery, error) {
+	/* Read the file content */
+	data, err := os.ReadFile(s.filePath)
+	if err != nil {
+		return nil, internal.ErrBadFile
+	}
+
+	/* Decode the deliveries */
+	var deliveries []internal.TWebhookDelivery
+	if err := json.Unmarshal(data, &deliveries); err != nil {
+		return nil, internal.ErrBadFile
+	}
+
+	/* Convert []TWebhookDelivery -> map[int]TWebhookDelivery */
+	deliveryMap := make(map[int]internal.TWebhookDelivery)
+	for _, delivery := range deliveries {
+		deliveryMap[delivery.ID] = delivery
+	}
+	return deliveryMap, nil
+}
+
+// WriteAll writes all the deliveries to the storage
+// WriteAll(map[int]TWebhookDelivery) -> error
+// Args:
+//		deliveries: Map of deliveries.
+// Return:
+//		error: Error raised during the execution (if exists).
+
+func (s *WebhookDeliveryStorageDefault) WriteAll(deliveries map[int]internal.TWebhookDelivery) error {
+	/* Open a file descriptor */
+	file, err := os.Create(s.filePath)
+	if err != nil {
+		return internal.ErrBadFile
+	}
+	defer file.Close()
+
+	/* Write all the deliveries into the storage ordered by id */
+	deliverySlice := make([]internal.TWebhookDelivery, 0, len(deliveries))
+	for _, value := range deliveries {
+		deliverySlice = append(deliverySlice, value)
+	}
+	sort.Slice(deliverySlice, func(i, j int) bool {
+		return deliverySlice[i].ID < deliverySlice[j].ID
+	})
+	return json.NewEncoder(file).Encode(deliverySlice)
+}
@@ -0,0 +1,32 @@
+package internal
+
+// TSupplier represents a supplier of products.
+type TSupplier struct {
+	ID       int            `json:"id"`
+	Name     string         `json:"name"`
+	Email    string         `json:"email"`
+	Phone    string         `json:"phone"`
+	Products []TSupplyTerms `json:"products"` // Products supplied and their terms.
+}
+
+// TSupplyTerms represents the terms a supplier offers for a product.
+type TSupplyTerms struct {
+	ProductID    int     `json:"product_id"`
+	Cost         float64 `json:"cost"`           // Unit cost paid to the supplier.
+	LeadTimeDays int     `json:"lead_time_days"` // Days between the order and the delivery.
+}
+
+// TSuppliedProduct represents a product along with the terms of one of its suppliers.
+type TSuppliedProduct struct {
+	TProduct
+	Cost         float64 `json:"cost"`
+	LeadTimeDays int     `json:"lead_time_days"`
+}
+
+// TProductSupplier represents a supplier of a product along with its terms.
+type TProductSupplier struct {
+	SupplierID   int     `json:"supplier_id"`
+	Name         string  `json:"name"`
+	Cost         float64 `json:"cost"`
+	LeadTimeDays int     `json:"lead_time_days"`
+}
@@ -0,0 +1,17 @@
+package internal
+
+import "errors"
+
+/* Errors definition */
+var (
+	ErrSupplierNotFound = errors.New("supplier not found")
+)
+
+/* Supplier repository definition */
+type SupplierRepository interface {
+	GetAllSuppliers() ([]TSupplier, error)       // Return all the suppliers in the repository.
+	GetSupplierByID(id int) (TSupplier, error)   // Return a supplier by its id.
+	InsertNewSupplier(supplier *TSupplier) error // Add a new supplier into the repository.
+	UpdateSupplier(supplier *TSupplier) error    // Update a supplier from the repository if it exists.
+	DeleteSupplier(id int) error                 // Delete a supplier from the repository.
+}
@@ -0,0 +1,24 @@
+package internal
+
+import "errors"
+
+/* Errors definition */
+var (
+	ErrSupplierNotExists     = errors.New("supplier not exists")
+	ErrSupplierAlreadyExists = errors.New("supplier already exists")
+	ErrInvalidSupplyTerms    = errors.New("invalid supply terms")
+	ErrSupplierIsOnlySource  = errors.New("supplier is the only source of a published product")
+)
+
+/* Supplier service definition */
+type SupplierService interface {
+	GetAllSuppliers() ([]TSupplier, error)                           // Return all the suppliers.
+	GetSupplierByID(id int) (TSupplier, error)                       // Return a supplier by its id.
+	InsertNewSupplier(supplier *TSupplier) error                     // Add a new supplier.
+	UpdateSupplier(supplier *TSupplier) error                        // Update the contact data of a supplier if it exists.
+	DeleteSupplier(id int) error                                     // Delete a supplier which is not the only source of a published product.
+	GetProductsBySupplier(id int) ([]TSuppliedProduct, error)        // Return the products of a supplier.
+	GetSuppliersByProduct(productID int) ([]TProductSupplier, error) // Return the suppliers of a product.
+	LinkProduct(id int, terms TSupplyTerms) error                    // Add or update the terms of a product of a supplier.
+	UnlinkProduct(id, productID int) error                           // Remove a product from a supplier.
+}
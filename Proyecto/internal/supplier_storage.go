@@ -0,0 +1,7 @@
+package internal
+
+/* Supplier storage definition */
+type SupplierStorage interface {
+	GetAll() (map[int]TSupplier, error) // Get all suppliers from storage
+	WriteAll(map[int]TSupplier) error   // Write all suppliers to storage
+}
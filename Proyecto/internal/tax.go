@@ -0,0 +1,38 @@
+package internal
+
+/* Tax rounding policies */
+const (
+	TaxRoundPerLine  = "line"  // The tax of every line is rounded to cents and the total is their sum.
+	TaxRoundPerTotal = "total" // The taxes of the lines are summed unrounded and the total is rounded once.
+)
+
+// TaxClassStandard is the tax class of the products without one.
+const TaxClassStandard = "standard"
+
+// TTaxRates represents the table of tax rates by jurisdiction and tax class.
+type TTaxRates struct {
+	DefaultJurisdiction string                        `json:"default_jurisdiction"` // Jurisdiction used when none is requested.
+	Rounding            string                        `json:"rounding"`             // Rounding policy: line or total.
+	Jurisdictions       map[string]map[string]float64 `json:"jurisdictions"`        // Rate by tax class by jurisdiction (0.21 means 21%).
+}
+
+// TTaxLine represents the taxes of a line: a product, a cart line or an order line.
+type TTaxLine struct {
+	ProductID int     `json:"product_id"`
+	Quantity  int     `json:"quantity"`
+	TaxClass  string  `json:"tax_class"`
+	Rate      float64 `json:"rate"`
+	Net       float64 `json:"net"`
+	Tax       float64 `json:"tax"`
+	Gross     float64 `json:"gross"`
+}
+
+// TTaxBreakdown represents the taxes of a set of lines in a jurisdiction.
+type TTaxBreakdown struct {
+	Jurisdiction string     `json:"jurisdiction"`
+	Rounding     string     `json:"rounding"`
+	Lines        []TTaxLine `json:"lines"`
+	Net          float64    `json:"net"`
+	Tax          float64    `json:"tax"`
+	Gross        float64    `json:"gross"`
+}
@@ -0,0 +1,15 @@
+package internal
+
+import "errors"
+
+/* Errors definition */
+var (
+	ErrJurisdictionNotExists = errors.New("jurisdiction not exists")
+	ErrUnknownTaxClass       = errors.New("unknown tax class")
+)
+
+/* Tax service definition */
+type TaxService interface {
+	ValidateTaxClass(taxClass string) error                                    // Check a tax class has a rate in every jurisdiction.
+	ComputeTaxes(lines []TTaxLine, jurisdiction string) (TTaxBreakdown, error) // Compute the rate, tax and gross of lines holding their net amount.
+}
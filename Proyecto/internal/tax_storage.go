@@ -0,0 +1,6 @@
+package internal
+
+/* Tax rate storage definition */
+type TaxRateStorage interface {
+	Load() (TTaxRates, error) // Load the tax rates table from storage
+}
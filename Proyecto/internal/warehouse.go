@@ -0,0 +1,19 @@
+package internal
+
+// DefaultWarehouseID is the warehouse holding the stock recorded before the warehouses existed.
+const DefaultWarehouseID = 1
+
+// TWarehouse represents a location where the products are stocked.
+type TWarehouse struct {
+	ID      int    `json:"id"`
+	Code    string `json:"code"` // Unique short code of the warehouse.
+	Name    string `json:"name"`
+	Address string `json:"address"`
+}
+
+// TStockLevel represents the stock of a product in a warehouse.
+type TStockLevel struct {
+	ProductID   int `json:"product_id"`
+	WarehouseID int `json:"warehouse_id"`
+	Quantity    int `json:"quantity"`
+}
@@ -0,0 +1,17 @@
+package internal
+
+import "errors"
+
+/* Errors definition */
+var (
+	ErrWarehouseNotFound = errors.New("warehouse not found")
+)
+
+/* Warehouse repository definition */
+type WarehouseRepository interface {
+	GetAllWarehouses() ([]TWarehouse, error)        // Return all the warehouses in the repository.
+	GetWarehouseByID(id int) (TWarehouse, error)    // Return a warehouse by its id.
+	InsertNewWarehouse(warehouse *TWarehouse) error // Add a new warehouse into the repository.
+	UpdateWarehouse(warehouse *TWarehouse) error    // Update a warehouse from the repository if it exists.
+	DeleteWarehouse(id int) error                   // Delete a warehouse from the repository.
+}
@@ -0,0 +1,20 @@
+package internal
+
+import "errors"
+
+/* Errors definition */
+var (
+	ErrWarehouseNotExists     = errors.New("warehouse not exists")
+	ErrWarehouseAlreadyExists = errors.New("warehouse already exists")
+	ErrWarehouseHasStock      = errors.New("warehouse has stock")
+	ErrWarehouseIsDefault     = errors.New("default warehouse can't be deleted")
+)
+
+/* Warehouse service definition */
+type WarehouseService interface {
+	GetAllWarehouses() ([]TWarehouse, error)        // Return all the warehouses.
+	GetWarehouseByID(id int) (TWarehouse, error)    // Return a warehouse by its id.
+	InsertNewWarehouse(warehouse *TWarehouse) error // Add a new warehouse.
+	UpdateWarehouse(warehouse *TWarehouse) error    // Update a warehouse if it exists.
+	DeleteWarehouse(id int) error                   // Delete a warehouse without stock.
+}
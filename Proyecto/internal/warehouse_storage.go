@@ -0,0 +1,7 @@
+package internal
+
+/* Warehouse storage definition */
+type WarehouseStorage interface {
+	GetAll() (map[int]TWarehouse, error) // Get all warehouses from storage
+	WriteAll(map[int]TWarehouse) error   // Write all warehouses to storage
+}
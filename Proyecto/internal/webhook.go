@@ -0,0 +1,86 @@
+package internal
+
+import (
+	"encoding/json"
+	"time"
+)
+
+/* Webhook event types told apart from the product events */
+const (
+	WebhookEventPriceChanged = "product.price_changed" // An update changed the base price of a product or its promotions changed.
+	WebhookEventStockChanged = "product.stock_changed" // An update or a movement changed the stock of a product.
+)
+
+// WebhookEventTypes lists the event types a webhook can subscribe to
+var WebhookEventTypes = append([]string{WebhookEventPriceChanged, WebhookEventStockChanged}, ProductEventTypes...)
+
+// WebhookEventTypesOf returns the webhook event types a product event matches, the most specific first
+func WebhookEventTypesOf(event TProductEvent) []string {
+	types := make([]string, 0, len(event.Changes)+1)
+	for _, change := range event.Changes {
+		switch change {
+		case ProductChangePrice:
+			types = append(types, WebhookEventPriceChanged)
+		case ProductChangeQuantity:
+			types = append(types, WebhookEventStockChanged)
+		}
+	}
+	return append(types, event.Type)
+}
+
+/* Webhook delivery statuses */
+const (
+	WebhookDeliveryPending   = "pending"   // Waiting for its next attempt.
+	WebhookDeliveryDelivered = "delivered" // The receiver answered with a 2xx status.
+	WebhookDeliveryDead      = "dead"      // Every attempt failed: kept in the dead-letter list.
+)
+
+// TWebhook represents a URL subscribed to the catalog events.
+type TWebhook struct {
+	ID        int       `json:"id"`
+	URL       string    `json:"url"`    // Absolute http or https URL the events are posted to.
+	Events    []string  `json:"events"` // WebhookEventTypes subscribed to.
+	Secret    string    `json:"secret"` // Key the payloads are signed with. Never returned by the API.
+	Active    bool      `json:"active"` // Inactive webhooks get no new deliveries.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Subscribed returns the first of some event types the webhook is subscribed to
+func (w TWebhook) Subscribed(types []string) (string, bool) {
+	for _, eventType := range types {
+		for _, subscribed := range w.Events {
+			if subscribed == eventType {
+				return eventType, true
+			}
+		}
+	}
+	return "", false
+}
+
+// TWebhookPayload is the body posted to a webhook.
+type TWebhookPayload struct {
+	Type  string        `json:"type"`  // Webhook event type the delivery was made for.
+	Event TProductEvent `json:"event"` // Product event delivered.
+}
+
+// TWebhookAttempt represents a try to deliver an event to a webhook.
+type TWebhookAttempt struct {
+	Date       time.Time `json:"date"`
+	StatusCode int       `json:"status_code"`     // Status answered by the receiver. 0 when no response was received.
+	Error      string    `json:"error,omitempty"` // Why the attempt failed.
+	DurationMs int64     `json:"duration_ms"`     // Time taken by the receiver.
+}
+
+// TWebhookDelivery represents an event to deliver to a webhook along with its attempts.
+type TWebhookDelivery struct {
+	ID            int               `json:"id"`
+	WebhookID     int               `json:"webhook_id"`
+	EventID       int64             `json:"event_id"`                  // Product event delivered.
+	EventType     string            `json:"event_type"`                // Webhook event type the delivery was made for.
+	Payload       json.RawMessage   `json:"payload"`                   // TWebhookPayload posted on every attempt.
+	Status        string            `json:"status"`                    // One of the webhook delivery statuses.
+	Attempts      []TWebhookAttempt `json:"attempts"`                  // Every attempt made, oldest first.
+	Failures      int               `json:"failures"`                  // Failed attempts since the delivery was queued or redelivered.
+	NextAttemptAt *time.Time        `json:"next_attempt_at,omitempty"` // Nil once delivered or dead.
+	CreatedAt     time.Time         `json:"created_at"`
+}
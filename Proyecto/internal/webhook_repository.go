@@ -0,0 +1,26 @@
+package internal
+
+import "errors"
+
+/* Errors definition */
+var (
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+/* Webhook repository definition */
+type WebhookRepository interface {
+	GetAllWebhooks() ([]TWebhook, error)      // Return all the webhooks in the repository.
+	GetWebhookByID(id int) (TWebhook, error)  // Return a webhook by its id.
+	InsertNewWebhook(webhook *TWebhook) error // Add a new webhook into the repository.
+	UpdateWebhook(webhook *TWebhook) error    // Update a webhook from the repository if it exists.
+	DeleteWebhook(id int) error               // Delete a webhook from the repository.
+}
+
+/* Webhook delivery repository definition */
+type WebhookDeliveryRepository interface {
+	GetAllDeliveries() ([]TWebhookDelivery, error)           // Return all the deliveries in the repository, oldest first.
+	GetDeliveryByID(id int) (TWebhookDelivery, error)        // Return a delivery by its id.
+	InsertNewDeliveries(deliveries []TWebhookDelivery) error // Add new deliveries into the repository. Their ids are updated in place.
+	UpdateDelivery(delivery *TWebhookDelivery) error         // Update a delivery from the repository if it exists.
+}
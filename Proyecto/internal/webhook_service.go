@@ -0,0 +1,32 @@
+package internal
+
+import (
+	"errors"
+	"time"
+)
+
+/* Errors definition */
+var (
+	ErrWebhookNotExists         = errors.New("webhook not exists")
+	ErrInvalidWebhookURL        = errors.New("invalid webhook url")
+	ErrInvalidWebhookEvent      = errors.New("invalid webhook event type")
+	ErrWebhookDeliveryNotExists = errors.New("webhook delivery not exists")
+	ErrWebhookDeliveryNotDead   = errors.New("webhook delivery not dead")
+)
+
+/* Webhook service definition */
+type WebhookService interface {
+	GetAllWebhooks() ([]TWebhook, error)      // Return all the webhooks.
+	GetWebhookByID(id int) (TWebhook, error)  // Return a webhook by its id.
+	InsertNewWebhook(webhook *TWebhook) error // Add a new webhook.
+	UpdateWebhook(webhook *TWebhook) error    // Update a webhook if it exists.
+	DeleteWebhook(id int) error               // Delete a webhook. Its pending deliveries die.
+
+	Enqueue(event TProductEvent) ([]TWebhookDelivery, error)                 // Create a delivery of an event for every active webhook subscribed to it.
+	GetDeliveries(webhookID int) ([]TWebhookDelivery, error)                 // Return the delivery history of a webhook, newest first.
+	GetDeadLetters() ([]TWebhookDelivery, error)                             // Return the deliveries whose attempts all failed, newest first.
+	Redeliver(id int) (TWebhookDelivery, error)                              // Queue a dead delivery for a new round of attempts.
+	DueDeliveries() ([]TWebhookDelivery, error)                              // Return the pending deliveries whose next attempt is due.
+	NextAttempt() (time.Time, bool)                                          // Return the moment the next pending attempt is due.
+	RecordAttempt(id int, attempt TWebhookAttempt) (TWebhookDelivery, error) // Store an attempt and schedule the retry, or dead-letter the delivery.
+}
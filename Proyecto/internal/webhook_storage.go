@@ -0,0 +1,13 @@
+package internal
+
+/* Webhook storage definition */
+type WebhookStorage interface {
+	GetAll() (map[int]TWebhook, error) // Get all webhooks from storage
+	WriteAll(map[int]TWebhook) error   // Write all webhooks to storage
+}
+
+/* Webhook delivery storage definition */
+type WebhookDeliveryStorage interface {
+	GetAll() (map[int]TWebhookDelivery, error) // Get all deliveries from storage
+	WriteAll(map[int]TWebhookDelivery) error   // Write all deliveries to storage
+}
@@ -0,0 +1,107 @@
+package worker
+
+import (
+	"fmt"
+	"io"
+	"proyecto/internal"
+	"sync"
+	"time"
+)
+
+// ExpirationSweeper periodically unpublishes the products past their expiration date
+type ExpirationSweeper struct {
+	service  internal.ProductService // Service used to find and unpublish the products
+	clock    internal.Clock          // Clock the expirations are evaluated against
+	interval time.Duration           // Time between two sweeps
+	grace    time.Duration           // Time a product stays published after it expires
+	dryRun   bool                    // Only record the products that would be unpublished
+	output   io.Writer               // Where the sweep actions are recorded
+	stop     chan struct{}           // Closed to stop the sweeper
+	wg       sync.WaitGroup          // Waits for the running sweep to finish
+}
+
+// NewExpirationSweeper creates a new ExpirationSweeper
+// NewExpirationSweeper(ps internal.ProductService, c internal.Clock, interval, grace time.Duration, dryRun bool, output io.Writer) -> *ExpirationSweeper
+// Args:
+//		ps: 	  Product service
+//		c: 		  Clock the expirations are evaluated against
+//		interval: Time between two sweeps
+//		grace: 	  Grace period after the expiration date
+//		dryRun:   If true the products are not unpublished, only recorded
+//		output:   Writer where the sweep actions are recorded
+// Return:
+//		*ExpirationSweeper: New ExpirationSweeper
+
+func NewExpirationSweeper(ps internal.ProductService, c internal.Clock, interval, grace time.Duration, dryRun bool, output io.Writer) *ExpirationSweeper {
+	return &ExpirationSweeper{
+		service:  ps,
+		clock:    c,
+		interval: interval,
+		grace:    grace,
+		dryRun:   dryRun,
+		output:   output,
+		stop:     make(chan struct{}),
+	}
+}
+
+// record writes a sweep action into the output
+// record(format string, args ...any)
+// Args:
+//		format: Message format
+//		args: 	Message arguments
+
+func (s *ExpirationSweeper) record(format string, args ...any) {
+	fmt.Fprintf(s.output, "[%s] SWEEP %s\n", s.clock.Now().Format("2006-01-02 15:04:05"), fmt.Sprintf(format, args...))
+}
+
+// Sweep unpublishes the products expired for longer than the grace period
+// Sweep() -> []internal.TProduct
+// Return:
+//		[]internal.TProduct: Products unpublished (or that would be on dry-run mode)
+
+func (s *ExpirationSweeper) Sweep() []internal.TProduct {
+	expired := s.service.GetExpiredProducts(s.clock.Now().Add(-s.grace))
+	swept := make([]internal.TProduct, 0, len(expired))
+	for _, product := range expired {
+		if s.dryRun {
+			s.record("dry-run: product %d would be unpublished (expiration %s)", product.ID, product.Expiration)
+			swept = append(swept, product)
+			continue
+		}
+		if err := s.service.UnpublishProduct(product.ID); err != nil {
+			s.record("product %d could not be unpublished: %v", product.ID, err)
+			continue
+		}
+		s.record("product %d unpublished (expiration %s)", product.ID, product.Expiration)
+		swept = append(swept, product)
+	}
+	return swept
+}
+
+// Start runs a sweep immediately and then once every interval until Stop is called
+// Start()
+
+func (s *ExpirationSweeper) Start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			s.Sweep()
+			select {
+			case <-ticker.C:
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the sweeper and waits for the running sweep to finish
+// Stop()
+
+func (s *ExpirationSweeper) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
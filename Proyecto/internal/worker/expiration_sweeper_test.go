@@ -0,0 +1,92 @@
+package worker_test
+
+import (
+	"bytes"
+	"proyecto/internal"
+	"proyecto/internal/clock"
+	"proyecto/internal/repository"
+	"proyecto/internal/service"
+	"proyecto/internal/worker"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestExpirationSweeper_Sweep tests the ExpirationSweeper Sweep method
+func TestExpirationSweeper_Sweep(t *testing.T) {
+	/* Prepare the test data */
+	initialProducts := func() map[int]internal.TProduct {
+		return map[int]internal.TProduct{
+			1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: true, Expiration: "01/03/2030", Price: 10.5},
+			2: {ID: 2, Name: "Product 2", Quantity: 20, CodeValue: "AX02", IsPublished: true, Expiration: "05/03/2030", Price: 20.5},
+			3: {ID: 3, Name: "Product 3", Quantity: 30, CodeValue: "AX03", IsPublished: false, Expiration: "01/01/2030", Price: 30.5},
+		}
+	}
+
+	// Test 1: should unpublish the products expired for longer than the grace period
+	t.Run("should unpublish the products expired for longer than the grace period", func(t *testing.T) {
+		/* Initialize dependencies */
+		fixedClock := clock.NewClockFixed(time.Date(2030, 3, 2, 12, 0, 0, 0, time.Local))
+		repository := repository.NewProductMap(initStorage(initialProducts()))
+		service := service.NewProductServiceDefault(repository)
+		service.SetClock(fixedClock)
+		var output bytes.Buffer
+		sweeper := worker.NewExpirationSweeper(service, fixedClock, time.Hour, 6*time.Hour, false, &output)
+
+		/* Product 1 expired at the end of 01/03, 12 hours ago */
+		swept := sweeper.Sweep()
+		require.Len(t, swept, 1)
+		require.Equal(t, 1, swept[0].ID)
+		product, err := repository.GetProductByID(1)
+		require.NoError(t, err)
+		require.False(t, product.IsPublished)
+		product, err = repository.GetProductByID(2)
+		require.NoError(t, err)
+		require.True(t, product.IsPublished)
+		require.Contains(t, output.String(), "[2030-03-02 12:00:00] SWEEP product 1 unpublished (expiration 01/03/2030)")
+
+		/* A second sweep finds nothing left to unpublish */
+		require.Empty(t, sweeper.Sweep())
+	})
+
+	// Test 2: should keep the products published within the grace period
+	t.Run("should keep the products published within the grace period", func(t *testing.T) {
+		/* Initialize dependencies */
+		fixedClock := clock.NewClockFixed(time.Date(2030, 3, 2, 12, 0, 0, 0, time.Local))
+		repository := repository.NewProductMap(initStorage(initialProducts()))
+		service := service.NewProductServiceDefault(repository)
+		service.SetClock(fixedClock)
+		var output bytes.Buffer
+		sweeper := worker.NewExpirationSweeper(service, fixedClock, time.Hour, 24*time.Hour, false, &output)
+
+		/* Assertions */
+		require.Empty(t, sweeper.Sweep())
+		product, err := repository.GetProductByID(1)
+		require.NoError(t, err)
+		require.True(t, product.IsPublished)
+		require.Empty(t, output.String())
+	})
+
+	// Test 3: should only record the products on dry-run mode
+	t.Run("should only record the products on dry-run mode", func(t *testing.T) {
+		/* Initialize dependencies */
+		fixedClock := clock.NewClockFixed(time.Date(2030, 3, 10, 0, 0, 0, 0, time.Local))
+		repository := repository.NewProductMap(initStorage(initialProducts()))
+		service := service.NewProductServiceDefault(repository)
+		service.SetClock(fixedClock)
+		var output bytes.Buffer
+		sweeper := worker.NewExpirationSweeper(service, fixedClock, time.Hour, 0, true, &output)
+
+		/* Assertions */
+		swept := sweeper.Sweep()
+		require.Len(t, swept, 2)
+		for _, id := range []int{1, 2} {
+			product, err := repository.GetProductByID(id)
+			require.NoError(t, err)
+			require.True(t, product.IsPublished)
+		}
+		require.Contains(t, output.String(), "SWEEP dry-run: product 1 would be unpublished (expiration 01/03/2030)")
+		require.Contains(t, output.String(), "SWEEP dry-run: product 2 would be unpublished (expiration 05/03/2030)")
+	})
+}
@@ -0,0 +1,113 @@
+package worker
+
+import (
+	"fmt"
+	"io"
+	"proyecto/internal"
+	"sync"
+	"time"
+)
+
+// PublicationScheduler publishes and unpublishes the products when their publish window is due
+type PublicationScheduler struct {
+	service  internal.ProductService // Service used to apply the publish windows
+	clock    internal.Clock          // Clock the publish windows are evaluated against
+	interval time.Duration           // Maximum time between two checks
+	output   io.Writer               // Where the applied changes are recorded
+	stop     chan struct{}           // Closed to stop the scheduler
+	wg       sync.WaitGroup          // Waits for the running check to finish
+}
+
+// NewPublicationScheduler creates a new PublicationScheduler
+// NewPublicationScheduler(ps internal.ProductService, c internal.Clock, interval time.Duration, output io.Writer) -> *PublicationScheduler
+// Args:
+//		ps: 	  Product service
+//		c: 		  Clock the publish windows are evaluated against
+//		interval: Maximum time between two checks. The scheduler wakes up earlier when a window is due before
+//		output:   Writer where the applied changes are recorded
+// Return:
+//		*PublicationScheduler: New PublicationScheduler
+
+func NewPublicationScheduler(ps internal.ProductService, c internal.Clock, interval time.Duration, output io.Writer) *PublicationScheduler {
+	return &PublicationScheduler{
+		service:  ps,
+		clock:    c,
+		interval: interval,
+		output:   output,
+		stop:     make(chan struct{}),
+	}
+}
+
+// record writes a scheduler action into the output
+// record(format string, args ...any)
+// Args:
+//		format: Message format
+//		args: 	Message arguments
+
+func (s *PublicationScheduler) record(format string, args ...any) {
+	fmt.Fprintf(s.output, "[%s] SCHEDULE %s\n", s.clock.Now().Format("2006-01-02 15:04:05"), fmt.Sprintf(format, args...))
+}
+
+// Apply flips the publish state of the products whose window is due
+// Apply() -> []internal.TProduct
+// Return:
+//		[]internal.TProduct: Products whose publish state was updated
+
+func (s *PublicationScheduler) Apply() []internal.TProduct {
+	changed, err := s.service.ApplyPublicationWindows()
+	for _, product := range changed {
+		if product.IsPublished {
+			s.record("product %d published", product.ID)
+		} else {
+			s.record("product %d unpublished", product.ID)
+		}
+	}
+	if err != nil {
+		s.record("publish windows could not be applied: %v", err)
+	}
+	return changed
+}
+
+// nextWait returns how long the scheduler sleeps until the next check
+// nextWait() -> time.Duration
+
+func (s *PublicationScheduler) nextWait() time.Duration {
+	wait := s.interval
+	if next, ok := s.service.NextPublicationChange(); ok {
+		if untilNext := next.Sub(s.clock.Now()); untilNext < wait {
+			wait = untilNext
+		}
+	}
+	if wait < time.Second {
+		wait = time.Second // Avoids spinning while a due window can't be applied
+	}
+	return wait
+}
+
+// Start applies the due windows immediately and then every time a window is due until Stop is called
+// Start()
+
+func (s *PublicationScheduler) Start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			s.Apply()
+			timer := time.NewTimer(s.nextWait())
+			select {
+			case <-timer.C:
+			case <-s.stop:
+				timer.Stop()
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the scheduler and waits for the running check to finish
+// Stop()
+
+func (s *PublicationScheduler) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
@@ -0,0 +1,116 @@
+package worker_test
+
+import (
+	"bytes"
+	"proyecto/internal"
+	"proyecto/internal/clock"
+	"proyecto/internal/repository"
+	"proyecto/internal/service"
+	"proyecto/internal/storage"
+	"proyecto/internal/worker"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// initStorage initializes the storage
+// initStorage(map[int]internal.TProduct) -> *storage.ProductStorageDefault
+// Args:
+// 	initialProducts: Initial products
+// Returns:
+// 	*ProductStorageDefault: Initialized storage
+
+func initStorage(initialProducts map[int]internal.TProduct) *storage.ProductStorageDefault {
+	/* Storage creation */
+	filepath := "/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/internal/worker/products_test.json"
+	storage := storage.NewProductStorageDefault(filepath)
+
+	/* Initial data of the storage */
+	err := storage.WriteAll(initialProducts)
+	if err != nil {
+		panic(err)
+	}
+	return storage
+}
+
+// TestPublicationScheduler_Apply tests the PublicationScheduler Apply method
+func TestPublicationScheduler_Apply(t *testing.T) {
+	// Test 1: should flip the publish state when the window is due
+	t.Run("should flip the publish state when the window is due", func(t *testing.T) {
+		/* Prepare the test data */
+		start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+		publishAt := start.Add(time.Hour)
+		unpublishAt := start.Add(3 * time.Hour)
+		initialProducts := map[int]internal.TProduct{
+			1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: false, Expiration: "11/11/2025", Price: 10.5,
+				PublishAt: &publishAt, UnpublishAt: &unpublishAt},
+			2: {ID: 2, Name: "Product 2", Quantity: 20, CodeValue: "AX02", IsPublished: true, Expiration: "11/11/2025", Price: 20.5},
+		}
+
+		/* Initialize dependencies */
+		fixedClock := clock.NewClockFixed(start)
+		repository := repository.NewProductMap(initStorage(initialProducts))
+		service := service.NewProductServiceDefault(repository)
+		service.SetClock(fixedClock)
+		var output bytes.Buffer
+		scheduler := worker.NewPublicationScheduler(service, fixedClock, time.Minute, &output)
+
+		/* Before the window nothing changes */
+		require.Empty(t, scheduler.Apply())
+		product, err := service.GetProductByID(1)
+		require.NoError(t, err)
+		require.False(t, product.IsPublished)
+
+		/* The product goes live at publish_at */
+		fixedClock.Set(publishAt)
+		changed := scheduler.Apply()
+		require.Len(t, changed, 1)
+		require.True(t, changed[0].IsPublished)
+		require.Nil(t, changed[0].PublishAt)
+		next, ok := service.NextPublicationChange()
+		require.True(t, ok)
+		require.Equal(t, unpublishAt, next)
+
+		/* The product is withdrawn at unpublish_at */
+		fixedClock.Set(unpublishAt.Add(time.Minute))
+		changed = scheduler.Apply()
+		require.Len(t, changed, 1)
+		require.False(t, changed[0].IsPublished)
+		_, ok = service.NextPublicationChange()
+		require.False(t, ok)
+
+		/* Every change is recorded */
+		expectedOutput := "[2024-03-01 10:00:00] SCHEDULE product 1 published\n" +
+			"[2024-03-01 12:01:00] SCHEDULE product 1 unpublished\n"
+		require.Equal(t, expectedOutput, output.String())
+	})
+	// Test 2: should not republish a product expired when its publish moment is due
+	t.Run("should not republish a product expired when its publish moment is due", func(t *testing.T) {
+		/* Prepare the test data */
+		publishAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.Local)
+		initialProducts := map[int]internal.TProduct{
+			1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: false, Expiration: "29/02/2024", Price: 10.5,
+				PublishAt: &publishAt},
+		}
+
+		/* Initialize dependencies */
+		fixedClock := clock.NewClockFixed(publishAt)
+		repository := repository.NewProductMap(initStorage(initialProducts))
+		service := service.NewProductServiceDefault(repository)
+		service.SetClock(fixedClock)
+		var output bytes.Buffer
+		scheduler := worker.NewPublicationScheduler(service, fixedClock, time.Minute, &output)
+
+		/* The due moment is cleared but the product stays unpublished */
+		changed := scheduler.Apply()
+		require.Len(t, changed, 1)
+		require.False(t, changed[0].IsPublished)
+		product, err := service.GetProductByID(1)
+		require.NoError(t, err)
+		require.False(t, product.IsPublished)
+		require.Nil(t, product.PublishAt)
+		_, ok := service.NextPublicationChange()
+		require.False(t, ok)
+	})
+}
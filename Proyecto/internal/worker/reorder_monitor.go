@@ -0,0 +1,105 @@
+package worker
+
+import (
+	"fmt"
+	"proyecto/internal"
+	"sync"
+	"time"
+)
+
+// ReorderMonitor periodically checks the stock of the products and notifies the ones which
+// crossed below their reorder point
+type ReorderMonitor struct {
+	service  internal.ProductService // Service used to build the reorder report
+	notifier internal.Notifier       // Where the crossings are notified
+	clock    internal.Clock          // Clock the notifications are dated with
+	interval time.Duration           // Time between two checks
+	below    map[int]bool            // Products at or below their reorder point on the last check
+	mu       sync.Mutex              // Guards below
+	stop     chan struct{}           // Closed to stop the monitor
+	wg       sync.WaitGroup          // Waits for the running check to finish
+}
+
+// NewReorderMonitor creates a new ReorderMonitor
+// NewReorderMonitor(ps internal.ProductService, n internal.Notifier, c internal.Clock, interval time.Duration) -> *ReorderMonitor
+// Args:
+//		ps: 	  Product service
+//		n: 		  Notifier of the crossings
+//		c: 		  Clock the notifications are dated with
+//		interval: Time between two checks
+// Return:
+//		*ReorderMonitor: New ReorderMonitor
+
+func NewReorderMonitor(ps internal.ProductService, n internal.Notifier, c internal.Clock, interval time.Duration) *ReorderMonitor {
+	return &ReorderMonitor{
+		service:  ps,
+		notifier: n,
+		clock:    c,
+		interval: interval,
+		below:    make(map[int]bool),
+		stop:     make(chan struct{}),
+	}
+}
+
+// Check notifies the products which are at or below their reorder point and were not on the
+// previous check. On the first check every product below its point is notified
+// Check() -> []internal.TNotification
+// Return:
+//		[]internal.TNotification: Notifications delivered
+
+func (m *ReorderMonitor) Check() []internal.TNotification {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	below := make(map[int]bool)
+	notifications := make([]internal.TNotification, 0)
+	for _, suggestion := range m.service.GetReorderReport() {
+		below[suggestion.ProductID] = true
+		if m.below[suggestion.ProductID] {
+			continue
+		}
+		notification := internal.TNotification{
+			Type:      internal.NotificationReorder,
+			ProductID: suggestion.ProductID,
+			Message: fmt.Sprintf("product %d (%s) at %d units, reorder point %d: purchase %d",
+				suggestion.ProductID, suggestion.CodeValue, suggestion.Quantity, suggestion.ReorderPoint, suggestion.SuggestedQuantity),
+			Date: m.clock.Now(),
+		}
+		if err := m.notifier.Notify(notification); err != nil {
+			/* Retried on the next check */
+			delete(below, suggestion.ProductID)
+			continue
+		}
+		notifications = append(notifications, notification)
+	}
+	m.below = below
+	return notifications
+}
+
+// Start runs a check immediately and then once every interval until Stop is called
+// Start()
+
+func (m *ReorderMonitor) Start() {
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+		for {
+			m.Check()
+			select {
+			case <-ticker.C:
+			case <-m.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the monitor and waits for the running check to finish
+// Stop()
+
+func (m *ReorderMonitor) Stop() {
+	close(m.stop)
+	m.wg.Wait()
+}
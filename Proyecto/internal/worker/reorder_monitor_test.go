@@ -0,0 +1,64 @@
+package worker_test
+
+import (
+	"bytes"
+	"proyecto/internal"
+	"proyecto/internal/clock"
+	"proyecto/internal/notifier"
+	"proyecto/internal/repository"
+	"proyecto/internal/service"
+	"proyecto/internal/worker"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestReorderMonitor_Check tests the ReorderMonitor Check method
+func TestReorderMonitor_Check(t *testing.T) {
+	// Test 1: should notify a product only when it crosses below its reorder point
+	t.Run("should notify a product only when it crosses below its reorder point", func(t *testing.T) {
+		/* Prepare the test data */
+		reorderPoint := 5
+		initialProducts := map[int]internal.TProduct{
+			1: {ID: 1, Name: "Product 1", Quantity: 10, CodeValue: "AX01", IsPublished: true, Expiration: "11/11/2024", Price: 10.5,
+				ReorderPoint: &reorderPoint, ReorderQuantity: 20},
+		}
+
+		/* Initialize dependencies */
+		fixedClock := clock.NewClockFixed(time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC))
+		repository := repository.NewProductMap(initStorage(initialProducts))
+		service := service.NewProductServiceDefault(repository)
+		var output bytes.Buffer
+		monitor := worker.NewReorderMonitor(service, notifier.NewNotifierLog(&output), fixedClock, time.Minute)
+
+		/* Above the reorder point nothing is notified */
+		require.Empty(t, monitor.Check())
+
+		/* The product crosses below its reorder point */
+		product, err := repository.GetProductByID(1)
+		require.NoError(t, err)
+		product.Quantity = 4
+		require.NoError(t, repository.UpdateProduct(&product))
+		notifications := monitor.Check()
+		require.Len(t, notifications, 1)
+		require.Equal(t, internal.NotificationReorder, notifications[0].Type)
+
+		/* While it stays below it is not notified again */
+		fixedClock.Advance(time.Minute)
+		require.Empty(t, monitor.Check())
+
+		/* After restocking a new crossing is notified again */
+		product.Quantity = 30
+		require.NoError(t, repository.UpdateProduct(&product))
+		require.Empty(t, monitor.Check())
+		product.Quantity = 5
+		require.NoError(t, repository.UpdateProduct(&product))
+		require.Len(t, monitor.Check(), 1)
+
+		/* Every crossing is recorded */
+		expectedOutput := "[2024-03-01 09:00:00] REORDER product 1 (AX01) at 4 units, reorder point 5: purchase 20\n" +
+			"[2024-03-01 09:01:00] REORDER product 1 (AX01) at 5 units, reorder point 5: purchase 20\n"
+		require.Equal(t, expectedOutput, output.String())
+	})
+}
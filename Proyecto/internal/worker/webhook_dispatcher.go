@@ -0,0 +1,279 @@
+package worker
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"proyecto/internal"
+	"proyecto/platform/web/webhook"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// webhookResponseLimit is the part of a receiver answer read before closing it
+const webhookResponseLimit = 64 << 10
+
+// WebhookDispatcher queues a webhook delivery for every product event published on the bus and
+// posts the due deliveries to their receivers, signed with the secret of their webhook
+type WebhookDispatcher struct {
+	service  internal.WebhookService  // Service holding the webhooks and their deliveries
+	bus      internal.ProductEventBus // Bus the product events are followed on
+	client   *http.Client             // Client the deliveries are posted with
+	clock    internal.Clock           // Clock the attempts are dated and signed with
+	interval time.Duration            // Maximum time between two dispatches
+	output   io.Writer                // Where the attempts are recorded
+	lastID   int64                    // Last product event queued
+	wake     chan struct{}            // Signaled when new deliveries are queued
+	stop     chan struct{}            // Closed to stop the dispatcher
+	mu       sync.Mutex               // Serializes the dispatches and the records
+	wg       sync.WaitGroup           // Waits for the running follower and dispatch to finish
+}
+
+// NewWebhookDispatcher creates a new WebhookDispatcher
+// NewWebhookDispatcher(ws internal.WebhookService, bus internal.ProductEventBus, client *http.Client, c internal.Clock, interval time.Duration, output io.Writer) -> *WebhookDispatcher
+// Args:
+//		ws: 	  Webhook service
+//		bus: 	  Product event bus the events are followed on
+//		client:   HTTP client the deliveries are posted with. Its timeout bounds every attempt
+//		c: 		  Clock the attempts are dated and signed with
+//		interval: Maximum time between two dispatches. The dispatcher wakes up earlier when an attempt is due before
+//		output:   Writer where the attempts are recorded
+// Return:
+//		*WebhookDispatcher: New WebhookDispatcher
+
+func NewWebhookDispatcher(ws internal.WebhookService, bus internal.ProductEventBus, client *http.Client, c internal.Clock, interval time.Duration, output io.Writer) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		service:  ws,
+		bus:      bus,
+		client:   client,
+		clock:    c,
+		interval: interval,
+		output:   output,
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+	}
+}
+
+// record writes a dispatcher action into the output
+// record(format string, args ...any)
+// Args:
+//		format: Message format
+//		args: 	Message arguments
+
+func (d *WebhookDispatcher) record(format string, args ...any) {
+	fmt.Fprintf(d.output, "[%s] WEBHOOK %s\n", d.clock.Now().Format("2006-01-02 15:04:05"), fmt.Sprintf(format, args...))
+}
+
+// enqueue queues the deliveries of a product event not queued yet
+// enqueue(event internal.TProductEvent)
+// Args:
+//		event: Product event published
+
+func (d *WebhookDispatcher) enqueue(event internal.TProductEvent) {
+	if event.ID <= d.lastID {
+		return
+	}
+	deliveries, err := d.service.Enqueue(event)
+	if err != nil {
+		d.mu.Lock()
+		d.record("event %d could not be queued: %v", event.ID, err)
+		d.mu.Unlock()
+		return
+	}
+	d.lastID = event.ID
+	if len(deliveries) > 0 {
+		select {
+		case d.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// catchUp queues the retained events published since the last one queued
+// catchUp()
+
+func (d *WebhookDispatcher) catchUp() {
+	events, err := d.bus.Since(d.lastID)
+	if errors.Is(err, internal.ErrProductEventsEvicted) {
+		d.mu.Lock()
+		d.record("events after %d no longer retained, some deliveries were lost", d.lastID)
+		d.mu.Unlock()
+		d.lastID = 0
+	} else if err != nil {
+		return
+	}
+	for _, event := range events {
+		d.enqueue(event)
+	}
+}
+
+// send posts a delivery to its webhook
+// send(hook internal.TWebhook, delivery internal.TWebhookDelivery) -> internal.TWebhookAttempt
+// Args:
+//		hook: 	  Webhook the delivery is posted to
+//		delivery: Delivery to post
+// Return:
+//		internal.TWebhookAttempt: Attempt made
+
+func (d *WebhookDispatcher) send(hook internal.TWebhook, delivery internal.TWebhookDelivery) internal.TWebhookAttempt {
+	attempt := internal.TWebhookAttempt{Date: d.clock.Now()}
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	timestamp := attempt.Date.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhook.HeaderID, strconv.Itoa(delivery.ID))
+	req.Header.Set(webhook.HeaderEvent, delivery.EventType)
+	req.Header.Set(webhook.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhook.HeaderSignature, webhook.Sign(hook.Secret, timestamp, delivery.Payload))
+
+	start := time.Now()
+	res, err := d.client.Do(req)
+	attempt.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	/* Drain the answer so the connection can be reused */
+	io.Copy(io.Discard, io.LimitReader(res.Body, webhookResponseLimit))
+	res.Body.Close()
+	attempt.StatusCode = res.StatusCode
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		attempt.Error = fmt.Sprintf("unexpected status %d", res.StatusCode)
+	}
+	return attempt
+}
+
+// Dispatch posts the due deliveries and records their attempts
+// Dispatch() []internal.TWebhookDelivery
+// Return:
+//		[]internal.TWebhookDelivery: Deliveries attempted, as updated after their attempt
+
+func (d *WebhookDispatcher) Dispatch() []internal.TWebhookDelivery {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	due, err := d.service.DueDeliveries()
+	if err != nil {
+		d.record("due deliveries could not be read: %v", err)
+		return nil
+	}
+	attempted := make([]internal.TWebhookDelivery, 0, len(due))
+	for _, delivery := range due {
+		/* A webhook deleted meanwhile fails the attempt without posting it */
+		var attempt internal.TWebhookAttempt
+		if hook, err := d.service.GetWebhookByID(delivery.WebhookID); err != nil {
+			attempt = internal.TWebhookAttempt{Date: d.clock.Now(), Error: err.Error()}
+		} else {
+			attempt = d.send(hook, delivery)
+		}
+		updated, err := d.service.RecordAttempt(delivery.ID, attempt)
+		if err != nil {
+			d.record("delivery %d attempt could not be recorded: %v", delivery.ID, err)
+			continue
+		}
+		switch updated.Status {
+		case internal.WebhookDeliveryDelivered:
+			d.record("delivery %d of event %d (%s) to webhook %d delivered", updated.ID, updated.EventID, updated.EventType, updated.WebhookID)
+		case internal.WebhookDeliveryDead:
+			d.record("delivery %d of event %d (%s) to webhook %d dead after %d failures: %s", updated.ID, updated.EventID, updated.EventType, updated.WebhookID, updated.Failures, attempt.Error)
+		default:
+			d.record("delivery %d of event %d (%s) to webhook %d failed: %s, retry at %s", updated.ID, updated.EventID, updated.EventType, updated.WebhookID, attempt.Error, updated.NextAttemptAt.Format("2006-01-02 15:04:05"))
+		}
+		attempted = append(attempted, updated)
+	}
+	return attempted
+}
+
+// nextWait returns how long the dispatcher sleeps until the next dispatch
+// nextWait() -> time.Duration
+
+func (d *WebhookDispatcher) nextWait() time.Duration {
+	wait := d.interval
+	if next, ok := d.service.NextAttempt(); ok {
+		if untilNext := next.Sub(d.clock.Now()); untilNext < wait {
+			wait = untilNext
+		}
+	}
+	if wait < time.Second {
+		wait = time.Second // Avoids spinning while a due delivery can't be recorded
+	}
+	return wait
+}
+
+// follow queues the deliveries of the events published on the bus until Stop is called. A
+// subscription dropped for lagging behind is resumed from the retained events
+// follow()
+
+func (d *WebhookDispatcher) follow() {
+	for {
+		events, cancel := d.bus.Subscribe()
+		d.catchUp()
+		for open := true; open; {
+			select {
+			case event, ok := <-events:
+				if open = ok; ok {
+					d.enqueue(event)
+				}
+			case <-d.stop:
+				cancel()
+				return
+			}
+		}
+		cancel()
+
+		/* The bus closes on shutdown: wait before subscribing again */
+		timer := time.NewTimer(time.Second)
+		select {
+		case <-timer.C:
+		case <-d.stop:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// Start follows the events published from now on and dispatches the due deliveries immediately,
+// then every time an attempt is due or new deliveries are queued, until Stop is called
+// Start()
+
+func (d *WebhookDispatcher) Start() {
+	/* The events published before the start were handled by the previous run */
+	if events, err := d.bus.Since(0); err == nil && len(events) > 0 {
+		d.lastID = events[len(events)-1].ID
+	}
+
+	d.wg.Add(2)
+	go func() {
+		defer d.wg.Done()
+		d.follow()
+	}()
+	go func() {
+		defer d.wg.Done()
+		for {
+			d.Dispatch()
+			timer := time.NewTimer(d.nextWait())
+			select {
+			case <-timer.C:
+			case <-d.wake:
+				timer.Stop()
+			case <-d.stop:
+				timer.Stop()
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the dispatcher and waits for the running dispatch to finish
+// Stop()
+
+func (d *WebhookDispatcher) Stop() {
+	close(d.stop)
+	d.wg.Wait()
+}
@@ -0,0 +1,235 @@
+package worker_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"proyecto/internal"
+	"proyecto/internal/clock"
+	"proyecto/internal/repository"
+	"proyecto/internal/service"
+	"proyecto/internal/storage"
+	"proyecto/internal/worker"
+	"proyecto/platform/web/webhook"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// initWebhookService initializes a webhook service over empty storages
+// initWebhookService(webhooks []internal.TWebhook) -> *service.WebhookServiceDefault
+// Args:
+// 	webhooks: Webhooks to insert
+// Returns:
+// 	*service.WebhookServiceDefault: Initialized service
+
+func initWebhookService(webhooks []internal.TWebhook) *service.WebhookServiceDefault {
+	/* Storage creation */
+	webhookStorage := storage.NewWebhookStorageDefault("/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/internal/worker/webhooks_test.json")
+	deliveryStorage := storage.NewWebhookDeliveryStorageDefault("/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/internal/worker/webhook_deliveries_test.json")
+	if err := webhookStorage.WriteAll(map[int]internal.TWebhook{}); err != nil {
+		panic(err)
+	}
+	if err := deliveryStorage.WriteAll(map[int]internal.TWebhookDelivery{}); err != nil {
+		panic(err)
+	}
+
+	/* Initial webhooks */
+	service := service.NewWebhookServiceDefault(repository.NewWebhookMap(webhookStorage), repository.NewWebhookDeliveryMap(deliveryStorage))
+	for i := range webhooks {
+		if err := service.InsertNewWebhook(&webhooks[i]); err != nil {
+			panic(err)
+		}
+	}
+	return service
+}
+
+// webhookReceiver is a local receiver of webhook requests
+type webhookReceiver struct {
+	server   *httptest.Server
+	status   int                        // Status answered
+	requests []*http.Request            // Requests received
+	payloads []internal.TWebhookPayload // Payloads received with a valid signature
+	mu       sync.Mutex
+}
+
+// newWebhookReceiver starts a receiver verifying the signatures with a secret
+// newWebhookReceiver(t *testing.T, secret string) -> *webhookReceiver
+
+func newWebhookReceiver(t *testing.T, secret string) *webhookReceiver {
+	receiver := &webhookReceiver{status: http.StatusNoContent}
+	receiver.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receiver.mu.Lock()
+		defer receiver.mu.Unlock()
+		receiver.requests = append(receiver.requests, r)
+		body, err := webhook.Verify(r, secret, time.Time{}, 0)
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var payload internal.TWebhookPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		receiver.payloads = append(receiver.payloads, payload)
+		w.WriteHeader(receiver.status)
+	}))
+	t.Cleanup(receiver.server.Close)
+	return receiver
+}
+
+// answer sets the status answered from now on
+func (r *webhookReceiver) answer(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+// received returns the payloads received
+func (r *webhookReceiver) received() []internal.TWebhookPayload {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]internal.TWebhookPayload(nil), r.payloads...)
+}
+
+// TestWebhookDispatcher_Dispatch tests the WebhookDispatcher Dispatch method
+func TestWebhookDispatcher_Dispatch(t *testing.T) {
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	priceEvent := internal.TProductEvent{ID: 7, Type: internal.ProductEventUpdated, ProductID: 1,
+		Changes: []string{internal.ProductChangePrice}, Date: start}
+
+	// Test 1: should post the signed payload to the subscribed webhooks
+	t.Run("should post the signed payload to the subscribed webhooks", func(t *testing.T) {
+		/* Initialize dependencies */
+		receiver := newWebhookReceiver(t, "partner-secret")
+		fixedClock := clock.NewClockFixed(start)
+		webhookService := initWebhookService([]internal.TWebhook{
+			{URL: receiver.server.URL + "/prices", Events: []string{internal.WebhookEventPriceChanged}, Secret: "partner-secret", Active: true},
+			{URL: receiver.server.URL + "/deletions", Events: []string{internal.ProductEventDeleted}, Secret: "partner-secret", Active: true},
+			{URL: receiver.server.URL + "/paused", Events: []string{internal.WebhookEventPriceChanged}, Secret: "partner-secret", Active: false},
+		})
+		webhookService.SetClock(fixedClock)
+		var output bytes.Buffer
+		dispatcher := worker.NewWebhookDispatcher(webhookService, nil, http.DefaultClient, fixedClock, time.Minute, &output)
+
+		/* Queue and dispatch the event */
+		queued, err := webhookService.Enqueue(priceEvent)
+		require.NoError(t, err)
+		require.Len(t, queued, 1)
+		attempted := dispatcher.Dispatch()
+
+		/* Assertions */
+		require.Len(t, attempted, 1)
+		require.Equal(t, internal.WebhookDeliveryDelivered, attempted[0].Status)
+		require.Nil(t, attempted[0].NextAttemptAt)
+		require.Equal(t, []internal.TWebhookAttempt{{Date: start, StatusCode: http.StatusNoContent, DurationMs: attempted[0].Attempts[0].DurationMs}}, attempted[0].Attempts)
+		require.Equal(t, []internal.TWebhookPayload{{Type: internal.WebhookEventPriceChanged, Event: priceEvent}}, receiver.received())
+		require.Len(t, receiver.requests, 1)
+		request := receiver.requests[0]
+		require.Equal(t, "/prices", request.URL.Path)
+		require.Equal(t, "1", request.Header.Get(webhook.HeaderID))
+		require.Equal(t, internal.WebhookEventPriceChanged, request.Header.Get(webhook.HeaderEvent))
+		require.Equal(t, "1709283600", request.Header.Get(webhook.HeaderTimestamp))
+		require.Equal(t, "[2024-03-01 09:00:00] WEBHOOK delivery 1 of event 7 (product.price_changed) to webhook 1 delivered\n", output.String())
+
+		/* Nothing is due anymore */
+		require.Empty(t, dispatcher.Dispatch())
+	})
+
+	// Test 2: should retry with exponential backoff and dead-letter the delivery
+	t.Run("should retry with exponential backoff and dead-letter the delivery", func(t *testing.T) {
+		/* Initialize dependencies */
+		receiver := newWebhookReceiver(t, "partner-secret")
+		receiver.answer(http.StatusInternalServerError)
+		fixedClock := clock.NewClockFixed(start)
+		webhookService := initWebhookService([]internal.TWebhook{
+			{URL: receiver.server.URL, Events: []string{internal.ProductEventUpdated}, Secret: "partner-secret", Active: true},
+		})
+		webhookService.SetClock(fixedClock)
+		webhookService.SetRetryPolicy(3, time.Minute, time.Hour)
+		var output bytes.Buffer
+		dispatcher := worker.NewWebhookDispatcher(webhookService, nil, http.DefaultClient, fixedClock, time.Minute, &output)
+		_, err := webhookService.Enqueue(priceEvent)
+		require.NoError(t, err)
+
+		/* First failure: retried a minute later */
+		attempted := dispatcher.Dispatch()
+		require.Len(t, attempted, 1)
+		require.Equal(t, internal.WebhookDeliveryPending, attempted[0].Status)
+		require.Equal(t, start.Add(time.Minute), *attempted[0].NextAttemptAt)
+		fixedClock.Advance(30 * time.Second)
+		require.Empty(t, dispatcher.Dispatch())
+
+		/* Second failure: the wait doubles */
+		fixedClock.Set(start.Add(time.Minute))
+		attempted = dispatcher.Dispatch()
+		require.Len(t, attempted, 1)
+		require.Equal(t, start.Add(3*time.Minute), *attempted[0].NextAttemptAt)
+
+		/* Third failure: dead-lettered */
+		fixedClock.Set(start.Add(3 * time.Minute))
+		attempted = dispatcher.Dispatch()
+		require.Len(t, attempted, 1)
+		require.Equal(t, internal.WebhookDeliveryDead, attempted[0].Status)
+		require.Equal(t, 3, attempted[0].Failures)
+		require.Len(t, attempted[0].Attempts, 3)
+		require.Equal(t, "unexpected status 500", attempted[0].Attempts[2].Error)
+		_, ok := webhookService.NextAttempt()
+		require.False(t, ok)
+		dead, err := webhookService.GetDeadLetters()
+		require.NoError(t, err)
+		require.Len(t, dead, 1)
+
+		/* A redelivery starts a new round */
+		receiver.answer(http.StatusOK)
+		_, err = webhookService.Redeliver(dead[0].ID)
+		require.NoError(t, err)
+		attempted = dispatcher.Dispatch()
+		require.Len(t, attempted, 1)
+		require.Equal(t, internal.WebhookDeliveryDelivered, attempted[0].Status)
+		require.Len(t, attempted[0].Attempts, 4)
+		require.Len(t, receiver.received(), 4)
+
+		expectedOutput := "[2024-03-01 09:00:00] WEBHOOK delivery 1 of event 7 (product.updated) to webhook 1 failed: unexpected status 500, retry at 2024-03-01 09:01:00\n" +
+			"[2024-03-01 09:01:00] WEBHOOK delivery 1 of event 7 (product.updated) to webhook 1 failed: unexpected status 500, retry at 2024-03-01 09:03:00\n" +
+			"[2024-03-01 09:03:00] WEBHOOK delivery 1 of event 7 (product.updated) to webhook 1 dead after 3 failures: unexpected status 500\n" +
+			"[2024-03-01 09:03:00] WEBHOOK delivery 1 of event 7 (product.updated) to webhook 1 delivered\n"
+		require.Equal(t, expectedOutput, output.String())
+	})
+}
+
+// TestWebhookDispatcher_Start tests the WebhookDispatcher Start method
+func TestWebhookDispatcher_Start(t *testing.T) {
+	// Test 1: should deliver the events published on the bus after the start
+	t.Run("should deliver the events published on the bus after the start", func(t *testing.T) {
+		/* Initialize dependencies */
+		receiver := newWebhookReceiver(t, "partner-secret")
+		webhookService := initWebhookService([]internal.TWebhook{
+			{URL: receiver.server.URL, Events: []string{internal.WebhookEventStockChanged}, Secret: "partner-secret", Active: true},
+		})
+		eventStorage := storage.NewProductEventStorageDefault("/Users/jdoffo/Desktop/Practica Bootcamp/Bootcamp-GoWeb/Proyecto/internal/worker/product_events_test.json")
+		require.NoError(t, eventStorage.WriteAll(nil))
+		bus := service.NewProductEventBusDefault(eventStorage, 10)
+		require.NoError(t, bus.Publish(&internal.TProductEvent{Type: internal.ProductEventUpdated, ProductID: 1, Changes: []string{internal.ProductChangeQuantity}}))
+		var output bytes.Buffer
+		dispatcher := worker.NewWebhookDispatcher(webhookService, bus, http.DefaultClient, clock.NewClockSystem(), time.Minute, &output)
+
+		/* Publish after the start */
+		dispatcher.Start()
+		require.NoError(t, bus.Publish(&internal.TProductEvent{Type: internal.ProductEventUpdated, ProductID: 2}))
+		require.NoError(t, bus.Publish(&internal.TProductEvent{Type: internal.ProductEventUpdated, ProductID: 3, Changes: []string{internal.ProductChangeQuantity}}))
+		require.Eventually(t, func() bool { return len(receiver.received()) > 0 }, 5*time.Second, 10*time.Millisecond)
+		dispatcher.Stop()
+
+		/* Assertions */
+		received := receiver.received()
+		require.Len(t, received, 1)
+		require.Equal(t, internal.WebhookEventStockChanged, received[0].Type)
+		require.Equal(t, int64(3), received[0].Event.ID)
+		require.Equal(t, 3, received[0].Event.ProductID)
+	})
+}
@@ -0,0 +1,57 @@
+package barcode
+
+import (
+	"errors"
+	"strings"
+)
+
+/* Supported symbologies */
+const (
+	Code128 = "code128"
+	EAN13   = "ean13"
+)
+
+/* Errors definition */
+var (
+	ErrUnknownSymbology = errors.New("unknown symbology")
+	ErrNotEncodable     = errors.New("not encodable")
+)
+
+// Encode encodes data in a symbology
+// Encode(symbology, data string) -> ([]bool, error)
+// Args:
+//		symbology: Symbology to encode with (Code128 or EAN13)
+//		data: 	   Data to encode
+// Return:
+//		[]bool: Modules of the barcode from left to right, true for the bars. Quiet zones are not included
+//		error:  Error raised during the execution (if exists)
+
+func Encode(symbology, data string) ([]bool, error) {
+	switch strings.ToLower(symbology) {
+	case Code128:
+		return encodeCode128(data)
+	case EAN13:
+		return encodeEAN13(data)
+	default:
+		return nil, ErrUnknownSymbology
+	}
+}
+
+// widths converts the alternating bar and space widths of a pattern into modules
+// widths(modules []bool, pattern string) -> []bool
+// Args:
+//		modules: Modules the pattern is appended to
+//		pattern: Widths starting with a bar, e.g. "212222"
+// Return:
+//		[]bool: Modules with the pattern appended
+
+func widths(modules []bool, pattern string) []bool {
+	bar := true
+	for _, width := range pattern {
+		for i := 0; i < int(width-'0'); i++ {
+			modules = append(modules, bar)
+		}
+		bar = !bar
+	}
+	return modules
+}
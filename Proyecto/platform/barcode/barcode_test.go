@@ -0,0 +1,126 @@
+package barcode_test
+
+import (
+	"bytes"
+	"image/png"
+	"proyecto/platform/barcode"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// modules converts a string of ones and zeros into modules
+func modules(pattern string) []bool {
+	result := make([]bool, len(pattern))
+	for i := range pattern {
+		result[i] = pattern[i] == '1'
+	}
+	return result
+}
+
+// Tests for EAN13CheckDigit and ValidateEAN13 functions
+func TestEAN13CheckDigit(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// act
+		check, err := barcode.EAN13CheckDigit("400638133393")
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 1, check)
+		require.NoError(t, barcode.ValidateEAN13("4006381333931"))
+	})
+
+	t.Run("wrong check digit", func(t *testing.T) {
+		// act
+		err := barcode.ValidateEAN13("4006381333932")
+
+		// assert
+		require.ErrorIs(t, err, barcode.ErrNotEncodable)
+	})
+
+	t.Run("not digits", func(t *testing.T) {
+		// act
+		_, err := barcode.NewEAN13("40063813339A")
+
+		// assert
+		require.ErrorIs(t, err, barcode.ErrNotEncodable)
+	})
+}
+
+// Tests for Encode function
+func TestEncode(t *testing.T) {
+	t.Run("ean13", func(t *testing.T) {
+		// act
+		result, err := barcode.Encode(barcode.EAN13, "4006381333931")
+
+		// assert
+		expected := modules("101" + "0001101" + "0100111" + "0101111" + "0111101" + "0001001" + "0110011" +
+			"01010" + "1000010" + "1000010" + "1000010" + "1110100" + "1000010" + "1100110" + "101")
+		require.NoError(t, err)
+		require.Equal(t, expected, result)
+	})
+
+	t.Run("code128 set B", func(t *testing.T) {
+		// act
+		result, err := barcode.Encode(barcode.Code128, "A1")
+
+		// assert: start B, "A" (33), "1" (17), check (104 + 33 + 17*2) % 103 = 68, stop
+		expected := modules("11010010000" + "10100011000" + "10011100110" + "10000100110" + "1100011101011")
+		require.NoError(t, err)
+		require.Equal(t, expected, result)
+	})
+
+	t.Run("code128 set C", func(t *testing.T) {
+		// act
+		result, err := barcode.Encode(barcode.Code128, "1234")
+
+		// assert: start C, 12, 34, check (105 + 12 + 34*2) % 103 = 82, stop
+		expected := modules("11010011100" + "10110011100" + "10001011000" + "10010011110" + "1100011101011")
+		require.NoError(t, err)
+		require.Equal(t, expected, result)
+	})
+
+	t.Run("code128 not printable", func(t *testing.T) {
+		// act
+		_, err := barcode.Encode(barcode.Code128, "AXñ01")
+
+		// assert
+		require.ErrorIs(t, err, barcode.ErrNotEncodable)
+	})
+
+	t.Run("unknown symbology", func(t *testing.T) {
+		// act
+		_, err := barcode.Encode("qr", "AX01")
+
+		// assert
+		require.ErrorIs(t, err, barcode.ErrUnknownSymbology)
+	})
+}
+
+// Tests for SVG and PNG functions
+func TestRender(t *testing.T) {
+	t.Run("svg", func(t *testing.T) {
+		// act
+		result := barcode.SVG(modules("1101"), 2, 10)
+
+		// assert
+		expected := `<svg xmlns="http://www.w3.org/2000/svg" width="48" height="10" viewBox="0 0 48 10">` +
+			`<rect width="48" height="10" fill="#fff"/><rect x="20" width="4" height="10"/><rect x="26" width="2" height="10"/></svg>`
+		require.Equal(t, expected, string(result))
+	})
+
+	t.Run("png", func(t *testing.T) {
+		// act
+		result, err := barcode.PNG(modules("1101"), 2, 10)
+
+		// assert
+		require.NoError(t, err)
+		img, err := png.Decode(bytes.NewReader(result))
+		require.NoError(t, err)
+		require.Equal(t, 48, img.Bounds().Dx())
+		r, _, _, _ := img.At(20, 0).RGBA()
+		require.Equal(t, uint32(0), r)
+		r, _, _, _ = img.At(24, 0).RGBA()
+		require.Equal(t, uint32(0xffff), r)
+	})
+}
@@ -0,0 +1,91 @@
+package barcode
+
+import "fmt"
+
+/* Code 128 symbol values */
+const (
+	code128StartB = 104
+	code128StartC = 105
+	code128Stop   = 106
+)
+
+// code128Patterns are the bar and space widths of every Code 128 symbol value
+var code128Patterns = [...]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+// onlyDigits checks if a string is made of decimal digits only
+// onlyDigits(data string) -> bool
+
+func onlyDigits(data string) bool {
+	for _, r := range data {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// code128Values returns the symbol values encoding data, start and check symbols included.
+// Even runs of digits use code set C (two digits per symbol), the rest code set B
+// code128Values(data string) -> ([]int, error)
+// Args:
+//		data: Data to encode. Only printable ASCII is accepted
+// Return:
+//		[]int: Symbol values without the stop symbol
+//		error: Error raised during the execution (if exists)
+
+func code128Values(data string) ([]int, error) {
+	if data == "" {
+		return nil, fmt.Errorf("%w: empty data", ErrNotEncodable)
+	}
+
+	/* Pick the code set */
+	var values []int
+	if len(data)%2 == 0 && onlyDigits(data) {
+		values = append(values, code128StartC)
+		for i := 0; i < len(data); i += 2 {
+			values = append(values, int(data[i]-'0')*10+int(data[i+1]-'0'))
+		}
+	} else {
+		values = append(values, code128StartB)
+		for _, r := range data {
+			if r < ' ' || r > '~' {
+				return nil, fmt.Errorf("%w: %q is not printable ASCII", ErrNotEncodable, r)
+			}
+			values = append(values, int(r-' '))
+		}
+	}
+
+	/* Weighted modulo 103 check symbol */
+	checksum := values[0]
+	for i, value := range values[1:] {
+		checksum += value * (i + 1)
+	}
+	return append(values, checksum%103), nil
+}
+
+// encodeCode128 encodes data in Code 128
+// encodeCode128(data string) -> ([]bool, error)
+
+func encodeCode128(data string) ([]bool, error) {
+	values, err := code128Values(data)
+	if err != nil {
+		return nil, err
+	}
+	modules := make([]bool, 0, len(values)*11+13)
+	for _, value := range append(values, code128Stop) {
+		modules = widths(modules, code128Patterns[value])
+	}
+	return modules, nil
+}
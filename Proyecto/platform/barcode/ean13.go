@@ -0,0 +1,123 @@
+package barcode
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ean13LCodes are the left hand odd parity patterns of the digits. The even parity (G) and
+// right hand (R) patterns are derived from them
+var ean13LCodes = [10]string{
+	"0001101", "0011001", "0010011", "0111101", "0100011", "0110001", "0101111", "0111011", "0110111", "0001011",
+}
+
+// ean13Parities are the parities of the left hand digits, selected by the first digit
+var ean13Parities = [10]string{
+	"LLLLLL", "LLGLGG", "LLGGLG", "LLGGGL", "LGLLGG", "LGGLLG", "LGGGLL", "LGLGLG", "LGLGGL", "LGGLGL",
+}
+
+// EAN13CheckDigit computes the check digit of the first twelve digits of an EAN-13
+// EAN13CheckDigit(data string) -> (int, error)
+// Args:
+//		data: Twelve digits
+// Return:
+//		int:   Check digit
+//		error: Error raised during the execution (if exists)
+
+func EAN13CheckDigit(data string) (int, error) {
+	if len(data) != 12 || !onlyDigits(data) {
+		return 0, fmt.Errorf("%w: an EAN-13 needs 12 digits plus the check digit", ErrNotEncodable)
+	}
+	sum := 0
+	for i := 0; i < 12; i++ {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(data[i]-'0') * weight
+	}
+	return (10 - sum%10) % 10, nil
+}
+
+// ValidateEAN13 checks a code is a thirteen digit EAN-13 with a correct check digit
+// ValidateEAN13(code string) -> error
+// Args:
+//		code: Code to check
+// Return:
+//		error: Error raised during the execution (if exists)
+
+func ValidateEAN13(code string) error {
+	if len(code) != 13 || !onlyDigits(code) {
+		return fmt.Errorf("%w: an EAN-13 has 13 digits", ErrNotEncodable)
+	}
+	check, err := EAN13CheckDigit(code[:12])
+	if err != nil {
+		return err
+	}
+	if strconv.Itoa(check) != code[12:] {
+		return fmt.Errorf("%w: wrong check digit, expected %d", ErrNotEncodable, check)
+	}
+	return nil
+}
+
+// NewEAN13 completes twelve digits into an EAN-13 appending their check digit
+// NewEAN13(data string) -> (string, error)
+// Args:
+//		data: Twelve digits
+// Return:
+//		string: EAN-13 code
+//		error:  Error raised during the execution (if exists)
+
+func NewEAN13(data string) (string, error) {
+	check, err := EAN13CheckDigit(data)
+	if err != nil {
+		return "", err
+	}
+	return data + strconv.Itoa(check), nil
+}
+
+// ean13Pattern returns the modules of a digit in a parity
+// ean13Pattern(digit byte, parity byte) -> string
+
+func ean13Pattern(digit byte, parity byte) string {
+	l := ean13LCodes[digit-'0']
+	pattern := make([]byte, len(l))
+	for i := range l {
+		switch parity {
+		case 'L':
+			pattern[i] = l[i]
+		case 'R':
+			pattern[i] = '0' + '1' - l[i]
+		case 'G':
+			pattern[i] = '0' + '1' - l[len(l)-1-i]
+		}
+	}
+	return string(pattern)
+}
+
+// encodeEAN13 encodes a code in EAN-13
+// encodeEAN13(code string) -> ([]bool, error)
+
+func encodeEAN13(code string) ([]bool, error) {
+	if err := ValidateEAN13(code); err != nil {
+		return nil, err
+	}
+
+	/* The first digit is only encoded in the parities of the left half */
+	pattern := "101"
+	parities := ean13Parities[code[0]-'0']
+	for i := 1; i <= 6; i++ {
+		pattern += ean13Pattern(code[i], parities[i-1])
+	}
+	pattern += "01010"
+	for i := 7; i <= 12; i++ {
+		pattern += ean13Pattern(code[i], 'R')
+	}
+	pattern += "101"
+
+	modules := make([]bool, len(pattern))
+	for i := range pattern {
+		modules[i] = pattern[i] == '1'
+	}
+	return modules, nil
+}
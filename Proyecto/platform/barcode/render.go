@@ -0,0 +1,73 @@
+package barcode
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// QuietZone is the blank modules rendered on each side of a barcode
+const QuietZone = 10
+
+// SVG renders the modules of a barcode as an SVG image
+// SVG(modules []bool, moduleWidth, height int) -> []byte
+// Args:
+//		modules: 	 Modules of the barcode
+//		moduleWidth: Width of a module in pixels
+//		height: 	 Height of the bars in pixels
+// Return:
+//		[]byte: SVG document
+
+func SVG(modules []bool, moduleWidth, height int) []byte {
+	width := (len(modules) + 2*QuietZone) * moduleWidth
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, width, height, width, height)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/>`, width, height)
+
+	/* One rect per run of bars */
+	for i := 0; i < len(modules); {
+		if !modules[i] {
+			i++
+			continue
+		}
+		start := i
+		for i < len(modules) && modules[i] {
+			i++
+		}
+		fmt.Fprintf(&buf, `<rect x="%d" width="%d" height="%d"/>`, (QuietZone+start)*moduleWidth, (i-start)*moduleWidth, height)
+	}
+	buf.WriteString(`</svg>`)
+	return buf.Bytes()
+}
+
+// PNG renders the modules of a barcode as a grayscale PNG image
+// PNG(modules []bool, moduleWidth, height int) -> ([]byte, error)
+// Args:
+//		modules: 	 Modules of the barcode
+//		moduleWidth: Width of a module in pixels
+//		height: 	 Height of the bars in pixels
+// Return:
+//		[]byte: PNG image
+//		error:  Error raised during the execution (if exists)
+
+func PNG(modules []bool, moduleWidth, height int) ([]byte, error) {
+	width := (len(modules) + 2*QuietZone) * moduleWidth
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		module := x/moduleWidth - QuietZone
+		shade := color.Gray{Y: 0xff}
+		if module >= 0 && module < len(modules) && modules[module] {
+			shade = color.Gray{Y: 0}
+		}
+		for y := 0; y < height; y++ {
+			img.SetGray(x, y, shade)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
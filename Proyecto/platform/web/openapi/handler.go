@@ -0,0 +1,45 @@
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+	"proyecto/platform/web/response"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// docsPage is the documentation page rendering a specification in the browser
+//
+//go:embed docs.html
+var docsPage string
+
+// Handler serves the OpenAPI 3 document of the described routes of a router
+// Handler(routes chi.Routes) -> http.HandlerFunc
+// Args:
+//		routes: Router serving the API. It is walked on every request, so routes registered
+//				after the handler are included
+// Return:
+//		http.HandlerFunc: HTTP handler
+
+func (s *Spec) Handler(routes chi.Routes) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response.JSON(w, http.StatusOK, s.Document(routes))
+	}
+}
+
+// DocsHandler serves the documentation page of a specification
+// DocsHandler(specURL string) -> http.HandlerFunc
+// Args:
+//		specURL: URL the OpenAPI document is served at
+// Return:
+//		http.HandlerFunc: HTTP handler
+
+func DocsHandler(specURL string) http.HandlerFunc {
+	page := []byte(strings.ReplaceAll(docsPage, "{{SPEC_URL}}", specURL))
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write(page)
+	}
+}
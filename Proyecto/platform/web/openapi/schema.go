@@ -0,0 +1,143 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Schema is an OpenAPI schema object
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+}
+
+/* Schemas of the types with their own JSON encoding */
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	rawType       = reflect.TypeOf(json.RawMessage{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schemas collects the schemas of the named struct types referenced by a document
+type schemas struct {
+	byName map[string]*Schema      // Schemas by component name.
+	names  map[reflect.Type]string // Component name of each type seen.
+	taken  map[string]reflect.Type // Type owning each component name.
+}
+
+// newSchemas creates an empty schema collection
+// newSchemas() -> *schemas
+
+func newSchemas() *schemas {
+	return &schemas{
+		byName: make(map[string]*Schema),
+		names:  make(map[reflect.Type]string),
+		taken:  make(map[string]reflect.Type),
+	}
+}
+
+// componentName returns the component name of a named type. Types sharing their name with a
+// type of another package are prefixed by their package name
+// componentName(t reflect.Type) -> string
+
+func (s *schemas) componentName(t reflect.Type) string {
+	if name, ok := s.names[t]; ok {
+		return name
+	}
+	name := t.Name()
+	if owner, ok := s.taken[name]; ok && owner != t {
+		path := strings.Split(t.PkgPath(), "/")
+		name = path[len(path)-1] + "." + name
+	}
+	s.names[t] = name
+	s.taken[name] = t
+	return name
+}
+
+// schemaOf returns the schema of the JSON encoding of a type. Named structs are registered as
+// components and referenced
+// schemaOf(t reflect.Type) -> *Schema
+// Args:
+//		t: Go type. Nil for values of any type
+// Return:
+//		*Schema: Schema of its JSON encoding
+
+func (s *schemas) schemaOf(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t, nullable = t.Elem(), true
+	}
+
+	var schema *Schema
+	switch {
+	case t == timeType:
+		schema = &Schema{Type: "string", Format: "date-time"}
+	case t == rawType || t.Kind() == reflect.Interface:
+		schema = &Schema{}
+	case t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType):
+		schema = &Schema{}
+	case t.Kind() == reflect.Struct && t.Name() != "":
+		name := s.componentName(t)
+		if _, ok := s.byName[name]; !ok {
+			s.byName[name] = &Schema{} // Placeholder breaking recursive types.
+			s.byName[name] = s.structSchema(t)
+		}
+		schema = &Schema{Ref: "#/components/schemas/" + name}
+	case t.Kind() == reflect.Struct:
+		schema = s.structSchema(t)
+	case t.Kind() == reflect.Map:
+		schema = &Schema{Type: "object", AdditionalProperties: s.schemaOf(t.Elem()), Nullable: true}
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		schema = &Schema{Type: "string", Format: "byte"}
+	case t.Kind() == reflect.Slice:
+		/* Nil maps and slices are encoded as null */
+		schema = &Schema{Type: "array", Items: s.schemaOf(t.Elem()), Nullable: true}
+	case t.Kind() == reflect.Array:
+		schema = &Schema{Type: "array", Items: s.schemaOf(t.Elem())}
+	case t.Kind() == reflect.Bool:
+		schema = &Schema{Type: "boolean"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		schema = &Schema{Type: "integer"}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		schema = &Schema{Type: "number"}
+	default:
+		schema = &Schema{Type: "string"}
+	}
+	if nullable && schema.Ref == "" {
+		schema.Nullable = true
+	}
+	return schema
+}
+
+// structSchema returns the object schema of a struct
+// structSchema(t reflect.Type) -> *Schema
+
+func (s *schemas) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = s.schemaOf(field.Type)
+	}
+	return schema
+}
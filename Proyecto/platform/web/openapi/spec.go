@@ -0,0 +1,385 @@
+package openapi
+
+import (
+	"mime"
+	"net/http"
+	"proyecto/platform/web/request"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Parameter describes a query parameter of an operation
+type Parameter struct {
+	Name        string // Name of the parameter.
+	Type        string // Schema type: string, integer, number or boolean.
+	Description string // What the parameter does.
+	Required    bool   // The operation fails without it.
+}
+
+// Operation describes an operation of the API
+type Operation struct {
+	ID            string         // Unique operation id. Usually the name of the handler.
+	Summary       string         // Short summary.
+	Tag           string         // Group of operations the operation is listed under.
+	Query         []Parameter    // Query parameters.
+	Request       any            // Value of the request body type. Nil without body.
+	RequestTypes  []string       // Media types of the request body. (Optional, application/json)
+	Requests      map[string]any // Value of the request body type per media type, when they differ. (Optional)
+	Status        int            // Status of the successful response. (Optional, 200)
+	Response      any            // Value of the data member of the response body. Nil without body.
+	Members       map[string]any // Members of the response body besides data. (Optional)
+	Raw           bool           // The response body is Response itself, with no data member.
+	ResponseTypes []string       // Media types of the response body. (Optional, application/json)
+	Responses     map[string]any // Value of the response body type per media type, when they differ. (Optional)
+	Problems      []int          // Statuses answered with an application/problem+json body.
+	Deprecated    bool           // The operation has a successor and will stop being served.
+}
+
+// Spec holds the descriptions of the operations of an API, keyed by method and route pattern
+type Spec struct {
+	title      string
+	version    string
+	operations map[string]Operation
+	problem    any // Value of the problem details type. Nil to leave the problems untyped
+}
+
+// NewSpec creates an empty specification
+// NewSpec(title string, version string) -> *Spec
+// Args:
+//		title:   Title of the API
+//		version: Version of the API
+// Return:
+//		*Spec: New Spec instance
+
+func NewSpec(title string, version string) *Spec {
+	return &Spec{title: title, version: version, operations: make(map[string]Operation)}
+}
+
+// operationKey returns the key an operation is described under
+// operationKey(method string, pattern string) -> string
+
+func operationKey(method string, pattern string) string {
+	return strings.ToUpper(method) + " " + pattern
+}
+
+// Describe describes the operation served on a route
+// Describe(method string, pattern string, operation Operation) -> *Spec
+// Args:
+//		method:    HTTP method
+//		pattern:   Route pattern as registered, path params included (/products/{id})
+//		operation: Description of the operation
+// Return:
+//		*Spec: The same spec, to chain descriptions
+
+func (s *Spec) Describe(method string, pattern string, operation Operation) *Spec {
+	s.operations[operationKey(method, pattern)] = operation
+	return s
+}
+
+// Alias describes the operations described so far again under a path prefix, as the routes
+// mounted under a version prefix serve them. The ids of the copies take a suffix to stay unique.
+// The prefix pins the version, so the copies leave out the bodies of the media types asking
+// for a version
+// Alias(prefix string, idSuffix string) -> *Spec
+// Args:
+//		prefix:   Path prefix of the copies (/v1)
+//		idSuffix: Suffix of the operation ids of the copies (V1)
+// Return:
+//		*Spec: The same spec, to chain descriptions
+
+func (s *Spec) Alias(prefix string, idSuffix string) *Spec {
+	aliases := make(map[string]Operation, len(s.operations))
+	for key, operation := range s.operations {
+		method, pattern, _ := strings.Cut(key, " ")
+		operation.ID += idSuffix
+		operation.Requests = unversioned(operation.Requests)
+		operation.Responses = unversioned(operation.Responses)
+		aliases[operationKey(method, prefix+pattern)] = operation
+	}
+	for key, operation := range aliases {
+		s.operations[key] = operation
+	}
+	return s
+}
+
+// unversioned returns the bodies of the media types with no version parameter
+// unversioned(bodies map[string]any) -> map[string]any
+
+func unversioned(bodies map[string]any) map[string]any {
+	if bodies == nil {
+		return nil
+	}
+	result := make(map[string]any, len(bodies))
+	for mediaType, body := range bodies {
+		if _, params, err := mime.ParseMediaType(mediaType); err == nil && params[request.VersionParam] != "" {
+			continue
+		}
+		result[mediaType] = body
+	}
+	return result
+}
+
+// SetProblem sets the type of the application/problem+json bodies
+// SetProblem(problem any) -> *Spec
+// Args:
+//		problem: Value of the problem details type
+// Return:
+//		*Spec: The same spec, to chain descriptions
+
+func (s *Spec) SetProblem(problem any) *Spec {
+	s.problem = problem
+	return s
+}
+
+// walk lists the method and pattern of the routes of a router
+// walk(routes chi.Routes) -> []string
+
+func walk(routes chi.Routes) []string {
+	keys := make([]string, 0)
+	chi.Walk(routes, func(method string, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		keys = append(keys, operationKey(method, strings.ReplaceAll(route, "/*/", "/")))
+		return nil
+	})
+	sort.Strings(keys)
+	return keys
+}
+
+// Undescribed lists the routes of a router which aren't described
+// Undescribed(routes chi.Routes) -> []string
+// Args:
+//		routes: Router to inspect
+// Return:
+//		[]string: Method and pattern of the routes, sorted
+
+func (s *Spec) Undescribed(routes chi.Routes) []string {
+	undescribed := make([]string, 0)
+	for _, key := range walk(routes) {
+		if _, ok := s.operations[key]; !ok {
+			undescribed = append(undescribed, key)
+		}
+	}
+	return undescribed
+}
+
+// Unregistered lists the operations described on routes a router doesn't have
+// Unregistered(routes chi.Routes) -> []string
+// Args:
+//		routes: Router to inspect
+// Return:
+//		[]string: Method and pattern of the operations, sorted
+
+func (s *Spec) Unregistered(routes chi.Routes) []string {
+	registered := make(map[string]bool)
+	for _, key := range walk(routes) {
+		registered[key] = true
+	}
+	unregistered := make([]string, 0)
+	for key := range s.operations {
+		if !registered[key] {
+			unregistered = append(unregistered, key)
+		}
+	}
+	sort.Strings(unregistered)
+	return unregistered
+}
+
+/* OpenAPI 3 document */
+type (
+	// Document is an OpenAPI 3 document
+	Document struct {
+		OpenAPI    string                               `json:"openapi"`
+		Info       Info                                 `json:"info"`
+		Paths      map[string]map[string]*PathOperation `json:"paths"`
+		Components Components                           `json:"components"`
+		Security   []map[string][]string                `json:"security,omitempty"`
+	}
+
+	// Info is the metadata of the API
+	Info struct {
+		Title   string `json:"title"`
+		Version string `json:"version"`
+	}
+
+	// Components holds the schemas and security schemes referenced by the document
+	Components struct {
+		Schemas         map[string]*Schema        `json:"schemas,omitempty"`
+		SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+	}
+
+	// SecurityScheme is an API key security scheme
+	SecurityScheme struct {
+		Type string `json:"type"`
+		In   string `json:"in"`
+		Name string `json:"name"`
+	}
+
+	// PathOperation is an operation of a path item
+	PathOperation struct {
+		OperationID string            `json:"operationId"`
+		Summary     string            `json:"summary,omitempty"`
+		Tags        []string          `json:"tags,omitempty"`
+		Parameters  []ParameterObject `json:"parameters,omitempty"`
+		RequestBody *Body             `json:"requestBody,omitempty"`
+		Responses   map[string]Body   `json:"responses"`
+		Deprecated  bool              `json:"deprecated,omitempty"`
+	}
+
+	// ParameterObject is a path or query parameter
+	ParameterObject struct {
+		Name        string  `json:"name"`
+		In          string  `json:"in"`
+		Description string  `json:"description,omitempty"`
+		Required    bool    `json:"required"`
+		Schema      *Schema `json:"schema"`
+	}
+
+	// Body is a request body or a response
+	Body struct {
+		Description string               `json:"description,omitempty"`
+		Required    bool                 `json:"required,omitempty"`
+		Content     map[string]MediaType `json:"content,omitempty"`
+	}
+
+	// MediaType holds the schema of a body in a media type
+	MediaType struct {
+		Schema *Schema `json:"schema"`
+	}
+)
+
+// pathParam matches the params of a route pattern
+var pathParam = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+// content returns the content of a body in several media types
+// content(schema *Schema, mediaTypes []string) -> map[string]MediaType
+
+func content(schema *Schema, mediaTypes []string) map[string]MediaType {
+	if len(mediaTypes) == 0 {
+		mediaTypes = []string{"application/json"}
+	}
+	result := make(map[string]MediaType, len(mediaTypes))
+	for _, mediaType := range mediaTypes {
+		result[mediaType] = MediaType{Schema: schema}
+	}
+	return result
+}
+
+// responseSchema returns the schema of a successful response body: the value itself when the
+// operation is raw, or the value as the data member along with the other members
+// responseSchema(operation Operation, response any, components *schemas) -> *Schema
+
+func responseSchema(operation Operation, response any, components *schemas) *Schema {
+	if operation.Raw {
+		return components.schemaOf(reflect.TypeOf(response))
+	}
+	envelope := &Schema{Type: "object", Properties: map[string]*Schema{"data": components.schemaOf(reflect.TypeOf(response))}}
+	for name, value := range operation.Members {
+		envelope.Properties[name] = components.schemaOf(reflect.TypeOf(value))
+	}
+	return envelope
+}
+
+// pathOperation builds the document operation of a described route
+// pathOperation(pattern string, operation Operation, components *schemas) -> *PathOperation
+
+func (s *Spec) pathOperation(pattern string, operation Operation, components *schemas) *PathOperation {
+	result := &PathOperation{
+		OperationID: operation.ID,
+		Summary:     operation.Summary,
+		Responses:   make(map[string]Body),
+		Deprecated:  operation.Deprecated,
+	}
+	if operation.Tag != "" {
+		result.Tags = []string{operation.Tag}
+	}
+
+	/* Parameters */
+	for _, match := range pathParam.FindAllStringSubmatch(pattern, -1) {
+		result.Parameters = append(result.Parameters, ParameterObject{Name: match[1], In: "path", Required: true, Schema: &Schema{Type: "integer"}})
+	}
+	for _, param := range operation.Query {
+		result.Parameters = append(result.Parameters, ParameterObject{
+			Name: param.Name, In: "query", Description: param.Description, Required: param.Required, Schema: &Schema{Type: param.Type},
+		})
+	}
+
+	/* Request body */
+	if operation.Request != nil {
+		result.RequestBody = &Body{Required: true, Content: content(components.schemaOf(reflect.TypeOf(operation.Request)), operation.RequestTypes)}
+	}
+	for mediaType, request := range operation.Requests {
+		if result.RequestBody == nil {
+			result.RequestBody = &Body{Required: true, Content: make(map[string]MediaType)}
+		}
+		result.RequestBody.Content[mediaType] = MediaType{Schema: components.schemaOf(reflect.TypeOf(request))}
+	}
+
+	/* Successful response */
+	status := operation.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := Body{Description: http.StatusText(status)}
+	switch {
+	case operation.Response != nil:
+		success.Content = content(responseSchema(operation, operation.Response, components), operation.ResponseTypes)
+	case len(operation.ResponseTypes) > 0:
+		success.Content = content(&Schema{Type: "string", Format: "binary"}, operation.ResponseTypes)
+	}
+	for mediaType, response := range operation.Responses {
+		if success.Content == nil {
+			success.Content = make(map[string]MediaType)
+		}
+		success.Content[mediaType] = MediaType{Schema: responseSchema(operation, response, components)}
+	}
+	result.Responses[strconv.Itoa(status)] = success
+
+	/* Error responses */
+	problem := &Schema{Type: "object"}
+	if s.problem != nil {
+		problem = components.schemaOf(reflect.TypeOf(s.problem))
+	}
+	for _, code := range operation.Problems {
+		result.Responses[strconv.Itoa(code)] = Body{Description: http.StatusText(code), Content: content(problem, []string{"application/problem+json"})}
+	}
+	return result
+}
+
+// Document builds the OpenAPI 3 document of the described routes a router has. Every
+// operation requires the TOKEN header
+// Document(routes chi.Routes) -> Document
+// Args:
+//		routes: Router serving the API
+// Return:
+//		Document: OpenAPI 3 document
+
+func (s *Spec) Document(routes chi.Routes) Document {
+	components := newSchemas()
+	document := Document{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: s.title, Version: s.version},
+		Paths:   make(map[string]map[string]*PathOperation),
+		Components: Components{
+			Schemas:         components.byName,
+			SecuritySchemes: map[string]SecurityScheme{"token": {Type: "apiKey", In: "header", Name: "TOKEN"}},
+		},
+		Security: []map[string][]string{{"token": {}}},
+	}
+	for _, key := range walk(routes) {
+		operation, ok := s.operations[key]
+		if !ok {
+			continue
+		}
+		method, pattern, _ := strings.Cut(key, " ")
+		path := pathParam.ReplaceAllString(pattern, "{$1}")
+		if document.Paths[path] == nil {
+			document.Paths[path] = make(map[string]*PathOperation)
+		}
+		document.Paths[path][strings.ToLower(method)] = s.pathOperation(pattern, operation, components)
+	}
+	return document
+}
@@ -0,0 +1,117 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"proyecto/platform/web/openapi"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+type item struct {
+	ID        int               `json:"id"`
+	Name      string            `json:"name"`
+	Price     *float64          `json:"price,omitempty"`
+	Tags      []string          `json:"tags"`
+	Labels    map[string]string `json:"labels"`
+	CreatedAt time.Time         `json:"created_at"`
+	Parent    *item             `json:"parent"`
+	secret    string
+}
+
+// Tests for Spec
+func TestSpec(t *testing.T) {
+	// arrange
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+	router := chi.NewRouter()
+	router.Route("/items", func(r chi.Router) {
+		r.Get("/", handler)
+		r.Get("/{id}", handler)
+		r.Delete("/{id}", handler)
+	})
+	spec := openapi.NewSpec("Items", "1.0.0").
+		Describe("GET", "/items/", openapi.Operation{ID: "GetItems", Response: []item{}}).
+		Describe("GET", "/items/{id}", openapi.Operation{ID: "GetItem", Response: item{}, Problems: []int{http.StatusNotFound}}).
+		Describe("POST", "/items/", openapi.Operation{ID: "AddItem"})
+
+	t.Run("coverage", func(t *testing.T) {
+		// act
+		undescribed := spec.Undescribed(router)
+		unregistered := spec.Unregistered(router)
+
+		// assert
+		require.Equal(t, []string{"DELETE /items/{id}"}, undescribed)
+		require.Equal(t, []string{"POST /items/"}, unregistered)
+	})
+
+	t.Run("document", func(t *testing.T) {
+		// act
+		data, err := json.Marshal(spec.Document(router))
+
+		// assert
+		expected := `{
+			"openapi": "3.0.3",
+			"info": {"title": "Items", "version": "1.0.0"},
+			"paths": {
+				"/items/": {"get": {"operationId": "GetItems", "responses": {"200": {"description": "OK", "content": {"application/json": {"schema":
+					{"type": "object", "properties": {"data": {"type": "array", "nullable": true, "items": {"$ref": "#/components/schemas/item"}}}}}}}}}},
+				"/items/{id}": {"get": {"operationId": "GetItem",
+					"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}],
+					"responses": {
+						"200": {"description": "OK", "content": {"application/json": {"schema":
+							{"type": "object", "properties": {"data": {"$ref": "#/components/schemas/item"}}}}}},
+						"404": {"description": "Not Found", "content": {"application/problem+json": {"schema": {"type": "object"}}}}
+					}}}
+			},
+			"components": {
+				"schemas": {"item": {"type": "object", "properties": {
+					"id": {"type": "integer"},
+					"name": {"type": "string"},
+					"price": {"type": "number", "nullable": true},
+					"tags": {"type": "array", "items": {"type": "string"}, "nullable": true},
+					"labels": {"type": "object", "additionalProperties": {"type": "string"}, "nullable": true},
+					"created_at": {"type": "string", "format": "date-time"},
+					"parent": {"$ref": "#/components/schemas/item"}
+				}}},
+				"securitySchemes": {"token": {"type": "apiKey", "in": "header", "name": "TOKEN"}}
+			},
+			"security": [{"token": []}]
+		}`
+		require.NoError(t, err)
+		require.JSONEq(t, expected, string(data))
+	})
+	t.Run("alias", func(t *testing.T) {
+		// arrange
+		handler := func(w http.ResponseWriter, r *http.Request) {}
+		router := chi.NewRouter()
+		items := func(r chi.Router) {
+			r.Get("/items/", handler)
+		}
+		items(router)
+		router.Route("/v1", items)
+		router.Get("/v2/items/", handler)
+		spec := openapi.NewSpec("Items", "2.0.0").
+			Describe("GET", "/items/", openapi.Operation{
+				ID: "GetItems", Deprecated: true,
+				Response: []item{}, Responses: map[string]any{"application/json; version=2": []string{}},
+			}).
+			Alias("/v1", "V1").
+			Describe("GET", "/v2/items/", openapi.Operation{ID: "GetItemsV2"})
+
+		// act
+		document := spec.Document(router)
+
+		// assert
+		require.Empty(t, spec.Undescribed(router))
+		require.Empty(t, spec.Unregistered(router))
+		require.Equal(t, "GetItemsV1", document.Paths["/v1/items/"]["get"].OperationID)
+		require.True(t, document.Paths["/v1/items/"]["get"].Deprecated)
+		require.False(t, document.Paths["/v2/items/"]["get"].Deprecated)
+		require.Len(t, document.Paths["/items/"]["get"].Responses["200"].Content, 2)
+		require.Contains(t, document.Paths["/items/"]["get"].Responses["200"].Content, "application/json; version=2")
+		require.Len(t, document.Paths["/v1/items/"]["get"].Responses["200"].Content, 1)
+	})
+}
@@ -0,0 +1,347 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"proyecto/platform/web/request"
+	"proyecto/platform/web/response"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+/* Validation errors */
+var (
+	ErrRequestInvalid         = errors.New("request does not match the specification")
+	ErrUnsupportedContentType = errors.New("content type not described by the specification")
+	ErrResponseInvalid        = errors.New("response does not match the specification")
+)
+
+// validationProblems maps the validation errors to their problem types
+var validationProblems = response.NewProblems().
+	Register(ErrRequestInvalid, http.StatusUnprocessableEntity, "invalid_request", "Invalid request").
+	Register(ErrUnsupportedContentType, http.StatusUnsupportedMediaType, "unsupported_media_type", "Unsupported media type").
+	Register(request.ErrRequestJSONInvalid, http.StatusBadRequest, "malformed_body", "Malformed JSON body").
+	Register(ErrResponseInvalid, http.StatusInternalServerError, "invalid_response", "Invalid response")
+
+// Validator checks the requests of the described routes against the specification before
+// they reach their handler. Invalid requests are answered with a problem whose errors are
+// keyed by JSON Pointers into the request: /path/{param}, /query/{param} and /body/...
+type Validator struct {
+	spec      *Spec
+	routes    chi.Routes
+	responses bool      // Also check the responses. Meant for tests
+	document  Document  // Document the requests are checked against. Built on the first request
+	once      sync.Once // Guards document
+}
+
+// Validator creates a validator of the requests served by a router
+// Validator(routes chi.Routes, responses bool) -> *Validator
+// Args:
+//		routes:    Router serving the API
+//		responses: Also check the status, content type and body of the responses, answering
+//				   a 500 problem when they drift from the specification. Meant for tests
+// Return:
+//		*Validator: New Validator instance
+
+func (s *Spec) Validator(routes chi.Routes, responses bool) *Validator {
+	return &Validator{spec: s, routes: routes, responses: responses}
+}
+
+// operation finds the described operation serving a request
+// operation(r *http.Request) -> (*PathOperation, *chi.Context)
+
+func (v *Validator) operation(r *http.Request) (*PathOperation, *chi.Context) {
+	v.once.Do(func() { v.document = v.spec.Document(v.routes) })
+	rctx := chi.NewRouteContext()
+	if !v.routes.Match(rctx, r.Method, r.URL.Path) {
+		return nil, nil
+	}
+	/* Joined as chi.Walk does: RoutePattern trims the trailing slash of the subrouter roots */
+	path := pathParam.ReplaceAllString(strings.ReplaceAll(strings.Join(rctx.RoutePatterns, ""), "/*/", "/"), "{$1}")
+	operation := v.document.Paths[path][strings.ToLower(r.Method)]
+	return operation, rctx
+}
+
+// resolve follows the reference of a schema
+// resolve(schema *Schema) -> *Schema
+
+func (v *Validator) resolve(schema *Schema) *Schema {
+	for schema != nil && schema.Ref != "" {
+		schema = v.document.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+	}
+	if schema == nil {
+		return &Schema{}
+	}
+	return schema
+}
+
+// expected describes the values a schema accepts
+// expected(schema *Schema) -> string
+
+func expected(schema *Schema) string {
+	switch {
+	case schema.Format == "date-time":
+		return "expected an RFC 3339 moment"
+	case schema.Type == "integer" || schema.Type == "array" || schema.Type == "object":
+		return "expected an " + schema.Type
+	default:
+		return "expected a " + schema.Type
+	}
+}
+
+// validateValue checks a decoded JSON value against a schema
+// validateValue(schema *Schema, value any, pointer string, invalid request.FieldErrors)
+// Args:
+//		schema:  Schema the value must match
+//		value:   Value decoded with json.Decoder.UseNumber
+//		pointer: JSON Pointer of the value the errors are reported at
+//		invalid: Errors found, by pointer
+
+func (v *Validator) validateValue(schema *Schema, value any, pointer string, invalid request.FieldErrors) {
+	schema = v.resolve(schema)
+	if schema.Type == "" {
+		return
+	}
+	if value == nil {
+		if !schema.Nullable {
+			invalid[pointer] = "must not be null"
+		}
+		return
+	}
+
+	switch schema.Type {
+	case "integer", "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			invalid[pointer] = expected(schema)
+			return
+		}
+		if schema.Type == "integer" {
+			if f, err := number.Float64(); err != nil || f != math.Trunc(f) {
+				invalid[pointer] = expected(schema)
+			}
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			invalid[pointer] = expected(schema)
+			return
+		}
+		if schema.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, text); err != nil {
+				invalid[pointer] = expected(schema)
+			}
+		}
+		if len(schema.Enum) > 0 {
+			valid := false
+			for _, option := range schema.Enum {
+				valid = valid || option == text
+			}
+			if !valid {
+				invalid[pointer] = "expected one of " + strings.Join(schema.Enum, ", ")
+			}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			invalid[pointer] = expected(schema)
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			invalid[pointer] = expected(schema)
+			return
+		}
+		for i, item := range items {
+			v.validateValue(schema.Items, item, pointer+"/"+strconv.Itoa(i), invalid)
+		}
+	case "object":
+		members, ok := value.(map[string]any)
+		if !ok {
+			invalid[pointer] = expected(schema)
+			return
+		}
+		for name, member := range members {
+			escaped := strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+			if property, ok := schema.Properties[name]; ok {
+				v.validateValue(property, member, pointer+"/"+escaped, invalid)
+			} else if schema.AdditionalProperties != nil {
+				v.validateValue(schema.AdditionalProperties, member, pointer+"/"+escaped, invalid)
+			}
+		}
+	}
+}
+
+// validateText checks the text of a path or query param against a schema
+// validateText(schema *Schema, text string) -> (string, bool)
+
+func validateText(schema *Schema, text string) (string, bool) {
+	var err error
+	switch schema.Type {
+	case "integer":
+		_, err = strconv.Atoi(text)
+	case "number":
+		_, err = strconv.ParseFloat(text, 64)
+	case "boolean":
+		_, err = strconv.ParseBool(text)
+	}
+	if err != nil {
+		return expected(schema), false
+	}
+	return "", true
+}
+
+// isJSON checks if a media type holds JSON
+// isJSON(mediaType string) -> bool
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// validateBody checks a JSON body against the schema of its media type. A media type asking
+// for a version is checked against the schema of that version when it is described
+// validateBody(content map[string]MediaType, contentType string, body []byte, invalid request.FieldErrors) -> error
+
+func (v *Validator) validateBody(content map[string]MediaType, contentType string, body []byte, invalid request.FieldErrors) error {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrUnsupportedContentType, contentType)
+	}
+	described, ok := content[mediaType]
+	if version := params[request.VersionParam]; version != "" {
+		if versioned, found := content[mime.FormatMediaType(mediaType, map[string]string{request.VersionParam: version})]; found {
+			described, ok = versioned, true
+		}
+	}
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnsupportedContentType, mediaType)
+	}
+	if !isJSON(mediaType) {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("%w. %v", request.ErrRequestJSONInvalid, err)
+	}
+	v.validateValue(described.Schema, value, "/body", invalid)
+	return nil
+}
+
+// validateRequest checks a request against its operation. The body is read and restored
+// validateRequest(r *http.Request, operation *PathOperation, rctx *chi.Context) -> error
+
+func (v *Validator) validateRequest(r *http.Request, operation *PathOperation, rctx *chi.Context) error {
+	invalid := request.FieldErrors{}
+
+	/* Path and query params */
+	query := r.URL.Query()
+	for _, param := range operation.Parameters {
+		var text string
+		var present bool
+		switch param.In {
+		case "path":
+			text, present = rctx.URLParam(param.Name), true
+		case "query":
+			text, present = query.Get(param.Name), query.Has(param.Name)
+		}
+		if !present {
+			if param.Required {
+				invalid["/"+param.In+"/"+param.Name] = "required parameter"
+			}
+			continue
+		}
+		if message, ok := validateText(param.Schema, text); !ok {
+			invalid["/"+param.In+"/"+param.Name] = message
+		}
+	}
+
+	/* Body */
+	if operation.RequestBody != nil {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return err
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if err := v.validateBody(operation.RequestBody.Content, r.Header.Get("Content-Type"), body, invalid); err != nil {
+			return err
+		}
+	}
+
+	if len(invalid) > 0 {
+		return fmt.Errorf("%w: %w", ErrRequestInvalid, invalid)
+	}
+	return nil
+}
+
+// validateResponse checks a recorded response against its operation
+// validateResponse(operation *PathOperation, recorder *httptest.ResponseRecorder) -> error
+
+func (v *Validator) validateResponse(operation *PathOperation, recorder *httptest.ResponseRecorder) error {
+	invalid := request.FieldErrors{}
+	described, ok := operation.Responses[strconv.Itoa(recorder.Code)]
+	if !ok {
+		invalid["/status"] = fmt.Sprintf("status %d not described", recorder.Code)
+	} else if len(described.Content) > 0 {
+		contentType := recorder.Header().Get("Content-Type")
+		if err := v.validateBody(described.Content, contentType, recorder.Body.Bytes(), invalid); err != nil {
+			invalid["/headers/Content-Type"] = err.Error()
+		}
+	} else if recorder.Body.Len() > 0 {
+		invalid["/body"] = "no body described"
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("%w: %w", ErrResponseInvalid, invalid)
+	}
+	return nil
+}
+
+// Middleware validates the requests of the described routes, and their responses when the
+// validator checks them
+// Middleware(handler http.Handler) -> http.Handler
+// Args:
+//		handler: HTTP handler
+// Return:
+//		http.Handler: HTTP handler
+
+func (v *Validator) Middleware(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		operation, rctx := v.operation(r)
+		if operation == nil {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		if err := v.validateRequest(r, operation, rctx); err != nil {
+			validationProblems.Write(w, r, err)
+			return
+		}
+		if !v.responses {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		/* Hold the response until it is checked */
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, r)
+		if err := v.validateResponse(operation, recorder); err != nil {
+			validationProblems.Write(w, r, err)
+			return
+		}
+		for name, values := range recorder.Header() {
+			w.Header()[name] = values
+		}
+		w.WriteHeader(recorder.Code)
+		w.Write(recorder.Body.Bytes())
+	})
+}
@@ -0,0 +1,153 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"proyecto/platform/web/openapi"
+	"proyecto/platform/web/response"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Validator
+func TestValidator(t *testing.T) {
+	// arrange
+	spec := openapi.NewSpec("Items", "1.0.0").
+		Describe("GET", "/items/{id}", openapi.Operation{
+			ID: "GetItem", Response: item{},
+			Query: []openapi.Parameter{{Name: "full", Type: "boolean"}, {Name: "lang", Type: "string", Required: true}},
+		}).
+		Describe("POST", "/items/", openapi.Operation{
+			ID: "AddItem", Request: item{}, Requests: map[string]any{"application/json; version=2": map[string]string{}},
+			Status: http.StatusCreated, Response: item{},
+		})
+	serve := func(responses bool, handler http.HandlerFunc, req *http.Request) (*httptest.ResponseRecorder, response.Problem) {
+		router := chi.NewRouter()
+		router.Use(spec.Validator(router, responses).Middleware)
+		router.Route("/items", func(r chi.Router) {
+			r.Get("/{id}", handler)
+			r.Post("/", handler)
+			r.Delete("/{id}", handler)
+		})
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+		var problem response.Problem
+		if res.Header().Get("Content-Type") == "application/problem+json" {
+			json.Unmarshal(res.Body.Bytes(), &problem)
+		}
+		return res, problem
+	}
+	reached := func(w http.ResponseWriter, r *http.Request) {
+		response.JSON(w, http.StatusTeapot, nil)
+	}
+
+	t.Run("path and query params", func(t *testing.T) {
+		// act
+		res, problem := serve(false, reached, httptest.NewRequest("GET", "/items/x?full=maybe", nil))
+
+		// assert
+		require.Equal(t, http.StatusUnprocessableEntity, res.Code)
+		require.Equal(t, "invalid_request", problem.Code)
+		require.Equal(t, map[string]string{
+			"/path/id":    "expected an integer",
+			"/query/full": "expected a boolean",
+			"/query/lang": "required parameter",
+		}, problem.Errors)
+	})
+
+	t.Run("body", func(t *testing.T) {
+		// arrange
+		body := `{"id": 1.5, "name": null, "price": null, "tags": ["a", 2], "labels": {"k": true}, "created_at": "yesterday", "parent": {"id": "1"}, "extra": 1}`
+		req := httptest.NewRequest("POST", "/items/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		// act
+		res, problem := serve(false, reached, req)
+
+		// assert
+		require.Equal(t, http.StatusUnprocessableEntity, res.Code)
+		require.Equal(t, map[string]string{
+			"/body/id":         "expected an integer",
+			"/body/name":       "must not be null",
+			"/body/tags/1":     "expected a string",
+			"/body/labels/k":   "expected a string",
+			"/body/created_at": "expected an RFC 3339 moment",
+			"/body/parent/id":  "expected an integer",
+		}, problem.Errors)
+	})
+
+	t.Run("content type", func(t *testing.T) {
+		// arrange
+		req := httptest.NewRequest("POST", "/items/", strings.NewReader("<item/>"))
+		req.Header.Set("Content-Type", "application/xml")
+
+		// act
+		res, problem := serve(false, reached, req)
+
+		// assert
+		require.Equal(t, http.StatusUnsupportedMediaType, res.Code)
+		require.Equal(t, "unsupported_media_type", problem.Code)
+	})
+
+	t.Run("versioned content type", func(t *testing.T) {
+		// arrange
+		req := httptest.NewRequest("POST", "/items/", strings.NewReader(`{"id": "1"}`))
+		req.Header.Set("Content-Type", "application/json; version=2")
+		reqCharset := httptest.NewRequest("POST", "/items/", strings.NewReader(`{"id": "1"}`))
+		reqCharset.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+		// act
+		res, _ := serve(false, reached, req)
+		resCharset, problem := serve(false, reached, reqCharset)
+
+		// assert
+		require.Equal(t, http.StatusTeapot, res.Code)
+		require.Equal(t, http.StatusUnprocessableEntity, resCharset.Code)
+		require.Equal(t, map[string]string{"/body/id": "expected an integer"}, problem.Errors)
+	})
+
+	t.Run("valid and undescribed requests reach the handler", func(t *testing.T) {
+		// arrange
+		req := httptest.NewRequest("POST", "/items/", strings.NewReader(`{"id": 1, "name": "a", "tags": null}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		// act
+		resPost, _ := serve(false, reached, req)
+		resGet, _ := serve(false, reached, httptest.NewRequest("GET", "/items/1?lang=es&full=true", nil))
+		resDelete, _ := serve(false, reached, httptest.NewRequest("DELETE", "/items/x", nil))
+
+		// assert
+		require.Equal(t, http.StatusTeapot, resPost.Code)
+		require.Equal(t, http.StatusTeapot, resGet.Code)
+		require.Equal(t, http.StatusTeapot, resDelete.Code)
+	})
+
+	t.Run("responses", func(t *testing.T) {
+		// arrange
+		valid := func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Item", "1")
+			response.JSON(w, http.StatusOK, map[string]any{"data": map[string]any{"id": 1, "name": "a"}})
+		}
+		invalid := func(w http.ResponseWriter, r *http.Request) {
+			response.JSON(w, http.StatusOK, map[string]any{"data": map[string]any{"id": "1"}})
+		}
+
+		// act
+		resValid, _ := serve(true, valid, httptest.NewRequest("GET", "/items/1?lang=es", nil))
+		resInvalid, problem := serve(true, invalid, httptest.NewRequest("GET", "/items/1?lang=es", nil))
+		resStatus, problemStatus := serve(true, reached, httptest.NewRequest("GET", "/items/1?lang=es", nil))
+
+		// assert
+		require.Equal(t, http.StatusOK, resValid.Code)
+		require.Equal(t, "1", resValid.Header().Get("X-Item"))
+		require.JSONEq(t, `{"data": {"id": 1, "name": "a"}}`, resValid.Body.String())
+		require.Equal(t, http.StatusInternalServerError, resInvalid.Code)
+		require.Equal(t, map[string]string{"/body/data/id": "expected an integer"}, problem.Errors)
+		require.Equal(t, http.StatusInternalServerError, resStatus.Code)
+		require.Equal(t, map[string]string{"/status": "status 418 not described"}, problemStatus.Errors)
+	})
+}
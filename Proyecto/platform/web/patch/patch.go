@@ -0,0 +1,137 @@
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+/* Patch media types */
+const (
+	MediaTypeMergePatch = "application/merge-patch+json"
+	MediaTypeJSONPatch  = "application/json-patch+json"
+)
+
+/* Errors definition */
+var (
+	ErrInvalidPatch = errors.New("invalid patch")
+	ErrPathNotFound = errors.New("patch path not found")
+	ErrTestFailed   = errors.New("patch test failed")
+)
+
+// Operation is an RFC 6902 JSON Patch operation
+type Operation struct {
+	Op    string          `json:"op"`    // add, remove, replace, move, copy or test.
+	Path  string          `json:"path"`  // JSON Pointer of the target.
+	From  string          `json:"from"`  // JSON Pointer of the source of move and copy.
+	Value json.RawMessage `json:"value"` // Value of add, replace and test.
+}
+
+// MergePatch applies an RFC 7396 JSON Merge Patch to a decoded JSON document
+// MergePatch(doc any, patch any) -> any
+// Args:
+//		doc   :  Decoded JSON document. Its objects are changed in place.
+//		patch :  Decoded merge patch. Null members remove the target members.
+// Return:
+//		any :  Patched document.
+
+func MergePatch(doc any, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	docObject, ok := doc.(map[string]any)
+	if !ok {
+		docObject = make(map[string]any, len(patchObject))
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(docObject, key)
+		} else {
+			docObject[key] = MergePatch(docObject[key], value)
+		}
+	}
+	return docObject
+}
+
+// operationValue decodes the value of an operation which requires one
+// operationValue(operation Operation) -> (any, error)
+
+func operationValue(operation Operation) (any, error) {
+	if len(operation.Value) == 0 {
+		return nil, fmt.Errorf("%w: %s %s has no value", ErrInvalidPatch, operation.Op, operation.Path)
+	}
+	var value any
+	if err := json.Unmarshal(operation.Value, &value); err != nil {
+		return nil, fmt.Errorf("%w: %s %s: %v", ErrInvalidPatch, operation.Op, operation.Path, err)
+	}
+	return value, nil
+}
+
+// JSONPatch applies an RFC 6902 JSON Patch to a decoded JSON document. The operations are
+// applied in order and the first failing one aborts the patch
+// JSONPatch(doc any, operations []Operation) -> (any, error)
+// Args:
+//		doc        :  Decoded JSON document. It may be changed even if the patch fails.
+//		operations :  Operations to apply.
+// Return:
+//		any   :  Patched document.
+//		error :  Error raised during the execution (if exists).
+
+func JSONPatch(doc any, operations []Operation) (any, error) {
+	for _, operation := range operations {
+		path, err := parsePointer(operation.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		switch operation.Op {
+		case "add", "replace":
+			var value any
+			if value, err = operationValue(operation); err != nil {
+				return nil, err
+			}
+			doc, err = set(doc, path, value, operation.Op == "replace")
+		case "remove":
+			doc, err = remove(doc, path)
+		case "test":
+			value, err := operationValue(operation)
+			if err != nil {
+				return nil, err
+			}
+			current, err := get(doc, path)
+			if err != nil {
+				return nil, err
+			}
+			if !reflect.DeepEqual(current, value) {
+				return nil, fmt.Errorf("%w: %s is %s", ErrTestFailed, operation.Path, string(operation.Value))
+			}
+		case "move", "copy":
+			var from []string
+			var value any
+			if from, err = parsePointer(operation.From); err != nil {
+				return nil, err
+			}
+			if value, err = get(doc, from); err != nil {
+				return nil, err
+			}
+			if operation.Op == "move" {
+				if doc, err = remove(doc, from); err != nil {
+					return nil, err
+				}
+			} else {
+				/* The copy must not share its containers with the source */
+				encoded, _ := json.Marshal(value)
+				json.Unmarshal(encoded, &value)
+			}
+			doc, err = set(doc, path, value, false)
+		default:
+			return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidPatch, operation.Op)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
@@ -0,0 +1,108 @@
+package patch_test
+
+import (
+	"encoding/json"
+	"proyecto/platform/web/patch"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// decode decodes a JSON text
+func decode(t *testing.T, text string) any {
+	var value any
+	require.NoError(t, json.Unmarshal([]byte(text), &value))
+	return value
+}
+
+// Tests for MergePatch function
+func TestMergePatch(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// arrange
+		doc := decode(t, `{"name": "Yerba", "price": 10, "tags": ["a", "b"], "attributes": {"brand": "X", "pack": "1kg"}}`)
+		merge := decode(t, `{"price": 12.5, "tags": ["c"], "attributes": {"pack": null, "flavor": "mint"}}`)
+
+		// act
+		result := patch.MergePatch(doc, merge)
+
+		// assert
+		expected := decode(t, `{"name": "Yerba", "price": 12.5, "tags": ["c"], "attributes": {"brand": "X", "flavor": "mint"}}`)
+		require.Equal(t, expected, result)
+	})
+
+	t.Run("non object patch replaces the document", func(t *testing.T) {
+		// act
+		result := patch.MergePatch(decode(t, `{"a": 1}`), decode(t, `["x"]`))
+
+		// assert
+		require.Equal(t, decode(t, `["x"]`), result)
+	})
+}
+
+// Tests for JSONPatch function
+func TestJSONPatch(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// arrange
+		doc := decode(t, `{"name": "Yerba", "price": 10, "tags": ["a", "b"], "attributes": {"brand": "X"}}`)
+		var operations []patch.Operation
+		require.NoError(t, json.Unmarshal([]byte(`[
+			{"op": "test", "path": "/price", "value": 10},
+			{"op": "replace", "path": "/price", "value": 12.5},
+			{"op": "add", "path": "/tags/1", "value": "z"},
+			{"op": "add", "path": "/tags/-", "value": "last"},
+			{"op": "remove", "path": "/tags/0"},
+			{"op": "add", "path": "/attributes/a~1b", "value": "slash"},
+			{"op": "copy", "from": "/name", "path": "/attributes/name"},
+			{"op": "move", "from": "/attributes/brand", "path": "/brand"}
+		]`), &operations))
+
+		// act
+		result, err := patch.JSONPatch(doc, operations)
+
+		// assert
+		expected := decode(t, `{"name": "Yerba", "price": 12.5, "tags": ["z", "b", "last"], "brand": "X",
+			"attributes": {"a/b": "slash", "name": "Yerba"}}`)
+		require.NoError(t, err)
+		require.Equal(t, expected, result)
+	})
+
+	t.Run("test failed", func(t *testing.T) {
+		// act
+		_, err := patch.JSONPatch(decode(t, `{"price": 10}`), []patch.Operation{
+			{Op: "test", Path: "/price", Value: json.RawMessage(`11`)},
+		})
+
+		// assert
+		require.ErrorIs(t, err, patch.ErrTestFailed)
+	})
+
+	t.Run("replace of a missing member", func(t *testing.T) {
+		// act
+		_, err := patch.JSONPatch(decode(t, `{"price": 10}`), []patch.Operation{
+			{Op: "replace", Path: "/name", Value: json.RawMessage(`"Yerba"`)},
+		})
+
+		// assert
+		require.ErrorIs(t, err, patch.ErrPathNotFound)
+	})
+
+	t.Run("remove out of range", func(t *testing.T) {
+		// act
+		_, err := patch.JSONPatch(decode(t, `{"tags": ["a"]}`), []patch.Operation{{Op: "remove", Path: "/tags/1"}})
+
+		// assert
+		require.ErrorIs(t, err, patch.ErrPathNotFound)
+	})
+
+	t.Run("invalid operations", func(t *testing.T) {
+		// act
+		_, errOp := patch.JSONPatch(decode(t, `{}`), []patch.Operation{{Op: "merge", Path: "/a"}})
+		_, errValue := patch.JSONPatch(decode(t, `{}`), []patch.Operation{{Op: "add", Path: "/a"}})
+		_, errPointer := patch.JSONPatch(decode(t, `{}`), []patch.Operation{{Op: "remove", Path: "a"}})
+
+		// assert
+		require.ErrorIs(t, errOp, patch.ErrInvalidPatch)
+		require.ErrorIs(t, errValue, patch.ErrInvalidPatch)
+		require.ErrorIs(t, errPointer, patch.ErrInvalidPatch)
+	})
+}
@@ -0,0 +1,182 @@
+package patch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parsePointer splits an RFC 6901 JSON Pointer into its unescaped reference tokens
+// parsePointer(pointer string) -> ([]string, error)
+// Args:
+//		pointer :  JSON Pointer. The empty pointer references the whole document.
+// Return:
+//		[]string :  Reference tokens.
+//		error    :  Error raised during the execution (if exists).
+
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: pointer %q must start with /", ErrInvalidPatch, pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// arrayIndex parses the reference token of an array element
+// arrayIndex(token string, length int, appendable bool) -> (int, error)
+// Args:
+//		token      :  Reference token.
+//		length     :  Length of the array.
+//		appendable :  True if the index right after the last element ("-" or length) is valid.
+// Return:
+//		int   :  Index of the element.
+//		error :  Error raised during the execution (if exists).
+
+func arrayIndex(token string, length int, appendable bool) (int, error) {
+	if token == "-" && appendable {
+		return length, nil
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf("%w: %q is not an array index", ErrPathNotFound, token)
+	}
+	if index > length || (index == length && !appendable) {
+		return 0, fmt.Errorf("%w: index %d out of range", ErrPathNotFound, index)
+	}
+	return index, nil
+}
+
+// get returns the value a pointer references
+// get(doc any, tokens []string) -> (any, error)
+
+func get(doc any, tokens []string) (any, error) {
+	for _, token := range tokens {
+		switch node := doc.(type) {
+		case map[string]any:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: member %q not exists", ErrPathNotFound, token)
+			}
+			doc = value
+		case []any:
+			index, err := arrayIndex(token, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			doc = node[index]
+		default:
+			return nil, fmt.Errorf("%w: %q is not inside an object or array", ErrPathNotFound, token)
+		}
+	}
+	return doc, nil
+}
+
+// set adds or replaces the value a pointer references
+// set(doc any, tokens []string, value any, replace bool) -> (any, error)
+// Args:
+//		doc     :  Document to change.
+//		tokens  :  Reference tokens of the target.
+//		value   :  Value to set.
+//		replace :  True if the target must exist, false to insert it (into arrays, shifting the rest).
+// Return:
+//		any   :  Changed document.
+//		error :  Error raised during the execution (if exists).
+
+func set(doc any, tokens []string, value any, replace bool) (any, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	token, rest := tokens[0], tokens[1:]
+	switch node := doc.(type) {
+	case map[string]any:
+		current, ok := node[token]
+		if len(rest) == 0 {
+			if replace && !ok {
+				return nil, fmt.Errorf("%w: member %q not exists", ErrPathNotFound, token)
+			}
+			node[token] = value
+			return node, nil
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: member %q not exists", ErrPathNotFound, token)
+		}
+		child, err := set(current, rest, value, replace)
+		if err != nil {
+			return nil, err
+		}
+		node[token] = child
+		return node, nil
+	case []any:
+		index, err := arrayIndex(token, len(node), len(rest) == 0 && !replace)
+		if err != nil {
+			return nil, err
+		}
+		if len(rest) == 0 && !replace {
+			node = append(node, nil)
+			copy(node[index+1:], node[index:])
+			node[index] = value
+			return node, nil
+		}
+		if len(rest) == 0 {
+			node[index] = value
+			return node, nil
+		}
+		child, err := set(node[index], rest, value, replace)
+		if err != nil {
+			return nil, err
+		}
+		node[index] = child
+		return node, nil
+	default:
+		return nil, fmt.Errorf("%w: %q is not inside an object or array", ErrPathNotFound, token)
+	}
+}
+
+// remove removes the value a pointer references
+// remove(doc any, tokens []string) -> (any, error)
+
+func remove(doc any, tokens []string) (any, error) {
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("%w: the whole document can't be removed", ErrInvalidPatch)
+	}
+	token, rest := tokens[0], tokens[1:]
+	switch node := doc.(type) {
+	case map[string]any:
+		current, ok := node[token]
+		if !ok {
+			return nil, fmt.Errorf("%w: member %q not exists", ErrPathNotFound, token)
+		}
+		if len(rest) == 0 {
+			delete(node, token)
+			return node, nil
+		}
+		child, err := remove(current, rest)
+		if err != nil {
+			return nil, err
+		}
+		node[token] = child
+		return node, nil
+	case []any:
+		index, err := arrayIndex(token, len(node), false)
+		if err != nil {
+			return nil, err
+		}
+		if len(rest) == 0 {
+			return append(node[:index], node[index+1:]...), nil
+		}
+		child, err := remove(node[index], rest)
+		if err != nil {
+			return nil, err
+		}
+		node[index] = child
+		return node, nil
+	default:
+		return nil, fmt.Errorf("%w: %q is not inside an object or array", ErrPathNotFound, token)
+	}
+}
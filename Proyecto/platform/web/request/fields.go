@@ -0,0 +1,33 @@
+package request
+
+import (
+	"sort"
+	"strings"
+)
+
+// FieldErrors holds one message per invalid field of a request, keyed by the JSON Pointer
+// of the field
+type FieldErrors map[string]string
+
+// Error returns the messages ordered by field
+// Error() -> string
+
+func (f FieldErrors) Error() string {
+	fields := make([]string, 0, len(f))
+	for field := range f {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	messages := make([]string, 0, len(fields))
+	for _, field := range fields {
+		messages = append(messages, field+": "+f[field])
+	}
+	return ErrRequestFieldsInvalid.Error() + ". " + strings.Join(messages, "; ")
+}
+
+// Unwrap makes the field errors match ErrRequestFieldsInvalid
+// Unwrap() -> error
+
+func (f FieldErrors) Unwrap() error {
+	return ErrRequestFieldsInvalid
+}
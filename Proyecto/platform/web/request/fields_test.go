@@ -0,0 +1,26 @@
+package request_test
+
+import (
+	"errors"
+	"proyecto/platform/web/request"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for FieldErrors
+func TestFieldErrors(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// arrange
+		var err error = request.FieldErrors{"/price": "expected a number", "/name": "expected a string"}
+
+		// act
+		var fields request.FieldErrors
+		found := errors.As(err, &fields)
+
+		// assert
+		require.True(t, found)
+		require.ErrorIs(t, err, request.ErrRequestFieldsInvalid)
+		require.Equal(t, "request fields invalid. /name: expected a string; /price: expected a number", err.Error())
+	})
+}
@@ -0,0 +1,33 @@
+package request
+
+import (
+	"context"
+	"net/http"
+)
+
+/* Context key of the request ID */
+type idKey struct{}
+
+// WithID returns a copy of the request carrying an ID
+// WithID(r *http.Request, id string) -> *http.Request
+// Args:
+//		r  :  HTTP request.
+//		id :  Request ID.
+// Return:
+//		*http.Request :  Request carrying the ID.
+
+func WithID(r *http.Request, id string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), idKey{}, id))
+}
+
+// ID returns the ID of a request
+// ID(r *http.Request) -> string
+// Args:
+//		r :  HTTP request.
+// Return:
+//		string :  Request ID (empty if the request has none).
+
+func ID(r *http.Request) string {
+	id, _ := r.Context().Value(idKey{}).(string)
+	return id
+}
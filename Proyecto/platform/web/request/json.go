@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
 )
 
@@ -11,6 +13,7 @@ import (
 var (
 	ErrRequestContentTypeNotJSON = errors.New("request content type is not application/json")
 	ErrRequestJSONInvalid        = errors.New("request json invalid")
+	ErrRequestFieldsInvalid      = errors.New("request fields invalid")
 )
 
 // JSON decodes the request body into the given pointer.
@@ -23,15 +26,28 @@ var (
 
 func JSON(r *http.Request, ptr any) (err error) {
 	// Checks if the request content type is application/json
-	if r.Header.Get("Content-Type") != "application/json" {
+	if !isJSON(r.Header.Get("Content-Type")) {
 		err = ErrRequestContentTypeNotJSON
 		return
 	}
 	// Decodes the request body into data structure
-	err = json.NewDecoder(r.Body).Decode(ptr)
-	if err != nil {
-		err = fmt.Errorf("%w. %v", ErrRequestJSONInvalid, err)
-		return
+	return decodeJSON(r.Body, ptr)
+}
+
+// isJSON checks if a content type is application/json. Parameters, as the API version, are allowed
+// isJSON(contentType string) -> bool
+
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/json"
+}
+
+// decodeJSON decodes a JSON value into the given pointer.
+// decodeJSON(reader io.Reader, ptr any) -> error
+
+func decodeJSON(reader io.Reader, ptr any) error {
+	if err := json.NewDecoder(reader).Decode(ptr); err != nil {
+		return fmt.Errorf("%w. %v", ErrRequestJSONInvalid, err)
 	}
-	return
+	return nil
 }
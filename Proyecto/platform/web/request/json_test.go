@@ -32,6 +32,25 @@ func TestRequestJSON(t *testing.T) {
 		require.Equal(t, expectedSchema, inputSchema)
 	})
 
+	t.Run("success - media type parameters", func(t *testing.T) {
+		// arrange
+		type schema struct {
+			Name string `json:"name"`
+		}
+
+		// act
+		inputSchema := schema{}
+		inputRequest := http.Request{
+			Header: http.Header{"Content-Type": []string{"application/json; version=2"}},
+			Body:   io.NopCloser(strings.NewReader(`{"name":"test"}`)),
+		}
+		err := request.JSON(&inputRequest, &inputSchema)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, schema{Name: "test"}, inputSchema)
+	})
+
 	t.Run("error - content-type", func(t *testing.T) {
 		// arrange
 		type schema struct {
@@ -74,3 +93,53 @@ func TestRequestJSON(t *testing.T) {
 		require.Equal(t, expectedSchema, inputSchema)
 	})
 }
+
+// Tests for JSONStrict function
+func TestRequestJSONStrict(t *testing.T) {
+	type schema struct {
+		Name  string  `json:"name"`
+		Price float64 `json:"price"`
+	}
+	newRequest := func(body string) *http.Request {
+		return &http.Request{
+			Header: http.Header{"Content-Type": []string{"application/json"}},
+			Body:   io.NopCloser(strings.NewReader(body)),
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		// act
+		inputSchema := schema{}
+		err := request.JSONStrict(newRequest(`{"name":"test","price":10} `), &inputSchema)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, schema{Name: "test", Price: 10}, inputSchema)
+	})
+
+	t.Run("error - unknown field", func(t *testing.T) {
+		// act
+		err := request.JSONStrict(newRequest(`{"name":"test","color":"red"}`), &schema{})
+
+		// assert
+		require.ErrorIs(t, err, request.ErrRequestFieldsInvalid)
+		require.Equal(t, request.FieldErrors{"/color": "unexpected field"}, err)
+	})
+
+	t.Run("error - wrong type", func(t *testing.T) {
+		// act
+		err := request.JSONStrict(newRequest(`{"name":"test","price":"10"}`), &schema{})
+
+		// assert
+		require.Equal(t, request.FieldErrors{"/price": "expected a number"}, err)
+	})
+
+	t.Run("error - trailing data", func(t *testing.T) {
+		// act
+		err := request.JSONStrict(newRequest(`{"name":"test"}{"name":"other"}`), &schema{})
+
+		// assert
+		require.ErrorIs(t, err, request.ErrRequestJSONInvalid)
+		require.EqualError(t, err, "request json invalid. data after the JSON value")
+	})
+}
@@ -0,0 +1,91 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// expectedType describes the JSON value a Go type is decoded from
+// expectedType(t reflect.Type) -> string
+// Args:
+//		t :  Go type of the field.
+// Return:
+//		string :  Description of the expected JSON value.
+
+func expectedType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return "an RFC 3339 moment"
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
+
+// JSONStrict decodes the request body into the given pointer rejecting unknown fields,
+// values of the wrong type and any data after the JSON value.
+// JSONStrict(r *http.Request, ptr any) -> (err :error)
+// Args:
+//		r    :	HTTP request to decode.
+//		ptr  :  Target data structure to decode into.
+// Return:
+//		err  :  Error raised during the execution (if exists). Unknown and mistyped fields
+//				are reported as FieldErrors.
+
+func JSONStrict(r *http.Request, ptr any) (err error) {
+	// Checks if the request content type is application/json
+	if !isJSON(r.Header.Get("Content-Type")) {
+		return ErrRequestContentTypeNotJSON
+	}
+
+	// Decodes the request body into data structure
+	return decodeJSONStrict(r.Body, ptr)
+}
+
+// decodeJSONStrict decodes a JSON value into the given pointer rejecting unknown fields,
+// values of the wrong type and any data after the value.
+// decodeJSONStrict(reader io.Reader, ptr any) -> (err :error)
+
+func decodeJSONStrict(reader io.Reader, ptr any) (err error) {
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(ptr); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field != "" {
+			return FieldErrors{"/" + strings.ReplaceAll(typeErr.Field, ".", "/"): "expected " + expectedType(typeErr.Type)}
+		}
+		if field, found := strings.CutPrefix(err.Error(), "json: unknown field "); found {
+			if name, unquoteErr := strconv.Unquote(field); unquoteErr == nil {
+				return FieldErrors{"/" + name: "unexpected field"}
+			}
+		}
+		return fmt.Errorf("%w. %v", ErrRequestJSONInvalid, err)
+	}
+
+	// Checks nothing follows the JSON value
+	if _, err = decoder.Token(); err != io.EOF {
+		return fmt.Errorf("%w. data after the JSON value", ErrRequestJSONInvalid)
+	}
+	return nil
+}
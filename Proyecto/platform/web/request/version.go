@@ -0,0 +1,73 @@
+package request
+
+import (
+	"context"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// VersionParam is the media type parameter the API version is asked with (application/json; version=2)
+const VersionParam = "version"
+
+/* Context key of the API version */
+type versionKey struct{}
+
+// WithVersion returns a copy of the request carrying the API version it is served with
+// WithVersion(r *http.Request, version string) -> *http.Request
+// Args:
+//		r       :  HTTP request.
+//		version :  API version.
+// Return:
+//		*http.Request :  Request carrying the version.
+
+func WithVersion(r *http.Request, version string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), versionKey{}, version))
+}
+
+// Version returns the API version a request is served with
+// Version(r *http.Request) -> string
+// Args:
+//		r :  HTTP request.
+// Return:
+//		string :  API version (empty if none was picked).
+
+func Version(r *http.Request) string {
+	version, _ := r.Context().Value(versionKey{}).(string)
+	return version
+}
+
+// AcceptVersion returns the API version asked for in the Accept header of a request, taken
+// from the VersionParam parameter of the first media range having it
+// AcceptVersion(r *http.Request) -> string
+// Args:
+//		r :  HTTP request.
+// Return:
+//		string :  API version asked for (empty if none).
+
+func AcceptVersion(r *http.Request) string {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		if _, params, err := mime.ParseMediaType(part); err == nil && params[VersionParam] != "" {
+			return params[VersionParam]
+		}
+	}
+	return ""
+}
+
+// ContentVersion returns the API version the body of a request is written in, taken from the
+// VersionParam parameter of its Content-Type header
+// ContentVersion(r *http.Request) -> string
+// Args:
+//		r :  HTTP request.
+// Return:
+//		string :  API version of the body (empty if none).
+
+func ContentVersion(r *http.Request) string {
+	if _, params, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err == nil {
+		return params[VersionParam]
+	}
+	return ""
+}
@@ -0,0 +1,78 @@
+package request_test
+
+import (
+	"net/http/httptest"
+	"proyecto/platform/web/request"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for AcceptVersion function
+func TestAcceptVersion(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// arrange
+		req := httptest.NewRequest("GET", "/products", nil)
+		req.Header.Set("Accept", "application/xml;q=0.5, application/json; version=2")
+
+		// act
+		version := request.AcceptVersion(req)
+
+		// assert
+		require.Equal(t, "2", version)
+	})
+
+	t.Run("without version", func(t *testing.T) {
+		// arrange
+		req := httptest.NewRequest("GET", "/products", nil)
+		req.Header.Set("Accept", "application/json, */*;q=0.1")
+
+		// act
+		version := request.AcceptVersion(req)
+
+		// assert
+		require.Empty(t, version)
+	})
+}
+
+// Tests for ContentVersion function
+func TestContentVersion(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// arrange
+		req := httptest.NewRequest("POST", "/products", nil)
+		req.Header.Set("Content-Type", "application/json; version=2")
+
+		// act
+		version := request.ContentVersion(req)
+
+		// assert
+		require.Equal(t, "2", version)
+	})
+
+	t.Run("without version", func(t *testing.T) {
+		// arrange
+		req := httptest.NewRequest("POST", "/products", nil)
+		req.Header.Set("Content-Type", "application/json")
+
+		// act
+		version := request.ContentVersion(req)
+
+		// assert
+		require.Empty(t, version)
+	})
+}
+
+// Tests for WithVersion and Version functions
+func TestVersion(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// arrange
+		req := httptest.NewRequest("GET", "/products", nil)
+
+		// act
+		versioned := request.WithVersion(req, "1")
+
+		// assert
+		require.Equal(t, "1", request.Version(versioned))
+		require.Empty(t, request.Version(req))
+	})
+}
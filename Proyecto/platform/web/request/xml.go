@@ -0,0 +1,282 @@
+package request
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+/* Errors definition */
+var (
+	ErrRequestContentTypeUnsupported = errors.New("request content type is not supported")
+	ErrRequestXMLInvalid             = errors.New("request xml invalid")
+)
+
+/* Element of an XML body */
+type xmlNode struct {
+	name     string
+	key      string // Key attribute, naming the entries whose name can't be an element name.
+	text     string
+	children []*xmlNode
+}
+
+// parseXML reads the root element of an XML document
+// parseXML(reader io.Reader) -> (*xmlNode, error)
+
+func parseXML(reader io.Reader) (*xmlNode, error) {
+	decoder := xml.NewDecoder(reader)
+	var stack []*xmlNode
+	var root *xmlNode
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%w. %v", ErrRequestXMLInvalid, err)
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			if root != nil && len(stack) == 0 {
+				return nil, fmt.Errorf("%w. data after the root element", ErrRequestXMLInvalid)
+			}
+			node := &xmlNode{name: token.Name.Local}
+			for _, attr := range token.Attr {
+				if attr.Name.Local == "key" {
+					node.key = attr.Value
+				}
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			} else {
+				root = node
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(token)
+			} else if len(bytes.TrimSpace(token)) > 0 {
+				return nil, fmt.Errorf("%w. text outside the root element", ErrRequestXMLInvalid)
+			}
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("%w. missing root element", ErrRequestXMLInvalid)
+	}
+	return root, nil
+}
+
+// jsonFields maps the JSON names of the fields of a struct to their types
+// jsonFields(t reflect.Type) -> map[string]reflect.Type
+
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// writeJSON writes an element as the JSON value a target type is decoded from. Scalars which
+// don't fit the type are written as strings, so decoding them reports the mistyped field
+// writeJSON(buffer *bytes.Buffer, node *xmlNode, t reflect.Type)
+// Args:
+//		buffer :  Buffer the JSON is written to.
+//		node   :  Element to convert.
+//		t      :  Type the value is decoded into. Nil when unknown.
+
+func writeJSON(buffer *bytes.Buffer, node *xmlNode, t reflect.Type) {
+	text := strings.TrimSpace(node.text)
+	if t != nil && t.Kind() == reflect.Pointer {
+		if len(node.children) == 0 && text == "" {
+			buffer.WriteString("null")
+			return
+		}
+		t = t.Elem()
+	}
+	writeString := func(s string) {
+		data, _ := json.Marshal(s)
+		buffer.Write(data)
+	}
+
+	switch {
+	case t == nil || t.Kind() == reflect.Interface:
+		if len(node.children) == 0 {
+			writeString(node.text)
+			return
+		}
+		writeObject(buffer, node, func(string) reflect.Type { return nil })
+	case t == reflect.TypeOf(time.Time{}):
+		writeString(text)
+	case t.Kind() == reflect.Struct:
+		fields := jsonFields(t)
+		writeObject(buffer, node, func(name string) reflect.Type { return fields[name] })
+	case t.Kind() == reflect.Map:
+		writeObject(buffer, node, func(string) reflect.Type { return t.Elem() })
+	case (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() != reflect.Uint8:
+		buffer.WriteByte('[')
+		for i, child := range node.children {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			writeJSON(buffer, child, t.Elem())
+		}
+		buffer.WriteByte(']')
+	case t.Kind() == reflect.Bool:
+		if text == "true" || text == "false" {
+			buffer.WriteString(text)
+			return
+		}
+		writeString(text)
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Float64:
+		if text != "" && (text[0] == '-' || (text[0] >= '0' && text[0] <= '9')) && json.Valid([]byte(text)) {
+			buffer.WriteString(text)
+			return
+		}
+		writeString(text)
+	default:
+		writeString(node.text)
+	}
+}
+
+// writeObject writes the children of an element as the members of a JSON object
+// writeObject(buffer *bytes.Buffer, node *xmlNode, typeOf func(name string) reflect.Type)
+
+func writeObject(buffer *bytes.Buffer, node *xmlNode, typeOf func(name string) reflect.Type) {
+	buffer.WriteByte('{')
+	for i, child := range node.children {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		name := child.name
+		if name == "entry" && child.key != "" {
+			name = child.key
+		}
+		data, _ := json.Marshal(name)
+		buffer.Write(data)
+		buffer.WriteByte(':')
+		writeJSON(buffer, child, typeOf(name))
+	}
+	buffer.WriteByte('}')
+}
+
+// xmlToJSON converts an XML body to the JSON body of the same shape: child elements are
+// members named after them, item elements are the items of arrays and the text of the leaves
+// is typed after the target
+// xmlToJSON(r *http.Request, ptr any) -> ([]byte, error)
+
+func xmlToJSON(r *http.Request, ptr any) ([]byte, error) {
+	root, err := parseXML(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	writeJSON(&buffer, root, reflect.TypeOf(ptr).Elem())
+	return buffer.Bytes(), nil
+}
+
+// isXML checks if a content type is an XML media type
+// isXML(contentType string) -> bool
+
+func isXML(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/xml" || mediaType == "text/xml")
+}
+
+// XML decodes an XML request body into the given pointer, reading it as the JSON body of the
+// same shape would be read.
+// XML(r *http.Request, ptr any) -> (err :error)
+// Args:
+//		r    :	HTTP request to decode.
+//		ptr  :  Target data structure to decode into.
+// Return:
+//		err  :  Error raised during the execution (if exists).
+
+func XML(r *http.Request, ptr any) (err error) {
+	if !isXML(r.Header.Get("Content-Type")) {
+		return ErrRequestContentTypeUnsupported
+	}
+	data, err := xmlToJSON(r, ptr)
+	if err != nil {
+		return err
+	}
+	return decodeJSON(bytes.NewReader(data), ptr)
+}
+
+// XMLStrict decodes an XML request body into the given pointer rejecting unknown and
+// mistyped fields, as JSONStrict does.
+// XMLStrict(r *http.Request, ptr any) -> (err :error)
+// Args:
+//		r    :	HTTP request to decode.
+//		ptr  :  Target data structure to decode into.
+// Return:
+//		err  :  Error raised during the execution (if exists).
+
+func XMLStrict(r *http.Request, ptr any) (err error) {
+	if !isXML(r.Header.Get("Content-Type")) {
+		return ErrRequestContentTypeUnsupported
+	}
+	data, err := xmlToJSON(r, ptr)
+	if err != nil {
+		return err
+	}
+	return decodeJSONStrict(bytes.NewReader(data), ptr)
+}
+
+// Body decodes a JSON or XML request body, following its content type, into the given pointer.
+// Body(r *http.Request, ptr any) -> (err :error)
+// Args:
+//		r    :	HTTP request to decode.
+//		ptr  :  Target data structure to decode into.
+// Return:
+//		err  :  Error raised during the execution (if exists).
+
+func Body(r *http.Request, ptr any) (err error) {
+	if isXML(r.Header.Get("Content-Type")) {
+		return XML(r, ptr)
+	}
+	if err = JSON(r, ptr); errors.Is(err, ErrRequestContentTypeNotJSON) {
+		return fmt.Errorf("%w. expected application/json or application/xml", ErrRequestContentTypeUnsupported)
+	}
+	return err
+}
+
+// BodyStrict decodes a JSON or XML request body, following its content type, into the given
+// pointer rejecting unknown and mistyped fields.
+// BodyStrict(r *http.Request, ptr any) -> (err :error)
+// Args:
+//		r    :	HTTP request to decode.
+//		ptr  :  Target data structure to decode into.
+// Return:
+//		err  :  Error raised during the execution (if exists).
+
+func BodyStrict(r *http.Request, ptr any) (err error) {
+	if isXML(r.Header.Get("Content-Type")) {
+		return XMLStrict(r, ptr)
+	}
+	if err = JSONStrict(r, ptr); errors.Is(err, ErrRequestContentTypeNotJSON) {
+		return fmt.Errorf("%w. expected application/json or application/xml", ErrRequestContentTypeUnsupported)
+	}
+	return err
+}
@@ -0,0 +1,100 @@
+package request_test
+
+import (
+	"io"
+	"net/http"
+	"proyecto/platform/web/request"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for XML function
+func TestRequestXML(t *testing.T) {
+	type schema struct {
+		Name       string            `json:"name"`
+		Code       string            `json:"code"`
+		Quantity   int               `json:"quantity"`
+		Price      *float64          `json:"price"`
+		Published  bool              `json:"published"`
+		PublishAt  *time.Time        `json:"publish_at"`
+		Tags       []string          `json:"tags"`
+		Attributes map[string]string `json:"attributes"`
+	}
+
+	t.Run("success", func(t *testing.T) {
+		// arrange
+		body := `<?xml version="1.0"?>
+		<product>
+			<name>a &amp; b</name>
+			<code>0042</code>
+			<quantity> 3 </quantity>
+			<price>1.5</price>
+			<published>true</published>
+			<publish_at/>
+			<tags><item>x</item><item>y</item></tags>
+			<attributes><entry key="pack size">6</entry><flavor>lime</flavor></attributes>
+		</product>`
+
+		// act
+		inputSchema := schema{}
+		inputRequest := http.Request{
+			Header: http.Header{"Content-Type": []string{"application/xml; charset=utf-8"}},
+			Body:   io.NopCloser(strings.NewReader(body)),
+		}
+		err := request.XML(&inputRequest, &inputSchema)
+
+		// assert
+		price := 1.5
+		expectedSchema := schema{Name: "a & b", Code: "0042", Quantity: 3, Price: &price, Published: true,
+			Tags: []string{"x", "y"}, Attributes: map[string]string{"pack size": "6", "flavor": "lime"}}
+		require.NoError(t, err)
+		require.Equal(t, expectedSchema, inputSchema)
+	})
+
+	t.Run("error - strict fields", func(t *testing.T) {
+		// arrange
+		body := `<product><quantity>three</quantity></product>`
+
+		// act
+		inputSchema := schema{}
+		inputRequest := http.Request{
+			Header: http.Header{"Content-Type": []string{"text/xml"}},
+			Body:   io.NopCloser(strings.NewReader(body)),
+		}
+		err := request.XMLStrict(&inputRequest, &inputSchema)
+
+		// assert
+		require.Equal(t, request.FieldErrors{"/quantity": "expected an integer"}, err)
+	})
+
+	t.Run("error - malformed", func(t *testing.T) {
+		// arrange
+		body := `<product><name>a</product>`
+
+		// act
+		inputSchema := schema{}
+		inputRequest := http.Request{
+			Header: http.Header{"Content-Type": []string{"application/xml"}},
+			Body:   io.NopCloser(strings.NewReader(body)),
+		}
+		err := request.XML(&inputRequest, &inputSchema)
+
+		// assert
+		require.ErrorIs(t, err, request.ErrRequestXMLInvalid)
+	})
+
+	t.Run("error - content-type", func(t *testing.T) {
+		// act
+		inputRequest := http.Request{
+			Header: http.Header{"Content-Type": []string{"text/plain"}},
+			Body:   io.NopCloser(strings.NewReader(`name`)),
+		}
+		err := request.Body(&inputRequest, &schema{})
+
+		// assert
+		require.ErrorIs(t, err, request.ErrRequestContentTypeUnsupported)
+	})
+}
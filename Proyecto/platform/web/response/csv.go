@@ -0,0 +1,107 @@
+package response
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/http"
+	"strings"
+)
+
+// flatten collects the cells of a row. Nested objects are flattened with dotted column names
+// and arrays of scalars are joined with semicolons
+// flatten(prefix string, v value, row map[string]string, columns *[]string, seen map[string]bool)
+
+func flatten(prefix string, v value, row map[string]string, columns *[]string, seen map[string]bool) {
+	if v.kind == kindObject {
+		for _, m := range v.members {
+			name := m.name
+			if prefix != "" {
+				name = prefix + "." + m.name
+			}
+			flatten(name, m.value, row, columns, seen)
+		}
+		return
+	}
+	if prefix == "" {
+		prefix = "value"
+	}
+	if !seen[prefix] {
+		seen[prefix] = true
+		*columns = append(*columns, prefix)
+	}
+	switch v.kind {
+	case kindArray:
+		texts := make([]string, 0, len(v.items))
+		for _, item := range v.items {
+			texts = append(texts, item.text)
+		}
+		row[prefix] = strings.Join(texts, ";")
+	default:
+		row[prefix] = v.text
+	}
+}
+
+// CSV writes a CSV response to the client. The data member of an envelope is rendered when
+// present, one row per item of an array (or a single row for an object). The header holds the
+// columns in the order they are first seen.
+// CSV(w http.ResponseWriter, code int, body any)
+// Args:
+//		w    :  HTTP response writer.
+//		code :  HTTP status code.
+//		body :  Response body.
+// Return:
+//		none
+
+func CSV(w http.ResponseWriter, code int, body any) {
+	/* Checks empty body */
+	if body == nil {
+		w.WriteHeader(code)
+		return
+	}
+
+	/* Collect the rows */
+	v, err := valueOf(body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if data, ok := v.get("data"); ok && v.kind == kindObject {
+		v = data
+	}
+	items := []value{v}
+	if v.kind == kindArray {
+		items = v.items
+	}
+	var columns []string
+	seen := make(map[string]bool)
+	rows := make([]map[string]string, 0, len(items))
+	for _, item := range items {
+		row := make(map[string]string)
+		flatten("", item, row, &columns, seen)
+		rows = append(rows, row)
+	}
+
+	/* Byte encoding the rows */
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	if len(columns) > 0 {
+		writer.Write(columns)
+	}
+	for _, row := range rows {
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			record[i] = row[column]
+		}
+		writer.Write(record)
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	/* Writes the response */
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.WriteHeader(code)
+	w.Write(buffer.Bytes())
+}
@@ -0,0 +1,153 @@
+package response
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"proyecto/platform/web/request"
+	"strconv"
+	"strings"
+)
+
+/* Media types the structured bodies can be rendered as */
+const (
+	MediaTypeJSON = "application/json"
+	MediaTypeXML  = "application/xml"
+	MediaTypeCSV  = "text/csv"
+)
+
+// ErrNotAcceptable is returned when none of the offered media types is accepted
+var ErrNotAcceptable = errors.New("none of the available media types is acceptable")
+
+// renderers writes a body per media type, in order of preference
+var renderers = []struct {
+	mediaType string
+	write     func(w http.ResponseWriter, code int, body any)
+}{
+	{MediaTypeJSON, JSON},
+	{MediaTypeXML, XML},
+	{MediaTypeCSV, CSV},
+}
+
+/* Media range of an Accept header */
+type mediaRange struct {
+	mediaType string  // Type and subtype. Either of them may be a wildcard.
+	quality   float64 // Relative preference between 0 and 1.
+}
+
+// parseAccept parses the media ranges of an Accept header. Invalid ranges are skipped
+// parseAccept(header string) -> []mediaRange
+
+func parseAccept(header string) []mediaRange {
+	ranges := make([]mediaRange, 0)
+	for _, part := range strings.Split(header, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			quality, err = strconv.ParseFloat(q, 64)
+			if err != nil || quality < 0 || quality > 1 {
+				continue
+			}
+		}
+		ranges = append(ranges, mediaRange{mediaType: mediaType, quality: quality})
+	}
+	return ranges
+}
+
+// qualityOf returns the quality the most specific matching range gives a media type
+// qualityOf(ranges []mediaRange, mediaType string) -> float64
+
+func qualityOf(ranges []mediaRange, mediaType string) float64 {
+	mainType, _, _ := strings.Cut(mediaType, "/")
+	quality, specificity := 0.0, -1
+	for _, r := range ranges {
+		var s int
+		switch {
+		case r.mediaType == mediaType:
+			s = 2
+		case r.mediaType == mainType+"/*":
+			s = 1
+		case r.mediaType == "*/*":
+			s = 0
+		default:
+			continue
+		}
+		if s > specificity {
+			quality, specificity = r.quality, s
+		}
+	}
+	return quality
+}
+
+// Negotiate picks the media type to answer a request with following its Accept header. Ties
+// are broken by the order of the offers. Without an Accept header the first offer is picked
+// Negotiate(r *http.Request, offers ...string) -> (string, error)
+// Args:
+//		r      :  HTTP request being served.
+//		offers :  Media types available, in order of preference.
+// Return:
+//		string :  Media type picked.
+//		error  :  ErrNotAcceptable if no offer is accepted.
+
+func Negotiate(r *http.Request, offers ...string) (string, error) {
+	header := r.Header.Get("Accept")
+	if strings.TrimSpace(header) == "" && len(offers) > 0 {
+		return offers[0], nil
+	}
+	ranges := parseAccept(header)
+	best, bestQuality := "", 0.0
+	for _, offer := range offers {
+		if quality := qualityOf(ranges, offer); quality > bestQuality {
+			best, bestQuality = offer, quality
+		}
+	}
+	if best == "" {
+		return "", fmt.Errorf("%w. available: %s", ErrNotAcceptable, strings.Join(offers, ", "))
+	}
+	return best, nil
+}
+
+// Render writes a structured response in the media type the client accepts: JSON, XML or CSV.
+// When none is accepted a 406 problem is written instead.
+// Render(w http.ResponseWriter, r *http.Request, code int, body any)
+// Args:
+//		w    :  HTTP response writer.
+//		r    :  HTTP request being served.
+//		code :  HTTP status code.
+//		body :  Response body.
+// Return:
+//		none
+
+func Render(w http.ResponseWriter, r *http.Request, code int, body any) {
+	w.Header().Add("Vary", "Accept")
+	offers := make([]string, 0, len(renderers))
+	for _, renderer := range renderers {
+		offers = append(offers, renderer.mediaType)
+	}
+	mediaType, err := Negotiate(r, offers...)
+	if err != nil {
+		ProblemJSON(w, Problem{
+			Type:      "/problems/not_acceptable",
+			Code:      "not_acceptable",
+			Title:     "Not acceptable",
+			Status:    http.StatusNotAcceptable,
+			Detail:    err.Error(),
+			Instance:  r.URL.Path,
+			RequestID: request.ID(r),
+		})
+		return
+	}
+	for _, renderer := range renderers {
+		if renderer.mediaType == mediaType {
+			renderer.write(w, code, body)
+			return
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package response_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"proyecto/platform/web/response"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Negotiate function
+func TestNegotiate(t *testing.T) {
+	offers := []string{response.MediaTypeJSON, response.MediaTypeXML, response.MediaTypeCSV}
+	cases := []struct {
+		name     string
+		accept   string
+		expected string
+	}{
+		{name: "no accept header", accept: "", expected: response.MediaTypeJSON},
+		{name: "exact match", accept: "text/csv", expected: response.MediaTypeCSV},
+		{name: "highest quality", accept: "application/json;q=0.2, application/xml;q=0.8", expected: response.MediaTypeXML},
+		{name: "ties follow the offers", accept: "text/csv, application/xml", expected: response.MediaTypeXML},
+		{name: "most specific range wins", accept: "application/*;q=0.1, application/xml;q=0, */*;q=0.5", expected: response.MediaTypeCSV},
+		{name: "wildcard", accept: "*/*", expected: response.MediaTypeJSON},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			r := httptest.NewRequest("GET", "/", nil)
+			r.Header.Set("Accept", c.accept)
+
+			// act
+			mediaType, err := response.Negotiate(r, offers...)
+
+			// assert
+			require.NoError(t, err)
+			require.Equal(t, c.expected, mediaType)
+		})
+	}
+
+	t.Run("error - not acceptable", func(t *testing.T) {
+		// arrange
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept", "image/png, */*;q=0")
+
+		// act
+		_, err := response.Negotiate(r, offers...)
+
+		// assert
+		require.ErrorIs(t, err, response.ErrNotAcceptable)
+	})
+}
+
+// Tests for XML function
+func TestXML(t *testing.T) {
+	t.Run("200 - members keep their order", func(t *testing.T) {
+		// arrange
+		body := map[string]any{"data": struct {
+			Name       string            `json:"name"`
+			Attributes map[string]string `json:"attributes"`
+			Missing    *int              `json:"missing"`
+		}{Name: "a & b", Attributes: map[string]string{"pack size": "6", "flavor": "lime"}}}
+
+		// act
+		rr := httptest.NewRecorder()
+		response.XML(rr, http.StatusOK, body)
+
+		// assert
+		expectedBody := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+			`<response><data><name>a &amp; b</name><attributes><flavor>lime</flavor><entry key="pack size">6</entry></attributes>` +
+			`<missing></missing></data></response>`
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, "application/xml", rr.Header().Get("Content-Type"))
+		require.Equal(t, expectedBody, rr.Body.String())
+	})
+}
+
+// Tests for CSV function
+func TestCSV(t *testing.T) {
+	t.Run("200 - single object with nested members", func(t *testing.T) {
+		// arrange
+		body := map[string]any{"data": struct {
+			ID         int               `json:"id"`
+			Note       string            `json:"note"`
+			Attributes map[string]string `json:"attributes"`
+		}{ID: 1, Note: "one, \"two\"", Attributes: map[string]string{"size": "L"}}}
+
+		// act
+		rr := httptest.NewRecorder()
+		response.CSV(rr, http.StatusOK, body)
+
+		// assert
+		expectedBody := "id,note,attributes.size\n1,\"one, \"\"two\"\"\",L\n"
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+		require.Equal(t, expectedBody, rr.Body.String())
+	})
+}
+
+// Tests for Render function
+func TestRender(t *testing.T) {
+	t.Run("406 - not acceptable", func(t *testing.T) {
+		// arrange
+		r := httptest.NewRequest("GET", "/products", nil)
+		r.Header.Set("Accept", "application/yaml")
+
+		// act
+		rr := httptest.NewRecorder()
+		response.Render(rr, r, http.StatusOK, map[string]any{"data": 1})
+
+		// assert
+		require.Equal(t, http.StatusNotAcceptable, rr.Code)
+		require.Equal(t, "application/problem+json", rr.Header().Get("Content-Type"))
+		require.Equal(t, "Accept", rr.Header().Get("Vary"))
+	})
+}
@@ -0,0 +1,125 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"proyecto/platform/web/request"
+)
+
+// Problem is an RFC 7807 problem details body
+type Problem struct {
+	Type      string            `json:"type"`                 // URI reference identifying the problem type.
+	Code      string            `json:"code"`                 // Machine readable code of the problem type.
+	Title     string            `json:"title"`                // Short summary of the problem type.
+	Status    int               `json:"status"`               // HTTP status code.
+	Detail    string            `json:"detail,omitempty"`     // Explanation of this occurrence of the problem.
+	Instance  string            `json:"instance,omitempty"`   // URI reference of the request the problem occurred on.
+	RequestID string            `json:"request_id,omitempty"` // ID of the request the problem occurred on.
+	Errors    map[string]string `json:"errors,omitempty"`     // Message per invalid field, keyed by JSON Pointer.
+}
+
+// ProblemJSON writes a problem details response to the client.
+// ProblemJSON(w http.ResponseWriter, problem Problem)
+// Args:
+//		w       :  HTTP response writer.
+//		problem :  Problem to write. Its status is the response status code.
+// Return:
+//		none
+
+func ProblemJSON(w http.ResponseWriter, problem Problem) {
+	bytes, err := json.Marshal(problem)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.Status)
+	w.Write(bytes)
+}
+
+/* Problem type of an error */
+type problemType struct {
+	target error  // Error matched with errors.Is.
+	status int    // HTTP status code.
+	code   string // Machine readable code.
+	title  string // Short summary.
+}
+
+// Problems maps errors to problem types. Errors without a registered type are rendered as
+// internal errors, hiding their detail
+type Problems struct {
+	types []problemType
+}
+
+// NewProblems creates an empty error to problem mapping
+// NewProblems() -> *Problems
+// Return:
+//		*Problems :  New Problems instance.
+
+func NewProblems() *Problems {
+	return &Problems{}
+}
+
+// Register maps an error (and the errors wrapping it) to a problem type. The first
+// registered match wins
+// Register(target error, status int, code string, title string) -> *Problems
+// Args:
+//		target :  Error to map.
+//		status :  HTTP status code.
+//		code   :  Stable machine readable code. The problem type URI is derived from it.
+//		title  :  Short summary of the problem type.
+// Return:
+//		*Problems :  The same mapping, to chain registrations.
+
+func (p *Problems) Register(target error, status int, code string, title string) *Problems {
+	p.types = append(p.types, problemType{target: target, status: status, code: code, title: title})
+	return p
+}
+
+// Problem builds the problem an error raised while serving a request maps to
+// Problem(r *http.Request, err error) -> Problem
+// Args:
+//		r   :  HTTP request being served.
+//		err :  Error raised.
+// Return:
+//		Problem :  Problem details.
+
+func (p *Problems) Problem(r *http.Request, err error) Problem {
+	problem := Problem{
+		Type:      "/problems/internal_error",
+		Code:      "internal_error",
+		Title:     "Internal server error",
+		Status:    http.StatusInternalServerError,
+		Instance:  r.URL.Path,
+		RequestID: request.ID(r),
+	}
+	for _, t := range p.types {
+		if errors.Is(err, t.target) {
+			problem.Type = "/problems/" + t.code
+			problem.Code = t.code
+			problem.Title = t.title
+			problem.Status = t.status
+			problem.Detail = err.Error()
+			var fields request.FieldErrors
+			if errors.As(err, &fields) {
+				problem.Errors = fields
+			}
+			break
+		}
+	}
+	return problem
+}
+
+// Write writes the problem an error maps to as response.
+// Write(w http.ResponseWriter, r *http.Request, err error)
+// Args:
+//		w   :  HTTP response writer.
+//		r   :  HTTP request being served.
+//		err :  Error raised.
+// Return:
+//		none
+
+func (p *Problems) Write(w http.ResponseWriter, r *http.Request, err error) {
+	ProblemJSON(w, p.Problem(r, err))
+}
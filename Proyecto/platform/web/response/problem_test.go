@@ -0,0 +1,53 @@
+package response_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"proyecto/platform/web/request"
+	"proyecto/platform/web/response"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Problems
+func TestProblems(t *testing.T) {
+	errNotFound := errors.New("thing not found")
+	problems := response.NewProblems().
+		Register(errNotFound, http.StatusNotFound, "thing_not_found", "Thing not found")
+
+	t.Run("case 1: should map a wrapped registered error", func(t *testing.T) {
+		// arrange
+		req := request.WithID(httptest.NewRequest("GET", "/things/7?full=true", nil), "abc123")
+
+		// act
+		rr := httptest.NewRecorder()
+		problems.Write(rr, req, fmt.Errorf("%w: id 7", errNotFound))
+
+		// assert
+		expectedCode := http.StatusNotFound
+		expectedBody := `{"type":"/problems/thing_not_found","code":"thing_not_found","title":"Thing not found","status":404,` +
+			`"detail":"thing not found: id 7","instance":"/things/7","request_id":"abc123"}`
+		expectedHeaders := http.Header{"Content-Type": []string{"application/problem+json"}}
+		require.Equal(t, expectedCode, rr.Code)
+		require.Equal(t, expectedBody, rr.Body.String())
+		require.Equal(t, expectedHeaders, rr.Header())
+	})
+
+	t.Run("case 2: should hide the detail of an unregistered error", func(t *testing.T) {
+		// arrange
+		req := httptest.NewRequest("GET", "/things/7", nil)
+
+		// act
+		rr := httptest.NewRecorder()
+		problems.Write(rr, req, errors.New("disk on fire"))
+
+		// assert
+		expectedCode := http.StatusInternalServerError
+		expectedBody := `{"type":"/problems/internal_error","code":"internal_error","title":"Internal server error","status":500,"instance":"/things/7"}`
+		require.Equal(t, expectedCode, rr.Code)
+		require.Equal(t, expectedBody, rr.Body.String())
+	})
+}
@@ -0,0 +1,109 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+/* Kinds of the values of a body */
+const (
+	kindScalar = iota // String, number, boolean or null.
+	kindObject        // Members in the order they were encoded.
+	kindArray         // Items.
+)
+
+/* Member of an object value */
+type member struct {
+	name  string
+	value value
+}
+
+// value is a body as its JSON encoding sees it. Objects keep the order of their members, so
+// the XML and CSV renderings follow the order of the struct fields
+type value struct {
+	kind    int
+	text    string // Text of a scalar. Empty for null.
+	null    bool   // The scalar is null.
+	members []member
+	items   []value
+}
+
+// valueOf encodes a body as JSON and reads it back as a value
+// valueOf(body any) -> (value, error)
+// Args:
+//		body :  Response body.
+// Return:
+//		value :  Body as a value.
+//		error :  Error raised during the execution (if exists).
+
+func valueOf(body any) (value, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return value{}, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return readValue(decoder)
+}
+
+// readValue reads the next value of a JSON stream
+// readValue(decoder *json.Decoder) -> (value, error)
+
+func readValue(decoder *json.Decoder) (value, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return value{}, err
+	}
+	switch token := token.(type) {
+	case json.Delim:
+		if token == '[' {
+			v := value{kind: kindArray}
+			for decoder.More() {
+				item, err := readValue(decoder)
+				if err != nil {
+					return value{}, err
+				}
+				v.items = append(v.items, item)
+			}
+			_, err = decoder.Token()
+			return v, err
+		}
+		v := value{kind: kindObject}
+		for decoder.More() {
+			name, err := decoder.Token()
+			if err != nil {
+				return value{}, err
+			}
+			item, err := readValue(decoder)
+			if err != nil {
+				return value{}, err
+			}
+			v.members = append(v.members, member{name: name.(string), value: item})
+		}
+		_, err = decoder.Token()
+		return v, err
+	case string:
+		return value{kind: kindScalar, text: token}, nil
+	case json.Number:
+		return value{kind: kindScalar, text: token.String()}, nil
+	case bool:
+		if token {
+			return value{kind: kindScalar, text: "true"}, nil
+		}
+		return value{kind: kindScalar, text: "false"}, nil
+	default:
+		return value{kind: kindScalar, null: true}, nil
+	}
+}
+
+// get returns the member of an object value by name
+// get(name string) -> (value, bool)
+
+func (v value) get(name string) (value, bool) {
+	for _, m := range v.members {
+		if m.name == name {
+			return m.value, true
+		}
+	}
+	return value{}, false
+}
@@ -0,0 +1,107 @@
+package response
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"unicode"
+)
+
+// XMLRoot is the name of the element wrapping every XML body
+const XMLRoot = "response"
+
+// xmlName checks a member name can be used as element name
+// xmlName(name string) -> bool
+
+func xmlName(name string) bool {
+	if name == "" || strings.HasPrefix(strings.ToLower(name), "xml") {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case unicode.IsLetter(r) || r == '_':
+		case i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// encodeXML writes a value as the content of an element. Objects become child elements named
+// after their members (or entry elements with a key attribute when the name can't be an
+// element name) and arrays become item elements
+// encodeXML(encoder *xml.Encoder, name xml.Name, attrs []xml.Attr, v value) -> error
+
+func encodeXML(encoder *xml.Encoder, name xml.Name, attrs []xml.Attr, v value) error {
+	start := xml.StartElement{Name: name, Attr: attrs}
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	switch v.kind {
+	case kindObject:
+		for _, m := range v.members {
+			childName, childAttrs := xml.Name{Local: m.name}, []xml.Attr(nil)
+			if !xmlName(m.name) {
+				childName = xml.Name{Local: "entry"}
+				childAttrs = []xml.Attr{{Name: xml.Name{Local: "key"}, Value: m.name}}
+			}
+			if err := encodeXML(encoder, childName, childAttrs, m.value); err != nil {
+				return err
+			}
+		}
+	case kindArray:
+		for _, item := range v.items {
+			if err := encodeXML(encoder, xml.Name{Local: "item"}, nil, item); err != nil {
+				return err
+			}
+		}
+	default:
+		if err := encoder.EncodeToken(xml.CharData(v.text)); err != nil {
+			return err
+		}
+	}
+	return encoder.EncodeToken(start.End())
+}
+
+// XML writes an XML response to the client. The body is rendered as its JSON encoding would
+// be, inside a response root element.
+// XML(w http.ResponseWriter, code int, body any)
+// Args:
+//		w    :  HTTP response writer.
+//		code :  HTTP status code.
+//		body :  Response body.
+// Return:
+//		none
+
+func XML(w http.ResponseWriter, code int, body any) {
+	/* Checks empty body */
+	if body == nil {
+		w.WriteHeader(code)
+		return
+	}
+
+	/* Byte encoding the body */
+	v, err := valueOf(body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	var buffer bytes.Buffer
+	buffer.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buffer)
+	if err := encodeXML(encoder, xml.Name{Local: XMLRoot}, nil, v); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if err := encoder.Flush(); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	/* Writes the response */
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(code)
+	w.Write(buffer.Bytes())
+}
@@ -0,0 +1,81 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/* Headers of a webhook request */
+const (
+	HeaderID        = "X-Webhook-ID"        // Id of the delivery. Repeated on every attempt, so receivers can drop duplicates.
+	HeaderEvent     = "X-Webhook-Event"     // Event type delivered.
+	HeaderTimestamp = "X-Webhook-Timestamp" // Unix seconds the request was signed at.
+	HeaderSignature = "X-Webhook-Signature" // "sha256=" followed by the hex HMAC-SHA256 of the timestamp, a dot and the body.
+)
+
+/* Errors definition */
+var (
+	ErrSignatureMissing = errors.New("webhook signature missing")
+	ErrSignatureInvalid = errors.New("webhook signature invalid")
+	ErrTimestampExpired = errors.New("webhook timestamp outside the tolerance")
+)
+
+// Sign returns the signature of a webhook body
+// Sign(secret string, timestamp int64, body []byte) -> string
+// Args:
+//		secret:    Secret shared with the receiver
+//		timestamp: Unix seconds the body is signed at. Signed along with the body so a request can't be replayed later
+//		body: 	   Body of the request
+// Return:
+//		string: Value of the HeaderSignature header
+
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of a webhook request and returns its body. The body of the request
+// is restored, so it can be read again
+// Verify(r *http.Request, secret string, now time.Time, tolerance time.Duration) -> ([]byte, error)
+// Args:
+//		r: 		   Webhook request received
+//		secret:    Secret shared with the sender
+//		now: 	   Moment the request is received at
+//		tolerance: Maximum distance between the timestamp and now. Not checked when zero
+// Return:
+//		[]byte: Body of the request
+//		error:  Error raised during the execution (if exists)
+
+func Verify(r *http.Request, secret string, now time.Time, tolerance time.Duration) ([]byte, error) {
+	signature := r.Header.Get(HeaderSignature)
+	timestamp, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+	if signature == "" || err != nil {
+		return nil, ErrSignatureMissing
+	}
+	if tolerance > 0 {
+		if distance := now.Sub(time.Unix(timestamp, 0)); distance > tolerance || distance < -tolerance {
+			return nil, ErrTimestampExpired
+		}
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if !strings.HasPrefix(signature, "sha256=") || !hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body))) {
+		return nil, ErrSignatureInvalid
+	}
+	return body, nil
+}
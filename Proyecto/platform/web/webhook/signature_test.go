@@ -0,0 +1,83 @@
+package webhook_test
+
+import (
+	"net/http/httptest"
+	"proyecto/platform/web/webhook"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Sign and Verify functions
+func TestVerify(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	body := `{"type":"product.price_changed"}`
+
+	t.Run("success", func(t *testing.T) {
+		// arrange
+		req := httptest.NewRequest("POST", "/hook", strings.NewReader(body))
+		req.Header.Set(webhook.HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+		req.Header.Set(webhook.HeaderSignature, webhook.Sign("secret", now.Unix(), []byte(body)))
+
+		// act
+		received, err := webhook.Verify(req, "secret", now.Add(time.Minute), 5*time.Minute)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, body, string(received))
+		require.True(t, strings.HasPrefix(req.Header.Get(webhook.HeaderSignature), "sha256="))
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		// arrange
+		req := httptest.NewRequest("POST", "/hook", strings.NewReader(body))
+		req.Header.Set(webhook.HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+		req.Header.Set(webhook.HeaderSignature, webhook.Sign("other", now.Unix(), []byte(body)))
+
+		// act
+		_, err := webhook.Verify(req, "secret", now, 5*time.Minute)
+
+		// assert
+		require.ErrorIs(t, err, webhook.ErrSignatureInvalid)
+	})
+
+	t.Run("tampered body", func(t *testing.T) {
+		// arrange
+		req := httptest.NewRequest("POST", "/hook", strings.NewReader(`{"type":"product.deleted"}`))
+		req.Header.Set(webhook.HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+		req.Header.Set(webhook.HeaderSignature, webhook.Sign("secret", now.Unix(), []byte(body)))
+
+		// act
+		_, err := webhook.Verify(req, "secret", now, 5*time.Minute)
+
+		// assert
+		require.ErrorIs(t, err, webhook.ErrSignatureInvalid)
+	})
+
+	t.Run("replayed", func(t *testing.T) {
+		// arrange
+		req := httptest.NewRequest("POST", "/hook", strings.NewReader(body))
+		req.Header.Set(webhook.HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+		req.Header.Set(webhook.HeaderSignature, webhook.Sign("secret", now.Unix(), []byte(body)))
+
+		// act
+		_, err := webhook.Verify(req, "secret", now.Add(time.Hour), 5*time.Minute)
+
+		// assert
+		require.ErrorIs(t, err, webhook.ErrTimestampExpired)
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		// arrange
+		req := httptest.NewRequest("POST", "/hook", strings.NewReader(body))
+
+		// act
+		_, err := webhook.Verify(req, "secret", now, 0)
+
+		// assert
+		require.ErrorIs(t, err, webhook.ErrSignatureMissing)
+	})
+}